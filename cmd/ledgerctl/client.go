@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// client calls the ledger HTTP API on behalf of an operator
+type client struct {
+	baseUrl string
+	tenant  string
+	actor   string
+	roles   string
+	token   string // admin token, for admin routes and calls without a tenant
+	http    *http.Client
+}
+
+// request sends body as JSON (or as-is when it is an io.Reader) and fails on any non-2xx status
+func (c *client) request(method, path string, body any, header http.Header) (*http.Response, error) {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.baseUrl, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if reader != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Actor", c.actor)
+	}
+	if c.roles != "" {
+		req.Header.Set("X-Actor-Roles", c.roles)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+// call sends the request and prints the JSON response indented
+func (c *client) call(method, path string, body any, header http.Header) error {
+	resp, err := c.request(method, path, body, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, raw, "", "  ") != nil {
+		_, err = os.Stdout.Write(raw)
+		return err
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// copy streams the response body to stdout as it arrives
+func (c *client) copy(method, path string, body any, header http.Header) error {
+	resp, err := c.request(method, path, body, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(os.Stdout, resp.Body)
+	return err
+}
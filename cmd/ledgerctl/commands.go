@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var errUsage = errors.New("see ledgerctl -h for usage")
+
+// parse parses flags that may come before or after a single positional argument
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() == 0 {
+		return "", nil
+	}
+	positional := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return "", err
+	}
+	return positional, nil
+}
+
+func accountsCommand(c *client, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("accounts create", flag.ExitOnError)
+		id := fs.String("id", "", "account ID (required)")
+		accountType := fs.String("type", "", "product type, e.g. wallet")
+		class := fs.String("class", "", "asset, liability, equity, revenue or expense")
+		currency := fs.String("currency", "", "ISO 4217 code; empty means the base currency")
+		parent := fs.String("parent", "", "parent account ID")
+		overdraft := fs.String("overdraft-limit", "0", "how far below zero the balance may go")
+		fs.Parse(args[1:])
+		if *id == "" {
+			return errors.New("accounts create: -id is required")
+		}
+		return c.call(http.MethodPost, "/accounts", map[string]any{
+			"id":              *id,
+			"type":            *accountType,
+			"class":           *class,
+			"currency":        *currency,
+			"parent_id":       *parent,
+			"overdraft_limit": *overdraft,
+		}, nil)
+	case "get":
+		if len(args) != 2 {
+			return errors.New("accounts get: expected an account ID")
+		}
+		return c.call(http.MethodGet, "/accounts/"+url.PathEscape(args[1]), nil, nil)
+	default:
+		return fmt.Errorf("accounts: unknown subcommand %q", args[0])
+	}
+}
+
+func transferCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("transfer", flag.ExitOnError)
+	from := fs.String("from", "", "account to debit (required)")
+	to := fs.String("to", "", "account to credit (required)")
+	amount := fs.String("amount", "", "amount to move (required)")
+	reference := fs.String("reference", "", "order or invoice reference")
+	description := fs.String("description", "", "text shown on statements")
+	key := fs.String("idempotency-key", "", "defaults to a new UUID; reuse it to retry safely")
+	force := fs.Bool("force", false, "post even if it looks like a duplicate")
+	dryRun := fs.Bool("dry-run", false, "run every check and show the projected balances without posting")
+	fs.Parse(args)
+	if *from == "" || *to == "" || *amount == "" {
+		return errors.New("transfer: -from, -to and -amount are required")
+	}
+	if *key == "" {
+		*key = uuid.New().String()
+	}
+
+	path := "/transactions"
+	if *dryRun {
+		path += "?dry_run=true"
+	}
+	// The amount is sent as a JSON string so no precision is lost on the way
+	return c.call(http.MethodPost, path, map[string]any{
+		"from_account": *from,
+		"to_account":   *to,
+		"amount":       *amount,
+		"reference":    *reference,
+		"description":  *description,
+		"force":        *force,
+	}, http.Header{"Idempotency-Key": {*key}})
+}
+
+func reverseCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("reverse", flag.ExitOnError)
+	reason := fs.String("reason", "", "why the transaction is reversed, kept on the reversal")
+	id, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return errors.New("reverse: expected a transaction ID")
+	}
+	return c.call(http.MethodPost, "/transactions/"+url.PathEscape(id)+"/reverse", map[string]any{"reason": *reason}, nil)
+}
+
+// reverseBatchCommand reverses the IDs given as arguments, read from -file (one per line,
+// "-" for stdin) and matched by -filter, all under one adjustment reference
+func reverseBatchCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("reverse-batch", flag.ExitOnError)
+	adjustment := fs.String("adjustment-reference", "", "reference shared by every reversal (required)")
+	file := fs.String("file", "", "file of transaction IDs, one per line; - reads stdin")
+	filter := fs.String("filter", "", `transactions to match, as GET /transactions query parameters, e.g. "reference=batch-42"`)
+	reason := fs.String("reason", "", "why the transactions are reversed")
+	dryRun := fs.Bool("dry-run", false, "list what would be reversed without posting")
+	fs.Parse(args)
+	if *adjustment == "" {
+		return errors.New("reverse-batch: -adjustment-reference is required")
+	}
+
+	ids := fs.Args()
+	if *file != "" {
+		input := os.Stdin
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			input = f
+		}
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			if id := strings.TrimSpace(scanner.Text()); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if len(ids) == 0 && *filter == "" {
+		return errors.New("reverse-batch: give transaction IDs, -file or -filter")
+	}
+
+	return c.call(http.MethodPost, "/admin/reversals", map[string]any{
+		"transaction_ids":      ids,
+		"filter":               *filter,
+		"adjustment_reference": *adjustment,
+		"reason":               *reason,
+		"dry_run":              *dryRun,
+	}, nil)
+}
+
+func balanceCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("balance", flag.ExitOnError)
+	asOf := fs.String("as-of", "", "RFC3339 time to compute the balance at")
+	rollup := fs.Bool("rollup", false, "add up every account beneath this one")
+	accountId, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if accountId == "" {
+		return errors.New("balance: expected an account ID")
+	}
+
+	if *asOf != "" {
+		query := url.Values{"account_id": {accountId}, "as_of": {*asOf}}
+		return c.call(http.MethodGet, "/accounts/balance?"+query.Encode(), nil, nil)
+	}
+	path := "/accounts/" + url.PathEscape(accountId) + "/balance"
+	if *rollup {
+		path += "?rollup=true"
+	}
+	return c.call(http.MethodGet, path, nil, nil)
+}
+
+func entriesCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("entries", flag.ExitOnError)
+	accountId := fs.String("account", "", "only entries of this account")
+	asCSV := fs.Bool("csv", false, "print CSV instead of JSON lines")
+	fs.Parse(args)
+
+	accept := "application/x-ndjson"
+	if *asCSV {
+		accept = "text/csv"
+	}
+	path := "/ledgerEntries"
+	if *accountId != "" {
+		path += "?" + url.Values{"account_id": {*accountId}}.Encode()
+	}
+	return c.copy(http.MethodGet, path, nil, http.Header{"Accept": {accept}})
+}
+
+// tailCommand prints committed entries as they happen until interrupted
+func tailCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	accountId := fs.String("account", "", "only entries of this account")
+	fs.Parse(args)
+
+	path := "/stream/entries"
+	if *accountId != "" {
+		path += "?" + url.Values{"account_id": {*accountId}}.Encode()
+	}
+
+	// The stream stays open, so the usual request timeout does not apply
+	stream := *c
+	stream.http = &http.Client{}
+	resp, err := stream.request(http.MethodGet, path, nil, http.Header{"Accept": {"text/event-stream"}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			fmt.Println(data)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("tail: stream closed by the server")
+}
+
+func reconcileCommand(c *client, args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	accountId := fs.String("account", "", "account to reconcile (required)")
+	path := fs.String("file", "", "bank statement file (required)")
+	format := fs.String("format", "csv", "statement format: csv or mt940")
+	fs.Parse(args)
+	if *accountId == "" || *path == "" {
+		return errors.New("reconcile: -account and -file are required")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	query := url.Values{"account_id": {*accountId}, "format": {*format}}
+	return c.call(http.MethodPost, "/reconciliations?"+query.Encode(), file,
+		http.Header{"Content-Type": {"application/octet-stream"}})
+}
+
+func deadLettersCommand(c *client, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("dead-letters list", flag.ExitOnError)
+		limit := fs.Int("limit", 100, "how many letters to show, oldest first")
+		fs.Parse(args[1:])
+		return c.call(http.MethodGet, fmt.Sprintf("/dead-letters?limit=%d", *limit), nil, nil)
+	case "redrive":
+		return c.call(http.MethodPost, "/dead-letters/redrive", nil, nil)
+	default:
+		return fmt.Errorf("dead-letters: unknown subcommand %q", args[0])
+	}
+}
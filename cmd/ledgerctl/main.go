@@ -0,0 +1,93 @@
+// Command ledgerctl is the operator CLI for the ledger HTTP API.
+//
+//	ledgerctl [-server URL] [-tenant ID] [-actor NAME] [-roles R,R] [-admin-token T] <command> [flags]
+//
+// Commands:
+//
+//	accounts create -id ID [-type T] [-class C] [-currency CUR] [-parent ID] [-overdraft-limit N]
+//	accounts get ID
+//	transfer -from ID -to ID -amount N [-reference R] [-description D] [-idempotency-key K] [-force] [-dry-run]
+//	reverse ID [-reason R]
+//	reverse-batch -adjustment-reference REF [-file PATH] [-filter QUERY] [-reason R] [-dry-run] [ID ...]
+//	balance ID [-as-of RFC3339] [-rollup]
+//	entries [-account ID] [-csv]
+//	tail [-account ID]
+//	reconcile -account ID -file PATH [-format csv|mt940]
+//	dead-letters list [-limit N]
+//	dead-letters redrive
+//
+// reverse-batch needs -admin-token.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+type command struct {
+	usage string
+	run   func(c *client, args []string) error
+}
+
+var commands = map[string]command{
+	"accounts":      {"accounts create|get ...", accountsCommand},
+	"transfer":      {"transfer -from ID -to ID -amount N", transferCommand},
+	"reverse":       {"reverse ID [-reason R]", reverseCommand},
+	"reverse-batch": {"reverse-batch -adjustment-reference REF [-file PATH] [-filter QUERY] [ID ...] (admin)", reverseBatchCommand},
+	"balance":       {"balance ID [-as-of RFC3339] [-rollup]", balanceCommand},
+	"entries":       {"entries [-account ID] [-csv]", entriesCommand},
+	"tail":          {"tail [-account ID]", tailCommand},
+	"reconcile":     {"reconcile -account ID -file PATH", reconcileCommand},
+	"dead-letters":  {"dead-letters list|redrive", deadLettersCommand},
+}
+
+func main() {
+	defaultServer := os.Getenv("LEDGER_URL")
+	if defaultServer == "" {
+		defaultServer = "http://localhost:8080"
+	}
+	server := flag.String("server", defaultServer, "ledger API base URL (LEDGER_URL)")
+	tenantId := flag.String("tenant", os.Getenv("LEDGER_TENANT"), "tenant to act as (LEDGER_TENANT)")
+	actor := flag.String("actor", os.Getenv("USER"), "name recorded in the audit log")
+	roles := flag.String("roles", os.Getenv("LEDGER_ROLES"), "comma-separated roles of the actor, e.g. ops (LEDGER_ROLES)")
+	adminToken := flag.String("admin-token", os.Getenv("LEDGER_ADMIN_TOKEN"), "admin token, needed without -tenant and by admin commands (LEDGER_ADMIN_TOKEN)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	c := &client{
+		baseUrl: *server,
+		tenant:  *tenantId,
+		actor:   *actor,
+		roles:   *roles,
+		token:   *adminToken,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if err := cmd.run(c, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ledgerctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ledgerctl [-server URL] [-tenant ID] [-actor NAME] [-roles R,R] [-admin-token T] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, name := range []string{"accounts", "transfer", "reverse", "reverse-batch", "balance", "entries", "tail", "reconcile", "dead-letters"} {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nglobal flags:")
+	flag.PrintDefaults()
+}
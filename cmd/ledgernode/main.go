@@ -0,0 +1,129 @@
+// Command ledgernode runs one node of a replicated ledger without Postgres: the entries live
+// in memory on every node and are kept in step by a Raft group. The leader takes writes;
+// every node serves reads, and a follower answers writes with 503 and the leader's address.
+//
+//	ledgernode -id node1 -raft-addr 10.0.0.1:7000 -http-addr :8080 -data-dir /var/lib/ledger \
+//	    -peers node1=10.0.0.1:7000,node2=10.0.0.2:7000,node3=10.0.0.3:7000 -bootstrap
+//
+// Run the same -peers and -bootstrap on every node; a node that already has a data
+// directory ignores them and rejoins from its own log and snapshots.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/api"
+	kafka "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/kafka"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/logger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/replication"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+)
+
+func main() {
+	id := flag.String("id", "", "this node's ID within the group")
+	raftAddr := flag.String("raft-addr", "127.0.0.1:7000", "host:port of this node's Raft transport, as the peers reach it")
+	httpAddr := flag.String("http-addr", ":8080", "address the ledger API is served on")
+	dataDir := flag.String("data-dir", "", "directory for the Raft log and snapshots")
+	peerSpec := flag.String("peers", "", "every member of the group as id=host:port,...; this node alone when empty")
+	bootstrap := flag.Bool("bootstrap", false, "bootstrap the group from -peers if this node has no state yet")
+	snapshotThreshold := flag.Uint64("snapshot-threshold", 0, "commands applied between snapshots; raft's default when 0")
+	snapshotInterval := flag.Duration("snapshot-interval", 0, "how often the snapshot threshold is checked; raft's default when 0")
+	brokers := flag.String("brokers", "", "comma-separated Kafka brokers; events are dropped when empty")
+	flag.Parse()
+
+	appLogger := logger.New()
+	peers, err := replication.ParsePeers(*peerSpec)
+	if err != nil {
+		appLogger.Error("invalid -peers", "error", err)
+		os.Exit(2)
+	}
+
+	local := memory.NewMemoryLedgerStore()
+	node, err := replication.Open(replication.Config{
+		ID:        *id,
+		BindAddr:  *raftAddr,
+		DataDir:   *dataDir,
+		Peers:     peers,
+		Bootstrap: *bootstrap,
+
+		SnapshotThreshold: *snapshotThreshold,
+		SnapshotInterval:  *snapshotInterval,
+	}, local, appLogger)
+	if err != nil {
+		appLogger.Error("failed to start raft node", "error", err)
+		os.Exit(1)
+	}
+
+	var publisher interfaces.EventPublisher = discardPublisher{}
+	if *brokers != "" {
+		kafkaPublisher := kafka.NewPublisher(strings.Split(*brokers, ","))
+		kafkaPublisher.SetTimeout(2 * time.Second)
+		publisher = kafkaPublisher
+	}
+	ledgerService := ledger.NewLedger(memory.NewReplicatedStore(local, node), appLogger, publisher)
+	mux := api.NewRouter(ledgerService, api.WithLogger(appLogger))
+	server := &http.Server{
+		Addr:              *httpAddr,
+		Handler:           api.TenantAccountGuard(ledgerService, leaderWrites(node, mux)),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			appLogger.Error("server shutdown failed", "error", err)
+		}
+	}()
+
+	appLogger.Info("ledger node started", "id", *id, "raft", *raftAddr, "http", *httpAddr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		appLogger.Error("server stopped", "error", err)
+	}
+	if err := node.Close(); err != nil {
+		appLogger.Error("raft shutdown failed", "error", err)
+	}
+}
+
+// leaderWrites turns writes away on a follower before they reach the ledger, which would
+// otherwise run its checks against a copy that may lag the leader's
+func leaderWrites(node *replication.Node, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		leader, isLeader := node.Leader()
+		if isLeader {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error":  memory.ErrNotLeader.Error(),
+			"leader": leader,
+		})
+	})
+}
+
+// discardPublisher drops events when no broker is configured
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(topic string, event any) error { return nil }
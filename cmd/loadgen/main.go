@@ -0,0 +1,347 @@
+// Command loadgen fires a mix of transfers and balance reads at a ledger and reports
+// throughput and latency percentiles, so performance regressions show up before release.
+//
+//	loadgen -store memory -duration 30s -concurrency 32 -read-ratio 0.8
+//	loadgen -store postgres -requests 100000
+//	loadgen -store sqlite -sqlite-path /tmp/ledger.db
+//	loadgen -url http://localhost:8080 -accounts 1000
+//	loadgen -store memory -advance 24h -advance-every 1s
+//
+// -store runs the ledger in this process (memory, sqlite, or postgres configured like the
+// server); sqlite writes to a fresh temporary file unless -sqlite-path names one, and needs a
+// cgo build. -url targets a running server over HTTP instead. Funds checks apply as configured, so
+// enable overdrafts on the generated accounts or leave FUNDS_CHECK_ENABLED off.
+//
+// -advance moves the ledger's clock forward while the load runs, so expiry, schedules,
+// interest and day closes are exercised over simulated days; against -url the server
+// must run with CLOCK_SIMULATED=true.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/logger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/sqlite"
+	"github.com/shopspring/decimal"
+)
+
+// options shape the generated traffic
+type options struct {
+	duration     time.Duration
+	requests     int64 // stops after this many operations when positive, otherwise runs for duration
+	concurrency  int
+	accounts     int
+	prefix       string
+	readRatio    float64
+	maxAmount    decimal.Decimal
+	advance      time.Duration // simulated time added every advanceEvery; zero keeps the wall clock
+	advanceEvery time.Duration
+}
+
+func main() {
+	store := flag.String("store", "memory", "in-process store: memory, postgres or sqlite")
+	sqlitePath := flag.String("sqlite-path", "", "database file for -store sqlite; a temporary one is used and removed when empty")
+	targetUrl := flag.String("url", "", "base URL of a running server; overrides -store")
+	tenantId := flag.String("tenant", "", "X-Tenant-ID sent with -url requests")
+	duration := flag.Duration("duration", 30*time.Second, "how long to run")
+	requests := flag.Int64("requests", 0, "stop after this many operations instead of -duration")
+	concurrency := flag.Int("concurrency", 16, "number of concurrent workers")
+	accounts := flag.Int("accounts", 100, "number of accounts to spread the load over")
+	prefix := flag.String("account-prefix", "loadgen-", "prefix of the generated account IDs")
+	readRatio := flag.Float64("read-ratio", 0.5, "share of operations that are balance reads (0-1)")
+	maxAmount := flag.String("max-amount", "10.00", "transfer amounts are drawn uniformly from 0.01 up to this")
+	advance := flag.Duration("advance", 0, "simulated time to move the ledger's clock forward by every -advance-every")
+	advanceEvery := flag.Duration("advance-every", time.Second, "wall time between clock advances")
+	flag.Parse()
+
+	appLogger := logger.New()
+	opts := options{
+		duration:     *duration,
+		requests:     *requests,
+		concurrency:  *concurrency,
+		accounts:     *accounts,
+		prefix:       *prefix,
+		readRatio:    *readRatio,
+		advance:      *advance,
+		advanceEvery: *advanceEvery,
+	}
+	amount, err := decimal.NewFromString(*maxAmount)
+	if err != nil || amount.LessThan(decimal.New(1, -2)) {
+		appLogger.Error("-max-amount must be at least 0.01", "value", *maxAmount)
+		os.Exit(2)
+	}
+	opts.maxAmount = amount
+	if opts.concurrency < 1 || opts.accounts < 2 || opts.readRatio < 0 || opts.readRatio > 1 {
+		appLogger.Error("invalid options: -concurrency must be at least 1, -accounts at least 2 and -read-ratio within 0-1")
+		os.Exit(2)
+	}
+	if opts.advance < 0 || opts.advanceEvery <= 0 {
+		appLogger.Error("invalid options: -advance must not be negative and -advance-every must be positive")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	name := *store
+	var t target
+	if *targetUrl != "" {
+		name = *targetUrl
+		t = httpTarget{
+			baseUrl: *targetUrl,
+			tenant:  *tenantId,
+			http: &http.Client{
+				Timeout:   30 * time.Second,
+				Transport: &http.Transport{MaxIdleConnsPerHost: opts.concurrency},
+			},
+		}
+	} else {
+		var closeStore func()
+		t, closeStore, err = openStore(ctx, *store, *sqlitePath, appLogger)
+		if err != nil {
+			appLogger.Error("failed to open store", "store", *store, "error", err)
+			os.Exit(1)
+		}
+		defer closeStore()
+	}
+
+	result := run(ctx, t, opts)
+	report(os.Stdout, name, opts, result)
+}
+
+// openStore builds a Ledger on the named store. The ledger's own logs are discarded:
+// one line per failed operation would bury the report.
+func openStore(ctx context.Context, name, sqlitePath string, appLogger *slog.Logger) (target, func(), error) {
+	quiet := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	simulated, _ := clock.NewSimulated(0)
+	newTarget := func(store interfaces.LedgerStore) ledgerTarget {
+		l := ledger.NewLedger(store, quiet, discardPublisher{})
+		l.SetClock(simulated)
+		return ledgerTarget{ledger: l, clock: simulated}
+	}
+
+	switch name {
+	case "memory":
+		store := memory.NewMemoryLedgerStore()
+		return newTarget(store), func() {}, nil
+
+	case "postgres":
+		if err := godotenv.Load(); err != nil {
+			appLogger.Info("no .env file found, using the environment")
+		}
+		db, err := sql.Open("postgres", postgres.ConnStringFromEnv())
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := db.PingContext(ctx); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		store := postgres.NewPostgresLedgerStore(db)
+		if _, err := store.EnsureEntryPartitions(ctx, time.Now().UTC(), 0); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return newTarget(store), func() { db.Close() }, nil
+
+	case "sqlite":
+		path, remove := sqlitePath, false
+		if path == "" {
+			dir, err := os.MkdirTemp("", "loadgen-")
+			if err != nil {
+				return nil, nil, err
+			}
+			path, remove = filepath.Join(dir, "ledger.db"), true
+		}
+		store, err := sqlite.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newTarget(store), func() {
+			store.Close()
+			if remove {
+				os.RemoveAll(filepath.Dir(path))
+			}
+		}, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown store %q", name)
+	}
+}
+
+// opStats collects the outcome of one kind of operation
+type opStats struct {
+	latencies []time.Duration // successful operations only
+	errors    int
+	firstErr  error
+}
+
+func (s *opStats) record(elapsed time.Duration, err error) {
+	if err != nil {
+		s.errors++
+		if s.firstErr == nil {
+			s.firstErr = err
+		}
+		return
+	}
+	s.latencies = append(s.latencies, elapsed)
+}
+
+func (s *opStats) merge(other *opStats) {
+	s.latencies = append(s.latencies, other.latencies...)
+	s.errors += other.errors
+	if s.firstErr == nil {
+		s.firstErr = other.firstErr
+	}
+}
+
+type result struct {
+	elapsed    time.Duration
+	advanced   time.Duration // simulated time the ledger's clock was moved forward by
+	advanceErr error
+	transfers  opStats
+	reads      opStats
+}
+
+// run keeps every worker busy until the duration elapses, the request budget is spent or
+// the run is interrupted. Workers record into their own stats, merged at the end.
+func run(ctx context.Context, t target, opts options) result {
+	if opts.requests <= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.duration)
+		defer cancel()
+	}
+
+	var issued atomic.Int64
+	workers := make([]result, opts.concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	var advanced time.Duration
+	var advanceErr error
+	if opts.advance > 0 {
+		wg.Go(func() {
+			ticker := time.NewTicker(opts.advanceEvery)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				if err := t.advance(ctx, opts.advance); err != nil {
+					if ctx.Err() == nil {
+						advanceErr = err
+					}
+					return
+				}
+				advanced += opts.advance
+			}
+		})
+	}
+	for i := range workers {
+		wg.Go(func() {
+			stats := &workers[i]
+			for ctx.Err() == nil {
+				if opts.requests > 0 && issued.Add(1) > opts.requests {
+					return
+				}
+
+				from := rand.IntN(opts.accounts)
+				if rand.Float64() < opts.readRatio {
+					began := time.Now()
+					err := t.balance(ctx, accountId(opts, from))
+					stats.reads.record(time.Since(began), err)
+					continue
+				}
+
+				// Any other account, never the sender itself
+				to := (from + 1 + rand.IntN(opts.accounts-1)) % opts.accounts
+				cents := opts.maxAmount.Shift(2).IntPart()
+				amount := decimal.New(rand.Int64N(cents)+1, -2)
+				began := time.Now()
+				err := t.transfer(ctx, accountId(opts, from), accountId(opts, to), amount)
+				stats.transfers.record(time.Since(began), err)
+			}
+		})
+	}
+	wg.Wait()
+
+	total := result{elapsed: time.Since(start), advanced: advanced, advanceErr: advanceErr}
+	for i := range workers {
+		total.transfers.merge(&workers[i].transfers)
+		total.reads.merge(&workers[i].reads)
+	}
+	return total
+}
+
+func accountId(opts options, n int) string {
+	return fmt.Sprintf("%s%04d", opts.prefix, n)
+}
+
+func report(w io.Writer, name string, opts options, r result) {
+	fmt.Fprintf(w, "target %s, %d workers, %d accounts, ran %s\n", name, opts.concurrency, opts.accounts, r.elapsed.Round(time.Millisecond))
+	if opts.advance > 0 {
+		fmt.Fprintf(w, "simulated clock moved forward by %s\n", r.advanced)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\tok\terrors\tops/s\tp50\tp90\tp99\tmax\t")
+	var all opStats
+	for _, row := range []struct {
+		op    string
+		stats *opStats
+	}{{"transfer", &r.transfers}, {"balance", &r.reads}} {
+		all.merge(row.stats)
+		writeRow(tw, row.op, row.stats, r.elapsed)
+	}
+	writeRow(tw, "total", &all, r.elapsed)
+	tw.Flush()
+
+	for _, row := range []struct {
+		op  string
+		err error
+	}{{"transfer", r.transfers.firstErr}, {"balance", r.reads.firstErr}, {"clock advance", r.advanceErr}} {
+		if row.err != nil {
+			fmt.Fprintf(w, "\nfirst %s error: %v\n", row.op, row.err)
+		}
+	}
+}
+
+func writeRow(w io.Writer, op string, s *opStats, elapsed time.Duration) {
+	slices.Sort(s.latencies)
+	throughput := float64(len(s.latencies)) / elapsed.Seconds()
+	fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n", op, len(s.latencies), s.errors, throughput,
+		percentile(s.latencies, 50), percentile(s.latencies, 90), percentile(s.latencies, 99), percentile(s.latencies, 100))
+}
+
+// percentile uses the nearest-rank method on sorted latencies
+func percentile(sorted []time.Duration, p int) string {
+	if len(sorted) == 0 {
+		return "-"
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1].Round(time.Microsecond).String()
+}
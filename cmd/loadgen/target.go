@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// target is the ledger under load, either in this process or behind its HTTP API
+type target interface {
+	transfer(ctx context.Context, from, to string, amount decimal.Decimal) error
+	balance(ctx context.Context, accountId string) error
+	advance(ctx context.Context, by time.Duration) error // moves the ledger's simulated clock forward
+}
+
+// ledgerTarget drives a Ledger directly, measuring the domain logic and the store without HTTP
+type ledgerTarget struct {
+	ledger *ledger.Ledger
+	clock  *clock.Simulated
+}
+
+func (t ledgerTarget) transfer(ctx context.Context, from, to string, amount decimal.Decimal) error {
+	key := uuid.New().String()
+	_, err := t.ledger.PostTransaction(ctx, models.Transaction{
+		ID:             key,
+		IdempotencyKey: key,
+		FromAccount:    from,
+		ToAccount:      to,
+		Amount:         amount,
+		CreatedAt:      t.ledger.Now(),
+		Force:          true, // generated traffic repeats parties and amounts by design
+	})
+	return err
+}
+
+func (t ledgerTarget) balance(ctx context.Context, accountId string) error {
+	_, err := t.ledger.GetBalance(accountId)
+	return err
+}
+
+func (t ledgerTarget) advance(ctx context.Context, by time.Duration) error {
+	return t.clock.Advance(by)
+}
+
+// httpTarget drives a running server through the same endpoints clients use
+type httpTarget struct {
+	baseUrl string
+	tenant  string
+	http    *http.Client
+}
+
+func (t httpTarget) transfer(ctx context.Context, from, to string, amount decimal.Decimal) error {
+	body, err := json.Marshal(map[string]any{
+		"from_account": from,
+		"to_account":   to,
+		"amount":       amount.String(),
+		"force":        true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := t.request(ctx, http.MethodPost, "/transactions", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", uuid.New().String())
+	return t.do(req)
+}
+
+func (t httpTarget) balance(ctx context.Context, accountId string) error {
+	req, err := t.request(ctx, http.MethodGet, "/accounts/"+url.PathEscape(accountId)+"/balance", nil)
+	if err != nil {
+		return err
+	}
+	return t.do(req)
+}
+
+// advance needs a server started with CLOCK_SIMULATED=true
+func (t httpTarget) advance(ctx context.Context, by time.Duration) error {
+	body, err := json.Marshal(map[string]string{"by": by.String()})
+	if err != nil {
+		return err
+	}
+	req, err := t.request(ctx, http.MethodPost, "/admin/clock/advance", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return t.do(req)
+}
+
+func (t httpTarget) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(t.baseUrl, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if t.tenant != "" {
+		req.Header.Set("X-Tenant-ID", t.tenant)
+	}
+	return req, nil
+}
+
+// do fails on any non-2xx status and drains the body so the connection is reused
+func (t httpTarget) do(req *http.Request) error {
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
+
+// discardPublisher drops events; loadgen measures the ledger, not the broker
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(topic string, event any) error { return nil }
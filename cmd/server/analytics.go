@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/analytics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/objectstore"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/scheduler"
+)
+
+// newAnalyticsExporter returns nil when ANALYTICS_EXPORT_URL is unset. S3 uses the usual
+// AWS_* variables; GCS uses the HMAC keys of a service account.
+func newAnalyticsExporter(store interfaces.AnalyticsStore, appLogger *slog.Logger) *analytics.Exporter {
+	exportUrl := os.Getenv("ANALYTICS_EXPORT_URL")
+	if exportUrl == "" {
+		return nil
+	}
+
+	credentials := objectstore.Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Region:          os.Getenv("AWS_REGION"),
+		Endpoint:        os.Getenv("ANALYTICS_ENDPOINT"),
+	}
+	if strings.HasPrefix(exportUrl, "gs://") {
+		credentials.AccessKeyID = os.Getenv("GCS_HMAC_ACCESS_ID")
+		credentials.SecretAccessKey = os.Getenv("GCS_HMAC_SECRET")
+		credentials.SessionToken = ""
+	}
+
+	objects, err := objectstore.Open(exportUrl, credentials)
+	if err != nil {
+		appLogger.Error("analytics export disabled", "error", err)
+		return nil
+	}
+	return analytics.NewExporter(store, objects, appLogger)
+}
+
+// registerAnalyticsExportJob writes every completed day to the analytics bucket
+func registerAnalyticsExportJob(sched *scheduler.Scheduler, exporter *analytics.Exporter, clk clock.Clock, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "analytics-export", envSchedule("ANALYTICS_EXPORT_INTERVAL", "1h"), func(ctx context.Context) error {
+		_, err := exporter.ExportDue(ctx, clk.Now())
+		return err
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
+)
+
+// newCalendar reads CALENDAR_WEEKENDS, CALENDAR_TIMEZONE and VALUE_DATE_CONVENTION, loads
+// the holidays and hands the calendar to the ledger; a bad setting is logged and replaced
+// by its default
+func newCalendar(store *postgres.PostgresLedgerStore, ledgerService *ledger.Ledger, appLogger *slog.Logger) *calendar.Calendar {
+	weekends, err := calendar.ParseWeekends(envString("CALENDAR_WEEKENDS", ""))
+	if err != nil {
+		appLogger.Error("invalid CALENDAR_WEEKENDS, using Saturday and Sunday", "error", err)
+		weekends, _ = calendar.ParseWeekends("")
+	}
+	location, err := time.LoadLocation(envString("CALENDAR_TIMEZONE", "UTC"))
+	if err != nil {
+		appLogger.Error("invalid CALENDAR_TIMEZONE, using UTC", "error", err)
+		location = time.UTC
+	}
+	convention, err := calendar.ParseConvention(envString("VALUE_DATE_CONVENTION", string(calendar.Following)))
+	if err != nil {
+		appLogger.Error("invalid VALUE_DATE_CONVENTION, using following", "error", err)
+		convention = calendar.Following
+	}
+
+	businessDays := calendar.New(store, location, weekends, appLogger)
+	if err := businessDays.Load(context.Background()); err != nil {
+		appLogger.Error("failed to load holidays", "error", err)
+	}
+	go businessDays.Run(context.Background(), envDuration("CALENDAR_REFRESH", time.Minute))
+	ledgerService.SetCalendar(businessDays, convention)
+	return businessDays
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/chaos"
+)
+
+// chaosConfig reads the fault injection settings. Nothing is injected unless CHAOS_ENABLED
+// is true, so a stray rate in a production environment file does no harm.
+func chaosConfig(appLogger *slog.Logger) chaos.Config {
+	if os.Getenv("CHAOS_ENABLED") != "true" {
+		return chaos.Config{}
+	}
+
+	config := chaos.Config{Latency: envDuration("CHAOS_LATENCY", 0)}
+	for key, rate := range map[string]*float64{
+		"CHAOS_LATENCY_RATE":         &config.LatencyRate,
+		"CHAOS_PUBLISH_FAILURE_RATE": &config.PublishFailureRate,
+		"CHAOS_COMMIT_FAILURE_RATE":  &config.CommitFailureRate,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			appLogger.Error("invalid "+key+", must be between 0 and 1; not injecting it", "value", value)
+			continue
+		}
+		*rate = parsed
+	}
+
+	if config.Enabled() {
+		appLogger.Warn("fault injection enabled; never run this in production",
+			"latency", config.Latency.String(),
+			"latency_rate", config.LatencyRate,
+			"publish_failure_rate", config.PublishFailureRate,
+			"commit_failure_rate", config.CommitFailureRate,
+		)
+	}
+	return config
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+)
+
+// simulatedClock reads CLOCK_SIMULATED and CLOCK_OFFSET. It returns nil, leaving the ledger
+// on the wall clock, unless the simulation is switched on: a clock that can be moved
+// forward lets a load test expire, schedule, accrue and close days in minutes.
+func simulatedClock(appLogger *slog.Logger) *clock.Simulated {
+	if os.Getenv("CLOCK_SIMULATED") != "true" {
+		return nil
+	}
+	simulated, err := clock.NewSimulated(envDuration("CLOCK_OFFSET", 0))
+	if err != nil {
+		appLogger.Error("invalid CLOCK_OFFSET, starting at the wall clock", "error", err)
+		simulated, _ = clock.NewSimulated(0)
+	}
+	appLogger.Warn("simulated clock enabled; never run this in production",
+		"offset", simulated.Offset().String(),
+		"now", simulated.Now(),
+	)
+	return simulated
+}
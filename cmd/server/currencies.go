@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/currency"
+)
+
+// registerCurrencyScales adds or overrides minor units from CURRENCY_SCALES, e.g. "XAU:4,BTC:8".
+// ISO 4217 currencies are known already.
+func registerCurrencyScales(appLogger *slog.Logger) {
+	for _, pair := range strings.Split(os.Getenv("CURRENCY_SCALES"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		code, digits, _ := strings.Cut(strings.TrimSpace(pair), ":")
+		scale, err := strconv.Atoi(digits)
+		if len(code) != 3 || err != nil || scale < 0 || scale > 8 {
+			appLogger.Error("invalid CURRENCY_SCALES entry, skipping it", "entry", pair)
+			continue
+		}
+		currency.Register(code, int32(scale))
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/decorate"
+)
+
+// decorations reads DECORATORS, the middleware put around the store and the event broker
+// in the order listed: any of logging, metrics and retry, or none. Unknown names are logged and skipped.
+func decorations(appLogger *slog.Logger) []decorate.Option {
+	if os.Getenv("DECORATORS") == "none" {
+		return nil
+	}
+	var opts []decorate.Option
+	for name := range strings.SplitSeq(envString("DECORATORS", "logging,metrics"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "logging":
+			opts = append(opts, decorate.WithLogging(appLogger, envDuration("DECORATOR_SLOW_CALL", 500*time.Millisecond)))
+		case "metrics":
+			opts = append(opts, decorate.WithMetrics())
+		case "retry":
+			attempts, err := strconv.Atoi(envString("DECORATOR_RETRY_ATTEMPTS", "3"))
+			if err != nil {
+				appLogger.Error("invalid DECORATOR_RETRY_ATTEMPTS, using 3", "error", err)
+				attempts = 3
+			}
+			opts = append(opts, decorate.WithRetry(decorate.RetryPolicy{
+				Attempts:  attempts,
+				BaseDelay: envDuration("DECORATOR_RETRY_BASE_DELAY", 50*time.Millisecond),
+				MaxDelay:  envDuration("DECORATOR_RETRY_MAX_DELAY", time.Second),
+			}))
+		default:
+			appLogger.Error("unknown decorator in DECORATORS, skipping it", "name", name, "known", "logging,metrics,retry")
+		}
+	}
+	return opts
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/bus"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// newEventBus builds the sinks listed in EVENT_SINKS (default "kafka"): kafka is the
+// broker behind its breaker, webhook POSTs to every URL in EVENT_WEBHOOK_URLS from a
+// background queue, audit records events in the audit log and log writes them to the
+// application log. An unknown sink is logged and skipped. The returned func drains the
+// webhook queues on shutdown.
+func newEventBus(broker interfaces.EventPublisher, auditLog *audit.Log, appLogger *slog.Logger) (*bus.Bus, func()) {
+	var sinks []bus.Sink
+	var queues []*bus.Async
+	for name := range strings.SplitSeq(envString("EVENT_SINKS", "kafka"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "kafka":
+			sinks = append(sinks, bus.Sink{Name: name, Publisher: broker})
+		case "audit":
+			sinks = append(sinks, bus.Sink{Name: name, Publisher: bus.AuditSink{Log: auditLog}})
+		case "log":
+			sinks = append(sinks, bus.Sink{Name: name, Publisher: bus.LogSink{Logger: appLogger}})
+		case "webhook":
+			buffer := webhookBuffer(appLogger)
+			for url := range strings.SplitSeq(os.Getenv("EVENT_WEBHOOK_URLS"), ",") {
+				if url = strings.TrimSpace(url); url == "" {
+					continue
+				}
+				webhook := bus.NewWebhook(url, os.Getenv("EVENT_WEBHOOK_SECRET"), envDuration("EVENT_WEBHOOK_TIMEOUT", 5*time.Second))
+				queue := bus.NewAsync("webhook:"+url, webhook, buffer, appLogger)
+				queues = append(queues, queue)
+				sinks = append(sinks, bus.Sink{Name: "webhook:" + url, Publisher: queue})
+			}
+		default:
+			appLogger.Error("unknown event sink in EVENT_SINKS, skipping it", "sink", name)
+		}
+	}
+
+	eventBus := bus.New(appLogger, sinks...)
+	appLogger.Info("event bus configured", "sinks", eventBus.Sinks())
+	return eventBus, func() {
+		for _, queue := range queues {
+			queue.Close()
+		}
+	}
+}
+
+// webhookBuffer reads EVENT_WEBHOOK_BUFFER, how many events each webhook queue holds
+func webhookBuffer(appLogger *slog.Logger) int {
+	buffer, err := strconv.Atoi(envString("EVENT_WEBHOOK_BUFFER", "1000"))
+	if err != nil || buffer <= 0 {
+		appLogger.Error("invalid EVENT_WEBHOOK_BUFFER, using the default", "value", os.Getenv("EVENT_WEBHOOK_BUFFER"), "default", 1000)
+		return 1000
+	}
+	return buffer
+}
+
+// newAlertWebhooks sends crossed balance alerts to the URL registered with each, signed
+// and timed out like the event webhooks
+func newAlertWebhooks(appLogger *slog.Logger) *bus.Webhooks {
+	return bus.NewWebhooks(os.Getenv("EVENT_WEBHOOK_SECRET"), envDuration("EVENT_WEBHOOK_TIMEOUT", 5*time.Second),
+		webhookBuffer(appLogger), appLogger)
+}
+
+// newEventFilter puts the stored event filter in front of the bus and keeps reloading it,
+// every EVENT_FILTER_REFRESH, so changes made through another replica take effect here
+func newEventFilter(next interfaces.EventPublisher, store interfaces.EventFilterStore, appLogger *slog.Logger) *bus.Filter {
+	filter := bus.NewFilter(next, store, appLogger)
+	if err := filter.Load(context.Background()); err != nil {
+		appLogger.Error("failed to load the event filter, publishing every event", "error", err)
+	}
+	go filter.Run(context.Background(), envDuration("EVENT_FILTER_REFRESH", 30*time.Second))
+	return filter
+}
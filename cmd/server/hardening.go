@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/api"
+)
+
+// streamingRoutes hold the connection open for as long as the client listens, so they get
+// neither a handler deadline nor a write deadline
+var streamingRoutes = map[string]bool{
+	"GET /stream/entries": true,
+	"GET /ws":             true,
+}
+
+// ownBodyLimitRoutes take files and cap their bodies themselves
+var ownBodyLimitRoutes = map[string]bool{
+	"POST /imports/pain001": true,
+	"POST /reconciliations": true,
+}
+
+// hardening bounds what a single request may cost the server
+type hardening struct {
+	handlerTimeout time.Duration            // context deadline of a request; zero means none
+	routeTimeouts  map[string]time.Duration // per route pattern, overriding handlerTimeout
+	maxBodyBytes   int64
+}
+
+// newHTTPServer applies the connection timeouts. The write timeout must outlast the longest
+// handler deadline, or a slow handler's 504 is cut off before it reaches the client.
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: envDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       envDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      envDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       envDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    64 << 10,
+	}
+}
+
+// hardeningFromEnv reads HTTP_HANDLER_TIMEOUT, HTTP_ROUTE_TIMEOUTS and HTTP_MAX_BODY_BYTES.
+// HTTP_ROUTE_TIMEOUTS lists route patterns as registered, e.g.
+// "GET /accounts/{id}/statement=60s,POST /reconciliations=2m"; a bad item is logged and skipped.
+func hardeningFromEnv(appLogger *slog.Logger) hardening {
+	h := hardening{
+		handlerTimeout: envDuration("HTTP_HANDLER_TIMEOUT", 15*time.Second),
+		routeTimeouts:  map[string]time.Duration{},
+		maxBodyBytes:   1 << 20,
+	}
+	if value := os.Getenv("HTTP_MAX_BODY_BYTES"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			appLogger.Error("invalid HTTP_MAX_BODY_BYTES, using the default", "value", value, "default", h.maxBodyBytes)
+		} else {
+			h.maxBodyBytes = n
+		}
+	}
+	for item := range strings.SplitSeq(os.Getenv("HTTP_ROUTE_TIMEOUTS"), ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(item, "=")
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || timeout < 0 {
+			appLogger.Error("invalid HTTP_ROUTE_TIMEOUTS item, skipping it", "item", item)
+			continue
+		}
+		h.routeTimeouts[strings.TrimSpace(pattern)] = timeout
+	}
+	return h
+}
+
+func (h hardening) timeout(pattern string) time.Duration {
+	if timeout, ok := h.routeTimeouts[pattern]; ok {
+		return timeout
+	}
+	return h.handlerTimeout
+}
+
+// Middleware gives each request a deadline and caps its body. It must wrap everything else
+// so the deadline covers the other middleware too. Streaming routes and streamed exports are
+// left unbounded, with the server's write deadline lifted.
+func (h hardening) Middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+
+		if !ownBodyLimitRoutes[pattern] && r.Body != nil {
+			if r.ContentLength > h.maxBodyBytes {
+				writeJSON(w, http.StatusRequestEntityTooLarge, map[string]any{"error": "request body too large", "max_bytes": h.maxBodyBytes})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+		}
+
+		if streamingRoutes[pattern] || api.ExportFormat(r.Header.Get("Accept")) != "" {
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+			next.ServeHTTP(w, r)
+			return
+		}
+		timeout := h.timeout(pattern)
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(&deadlineWriter{ResponseWriter: w, ctx: ctx, timeout: timeout}, r.WithContext(ctx))
+	})
+}
+
+// deadlineWriter turns the server error a handler writes after its deadline has passed -
+// typically a query cancelled mid-flight - into a 504, so clients can tell a timeout,
+// which may succeed on retry, from a failure
+type deadlineWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	timeout  time.Duration
+	timedOut bool
+}
+
+func (d *deadlineWriter) WriteHeader(status int) {
+	if status >= http.StatusInternalServerError && errors.Is(d.ctx.Err(), context.DeadlineExceeded) {
+		d.timedOut = true
+		writeJSON(d.ResponseWriter, http.StatusGatewayTimeout, map[string]any{"error": "request timed out", "timeout": d.timeout.String()})
+		return
+	}
+	d.ResponseWriter.WriteHeader(status)
+}
+
+// Write drops the handler's own error body once the 504 has been written
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	if d.timedOut {
+		return len(p), nil
+	}
+	return d.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (d *deadlineWriter) Unwrap() http.ResponseWriter {
+	return d.ResponseWriter
+}
+
+// writeJSON answers the requests the middleware turns away before they reach the router
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
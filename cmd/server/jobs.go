@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/eod"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/breaker"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
+	kafka "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/kafka"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interest"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/netting"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/scheduler"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
+)
+
+// envDuration reads a duration such as "5m" from the environment, falling back to def
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// envString reads a string from the environment, falling back to def
+func envString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// envSchedule reads a job schedule from the environment: either a plain duration such as
+// "5m" (run at that interval) or a cron expression such as "0 2 * * *"
+func envSchedule(key, def string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = def
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return "@every " + value
+	}
+	return value
+}
+
+// registerJob adds a job to the scheduler, logging instead of failing startup on a bad schedule
+func registerJob(sched *scheduler.Scheduler, appLogger *slog.Logger, name, spec string, run func(ctx context.Context) error) {
+	jitter := envDuration("SCHEDULER_JITTER", 5*time.Second)
+	if err := sched.Register(name, spec, jitter, run); err != nil {
+		appLogger.Error("job not scheduled", "job", name, "error", err)
+	}
+}
+
+// registerSnapshotJob periodically checkpoints balances and, when ENTRY_RETENTION is set,
+// compacts entries older than the retention period into the archive table.
+func registerSnapshotJob(sched *scheduler.Scheduler, ledgerService *ledger.Ledger, appLogger *slog.Logger) {
+	retention := envDuration("ENTRY_RETENTION", 0)
+
+	registerJob(sched, appLogger, "snapshots", envSchedule("SNAPSHOT_INTERVAL", "5m"), func(ctx context.Context) error {
+		if _, err := ledgerService.TakeSnapshots(ctx); err != nil {
+			return err
+		}
+		if retention <= 0 {
+			return nil
+		}
+		_, err := ledgerService.CompactEntries(ctx, retention)
+		return err
+	})
+}
+
+// registerColdStorageJob moves archived entries older than COLD_STORAGE_AFTER into
+// compressed cold storage; it is disabled while COLD_STORAGE_AFTER is unset
+func registerColdStorageJob(sched *scheduler.Scheduler, ledgerService *ledger.Ledger, appLogger *slog.Logger) {
+	after := envDuration("COLD_STORAGE_AFTER", 0)
+	if after <= 0 {
+		return
+	}
+
+	registerJob(sched, appLogger, "cold-storage", envSchedule("COLD_STORAGE_INTERVAL", "24h"), func(ctx context.Context) error {
+		_, err := ledgerService.FreezeEntries(ctx, after)
+		return err
+	})
+}
+
+// registerInvariantJob periodically verifies that the ledger still balances
+func registerInvariantJob(sched *scheduler.Scheduler, reportService *reports.Service, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "invariants", envSchedule("INVARIANT_CHECK_INTERVAL", "10m"), func(ctx context.Context) error {
+		_, err := reportService.VerifyInvariants(ctx)
+		return err
+	})
+}
+
+// registerStreamCheckJob replays the transaction events of the last STREAM_CHECK_WINDOW
+// and checks them against the database. The window ends STREAM_CHECK_SETTLE ago, so events
+// still on their way are not reported missing. It is disabled while STREAM_CHECK_INTERVAL
+// is unset: each run reads the whole window back from the broker.
+func registerStreamCheckJob(sched *scheduler.Scheduler, checker *reconciliation.StreamChecker, appLogger *slog.Logger) {
+	if os.Getenv("STREAM_CHECK_INTERVAL") == "" {
+		return
+	}
+	window := envDuration("STREAM_CHECK_WINDOW", 24*time.Hour)
+	settle := envDuration("STREAM_CHECK_SETTLE", 5*time.Minute)
+
+	registerJob(sched, appLogger, "stream-check", envSchedule("STREAM_CHECK_INTERVAL", "24h"), func(ctx context.Context) error {
+		to := time.Now().UTC().Add(-settle)
+		_, err := checker.Check(ctx, to.Add(-window), to)
+		return err
+	})
+}
+
+// registerBalanceCheckJob compares materialized balances with their entries.
+// BALANCE_CHECK_SAMPLE accounts are picked at random each run; 0 checks them all.
+func registerBalanceCheckJob(sched *scheduler.Scheduler, checker *reports.BalanceChecker, appLogger *slog.Logger) {
+	size, err := strconv.Atoi(envString("BALANCE_CHECK_SAMPLE", "1000"))
+	if err != nil || size < 0 {
+		appLogger.Error("invalid BALANCE_CHECK_SAMPLE, using the default", "value", os.Getenv("BALANCE_CHECK_SAMPLE"), "default", 1000)
+		size = 1000
+	}
+	registerJob(sched, appLogger, "balance-check", envSchedule("BALANCE_CHECK_INTERVAL", "15m"), func(ctx context.Context) error {
+		_, err := checker.Check(ctx, size)
+		return err
+	})
+}
+
+// registerTwoPhaseRecoveryJob finishes cross-instance transfers left undecided or unacknowledged
+// for longer than TWO_PC_DECISION_TIMEOUT, e.g. by a coordinator that crashed mid-way
+func registerTwoPhaseRecoveryJob(sched *scheduler.Scheduler, coordinator *twophase.Coordinator, appLogger *slog.Logger) {
+	timeout := envDuration("TWO_PC_DECISION_TIMEOUT", time.Minute)
+	registerJob(sched, appLogger, "2pc-recovery", envSchedule("TWO_PC_RECOVERY_INTERVAL", "1m"), func(ctx context.Context) error {
+		n, err := coordinator.Recover(ctx, timeout)
+		if n > 0 {
+			appLogger.Info("finished cross-instance transfers", "transfers", n)
+		}
+		return err
+	})
+}
+
+// registerInterestJob accrues daily interest; it runs more often than daily so a missed
+// run or a restart only delays the accrual, and each day is posted exactly once.
+func registerInterestJob(sched *scheduler.Scheduler, interestService *interest.Service, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "interest", envSchedule("INTEREST_ACCRUAL_INTERVAL", "1h"), func(ctx context.Context) error {
+		_, err := interestService.AccrueDue(ctx)
+		return err
+	})
+}
+
+// registerScheduleJobs execute standing orders and future-dated transactions as they fall due,
+// catching up after downtime
+func registerScheduleJobs(sched *scheduler.Scheduler, scheduleService *schedules.Service, clk clock.Clock, appLogger *slog.Logger) {
+	spec := envSchedule("SCHEDULE_POLL_INTERVAL", "1m")
+
+	registerJob(sched, appLogger, "standing-orders", spec, func(ctx context.Context) error {
+		_, err := scheduleService.RunDue(ctx, clk.Now().UTC())
+		return err
+	})
+	registerJob(sched, appLogger, "future-dated-transactions", spec, func(ctx context.Context) error {
+		_, err := scheduleService.RunDuePayments(ctx, clk.Now().UTC())
+		return err
+	})
+}
+
+// registerNettingJob settles each counterparty pair once its settlement window has closed
+func registerNettingJob(sched *scheduler.Scheduler, nettingService *netting.Service, clk clock.Clock, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "netting", envSchedule("NETTING_POLL_INTERVAL", "1m"), func(ctx context.Context) error {
+		_, err := nettingService.CloseDue(ctx, clk.Now().UTC())
+		return err
+	})
+}
+
+// registerEODJob closes the business day shortly after the cutoff; the job itself only polls,
+// the cutoff decides which day is due
+func registerEODJob(sched *scheduler.Scheduler, eodService *eod.Service, clk clock.Clock, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "end-of-day", envSchedule("EOD_POLL_INTERVAL", "1m"), func(ctx context.Context) error {
+		closed, err := eodService.CloseDue(ctx, clk.Now())
+		if err != nil {
+			return fmt.Errorf("closed %d days before failing: %w", len(closed), err)
+		}
+		return nil
+	})
+}
+
+// registerDeadLetterJob sends parked events to the broker once the breaker has closed again.
+// It bypasses the breaker so a failing letter stays where it is instead of being parked twice.
+func registerDeadLetterJob(sched *scheduler.Scheduler, deadLetters *deadletter.Queue, publisher *breaker.Publisher, broker interfaces.EventPublisher, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "dead-letter-redrive", envSchedule("DEAD_LETTER_REDRIVE_INTERVAL", "1m"), func(ctx context.Context) error {
+		// Redrive refreshes the backlog and lag gauges; while the circuit is open they are
+		// refreshed here, so the lag keeps growing on the dashboards
+		if publisher.State() != breaker.Closed {
+			if _, err := deadLetters.Stats(ctx); err != nil {
+				return fmt.Errorf("sizing the dead-letter backlog: %w", err)
+			}
+			return nil
+		}
+		sent, err := deadLetters.Redrive(ctx, broker)
+		if err != nil {
+			return fmt.Errorf("redrove %d events before failing: %w", sent, err)
+		}
+		return nil
+	})
+}
+
+// bufferedOptions reads the buffered publisher settings; an unknown policy falls back to block
+func bufferedOptions(appLogger *slog.Logger) kafka.BufferedOptions {
+	options := kafka.BufferedOptions{
+		BufferSize:    10000,
+		BatchSize:     100,
+		FlushInterval: envDuration("EVENT_FLUSH_INTERVAL", 100*time.Millisecond),
+		Policy:        envString("EVENT_BUFFER_POLICY", kafka.PolicyBlock),
+	}
+	if size, err := strconv.Atoi(os.Getenv("EVENT_BUFFER_SIZE")); err == nil {
+		options.BufferSize = size
+	}
+	if size, err := strconv.Atoi(os.Getenv("EVENT_BATCH_SIZE")); err == nil {
+		options.BatchSize = size
+	}
+	if options.Policy != kafka.PolicyBlock && options.Policy != kafka.PolicyDrop {
+		appLogger.Error("invalid EVENT_BUFFER_POLICY, blocking when full", "policy", options.Policy)
+		options.Policy = kafka.PolicyBlock
+	}
+	return options
+}
+
+// poolConfig reads the database connection pool settings. Idle connections default to
+// the open limit so the pool does not close and reopen connections on every burst.
+func poolConfig() postgres.PoolConfig {
+	config := postgres.PoolConfig{
+		MaxOpenConns:    25,
+		ConnMaxLifetime: envDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: envDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+	}
+	if n, err := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS")); err == nil {
+		config.MaxOpenConns = n
+	}
+	config.MaxIdleConns = config.MaxOpenConns
+	if n, err := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS")); err == nil {
+		config.MaxIdleConns = n
+	}
+	return config
+}
+
+// registerPartitionJob keeps PARTITION_MONTHS_AHEAD monthly ledger_entries partitions ready,
+// so postings never fall into the default partition
+func registerPartitionJob(sched *scheduler.Scheduler, partitions interfaces.PartitionStore, clk clock.Clock, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "entry-partitions", envSchedule("PARTITION_MAINTENANCE_INTERVAL", "24h"), func(ctx context.Context) error {
+		return ensurePartitions(ctx, partitions, clk, appLogger)
+	})
+}
+
+// registerPIIReencryptJob moves account holder details sealed with a retired key onto
+// the current one, so the old key can be dropped from PII_ENCRYPTION_KEYS
+func registerPIIReencryptJob(sched *scheduler.Scheduler, store *postgres.PostgresLedgerStore, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "pii-reencrypt", envSchedule("PII_REENCRYPT_INTERVAL", "1h"), func(ctx context.Context) error {
+		n, err := store.ReencryptAccounts(ctx)
+		if n > 0 {
+			appLogger.Info("re-encrypted account holder details", "accounts", n)
+		}
+		return err
+	})
+}
+
+func ensurePartitions(ctx context.Context, partitions interfaces.PartitionStore, clk clock.Clock, appLogger *slog.Logger) error {
+	monthsAhead, err := strconv.Atoi(envString("PARTITION_MONTHS_AHEAD", "3"))
+	if err != nil {
+		monthsAhead = 3
+	}
+	created, err := partitions.EnsureEntryPartitions(ctx, clk.Now().UTC(), monthsAhead)
+	for _, name := range created {
+		appLogger.Info("created ledger entry partition", "partition", name)
+	}
+	return err
+}
+
+// checkAppendOnly verifies at startup that the ledger tables still reject updates and
+// deletes. APPEND_ONLY_CHECK is warn (the default), enforce, which refuses to start, or off.
+func checkAppendOnly(ctx context.Context, store interfaces.AppendOnlyStore, appLogger *slog.Logger) bool {
+	mode := envString("APPEND_ONLY_CHECK", "warn")
+	if mode == "off" {
+		return true
+	}
+	tables, err := store.UnprotectedTables(ctx)
+	if err != nil {
+		appLogger.Error("failed to verify that the ledger tables are append-only", "error", err)
+		return mode != "enforce"
+	}
+	if len(tables) == 0 {
+		return true
+	}
+	appLogger.Error("ALERT: ledger tables accept updates and deletes; apply the append-only triggers in schema.sql",
+		"tables", tables,
+	)
+	return mode != "enforce"
+}
@@ -3,30 +3,55 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/api"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/chaos"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/decorate"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/eod"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/breaker"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
 	kafka "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/kafka"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/registry"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/fx"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ids"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interest"
 	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/iso20022"
 
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
-	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/pii"
 
 	// "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/logger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/netting"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/scheduler"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/slo"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/statements"
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
-	"github.com/shopspring/decimal"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/stream"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
 )
 
 func main() {
-	publisher := kafka.NewPublisher([]string{"localhost:9092"})
+	brokers := []string{"localhost:9092"}
+	kafkaPublisher := kafka.NewPublisher(brokers)
 	appLogger := logger.New()
 	// var store interfaces.LedgerStore = memory.NewMemoryLedgerStore()
 	// ledgerService := ledger.NewLedger(store)
@@ -36,130 +61,253 @@ func main() {
 		appLogger.Error("No .env file found.")
 	}
 
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
+	kafkaPublisher.SetTimeout(envDuration("EVENT_PUBLISH_TIMEOUT", 2*time.Second))
+	if err := kafkaPublisher.SetFormat(envString("EVENT_FORMAT", kafka.FormatJSON)); err != nil {
+		appLogger.Error("invalid event format, publishing json", "error", err)
+	}
+	// Refuse to start rather than publish events that break downstream consumers
+	if registryUrl := os.Getenv("SCHEMA_REGISTRY_URL"); registryUrl != "" {
+		if err := kafkaPublisher.UseSchemaRegistry(context.Background(), registry.NewClient(registryUrl)); err != nil {
+			appLogger.Error("failed to register event schemas", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", postgres.ConnStringFromEnv())
 	if err != nil {
 		appLogger.Error("failed to open database connection", "error", err)
 	}
 
+	postgres.ConfigurePool(db, poolConfig())
+	postgres.RegisterPoolMetrics(db)
+
 	// Ping to check connection
 	if err := db.Ping(); err != nil {
 		appLogger.Error("database ping failed", "error", err)
 	}
 	// Inject DB into PostgresLedgerStore
-	var store interfaces.LedgerStore = postgres.NewPostgresLedgerStore(db)
+	pgStore := postgres.NewPostgresLedgerStore(db)
+	if err := pgStore.SetConcurrencyMode(envString("BALANCE_CONCURRENCY", postgres.Pessimistic)); err != nil {
+		appLogger.Error("invalid BALANCE_CONCURRENCY, locking balance rows", "error", err)
+	}
+	if attempts, err := strconv.Atoi(os.Getenv("DB_RETRY_ATTEMPTS")); err == nil {
+		pgStore.SetRetryPolicy(postgres.RetryPolicy{
+			Attempts:  attempts,
+			BaseDelay: envDuration("DB_RETRY_BASE_DELAY", 50*time.Millisecond),
+			MaxDelay:  envDuration("DB_RETRY_MAX_DELAY", time.Second),
+		})
+	}
+	piiKeys, err := pii.KeyringFromEnv()
+	if err != nil {
+		appLogger.Error("invalid PII_ENCRYPTION_KEYS, refusing to store account holder details", "error", err)
+	}
+	pgStore.SetPIIKeyring(piiKeys)
+	decorators := decorations(appLogger)
+	store := decorate.Store(pgStore, decorators...)
 
-	// Create Ledger service with Postgres store
-	ledgerService := ledger.NewLedger(store, appLogger, publisher)
-
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	})
-
-	// 3️⃣ Transactions endpoint (NEW)
-	http.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
+	// Dev-only fault injection; everything below sees the faults like real outages
+	faults := chaosConfig(appLogger)
+	pgStore.SetCommitFailureRate(faults.CommitFailureRate)
 
-		idempotencyKey := r.Header.Get("Idempotency-Key")
+	// Events the broker does not take within the timeout, or while the breaker is open,
+	// are parked in the database and redriven by a background job
+	deadLetters := deadletter.NewQueue(pgStore)
+	breakerThreshold, err := strconv.Atoi(envString("EVENT_BREAKER_THRESHOLD", "5"))
+	if err != nil {
+		appLogger.Error("invalid EVENT_BREAKER_THRESHOLD, using 5", "error", err)
+		breakerThreshold = 5
+	}
 
-		var req struct {
-			FromAccount string          `json:"from_account"`
-			ToAccount   string          `json:"to_account"`
-			Amount      decimal.Decimal `json:"amount"`
-		}
+	// Buffered mode takes broker round trips off the request path; events are written in batches
+	var broker interfaces.EventPublisher = kafkaPublisher
+	var bufferedPublisher *kafka.BufferedPublisher
+	if envString("EVENT_PUBLISH_MODE", "sync") == "buffered" {
+		bufferedPublisher = kafka.NewBufferedPublisher(kafkaPublisher, deadLetters, bufferedOptions(appLogger), appLogger)
+		broker = bufferedPublisher
+	}
+	if faults.PublishFailureRate > 0 {
+		broker = chaos.NewPublisher(broker, faults.PublishFailureRate)
+	}
+	broker = decorate.Publisher(broker, decorators...)
+	publisher := breaker.NewPublisher(broker, deadLetters, breakerThreshold,
+		envDuration("EVENT_BREAKER_COOLDOWN", 30*time.Second), appLogger)
 
-		// Parse JSON body
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
-			return
-		}
+	// Every event goes out through the bus, which fans it out to the configured sinks
+	auditLog := audit.NewLog(pgStore, appLogger)
+	eventBus, closeEventBus := newEventBus(publisher, auditLog, appLogger)
+	eventFilter := newEventFilter(eventBus, pgStore, appLogger)
 
-		// Create domain transaction
-		tx := models.Transaction{
-			ID:             uuid.New().String(),
-			IdempotencyKey: idempotencyKey,
-			FromAccount:    req.FromAccount,
-			ToAccount:      req.ToAccount,
-			Amount:         req.Amount,
-			CreatedAt:      time.Now(),
-		}
+	registerCurrencyScales(appLogger)
 
-		// Call domain logic
-		exists, err := ledgerService.PostTransaction(context.Background(), tx)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		if exists {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"status":"already processed"}`))
-			return
-		}
+	// Create Ledger service with Postgres store
+	ledgerService := ledger.NewLedger(store, appLogger, eventFilter)
+	// Time-ordered IDs keep inserts at the end of the primary key indexes
+	if generator, err := ids.FromEnv(ledgerService); err != nil {
+		appLogger.Error("invalid ID_STRATEGY, using uuid", "error", err)
+	} else {
+		ledgerService.SetIDGenerator(generator)
+	}
+	// Dev-only simulated time; everything that stamps or falls due reads the ledger's clock
+	simulated := simulatedClock(appLogger)
+	if simulated != nil {
+		ledgerService.SetClock(simulated)
+	}
+	// Built before the ledger, so they follow its clock instead of taking it
+	pgStore.SetClock(ledgerService)
+	deadLetters.SetClock(ledgerService)
+	auditLog.SetClock(ledgerService)
+	alertWebhooks := newAlertWebhooks(appLogger)
+	ledgerService.SetWebhookSender(alertWebhooks)
 
-		w.WriteHeader(http.StatusCreated)
-		w.Write([]byte(`{"status":"Created Transaction"}`))
-	})
+	// The current month's partition must exist before the first posting
+	if err := ensurePartitions(context.Background(), pgStore, ledgerService, appLogger); err != nil {
+		appLogger.Error("failed to create ledger entry partitions", "error", err)
+	}
+	// No code path or operator may rewrite history, so the guards must be in place
+	if !checkAppendOnly(context.Background(), pgStore, appLogger) {
+		os.Exit(1)
+	}
 
-	http.HandleFunc("/accounts/balance", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
+	// Fee, suspense and settlement accounts get their place in the chart of accounts
+	if err := ledgerService.EnsureSystemAccounts(context.Background()); err != nil {
+		appLogger.Error("failed to set up system accounts", "error", err)
+	}
+	var systemAccounts []string
+	for _, account := range ledgerService.SystemAccounts() {
+		systemAccounts = append(systemAccounts, account.AccountID)
+	}
+	eventFilter.SetSystemAccounts(systemAccounts)
 
-		accountId := r.URL.Query().Get("account_id")
-		if accountId == "" {
-			http.Error(w, "account_id is a mandatory field", http.StatusBadRequest)
-			return
-		}
+	// Live updates for SSE and WebSocket subscribers, fed after every committed posting
+	hub := stream.NewHub()
+	ledgerService.AddEntryListener(hub)
 
-		balance, err := ledgerService.GetBalance(accountId)
+	// Exchange rates for cross-currency transfers whose caller does not fix the rate
+	if spec := os.Getenv("FX_RATES"); spec != "" {
+		rates, err := fx.ParseStaticRates(spec)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			appLogger.Error("ignoring invalid FX_RATES", "error", err)
+		} else {
+			ledgerService.SetRateProvider(rates)
 		}
+	}
 
-		response := struct {
-			AccountID string          `json:"account_id"`
-			Balance   decimal.Decimal `json:"balance"`
-		}{
-			AccountID: accountId,
-			Balance:   balance,
-		}
+	reportService := reports.NewService(pgStore, ledgerService, appLogger)
+	balanceChecker := reports.NewBalanceChecker(pgStore, ledgerService, appLogger)
+
+	// Daily aggregates are folded in right after each posting so reports never scan raw entries
+	dailyProjection := reports.NewDailyProjection(pgStore, ledgerService, appLogger)
+	ledgerService.AddEntryListener(dailyProjection)
+	go dailyProjection.Run(context.Background(), envDuration("DAILY_PROJECTION_INTERVAL", 30*time.Second))
+	reconciliationService := reconciliation.NewService(store, ledgerService, appLogger)
+	statementService := statements.NewService(ledgerService, pgStore)
+	interestService := interest.NewService(ledgerService, pgStore, appLogger)
+	scheduleService := schedules.NewService(ledgerService, pgStore, pgStore, eventFilter, appLogger)
+	cutoff, err := eod.ParseCutoff(envString("EOD_CUTOFF", "00:00"), envString("EOD_TIMEZONE", "UTC"))
+	if err != nil {
+		appLogger.Error("invalid end-of-day cutoff, using midnight UTC", "error", err)
+		cutoff, _ = eod.ParseCutoff("00:00", "UTC")
+	}
+	eodService := eod.NewService(ledgerService, pgStore, eventFilter, cutoff, appLogger)
+	importer := iso20022.NewImporter(ledgerService, scheduleService, appLogger)
+	nettingService := netting.NewService(ledgerService, pgStore, envDuration("SETTLEMENT_WINDOW", time.Hour), appLogger)
+	analyticsExporter := newAnalyticsExporter(pgStore, appLogger)
+	participant := twophase.NewParticipant(ledgerService, pgStore)
+	coordinator := newCoordinator(participant, pgStore, ledgerService, appLogger)
+	checkpointer := newCheckpointer(pgStore, ledgerService, appLogger)
+	sloTracker := newSLOTracker(appLogger)
+	meter := newMeter(pgStore, ledgerService, appLogger)
+	businessDays := newCalendar(pgStore, ledgerService, appLogger)
+	go sloTracker.Run(context.Background(), envDuration("SLO_REFRESH_INTERVAL", time.Minute))
+
+	// Background jobs, run only by the replica holding the scheduler lease
+	sched := scheduler.New(pgStore, envDuration("SCHEDULER_LEASE_TTL", 30*time.Second), appLogger)
+	registerSnapshotJob(sched, ledgerService, appLogger)
+	registerColdStorageJob(sched, ledgerService, appLogger)
+	registerInvariantJob(sched, reportService, appLogger)
+	registerBalanceCheckJob(sched, balanceChecker, appLogger)
+	registerStreamCheckJob(sched, reconciliation.NewStreamChecker(pgStore, brokers, appLogger), appLogger)
+	registerInterestJob(sched, interestService, appLogger)
+	registerScheduleJobs(sched, scheduleService, ledgerService, appLogger)
+	registerNettingJob(sched, nettingService, ledgerService, appLogger)
+	registerEODJob(sched, eodService, ledgerService, appLogger)
+	registerPartitionJob(sched, pgStore, ledgerService, appLogger)
+	registerPIIReencryptJob(sched, pgStore, appLogger)
+	registerTwoPhaseRecoveryJob(sched, coordinator, appLogger)
+	registerDeadLetterJob(sched, deadLetters, publisher, kafkaPublisher, appLogger)
+	if analyticsExporter != nil {
+		registerAnalyticsExportJob(sched, analyticsExporter, ledgerService, appLogger)
+	}
+	if checkpointer != nil {
+		registerCheckpointJob(sched, checkpointer, appLogger)
+	}
+	sched.Start(context.Background())
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+	// Unset or invalid leaves the router's default
+	wsMaxSubscriptions, _ := strconv.Atoi(os.Getenv("WS_MAX_SUBSCRIPTIONS"))
+	mux := api.NewRouter(ledgerService,
+		api.WithLogger(appLogger),
+		api.WithReports(reportService, dailyProjection, balanceChecker),
+		api.WithReconciliation(reconciliationService),
+		api.WithAudit(auditLog),
+		api.WithStatements(statementService),
+		api.WithStream(hub, os.Getenv("WS_AUTH_TOKEN"), wsMaxSubscriptions),
+		api.WithTwoPhase(participant, coordinator, os.Getenv("TWO_PC_TOKEN")),
+		api.WithInterest(interestService),
+		api.WithSchedules(scheduleService),
+		api.WithNetting(nettingService),
+		api.WithEOD(eodService),
+		api.WithImporter(importer),
+		api.WithDeadLetters(deadLetters, publisher, kafkaPublisher),
+		api.WithEventFilter(eventFilter),
+		api.WithSLO(sloTracker),
+		api.WithCalendar(businessDays),
+		api.WithAdminToken(os.Getenv("ADMIN_TOKEN")),
+		api.WithAdminUI(),
+		api.WithUsage(meter),
+		api.WithAnalytics(analyticsExporter),
+		api.WithProofs(checkpointer),
+		api.WithSimulatedClock(simulated),
+	)
+	mux.Handle("/metrics", metrics.Handler())
 
-	})
+	log.Println("Starting server on :8080")
+	handler := api.TenantAccountGuard(ledgerService, auditLog.Middleware(mux))
+	// Only the admin and peer instances may act without a tenant, unless a single-tenant
+	// deployment turns the requirement off
+	platform := api.PlatformAccess(os.Getenv("ADMIN_TOKEN"), os.Getenv("TWO_PC_TOKEN"))
+	if os.Getenv("TENANT_REQUIRED") == "false" {
+		platform = tenant.AnyRequest
+	}
+	handler = chaos.Middleware(faults, tenant.Middleware(livemode.Middleware(meter.Middleware(handler)), platform))
+	handler = hardeningFromEnv(appLogger).Middleware(mux, handler)
+	handler = slo.Middleware(sloTracker, handler)
+	server := newHTTPServer(":8080", handler)
 
-	http.HandleFunc("/ledgerEntries", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
+	// On SIGINT/SIGTERM stop taking requests and let the ones in flight finish
+	go func() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
 
-		ledgerEntries, err := ledgerService.GetLedgerEntries()
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			appLogger.Error("server shutdown failed", "error", err)
 		}
+	}()
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(ledgerEntries)
-
-	})
-	log.Println("Starting server on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	// Events still queued for webhooks or in the buffer are written before the process exits
+	closeEventBus()
+	alertWebhooks.Close()
+	if err := meter.Flush(context.Background()); err != nil {
+		appLogger.Error("failed to flush usage", "error", err)
+	}
+	if bufferedPublisher != nil {
+		bufferedPublisher.Close()
+	}
 
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/proofs"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/scheduler"
+)
+
+// newCheckpointer returns nil when CHECKPOINT_SIGNING_KEY is unset or invalid
+func newCheckpointer(store interfaces.CheckpointStore, clk clock.Clock, appLogger *slog.Logger) *proofs.Checkpointer {
+	key, err := proofs.KeyFromEnv()
+	if err != nil {
+		appLogger.Error("ledger checkpoints disabled", "error", err)
+		return nil
+	}
+	if key == nil {
+		return nil
+	}
+	maxEntries, err := strconv.Atoi(envString("CHECKPOINT_MAX_ENTRIES", "10000"))
+	if err != nil || maxEntries <= 0 {
+		appLogger.Error("invalid CHECKPOINT_MAX_ENTRIES, using the default", "value", os.Getenv("CHECKPOINT_MAX_ENTRIES"), "default", 10000)
+		maxEntries = 10000
+	}
+	return proofs.NewCheckpointer(store, key, maxEntries, clk, appLogger)
+}
+
+// registerCheckpointJob signs a Merkle root over the entries posted since the last checkpoint
+func registerCheckpointJob(sched *scheduler.Scheduler, checkpointer *proofs.Checkpointer, appLogger *slog.Logger) {
+	registerJob(sched, appLogger, "ledger-checkpoint", envSchedule("CHECKPOINT_INTERVAL", "1h"), func(ctx context.Context) error {
+		_, err := checkpointer.Run(ctx)
+		return err
+	})
+}
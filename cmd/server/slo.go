@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/slo"
+)
+
+// newSLOTracker reads SLO_SUCCESS_OBJECTIVE, SLO_LATENCY_OBJECTIVE and SLO_LATENCY_THRESHOLD;
+// an objective outside (0, 1) is logged and replaced by its default
+func newSLOTracker(appLogger *slog.Logger) *slo.Tracker {
+	objectives := slo.Objectives{
+		Success:          0.999,
+		Latency:          0.99,
+		LatencyThreshold: envDuration("SLO_LATENCY_THRESHOLD", 500*time.Millisecond),
+	}
+	for key, objective := range map[string]*float64{
+		"SLO_SUCCESS_OBJECTIVE": &objectives.Success,
+		"SLO_LATENCY_OBJECTIVE": &objectives.Latency,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed >= 1 {
+			appLogger.Error("invalid "+key+", must be between 0 and 1; using the default", "value", value, "default", *objective)
+			continue
+		}
+		*objective = parsed
+	}
+	return slo.NewTracker(objectives, appLogger)
+}
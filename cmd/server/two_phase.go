@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
+)
+
+// newCoordinator reads LEDGER_INSTANCE, the name peers know this instance by, and
+// LEDGER_PEERS ("name=url,..."), the instances it may transfer to. TWO_PC_TOKEN
+// authenticates the instances to each other.
+func newCoordinator(participant *twophase.Participant, store interfaces.TwoPhaseCoordinatorStore, clk clock.Clock, appLogger *slog.Logger) *twophase.Coordinator {
+	peers, err := twophase.ParsePeers(os.Getenv("LEDGER_PEERS"), os.Getenv("TWO_PC_TOKEN"))
+	if err != nil {
+		appLogger.Error("ignoring invalid LEDGER_PEERS", "error", err)
+		peers = map[string]twophase.Peer{}
+	}
+	return twophase.NewCoordinator(envString("LEDGER_INSTANCE", "local"), participant, peers, store, clk, appLogger)
+}
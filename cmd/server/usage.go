@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+)
+
+// newMeter reads USAGE_QUOTAS, the monthly limits per tenant, and flushes the counts every
+// USAGE_FLUSH_INTERVAL; invalid quotas are logged and none are enforced
+func newMeter(store interfaces.UsageStore, clk clock.Clock, appLogger *slog.Logger) *usage.Meter {
+	quotas, err := usage.ParseQuotas(os.Getenv("USAGE_QUOTAS"))
+	if err != nil {
+		appLogger.Error("ignoring invalid USAGE_QUOTAS", "error", err)
+		quotas = usage.Quotas{}
+	}
+	meter := usage.NewMeter(store, clk, quotas, appLogger)
+	go meter.Run(context.Background(), envDuration("USAGE_FLUSH_INTERVAL", 30*time.Second))
+	return meter
+}
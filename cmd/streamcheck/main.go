@@ -0,0 +1,78 @@
+// Command streamcheck replays the transactions.completed topic and cross-checks it with
+// the transactions table, reporting events without a row and rows without an event. It
+// prints the report as JSON and exits 1 when they disagree, so it can gate a pipeline.
+//
+//	streamcheck [-brokers localhost:9092] [-from 2024-06-01T00:00:00Z] [-to 2024-06-02T00:00:00Z]
+//
+// Without -from the topic is read from the beginning; without -to the range ends -settle
+// before now, leaving time for the events of the newest postings to be published.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/logger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
+)
+
+func main() {
+	brokers := flag.String("brokers", "localhost:9092", "comma-separated Kafka brokers")
+	from := flag.String("from", "", "start of the range, RFC 3339; the beginning of the topic when empty")
+	to := flag.String("to", "", "end of the range, RFC 3339; now minus -settle when empty")
+	settle := flag.Duration("settle", 5*time.Minute, "time given to the newest postings to publish their events")
+	flag.Parse()
+
+	appLogger := logger.New()
+	if err := godotenv.Load(); err != nil {
+		appLogger.Info("no .env file found, using the environment")
+	}
+	var start, end time.Time
+	var err error
+	if *from != "" {
+		if start, err = time.Parse(time.RFC3339, *from); err != nil {
+			appLogger.Error("-from must be an RFC 3339 time", "error", err)
+			os.Exit(2)
+		}
+	}
+	end = time.Now().UTC().Add(-*settle)
+	if *to != "" {
+		if end, err = time.Parse(time.RFC3339, *to); err != nil {
+			appLogger.Error("-to must be an RFC 3339 time", "error", err)
+			os.Exit(2)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := sql.Open("postgres", postgres.ConnStringFromEnv())
+	if err != nil {
+		appLogger.Error("failed to open database connection", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	checker := reconciliation.NewStreamChecker(postgres.NewPostgresLedgerStore(db), strings.Split(*brokers, ","), appLogger)
+	report, err := checker.Check(ctx, start, end)
+	if err != nil {
+		appLogger.Error("stream check failed", "error", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+	if !report.Consistent() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,22 @@
+// Package adminui holds the static assets of the read-only admin UI. The pages are compiled
+// into the binary, so a small deployment can look at its ledger without a dashboard stack;
+// the data comes from a single overview endpoint the server serves next to them.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the assets under prefix, which must end with a slash
+func Handler(prefix string) http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the directory is embedded above; this cannot fail at run time
+	}
+	return http.StripPrefix(prefix, http.FileServerFS(assets))
+}
@@ -0,0 +1,102 @@
+// Renders the overview the server assembles at api/overview. Values are inserted as text,
+// never as markup: references, descriptions and IDs come from API callers.
+"use strict";
+
+function cell(row, value, className) {
+  const td = row.insertCell();
+  td.textContent = value ?? "";
+  if (className) td.className = className;
+}
+
+function fill(id, items, columns, empty) {
+  const body = document.querySelector("#" + id + " tbody");
+  body.replaceChildren();
+  if (!items || items.length === 0) {
+    const td = body.insertRow().insertCell();
+    td.colSpan = columns;
+    td.className = "empty";
+    td.textContent = empty;
+    return null;
+  }
+  return body;
+}
+
+function time(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+function render(overview) {
+  document.getElementById("generated").textContent = "as of " + time(overview.generated_at);
+
+  const backlog = document.getElementById("backlog");
+  backlog.replaceChildren();
+  const letters = overview.dead_letters || {};
+  for (const [label, value, alert] of [
+    ["Parked events", letters.backlog ?? "-", letters.backlog > 0],
+    ["Failed for good", letters.failed ?? "-", letters.failed > 0],
+    ["Oldest parked", letters.oldest_at ? time(letters.oldest_at) : "-", false],
+  ]) {
+    const dt = document.createElement("dt");
+    dt.textContent = label;
+    const dd = document.createElement("dd");
+    dd.textContent = value;
+    if (alert) dd.className = "alert";
+    backlog.append(dt, dd);
+  }
+
+  let body = fill("discrepancies", overview.discrepancies, 6, "None open");
+  for (const d of overview.discrepancies || []) {
+    const row = body.insertRow();
+    cell(row, d.account_id);
+    cell(row, d.materialized, "amount");
+    cell(row, d.recomputed, "amount");
+    cell(row, d.difference, "amount alert");
+    cell(row, d.occurrences);
+    cell(row, time(d.last_detected_at));
+  }
+
+  body = fill("transactions", overview.transactions, 7, "No transactions yet");
+  for (const tx of overview.transactions || []) {
+    const row = body.insertRow();
+    cell(row, time(tx.created_at));
+    cell(row, tx.id);
+    cell(row, tx.from_account);
+    cell(row, tx.to_account);
+    cell(row, tx.amount, "amount");
+    cell(row, tx.reference);
+    cell(row, tx.description);
+  }
+
+  body = fill("accounts", overview.accounts, 5, "No accounts yet");
+  for (const account of overview.accounts || []) {
+    const row = body.insertRow();
+    cell(row, account.id);
+    cell(row, account.type);
+    cell(row, account.status);
+    cell(row, account.currency);
+    cell(row, account.balance, "amount");
+  }
+
+  const error = document.getElementById("error");
+  const failed = Object.entries(overview.errors || {}).map(([section, message]) => section + ": " + message);
+  error.hidden = failed.length === 0;
+  error.textContent = failed.join("; ");
+}
+
+async function refresh() {
+  try {
+    const response = await fetch("api/overview", { cache: "no-store" });
+    if (!response.ok) throw new Error(response.status + " " + (await response.text()).trim());
+    render(await response.json());
+  } catch (err) {
+    const error = document.getElementById("error");
+    error.hidden = false;
+    error.textContent = "Could not load the overview: " + err.message;
+  }
+}
+
+document.getElementById("refresh").addEventListener("click", refresh);
+setInterval(() => {
+  if (document.getElementById("autorefresh").checked) refresh();
+}, 10000);
+refresh();
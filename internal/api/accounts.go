@@ -0,0 +1,342 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/pii"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/validation"
+	"github.com/shopspring/decimal"
+)
+
+// accountErrorStatus maps account control errors onto HTTP status codes
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrAccountIDRequired), errors.Is(err, ledger.ErrInvalidOverdraftLimit),
+		errors.Is(err, ledger.ErrInvalidCurrency), errors.Is(err, ledger.ErrInvalidParent),
+		errors.Is(err, ledger.ErrInvalidAccountClass):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrInvalidStatusChange), errors.Is(err, ledger.ErrNonZeroBalance),
+		errors.Is(err, ledger.ErrAccountExists):
+		return http.StatusConflict
+	case errors.Is(err, ledger.ErrAccountFrozen), errors.Is(err, ledger.ErrAccountClosed):
+		return http.StatusForbidden
+	case errors.Is(err, ledger.ErrAccountsNotSupported), errors.Is(err, ledger.ErrHierarchyNotSupported),
+		errors.Is(err, pii.ErrNoKey):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerAccountRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, adminToken string) {
+	statusChange := func(change func(ctx context.Context, id, reason string) (models.Account, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Reason string `json:"reason"`
+			}
+			// The body is optional
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "invalid request body", http.StatusBadRequest)
+					return
+				}
+			}
+
+			account, err := change(r.Context(), r.PathValue("id"), req.Reason)
+			if err != nil {
+				http.Error(w, err.Error(), accountErrorStatus(err))
+				return
+			}
+			writeJSON(w, http.StatusOK, account)
+		}
+	}
+
+	mux.HandleFunc("POST /accounts", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID             string          `json:"id"`
+			Type           string          `json:"type"`
+			Class          string          `json:"class"`
+			Currency       string          `json:"currency"`
+			ParentID       string          `json:"parent_id"`
+			OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+			HolderName     string          `json:"holder_name"`
+			HolderEmail    string          `json:"holder_email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.CreateAccount(r.Context(), models.Account{
+			ID:             req.ID,
+			Type:           req.Type,
+			Class:          req.Class,
+			Currency:       req.Currency,
+			ParentID:       req.ParentID,
+			OverdraftLimit: req.OverdraftLimit,
+			HolderName:     req.HolderName,
+			HolderEmail:    req.HolderEmail,
+		})
+		if writeValidationError(w, err) {
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, account)
+	})
+
+	// Back-office browsing: type, status and currency filter, q matches an ID prefix or an alias,
+	// sort is one of id, created_at or updated_at with "-" for descending
+	mux.HandleFunc("GET /accounts", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := accountFilter(r.URL.Query())
+		if writeValidationError(w, err) {
+			return
+		}
+
+		accounts, next, err := ledgerService.SearchAccounts(r.Context(), filter)
+		if writeValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, ledger.ErrAccountSearchNotSupported) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		if next != nil {
+			w.Header().Set("X-Next-Cursor", next.String())
+		}
+		writeJSON(w, http.StatusOK, accounts)
+	})
+
+	mux.HandleFunc("POST /accounts/{id}/freeze", statusChange(ledgerService.FreezeAccount))
+	mux.HandleFunc("POST /accounts/{id}/unfreeze", statusChange(ledgerService.UnfreezeAccount))
+
+	mux.HandleFunc("GET /accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		account, err := ledgerService.GetAccount(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	mux.HandleFunc("PUT /accounts/{id}/overdraft-limit", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Limit decimal.Decimal `json:"limit"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.SetOverdraftLimit(r.Context(), r.PathValue("id"), req.Limit)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	mux.HandleFunc("PUT /accounts/{id}/type", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Type string `json:"type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.SetAccountType(r.Context(), r.PathValue("id"), req.Type)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	mux.HandleFunc("PUT /accounts/{id}/class", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Class string `json:"class"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.SetAccountClass(r.Context(), r.PathValue("id"), req.Class)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	mux.HandleFunc("GET /system-accounts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ledgerService.SystemAccounts())
+	})
+
+	// The currency can only change while the account holds no money
+	mux.HandleFunc("PUT /accounts/{id}/currency", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Currency string `json:"currency"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.SetAccountCurrency(r.Context(), r.PathValue("id"), req.Currency)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	// An empty parent_id moves the account back to the top level
+	mux.HandleFunc("PUT /accounts/{id}/parent", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ParentID string `json:"parent_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.SetParent(r.Context(), r.PathValue("id"), req.ParentID)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+
+	// Right to erasure: wipes the holder details and aliases, keeping every entry and audit record.
+	// It cannot be undone, so only the operator may ask for it.
+	mux.HandleFunc("POST /accounts/{id}/erase", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+
+		erasure, err := ledgerService.EraseAccount(r.Context(), r.PathValue("id"), req.Reason)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, erasure)
+	})))
+
+	// rollup=true adds up the balances of every account beneath this one
+	mux.HandleFunc("GET /accounts/{id}/balance", func(w http.ResponseWriter, r *http.Request) {
+		accountId := r.PathValue("id")
+		if r.URL.Query().Get("rollup") == "true" {
+			rollup, err := ledgerService.GetRollupBalance(r.Context(), accountId)
+			if err != nil {
+				http.Error(w, err.Error(), accountErrorStatus(err))
+				return
+			}
+			writeJSON(w, http.StatusOK, rollup)
+			return
+		}
+
+		balance, err := ledgerService.GetBalance(accountId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, models.AccountBalance{AccountID: accountId, Balance: balance})
+	})
+
+	// Many balances in one call, for dashboards listing hundreds of wallets; currencies,
+	// when given, keeps only the accounts held in one of them
+	mux.HandleFunc("POST /accounts/balances", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			AccountIDs []string `json:"account_ids"`
+			Currencies []string `json:"currencies"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		batch, err := ledgerService.GetBalances(r.Context(), req.AccountIDs, req.Currencies)
+		if writeValidationError(w, err) {
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, batch)
+	})
+
+	// Closing requires a zero balance unless sweep_to names an account to move the residue to
+	mux.HandleFunc("POST /accounts/{id}/close", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SweepTo string `json:"sweep_to"`
+			Reason  string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+
+		account, err := ledgerService.CloseAccount(r.Context(), r.PathValue("id"), req.SweepTo, req.Reason)
+		if err != nil {
+			http.Error(w, err.Error(), accountErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+}
+
+func accountFilter(query url.Values) (models.AccountFilter, error) {
+	filter := models.AccountFilter{
+		Type:     query.Get("type"),
+		Status:   query.Get("status"),
+		Currency: query.Get("currency"),
+		Query:    query.Get("q"),
+		Sort:     query.Get("sort"),
+	}
+
+	var errs validation.Errors
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := models.ParseAccountCursor(cursor)
+		if err != nil {
+			errs = append(errs, validation.FieldError{Field: "cursor", Message: err.Error()})
+		} else {
+			filter.After = &after
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			errs = append(errs, validation.FieldError{Field: "limit", Message: "must be a number"})
+		}
+		filter.Limit = n
+	}
+	if len(errs) > 0 {
+		return filter, errs
+	}
+	return filter, nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/adminui"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+	"github.com/shopspring/decimal"
+)
+
+const adminOverviewLimit = 50 // transactions and accounts shown
+
+// adminOverview is everything the admin UI shows. A section that fails to load is left
+// empty and named in Errors, so one broken store call does not blank the whole page.
+type adminOverview struct {
+	GeneratedAt   time.Time                   `json:"generated_at"`
+	Transactions  []models.Transaction        `json:"transactions"`
+	Accounts      []adminAccount              `json:"accounts"`
+	DeadLetters   *models.DeadLetterStats     `json:"dead_letters,omitempty"`
+	Discrepancies []models.BalanceDiscrepancy `json:"discrepancies"`
+	Errors        map[string]string           `json:"errors,omitempty"`
+}
+
+type adminAccount struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type,omitempty"`
+	Status   string          `json:"status"`
+	Currency string          `json:"currency,omitempty"`
+	Balance  decimal.Decimal `json:"balance"`
+}
+
+// adminAuthorized accepts the admin token as a bearer token, or as the password of HTTP basic
+// auth so a browser can prompt for it; the user name is ignored
+func adminAuthorized(r *http.Request, token string) bool {
+	given := bearerToken(r)
+	if _, password, ok := r.BasicAuth(); ok {
+		given = password
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// requireAdmin refuses callers without the admin token, and every caller while it is empty
+func requireAdmin(token string, handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !adminAuthorized(r, token) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="ledger admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// registerAdminUIRoutes serves the read-only admin UI at /admin/ui/ behind the admin token.
+// The dead letter and discrepancy sections stay empty without their services.
+func registerAdminUIRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, deadLetters *deadletter.Queue, checker *reports.BalanceChecker, token string) {
+	admin := func(handler http.Handler) http.HandlerFunc {
+		return requireAdmin(token, handler)
+	}
+
+	mux.HandleFunc("GET /admin/ui/", admin(adminui.Handler("/admin/ui/")))
+
+	mux.HandleFunc("GET /admin/ui/api/overview", admin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		overview := adminOverview{
+			GeneratedAt:   ledgerService.Now().UTC(),
+			Transactions:  []models.Transaction{},
+			Accounts:      []adminAccount{},
+			Discrepancies: []models.BalanceDiscrepancy{},
+			Errors:        map[string]string{},
+		}
+
+		if transactions, _, err := ledgerService.SearchTransactions(ctx, models.TransactionFilter{Limit: adminOverviewLimit}); err != nil {
+			overview.Errors["transactions"] = err.Error()
+		} else {
+			overview.Transactions = transactions
+		}
+
+		accounts, _, err := ledgerService.SearchAccounts(ctx, models.AccountFilter{Sort: "-updated_at", Limit: adminOverviewLimit})
+		if err != nil {
+			overview.Errors["accounts"] = err.Error()
+		}
+		if len(accounts) > 0 {
+			ids := make([]string, len(accounts))
+			for i, account := range accounts {
+				ids[i] = account.ID
+			}
+			batch, err := ledgerService.GetBalances(ctx, ids, nil)
+			if err != nil {
+				overview.Errors["balances"] = err.Error()
+			}
+			balances := make(map[string]decimal.Decimal, len(batch.Balances))
+			for _, balance := range batch.Balances {
+				balances[balance.AccountID] = balance.Balance
+			}
+			for _, account := range accounts {
+				overview.Accounts = append(overview.Accounts, adminAccount{
+					ID:       account.ID,
+					Type:     account.Type,
+					Status:   account.Status,
+					Currency: account.Currency,
+					Balance:  balances[account.ID],
+				})
+			}
+		}
+
+		if deadLetters != nil {
+			if stats, err := deadLetters.Stats(ctx); err != nil {
+				overview.Errors["dead_letters"] = err.Error()
+			} else {
+				overview.DeadLetters = &stats
+			}
+		}
+
+		if checker != nil {
+			open := true
+			if discrepancies, err := checker.List(ctx, models.DiscrepancyFilter{Open: &open, Limit: adminOverviewLimit}); err != nil {
+				overview.Errors["discrepancies"] = err.Error()
+			} else {
+				overview.Discrepancies = discrepancies
+			}
+		}
+
+		writeJSON(w, http.StatusOK, overview)
+	})))
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func aliasErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrInvalidAlias), errors.Is(err, ledger.ErrAccountIDRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrAliasNotFound), errors.Is(err, ledger.ErrTenantMismatch):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrAliasExists):
+		return http.StatusConflict
+	case errors.Is(err, ledger.ErrAliasesNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// resolveParty returns the account of one side of a transfer, given either its ID or an alias
+func resolveParty(ctx context.Context, ledgerService *ledger.Ledger, side, accountId, alias string) (string, error) {
+	if alias == "" {
+		return accountId, nil
+	}
+	if accountId != "" {
+		return "", fmt.Errorf("%w: give either %s_account or %s_alias", ledger.ErrInvalidAlias, side, side)
+	}
+	return ledgerService.ResolveAlias(ctx, alias)
+}
+
+func registerAliasRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("POST /aliases", func(w http.ResponseWriter, r *http.Request) {
+		var alias models.AccountAlias
+		if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		created, err := ledgerService.CreateAlias(r.Context(), alias)
+		if err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	})
+
+	mux.HandleFunc("GET /aliases/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		alias, err := ledgerService.GetAlias(r.Context(), r.PathValue("alias"))
+		if err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, alias)
+	})
+
+	mux.HandleFunc("DELETE /aliases/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		if err := ledgerService.DeleteAlias(r.Context(), r.PathValue("alias")); err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /accounts/{id}/aliases", func(w http.ResponseWriter, r *http.Request) {
+		aliases, err := ledgerService.ListAliases(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, aliases)
+	})
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/analytics"
+)
+
+func registerAnalyticsRoutes(mux *http.ServeMux, exporter *analytics.Exporter) {
+	// Re-exports one day, e.g. after a late correction; the files are overwritten in place
+	mux.HandleFunc("POST /analytics/exports/{date}", func(w http.ResponseWriter, r *http.Request) {
+		day, err := time.Parse("2006-01-02", r.PathValue("date"))
+		if err != nil {
+			http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+
+		export, err := exporter.ExportDay(r.Context(), day)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, export)
+	})
+}
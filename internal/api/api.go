@@ -0,0 +1,297 @@
+// Package api serves the ledger over HTTP. NewRouter registers the handlers on a mux of
+// their own, so another Go service can embed the ledger in-process, and tests can drive
+// the handlers with httptest, without the server's env, jobs or middleware.
+//
+//	mux := api.NewRouter(ledgerService, api.WithSchedules(scheduleService), api.WithLogger(appLogger))
+//	http.ListenAndServe(":8080", api.TenantAccountGuard(ledgerService, mux))
+//
+// The ledger's own routes are always served; every other feature is served only once its
+// option hands over the service behind it.
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/analytics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/eod"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/breaker"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/bus"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interest"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/iso20022"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/netting"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/proofs"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/slo"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/statements"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/stream"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+)
+
+type Option func(*options)
+
+type options struct {
+	appLogger *slog.Logger
+
+	reports         *reports.Service
+	dailyProjection *reports.DailyProjection
+	balanceChecker  *reports.BalanceChecker
+	reconciliation  *reconciliation.Service
+	statements      *statements.Service
+	interest        *interest.Service
+	schedules       *schedules.Service
+	netting         *netting.Service
+	eod             *eod.Service
+	importer        *iso20022.Importer
+	auditLog        *audit.Log
+	calendar        *calendar.Calendar
+	analytics       *analytics.Exporter
+	checkpointer    *proofs.Checkpointer
+	sloTracker      *slo.Tracker
+	meter           *usage.Meter
+	clock           *clock.Simulated
+	eventFilter     *bus.Filter
+
+	hub              *stream.Hub
+	wsToken          string
+	maxSubscriptions int
+
+	participant   *twophase.Participant
+	coordinator   *twophase.Coordinator
+	twoPhaseToken string
+
+	deadLetters *deadletter.Queue
+	publisher   *breaker.Publisher
+	broker      interfaces.EventPublisher
+
+	adminToken string
+	adminUI    bool
+}
+
+// WithLogger logs export and statement failures to appLogger instead of slog.Default()
+func WithLogger(appLogger *slog.Logger) Option {
+	return func(o *options) { o.appLogger = appLogger }
+}
+
+// WithReports serves the invariant and trial balance reports, the daily aggregates and the
+// balance discrepancies; a nil service leaves its routes out
+func WithReports(reportService *reports.Service, projection *reports.DailyProjection, checker *reports.BalanceChecker) Option {
+	return func(o *options) {
+		o.reports = reportService
+		o.dailyProjection = projection
+		o.balanceChecker = checker
+	}
+}
+
+func WithReconciliation(reconciliationService *reconciliation.Service) Option {
+	return func(o *options) { o.reconciliation = reconciliationService }
+}
+
+func WithStatements(statementService *statements.Service) Option {
+	return func(o *options) { o.statements = statementService }
+}
+
+func WithInterest(interestService *interest.Service) Option {
+	return func(o *options) { o.interest = interestService }
+}
+
+// WithSchedules serves standing orders and holds transactions posted with a future
+// execute_at until they are due
+func WithSchedules(scheduleService *schedules.Service) Option {
+	return func(o *options) { o.schedules = scheduleService }
+}
+
+func WithNetting(nettingService *netting.Service) Option {
+	return func(o *options) { o.netting = nettingService }
+}
+
+func WithEOD(eodService *eod.Service) Option {
+	return func(o *options) { o.eod = eodService }
+}
+
+func WithImporter(importer *iso20022.Importer) Option {
+	return func(o *options) { o.importer = importer }
+}
+
+func WithAudit(auditLog *audit.Log) Option {
+	return func(o *options) { o.auditLog = auditLog }
+}
+
+func WithCalendar(businessDays *calendar.Calendar) Option {
+	return func(o *options) { o.calendar = businessDays }
+}
+
+func WithAnalytics(exporter *analytics.Exporter) Option {
+	return func(o *options) { o.analytics = exporter }
+}
+
+func WithProofs(checkpointer *proofs.Checkpointer) Option {
+	return func(o *options) { o.checkpointer = checkpointer }
+}
+
+func WithSLO(tracker *slo.Tracker) Option {
+	return func(o *options) { o.sloTracker = tracker }
+}
+
+func WithUsage(meter *usage.Meter) Option {
+	return func(o *options) { o.meter = meter }
+}
+
+// WithSimulatedClock serves /admin/clock to read and move the simulated time; never in
+// production
+func WithSimulatedClock(simulated *clock.Simulated) Option {
+	return func(o *options) { o.clock = simulated }
+}
+
+func WithEventFilter(filter *bus.Filter) Option {
+	return func(o *options) { o.eventFilter = filter }
+}
+
+// WithStream serves live balance updates over SSE and WebSocket. WebSocket clients must
+// present token, and are refused while it is empty; each may follow up to
+// maxSubscriptions accounts, 50 when it is not positive.
+func WithStream(hub *stream.Hub, token string, maxSubscriptions int) Option {
+	return func(o *options) {
+		o.hub = hub
+		o.wsToken = token
+		o.maxSubscriptions = maxSubscriptions
+	}
+}
+
+// WithTwoPhase serves cross-instance transfers, and this instance's legs to the other
+// instances, which must present token
+func WithTwoPhase(participant *twophase.Participant, coordinator *twophase.Coordinator, token string) Option {
+	return func(o *options) {
+		o.participant = participant
+		o.coordinator = coordinator
+		o.twoPhaseToken = token
+	}
+}
+
+// WithDeadLetters serves the dead letter queue; redriven events go out through publisher,
+// or straight to broker when forced past an open breaker
+func WithDeadLetters(deadLetters *deadletter.Queue, publisher *breaker.Publisher, broker interfaces.EventPublisher) Option {
+	return func(o *options) {
+		o.deadLetters = deadLetters
+		o.publisher = publisher
+		o.broker = broker
+	}
+}
+
+// WithAdminToken guards the operator routes, such as balance repairs, with token. Without
+// it they are refused to everyone.
+func WithAdminToken(token string) Option {
+	return func(o *options) { o.adminToken = token }
+}
+
+// WithAdminUI serves the read-only admin UI behind the admin token. Its dead letter and
+// discrepancy sections come from WithDeadLetters and WithReports.
+func WithAdminUI() Option {
+	return func(o *options) { o.adminUI = true }
+}
+
+// NewRouter returns a mux serving the ledger and every service the options hand over. It
+// has no middleware: tenants, API keys, usage metering, auditing and timeouts are up to the
+// caller, who may also add routes of its own to the mux.
+func NewRouter(ledgerService *ledger.Ledger, opts ...Option) *http.ServeMux {
+	o := options{appLogger: slog.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mux := http.NewServeMux()
+
+	registerCoreRoutes(mux, ledgerService, o.schedules, o.appLogger)
+	registerLedgerRoutes(mux, ledgerService)
+	registerPeriodRoutes(mux, ledgerService)
+	registerTransactionRoutes(mux, ledgerService, o.adminToken)
+	registerAccountRoutes(mux, ledgerService, o.adminToken)
+	registerLimitRoutes(mux, ledgerService)
+	registerRuleRoutes(mux, ledgerService)
+	registerBalanceAlertRoutes(mux, ledgerService)
+	registerAliasRoutes(mux, ledgerService)
+	registerPaymentRequestRoutes(mux, ledgerService)
+	registerSuspenseRoutes(mux, ledgerService, o.adminToken)
+	registerFeeRoutes(mux, ledgerService, o.adminToken)
+
+	if o.reports != nil {
+		registerReportRoutes(mux, o.reports)
+	}
+	if o.dailyProjection != nil {
+		registerDailyReportRoutes(mux, o.dailyProjection, ledgerService)
+	}
+	if o.balanceChecker != nil {
+		registerDiscrepancyRoutes(mux, o.balanceChecker, o.adminToken)
+	}
+	if o.reconciliation != nil {
+		registerReconciliationRoutes(mux, o.reconciliation)
+	}
+	if o.auditLog != nil {
+		registerAuditRoutes(mux, o.auditLog)
+	}
+	if o.statements != nil {
+		registerStatementRoutes(mux, o.statements, ledgerService, o.appLogger)
+	}
+	if o.hub != nil {
+		maxSubscriptions := o.maxSubscriptions
+		if maxSubscriptions <= 0 {
+			maxSubscriptions = 50
+		}
+		registerStreamRoutes(mux, o.hub)
+		registerWebSocketRoutes(mux, o.hub, o.wsToken, maxSubscriptions)
+	}
+	if o.participant != nil && o.coordinator != nil {
+		registerTwoPhaseRoutes(mux, o.participant, o.coordinator, o.twoPhaseToken)
+	}
+	if o.interest != nil {
+		registerInterestRoutes(mux, o.interest)
+	}
+	if o.schedules != nil {
+		registerScheduleRoutes(mux, o.schedules)
+	}
+	if o.netting != nil {
+		registerNettingRoutes(mux, o.netting, ledgerService)
+	}
+	if o.eod != nil {
+		registerEODRoutes(mux, o.eod, ledgerService)
+	}
+	if o.importer != nil {
+		registerImportRoutes(mux, o.importer)
+	}
+	if o.deadLetters != nil {
+		registerDeadLetterRoutes(mux, o.deadLetters, o.publisher, o.broker)
+	}
+	if o.eventFilter != nil {
+		registerEventFilterRoutes(mux, o.eventFilter, o.adminToken)
+	}
+	if o.sloTracker != nil {
+		registerSLORoutes(mux, o.sloTracker)
+	}
+	if o.calendar != nil {
+		registerCalendarRoutes(mux, o.calendar, ledgerService)
+	}
+	if o.adminUI {
+		registerAdminUIRoutes(mux, ledgerService, o.deadLetters, o.balanceChecker, o.adminToken)
+	}
+	if o.meter != nil {
+		registerUsageRoutes(mux, o.meter, ledgerService)
+	}
+	if o.analytics != nil {
+		registerAnalyticsRoutes(mux, o.analytics)
+	}
+	if o.checkpointer != nil {
+		registerProofRoutes(mux, o.checkpointer)
+	}
+	if o.clock != nil {
+		registerClockRoutes(mux, o.clock)
+	}
+	return mux
+}
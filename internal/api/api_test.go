@@ -0,0 +1,352 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/analytics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/eod"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/breaker"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/bus"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interest"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/iso20022"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/netting"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/proofs"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/slo"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/statements"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/stream"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+)
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(topic string, event any) error { return nil }
+
+func newTestLedger() (*ledger.Ledger, *slog.Logger) {
+	appLogger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	return ledger.NewLedger(memory.NewMemoryLedgerStore(), appLogger, discardPublisher{}), appLogger
+}
+
+func newTestServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	ledgerService, appLogger := newTestLedger()
+	server := httptest.NewServer(NewRouter(ledgerService, append([]Option{WithLogger(appLogger)}, opts...)...))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const testAdminToken = "admin-token"
+
+// newTenantServer resolves tenants in front of the router, as cmd/server does
+func newTenantServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	ledgerService, appLogger := newTestLedger()
+	mux := NewRouter(ledgerService, append([]Option{WithLogger(appLogger)}, opts...)...)
+	server := httptest.NewServer(tenant.Middleware(TenantAccountGuard(ledgerService, mux), PlatformAccess(testAdminToken, "")))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// send makes a request with the given headers and closes the response when the test ends
+func send(t *testing.T, method, url, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func postTransaction(t *testing.T, server *httptest.Server, key, body string) *http.Response {
+	t.Helper()
+	return send(t, http.MethodPost, server.URL+"/transactions", body, map[string]string{"Idempotency-Key": key})
+}
+
+func TestHealth(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestPostTransactionMovesBalance(t *testing.T) {
+	server := newTestServer(t)
+
+	resp := postTransaction(t, server, "key-1", `{"from_account":"a","to_account":"b","amount":"5"}`)
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d, want %d: %s", resp.StatusCode, http.StatusCreated, body)
+	}
+
+	// The same key again is answered without posting twice
+	resp = postTransaction(t, server, "key-1", `{"from_account":"a","to_account":"b","amount":"5"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("repeated status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	balance, err := http.Get(server.URL + "/accounts/balance?account_id=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer balance.Body.Close()
+	var got struct {
+		AccountID string `json:"account_id"`
+		Balance   string `json:"balance"`
+	}
+	if err := json.NewDecoder(balance.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.AccountID != "b" || got.Balance != "5" {
+		t.Fatalf("balance = %+v, want b at 5", got)
+	}
+}
+
+func TestPostTransactionWithoutKey(t *testing.T) {
+	server := newTestServer(t)
+
+	// Keyless postings are not repeats of one another
+	for _, body := range []string{
+		`{"from_account":"a","to_account":"b","amount":"5"}`,
+		`{"from_account":"c","to_account":"d","amount":"7"}`,
+	} {
+		resp := postTransaction(t, server, "", body)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("status = %d, want %d for %s", resp.StatusCode, http.StatusCreated, body)
+		}
+	}
+}
+
+func TestPostTransactionRejectsReservedKey(t *testing.T) {
+	server := newTestServer(t)
+
+	// A reversal- key posted first would stop the transaction it names from being reversed
+	resp := postTransaction(t, server, "reversal-tx-1", `{"from_account":"a","to_account":"b","amount":"5"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestPostTransactionRejectsFutureDate(t *testing.T) {
+	server := newTestServer(t)
+
+	// Backdating is fine; a date beyond the clock skew is not
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	resp := postTransaction(t, server, "key-1", `{"from_account":"a","to_account":"b","amount":"5","effective_at":"`+past+`"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("backdated status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	resp = postTransaction(t, server, "key-2", `{"from_account":"a","to_account":"b","amount":"5","effective_at":"`+future+`"}`)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("future dated status = %d, want %d", resp.StatusCode, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestPostTransactionRejectsBadBody(t *testing.T) {
+	server := newTestServer(t)
+
+	resp := postTransaction(t, server, "key-1", `{"amount":`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestExecuteAtWithoutSchedules(t *testing.T) {
+	server := newTestServer(t)
+
+	executeAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	resp := postTransaction(t, server, "key-1", `{"from_account":"a","to_account":"b","amount":"5","execute_at":"`+executeAt+`"}`)
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotImplemented)
+	}
+}
+
+func TestOptionalRoutesNeedTheirOption(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/reports/trial-balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// TestNewRouterWithEveryOption registers every route at once; ServeMux panics on patterns
+// that conflict, which is how the /accounts/balance clash first showed up
+func TestNewRouterWithEveryOption(t *testing.T) {
+	ledgerService, appLogger := newTestLedger()
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduleService := schedules.NewService(ledgerService, nil, nil, discardPublisher{}, appLogger)
+	participant := twophase.NewParticipant(ledgerService, nil)
+	deadLetters := deadletter.NewQueue(nil)
+	simulated, err := clock.NewSimulated(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	NewRouter(ledgerService,
+		WithLogger(appLogger),
+		WithReports(reports.NewService(nil, ledgerService, appLogger), reports.NewDailyProjection(nil, ledgerService, appLogger), reports.NewBalanceChecker(nil, ledgerService, appLogger)),
+		WithReconciliation(reconciliation.NewService(nil, ledgerService, appLogger)),
+		WithStatements(statements.NewService(ledgerService, nil)),
+		WithInterest(interest.NewService(ledgerService, nil, appLogger)),
+		WithSchedules(scheduleService),
+		WithNetting(netting.NewService(ledgerService, nil, time.Hour, appLogger)),
+		WithEOD(eod.NewService(ledgerService, nil, discardPublisher{}, eod.Cutoff{}, appLogger)),
+		WithImporter(iso20022.NewImporter(ledgerService, scheduleService, appLogger)),
+		WithAudit(audit.NewLog(nil, appLogger)),
+		WithCalendar(calendar.New(nil, time.UTC, nil, appLogger)),
+		WithAnalytics(analytics.NewExporter(nil, nil, appLogger)),
+		WithProofs(proofs.NewCheckpointer(nil, key, 100, ledgerService, appLogger)),
+		WithSLO(slo.NewTracker(slo.Objectives{}, appLogger)),
+		WithUsage(usage.NewMeter(nil, ledgerService, usage.Quotas{}, appLogger)),
+		WithSimulatedClock(simulated),
+		WithEventFilter(bus.NewFilter(discardPublisher{}, nil, appLogger)),
+		WithStream(stream.NewHub(), "token", 0),
+		WithTwoPhase(participant, twophase.NewCoordinator("node", participant, nil, nil, ledgerService, appLogger), "token"),
+		WithDeadLetters(deadLetters, breaker.NewPublisher(discardPublisher{}, deadLetters, 5, time.Second, appLogger), discardPublisher{}),
+		WithAdminToken("token"),
+		WithAdminUI(),
+	)
+}
+
+func TestTenantRequired(t *testing.T) {
+	server := newTenantServer(t)
+
+	resp := send(t, http.MethodGet, server.URL+"/accounts/balance?account_id=a", "", nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("without a tenant status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	resp = send(t, http.MethodGet, server.URL+"/accounts/balance?account_id=a", "", map[string]string{"Authorization": "Bearer wrong"})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("with a wrong token status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	resp = send(t, http.MethodGet, server.URL+"/accounts/balance?account_id=a", "", map[string]string{"Authorization": "Bearer " + testAdminToken})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("as admin status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	resp = send(t, http.MethodGet, server.URL+"/health", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("health status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestIdempotencyKeyPerTenant(t *testing.T) {
+	server := newTenantServer(t)
+
+	for _, tenantId := range []string{"acme", "globex"} {
+		resp := send(t, http.MethodPost, server.URL+"/transactions", `{"from_account":"`+tenantId+`-a","to_account":"`+tenantId+`-b","amount":"5"}`,
+			map[string]string{tenant.Header: tenantId, "Idempotency-Key": "key-1"})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("%s status = %d, want %d", tenantId, resp.StatusCode, http.StatusCreated)
+		}
+	}
+}
+
+// adminRoutes are refused without the admin token. The requests stop at the guard, so the
+// services behind them, built without stores, are never called.
+var adminRoutes = []struct{ method, path string }{
+	{http.MethodPost, "/admin/accounts/a/recompute-balance"},
+	{http.MethodGet, "/admin/discrepancies"},
+	{http.MethodPost, "/admin/reversals"},
+	{http.MethodPost, "/accounts/a/erase"},
+	{http.MethodGet, "/admin/suspense"},
+	{http.MethodGet, "/admin/suspense/s"},
+	{http.MethodPost, "/admin/suspense/s/resolve"},
+	{http.MethodGet, "/admin/event-filter"},
+	{http.MethodPut, "/admin/event-filter"},
+	{http.MethodPost, "/fee-schedules"},
+	{http.MethodPut, "/fee-schedules/f"},
+	{http.MethodDelete, "/fee-schedules/f"},
+}
+
+// newAdminServer serves every route in adminRoutes behind testAdminToken
+func newAdminServer(t *testing.T, opts ...Option) *httptest.Server {
+	t.Helper()
+	ledgerService, appLogger := newTestLedger()
+	return newTestServer(t, append([]Option{
+		WithAdminToken(testAdminToken),
+		WithEventFilter(bus.NewFilter(nil, nil, appLogger)),
+		WithReports(reports.NewService(nil, ledgerService, appLogger), nil, reports.NewBalanceChecker(nil, ledgerService, appLogger)),
+	}, opts...)...)
+}
+
+func TestAdminRoutesNeedToken(t *testing.T) {
+	server := newAdminServer(t)
+	unconfigured := newTestServer(t, WithReports(nil, nil, reports.NewBalanceChecker(nil, nil, nil)))
+
+	for _, route := range adminRoutes {
+		for name, header := range map[string]map[string]string{
+			"no token":    nil,
+			"wrong token": {"Authorization": "Bearer wrong"},
+			"tenant only": {tenant.Header: "acme"},
+		} {
+			resp := send(t, route.method, server.URL+route.path, "{}", header)
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("%s %s with %s: status = %d, want %d", route.method, route.path, name, resp.StatusCode, http.StatusUnauthorized)
+			}
+		}
+	}
+	// The token gets past the guard to the handler, which finds the batch empty
+	resp := send(t, http.MethodPost, server.URL+"/admin/reversals", `{"dry_run":true}`, map[string]string{"Authorization": "Bearer " + testAdminToken})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("reversals as admin: status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	// The memory store keeps no fee schedules; the basic-auth password works as well
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/fee-schedules/f", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("admin", testAdminToken)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("fee schedule delete as admin: status = %d, want %d", resp.StatusCode, http.StatusNotImplemented)
+	}
+	// Without a configured token nobody gets in, not even with an empty one
+	resp = send(t, http.MethodGet, unconfigured.URL+"/admin/discrepancies", "", map[string]string{"Authorization": "Bearer "})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unconfigured token: status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func registerAuditRoutes(mux *http.ServeMux, auditLog *audit.Log) {
+	// Filters: request_id, actor, action, resource (prefix), from/to (RFC3339), limit
+	mux.HandleFunc("GET /audit", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := models.AuditFilter{
+			RequestID: query.Get("request_id"),
+			Actor:     query.Get("actor"),
+			Action:    query.Get("action"),
+			Resource:  query.Get("resource"),
+		}
+
+		var err error
+		if from := query.Get("from"); from != "" {
+			if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+				http.Error(w, "from must be an RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+		}
+		if to := query.Get("to"); to != "" {
+			if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+				http.Error(w, "to must be an RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+		}
+		if limit := query.Get("limit"); limit != "" {
+			if filter.Limit, err = strconv.Atoi(limit); err != nil {
+				http.Error(w, "limit must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+
+		records, err := auditLog.Query(r.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, records)
+	})
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func balanceAlertErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrBalanceAlertNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrInvalidBalanceAlert), errors.Is(err, ledger.ErrAccountIDRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrBalanceAlertsNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// registerBalanceAlertRoutes manages the thresholds checked after every posting to an
+// account; crossing one publishes accounts.threshold_crossed and calls its webhook
+func registerBalanceAlertRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("GET /accounts/{id}/alerts", func(w http.ResponseWriter, r *http.Request) {
+		alerts, err := ledgerService.ListBalanceAlerts(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), balanceAlertErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, alerts)
+	})
+
+	saveAlert := func(w http.ResponseWriter, r *http.Request) {
+		alert := models.BalanceAlert{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		alert.AccountID = r.PathValue("id")
+		if id := r.PathValue("alertId"); id != "" {
+			alert.ID = id
+		}
+
+		saved, err := ledgerService.SaveBalanceAlert(r.Context(), alert)
+		if err != nil {
+			http.Error(w, err.Error(), balanceAlertErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}
+	mux.HandleFunc("POST /accounts/{id}/alerts", saveAlert)
+	mux.HandleFunc("PUT /accounts/{id}/alerts/{alertId}", saveAlert)
+
+	mux.HandleFunc("DELETE /accounts/{id}/alerts/{alertId}", func(w http.ResponseWriter, r *http.Request) {
+		if err := ledgerService.DeleteBalanceAlert(r.Context(), r.PathValue("id"), r.PathValue("alertId")); err != nil {
+			http.Error(w, err.Error(), balanceAlertErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
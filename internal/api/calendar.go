@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func registerCalendarRoutes(mux *http.ServeMux, businessDays *calendar.Calendar, ledgerService *ledger.Ledger) {
+	// currency narrows the list to one currency
+	mux.HandleFunc("GET /calendar/holidays", func(w http.ResponseWriter, r *http.Request) {
+		holidays, err := businessDays.Holidays(r.Context(), r.URL.Query().Get("currency"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, holidays)
+	})
+
+	mux.HandleFunc("PUT /calendar/holidays/{currency}/{date}", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+		holiday, err := businessDays.AddHoliday(r.Context(), models.Holiday{
+			Currency: r.PathValue("currency"),
+			Date:     r.PathValue("date"),
+			Name:     req.Name,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), calendarErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, holiday)
+	})
+
+	mux.HandleFunc("DELETE /calendar/holidays/{currency}/{date}", func(w http.ResponseWriter, r *http.Request) {
+		if err := businessDays.RemoveHoliday(r.Context(), r.PathValue("currency"), r.PathValue("date")); err != nil {
+			http.Error(w, err.Error(), calendarErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Checks a date against the calendars of currencies (comma-separated), e.g.
+	// /calendar/days/2024-12-25?currencies=EUR,USD&add=2 for the value date a transfer booked
+	// then would get and the settlement date two business days later
+	mux.HandleFunc("GET /calendar/days/{date}", func(w http.ResponseWriter, r *http.Request) {
+		date, err := time.Parse(calendar.DateLayout, r.PathValue("date"))
+		if err != nil {
+			http.Error(w, "date must be written as YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		var currencies []string
+		for code := range strings.SplitSeq(r.URL.Query().Get("currencies"), ",") {
+			if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+				currencies = append(currencies, code)
+			}
+		}
+		if len(currencies) == 0 {
+			currencies = []string{ledgerService.BaseCurrency()}
+		}
+
+		convention := ledgerService.ValueDateConvention()
+		response := map[string]any{
+			"date":         date.Format(calendar.DateLayout),
+			"currencies":   currencies,
+			"business_day": businessDays.IsBusinessDay(date, currencies...),
+			"convention":   convention,
+			"value_date":   businessDays.Roll(date, convention, currencies...).Format(calendar.DateLayout),
+		}
+		if value := r.URL.Query().Get("add"); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "add must be a whole number of business days", http.StatusBadRequest)
+				return
+			}
+			response["settlement_date"] = businessDays.AddBusinessDays(date, n, currencies...).Format(calendar.DateLayout)
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+func calendarErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, calendar.ErrInvalidHoliday):
+		return http.StatusBadRequest
+	case errors.Is(err, calendar.ErrHolidayNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
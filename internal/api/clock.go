@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+)
+
+type advanceClockRequest struct {
+	By string `json:"by"` // a Go duration, e.g. "24h"
+}
+
+type clockResponse struct {
+	Now    time.Time `json:"now"`
+	Offset string    `json:"offset"`
+}
+
+func registerClockRoutes(mux *http.ServeMux, simulated *clock.Simulated) {
+	mux.HandleFunc("GET /admin/clock", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, clockResponse{Now: simulated.Now(), Offset: simulated.Offset().String()})
+	})
+
+	// Moves the simulated time forward; jobs due by the new time run on their next tick
+	mux.HandleFunc("POST /admin/clock/advance", func(w http.ResponseWriter, r *http.Request) {
+		var req advanceClockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		by, err := time.ParseDuration(req.By)
+		if err != nil {
+			http.Error(w, "by must be a duration such as 24h", http.StatusBadRequest)
+			return
+		}
+		if err := simulated.Advance(by); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, clock.ErrBackwards) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, http.StatusOK, clockResponse{Now: simulated.Now(), Offset: simulated.Offset().String()})
+	})
+}
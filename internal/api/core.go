@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/shopspring/decimal"
+)
+
+// registerCoreRoutes serves postings, balances and entries. Without a schedule service a
+// transaction with a future execute_at is refused rather than posted at once.
+func registerCoreRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, scheduleService *schedules.Service, appLogger *slog.Logger) {
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	// 3️⃣ Transactions endpoint (NEW)
+	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		idempotencyKey := r.Header.Get("Idempotency-Key")
+
+		var req struct {
+			FromAccount string          `json:"from_account"`
+			ToAccount   string          `json:"to_account"`
+			FromAlias   string          `json:"from_alias"` // IBAN, card token or partner ID instead of from_account
+			ToAlias     string          `json:"to_alias"`
+			Amount      decimal.Decimal `json:"amount"`
+			EffectiveAt *time.Time      `json:"effective_at"` // optional, for backdated postings
+
+			Reference   string            `json:"reference"`
+			Description string            `json:"description"`
+			Metadata    map[string]string `json:"metadata"`
+			Tags        []string          `json:"tags"`
+
+			PaymentRequestID string `json:"payment_request_id"` // to_account and amount default to the request's
+
+			Force  bool             `json:"force"`   // post even if it looks like a duplicate of a recent payment
+			FXRate *decimal.Decimal `json:"fx_rate"` // optional fixed rate for cross-currency transfers
+
+			ExecuteAt *time.Time `json:"execute_at"` // optional, holds the transaction until then
+			ValueDate string     `json:"value_date"` // optional YYYY-MM-DD; defaults to the booking date
+		}
+
+		// Parse JSON body
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// Aliases resolve within the tenant of the request
+		var err error
+		req.FromAccount, err = resolveParty(r.Context(), ledgerService, "from", req.FromAccount, req.FromAlias)
+		if err == nil {
+			req.ToAccount, err = resolveParty(r.Context(), ledgerService, "to", req.ToAccount, req.ToAlias)
+		}
+		if errors.Is(err, ledger.ErrAliasNotFound) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+
+		// Create domain transaction
+		tx := models.Transaction{
+			ID:             ledgerService.NewID(),
+			IdempotencyKey: idempotencyKey,
+			FromAccount:    req.FromAccount,
+			ToAccount:      req.ToAccount,
+			Amount:         req.Amount,
+			CreatedAt:      ledgerService.Now(),
+			Reference:      req.Reference,
+			Description:    req.Description,
+			Metadata:       req.Metadata,
+			Tags:           req.Tags,
+			Force:          req.Force || r.URL.Query().Get("force") == "true",
+
+			PaymentRequestID: req.PaymentRequestID,
+			ValueDate:        req.ValueDate,
+		}
+		if req.FXRate != nil {
+			tx.FX = &models.FXConversion{Rate: *req.FXRate}
+		}
+		if req.EffectiveAt != nil {
+			tx.CreatedAt = *req.EffectiveAt
+		}
+
+		// dry_run=true pre-flights a payment: every check runs, nothing is stored
+		dryRun := r.URL.Query().Get("dry_run") == "true"
+
+		// Future-dated transactions are held and posted by the scheduler when due
+		if !dryRun && req.ExecuteAt != nil && req.ExecuteAt.After(ledgerService.Now()) {
+			if scheduleService == nil {
+				http.Error(w, "execute_at is not supported: scheduled payments are not enabled", http.StatusNotImplemented)
+				return
+			}
+			pending, err := scheduleService.SchedulePayment(r.Context(), tx, *req.ExecuteAt)
+			if err != nil {
+				http.Error(w, err.Error(), scheduleErrorStatus(err))
+				return
+			}
+			writeJSON(w, http.StatusAccepted, pending)
+			return
+		}
+
+		// Call domain logic
+		var posted models.Transaction
+		var exists bool
+		var simulation ledger.Simulation
+		if dryRun {
+			simulation, err = ledgerService.SimulateTransaction(r.Context(), tx)
+		} else {
+			posted, exists, err = ledgerService.PostTransactionDetailed(r.Context(), tx)
+		}
+		if err != nil {
+			writePostingError(w, err)
+			return
+		}
+		if dryRun {
+			writeJSON(w, http.StatusOK, simulation)
+			return
+		}
+		if exists {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"status":"already processed"}`))
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, map[string]any{
+			"status":         "Created Transaction",
+			"transaction_id": posted.ID,
+			"fees":           posted.Fees,
+			"total_fees":     posted.TotalFees(),
+			"fx":             posted.FX,
+		})
+	})
+
+	// GET in the pattern keeps it apart from GET /accounts/{id}, which a method-less
+	// pattern would conflict with
+	mux.HandleFunc("GET /accounts/balance", func(w http.ResponseWriter, r *http.Request) {
+		accountId := r.URL.Query().Get("account_id")
+		if accountId == "" {
+			http.Error(w, "account_id is a mandatory field", http.StatusBadRequest)
+			return
+		}
+
+		var balance decimal.Decimal
+		var err error
+		if asOfParam := r.URL.Query().Get("as_of"); asOfParam != "" {
+			asOf, err := time.Parse(time.RFC3339, asOfParam)
+			if err != nil {
+				http.Error(w, "as_of must be an RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			balance, err = ledgerService.GetBalanceAsOf(accountId, asOf)
+		} else {
+			balance, err = ledgerService.GetBalance(accountId)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := struct {
+			AccountID string          `json:"account_id"`
+			Balance   decimal.Decimal `json:"balance"`
+		}{
+			AccountID: accountId,
+			Balance:   balance,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+
+	})
+
+	mux.HandleFunc("/ledgerEntries", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Large exports are streamed row by row from the database cursor
+		if format := ExportFormat(r.Header.Get("Accept")); format != "" {
+			streamEntries(w, r, ledgerService, format, appLogger)
+			return
+		}
+
+		ledgerEntries, err := ledgerService.GetLedgerEntries()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Without a tenant the listing is live: test entries are left out
+		tenantId := tenant.FromContext(r.Context())
+		owned := ledgerEntries[:0]
+		for _, entry := range ledgerEntries {
+			if entry.TenantID == tenantId || (tenantId == "" && !livemode.IsTestTenant(entry.TenantID)) {
+				owned = append(owned, entry)
+			}
+		}
+		ledgerEntries = owned
+		// Counterparty, direction and narrative come from the entries' transactions
+		described, err := ledgerService.DescribeEntries(r.Context(), ledgerEntries)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(described)
+
+	})
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/breaker"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/deadletter"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func deadLetterErrorStatus(err error) int {
+	if errors.Is(err, deadletter.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// deadLetterID reads the {id} path value, answering 400 when it is not a number
+func deadLetterID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "id must be a number", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func registerDeadLetterRoutes(mux *http.ServeMux, deadLetters *deadletter.Queue, publisher *breaker.Publisher, broker interfaces.EventPublisher) {
+	// Oldest first; status is pending or failed, limit defaults to 100
+	mux.HandleFunc("GET /dead-letters", func(w http.ResponseWriter, r *http.Request) {
+		filter := models.DeadLetterFilter{Status: r.URL.Query().Get("status"), Limit: 100}
+		switch filter.Status {
+		case "", models.DeadLetterPending, models.DeadLetterFailed:
+		default:
+			http.Error(w, "status must be pending or failed", http.StatusBadRequest)
+			return
+		}
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			filter.Limit = parsed
+		}
+
+		letters, err := deadLetters.List(r.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, letters)
+	})
+
+	// Backlog size, failed letters and the creation time of the oldest one
+	mux.HandleFunc("GET /dead-letters/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats, err := deadLetters.Stats(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	})
+
+	mux.HandleFunc("GET /dead-letters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := deadLetterID(w, r)
+		if !ok {
+			return
+		}
+		letter, err := deadLetters.Get(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), deadLetterErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, letter)
+	})
+
+	// Redrives without waiting for the next job run, under the same rule: only while the broker is healthy
+	mux.HandleFunc("POST /dead-letters/redrive", func(w http.ResponseWriter, r *http.Request) {
+		if publisher.State() != breaker.Closed {
+			http.Error(w, "event broker circuit is open", http.StatusConflict)
+			return
+		}
+
+		sent, err := deadLetters.Redrive(r.Context(), broker)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("redrove %d events before failing: %v", sent, err), http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"redriven": sent})
+	})
+
+	// Publishes one letter now, ahead of older ones
+	mux.HandleFunc("POST /dead-letters/{id}/retry", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := deadLetterID(w, r)
+		if !ok {
+			return
+		}
+		if publisher.State() != breaker.Closed {
+			http.Error(w, "event broker circuit is open", http.StatusConflict)
+			return
+		}
+
+		letter, err := deadLetters.Retry(r.Context(), id, broker)
+		if errors.Is(err, deadletter.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, letter)
+	})
+
+	mux.HandleFunc("DELETE /dead-letters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := deadLetterID(w, r)
+		if !ok {
+			return
+		}
+		if err := deadLetters.Discard(r.Context(), id); err != nil {
+			http.Error(w, err.Error(), deadLetterErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
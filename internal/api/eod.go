@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/eod"
+)
+
+func eodErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, eod.ErrDayNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, eod.ErrInvalidDate):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerEODRoutes(mux *http.ServeMux, eodService *eod.Service, clk clock.Clock) {
+	mux.HandleFunc("GET /business-days/latest", func(w http.ResponseWriter, r *http.Request) {
+		day, err := eodService.LatestDay(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), eodErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, day)
+	})
+
+	// A closed day together with the net movement of every account
+	mux.HandleFunc("GET /business-days/{date}", func(w http.ResponseWriter, r *http.Request) {
+		day, summaries, err := eodService.GetDay(r.Context(), r.PathValue("date"))
+		if err != nil {
+			http.Error(w, err.Error(), eodErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"day":      day,
+			"accounts": summaries,
+		})
+	})
+
+	// Closes days whose cutoff has passed without waiting for the next job run
+	mux.HandleFunc("POST /business-days/close", func(w http.ResponseWriter, r *http.Request) {
+		closed, err := eodService.CloseDue(r.Context(), clk.Now())
+		if err != nil {
+			http.Error(w, err.Error(), eodErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, closed)
+	})
+}
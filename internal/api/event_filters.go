@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/bus"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// registerEventFilterRoutes shows and replaces the event filter; both need the admin token
+func registerEventFilterRoutes(mux *http.ServeMux, filter *bus.Filter, adminToken string) {
+	mux.HandleFunc("GET /admin/event-filter", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, filter.Current())
+	})))
+
+	// Replaces the filter: send every list, not only the ones that change
+	mux.HandleFunc("PUT /admin/event-filter", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.EventFilter
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		updated, err := filter.Update(r.Context(), req)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, bus.ErrInvalidEventFilter) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+	})))
+}
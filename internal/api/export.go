@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const (
+	exportCSV    = "text/csv"
+	exportNDJSON = "application/x-ndjson"
+
+	// flushEvery bounds how many rows sit in the response buffer before being sent
+	flushEvery = 1000
+)
+
+// ExportFormat picks a streaming format from the Accept header, or "" for the default JSON array
+func ExportFormat(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if mediaType == exportCSV || mediaType == exportNDJSON {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// streamEntries writes entries as CSV or NDJSON while they are read from the store
+func streamEntries(w http.ResponseWriter, r *http.Request, ledgerService *ledger.Ledger, format string, appLogger *slog.Logger) {
+	accountId := r.URL.Query().Get("account_id")
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", format)
+	w.WriteHeader(http.StatusOK)
+
+	var write func(models.LedgerEntry) error
+	var flush func() error
+	switch format {
+	case exportCSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "transaction_id", "account_id", "amount", "created_at", "sequence", "hash"})
+		write = func(entry models.LedgerEntry) error {
+			return writer.Write([]string{
+				entry.ID,
+				entry.TransactionID,
+				entry.AccountID,
+				entry.Amount.String(),
+				entry.CreatedAt.Format(time.RFC3339Nano),
+				strconv.FormatInt(entry.Sequence, 10),
+				entry.Hash,
+			})
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	default:
+		encoder := json.NewEncoder(w) // Encode appends the newline NDJSON needs
+		write = func(entry models.LedgerEntry) error { return encoder.Encode(entry) }
+		flush = func() error { return nil }
+	}
+
+	rows := 0
+	err := ledgerService.StreamLedgerEntries(r.Context(), accountId, func(entry models.LedgerEntry) error {
+		if err := write(entry); err != nil {
+			return err
+		}
+		rows++
+		if rows%flushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			return controller.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		// The status line is already sent, so the client sees a truncated body
+		appLogger.Error("entry export aborted", "format", format, "rows", rows, "error", err)
+	}
+}
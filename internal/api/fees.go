@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func feeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrFeeScheduleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrInvalidFeeSchedule):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrFeesNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// registerFeeRoutes serves the fee schedules to everyone; changing them needs the admin token
+func registerFeeRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, adminToken string) {
+	mux.HandleFunc("GET /fee-schedules", func(w http.ResponseWriter, r *http.Request) {
+		schedules, err := ledgerService.ListFeeSchedules(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), feeErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, schedules)
+	})
+
+	saveSchedule := func(w http.ResponseWriter, r *http.Request) {
+		var schedule models.FeeSchedule
+		if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if id := r.PathValue("id"); id != "" {
+			schedule.ID = id
+		}
+
+		saved, err := ledgerService.SaveFeeSchedule(r.Context(), schedule)
+		if err != nil {
+			http.Error(w, err.Error(), feeErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}
+	mux.HandleFunc("POST /fee-schedules", requireAdmin(adminToken, http.HandlerFunc(saveSchedule)))
+	mux.HandleFunc("PUT /fee-schedules/{id}", requireAdmin(adminToken, http.HandlerFunc(saveSchedule)))
+
+	mux.HandleFunc("DELETE /fee-schedules/{id}", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := ledgerService.DeleteFeeSchedule(r.Context(), r.PathValue("id")); err != nil {
+			http.Error(w, err.Error(), feeErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	// Quote: the fees a transfer would be charged, without posting it
+	mux.HandleFunc("POST /fees/quote", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			FromAccount string          `json:"from_account"`
+			ToAccount   string          `json:"to_account"`
+			Amount      decimal.Decimal `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		fees, err := ledgerService.QuoteFees(r.Context(), models.Transaction{
+			FromAccount: req.FromAccount,
+			ToAccount:   req.ToAccount,
+			Amount:      req.Amount,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), feeErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, fees)
+	})
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/iso20022"
+)
+
+// maxPaymentFileSize bounds uploaded payment files
+const maxPaymentFileSize = 10 << 20
+
+func registerImportRoutes(mux *http.ServeMux, importer *iso20022.Importer) {
+	// Accepts a pain.001 credit transfer file and answers with a pain.002 status report.
+	// Transfers are processed one by one; the report tells which were accepted or rejected.
+	mux.HandleFunc("POST /imports/pain001", func(w http.ResponseWriter, r *http.Request) {
+		doc, err := iso20022.ParsePain001(http.MaxBytesReader(w, r.Body, maxPaymentFileSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report := importer.Import(r.Context(), doc)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		report.Write(w)
+	})
+}
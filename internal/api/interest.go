@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interest"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func registerInterestRoutes(mux *http.ServeMux, interestService *interest.Service) {
+	mux.HandleFunc("GET /accounts/{id}/interest", func(w http.ResponseWriter, r *http.Request) {
+		accrued, err := interestService.AccruedInterest(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, accrued)
+	})
+
+	mux.HandleFunc("GET /interest-rates", func(w http.ResponseWriter, r *http.Request) {
+		rates, err := interestService.ListRates(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, rates)
+	})
+
+	// scope is "account" (id is an account ID) or "product" (id is an account type)
+	mux.HandleFunc("PUT /interest-rates/{scope}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var rate models.InterestRate
+		if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		rate.Scope = r.PathValue("scope")
+		rate.ID = r.PathValue("id")
+
+		saved, err := interestService.SetRate(r.Context(), rate)
+		if errors.Is(err, interest.ErrInvalidScope) || errors.Is(err, interest.ErrInvalidRate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	})
+
+	mux.HandleFunc("DELETE /interest-rates/{scope}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := interestService.DeleteRate(r.Context(), r.PathValue("scope"), r.PathValue("id")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Runs the accrual job now instead of waiting for the next tick
+	mux.HandleFunc("POST /interest/accrue", func(w http.ResponseWriter, r *http.Request) {
+		posted, err := interestService.AccrueDue(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"accruals_posted": posted})
+	})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+)
+
+// writeJSON encodes v as the response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func registerLedgerRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	// Walks the hash chain of one account, or of every account when account_id is omitted.
+	// include_archived=true also walks the entries in cold storage.
+	mux.HandleFunc("GET /ledger/verify", func(w http.ResponseWriter, r *http.Request) {
+		accountId := r.URL.Query().Get("account_id")
+		includeArchived := r.URL.Query().Get("include_archived") == "true"
+
+		var result any
+		var err error
+		if accountId != "" {
+			result, err = ledgerService.VerifyChain(r.Context(), accountId, includeArchived)
+		} else {
+			result, err = ledgerService.VerifyAllChains(r.Context(), includeArchived)
+		}
+		if errors.Is(err, ledger.ErrHashChainNotSupported) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func limitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrLimitProfileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrInvalidLimitProfile), errors.Is(err, ledger.ErrLimitProfileIDMissing),
+		errors.Is(err, ledger.ErrAccountIDRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrLimitsNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerLimitRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("GET /limit-profiles", func(w http.ResponseWriter, r *http.Request) {
+		profiles, err := ledgerService.ListLimitProfiles(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), limitErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, profiles)
+	})
+
+	mux.HandleFunc("GET /limit-profiles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		profile, err := ledgerService.GetLimitProfile(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), limitErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, profile)
+	})
+
+	// Creates or replaces the profile; every account assigned to it picks up the new rules immediately
+	mux.HandleFunc("PUT /limit-profiles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var profile models.LimitProfile
+		if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		profile.ID = r.PathValue("id")
+
+		saved, err := ledgerService.SaveLimitProfile(r.Context(), profile)
+		if err != nil {
+			http.Error(w, err.Error(), limitErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	})
+
+	mux.HandleFunc("PUT /accounts/{id}/limit-profile", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ProfileID string `json:"profile_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		account, err := ledgerService.AssignLimitProfile(r.Context(), r.PathValue("id"), req.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), limitErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/netting"
+)
+
+func nettingErrorStatus(err error) int {
+	if errors.Is(err, netting.ErrInvalidObligation) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// nettingWindow reads the window query parameter; it defaults to the window open right now
+func nettingWindow(r *http.Request, clk clock.Clock) (time.Time, error) {
+	value := r.URL.Query().Get("window")
+	if value == "" {
+		return clk.Now(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func registerNettingRoutes(mux *http.ServeMux, nettingService *netting.Service, clk clock.Clock) {
+	mux.HandleFunc("POST /netting/obligations", func(w http.ResponseWriter, r *http.Request) {
+		var obligation models.NettingObligation
+		if err := json.NewDecoder(r.Body).Decode(&obligation); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		created, err := nettingService.Submit(r.Context(), obligation)
+		if err != nil {
+			http.Error(w, err.Error(), nettingErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusAccepted, created)
+	})
+
+	mux.HandleFunc("GET /netting/obligations", func(w http.ResponseWriter, r *http.Request) {
+		window, err := nettingWindow(r, clk)
+		if err != nil {
+			http.Error(w, "window must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		obligations, err := nettingService.Obligations(r.Context(), window)
+		if err != nil {
+			http.Error(w, err.Error(), nettingErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, obligations)
+	})
+
+	// Gross obligations against net positions per counterparty pair
+	mux.HandleFunc("GET /netting/report", func(w http.ResponseWriter, r *http.Request) {
+		window, err := nettingWindow(r, clk)
+		if err != nil {
+			http.Error(w, "window must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		report, err := nettingService.Report(r.Context(), window)
+		if err != nil {
+			http.Error(w, err.Error(), nettingErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+
+	// Settles windows that have already ended without waiting for the next job run
+	mux.HandleFunc("POST /netting/close", func(w http.ResponseWriter, r *http.Request) {
+		posted, err := nettingService.CloseDue(r.Context(), clk.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), nettingErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"settlements_posted": posted})
+	})
+}
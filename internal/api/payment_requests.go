@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func paymentRequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrInvalidPaymentRequest), errors.Is(err, ledger.ErrAccountIDRequired),
+		errors.Is(err, ledger.ErrExcessPrecision):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrPaymentRequestNotFound), errors.Is(err, ledger.ErrTenantMismatch):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrPaymentRequestNotOpen):
+		return http.StatusConflict
+	case errors.Is(err, ledger.ErrPaymentRequestsNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerPaymentRequestRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("POST /payment-requests", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			PayeeAccount string          `json:"payee_account"`
+			Amount       decimal.Decimal `json:"amount"`
+			Reference    string          `json:"reference"`
+			Description  string          `json:"description"`
+			ExpiresAt    time.Time       `json:"expires_at"` // optional, defaults to a week
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		request, err := ledgerService.CreatePaymentRequest(r.Context(), models.PaymentRequest{
+			PayeeAccount: req.PayeeAccount,
+			Amount:       req.Amount,
+			Reference:    req.Reference,
+			Description:  req.Description,
+			ExpiresAt:    req.ExpiresAt,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), paymentRequestErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, request)
+	})
+
+	mux.HandleFunc("GET /payment-requests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		request, err := ledgerService.GetPaymentRequest(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), paymentRequestErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, request)
+	})
+
+	mux.HandleFunc("GET /accounts/{id}/payment-requests", func(w http.ResponseWriter, r *http.Request) {
+		requests, err := ledgerService.ListPaymentRequests(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), paymentRequestErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, requests)
+	})
+
+	mux.HandleFunc("POST /payment-requests/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		request, err := ledgerService.CancelPaymentRequest(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), paymentRequestErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, request)
+	})
+
+	// Pays the request in full. Without an Idempotency-Key the request ID is used, so a
+	// resubmitted payment is answered as already processed instead of being rejected.
+	mux.HandleFunc("POST /payment-requests/{id}/pay", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			FromAccount string `json:"from_account"`
+			FromAlias   string `json:"from_alias"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		fromAccount, err := resolveParty(r.Context(), ledgerService, "from", req.FromAccount, req.FromAlias)
+		if err != nil {
+			http.Error(w, err.Error(), aliasErrorStatus(err))
+			return
+		}
+
+		id := r.PathValue("id")
+		idempotencyKey := r.Header.Get("Idempotency-Key")
+		if idempotencyKey == "" {
+			idempotencyKey = "payment-request:" + id
+		}
+		posted, exists, err := ledgerService.PostTransactionDetailed(r.Context(), models.Transaction{
+			ID:               ledgerService.NewID(),
+			IdempotencyKey:   idempotencyKey,
+			FromAccount:      fromAccount,
+			PaymentRequestID: id,
+			CreatedAt:        ledgerService.Now(),
+		})
+		if err != nil {
+			writePostingError(w, err)
+			return
+		}
+
+		request, err := ledgerService.GetPaymentRequest(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), paymentRequestErrorStatus(err))
+			return
+		}
+		status := http.StatusCreated
+		if exists {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, map[string]any{
+			"payment_request": request,
+			"transaction_id":  request.TransactionID,
+			"fees":            posted.Fees,
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+)
+
+func registerPeriodRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("GET /periods", func(w http.ResponseWriter, r *http.Request) {
+		periods, err := ledgerService.ListPeriods(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, periods)
+	})
+
+	mux.HandleFunc("POST /periods/{id}/close", func(w http.ResponseWriter, r *http.Request) {
+		period, err := ledgerService.ClosePeriod(r.Context(), r.PathValue("id"))
+		switch {
+		case errors.Is(err, ledger.ErrInvalidPeriod), errors.Is(err, ledger.ErrCannotCloseOpenPeriod):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, ledger.ErrPeriodAlreadyClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case errors.Is(err, ledger.ErrPeriodsNotSupported):
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, period)
+	})
+}
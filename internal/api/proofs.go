@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/proofs"
+)
+
+func proofErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, proofs.ErrEntryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, proofs.ErrNotCheckpointed):
+		return http.StatusConflict
+	case errors.Is(err, proofs.ErrProofUnavailable):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerProofRoutes(mux *http.ServeMux, checkpointer *proofs.Checkpointer) {
+	// Newest first; limit defaults to 100
+	mux.HandleFunc("GET /checkpoints", func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		checkpoints, err := checkpointer.List(r.Context(), limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, checkpoints)
+	})
+
+	mux.HandleFunc("GET /checkpoints/public-key", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, checkpointer.PublicKey())
+	})
+
+	// Everything a third party needs to check the entry against a signed checkpoint
+	mux.HandleFunc("GET /proofs/{entry_id}", func(w http.ResponseWriter, r *http.Request) {
+		proof, err := checkpointer.Proof(r.Context(), r.PathValue("entry_id"))
+		if err != nil {
+			http.Error(w, err.Error(), proofErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, proof)
+	})
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reconciliation"
+	"github.com/shopspring/decimal"
+)
+
+const maxStatementSize = 10 << 20 // 10 MiB
+
+func registerReconciliationRoutes(mux *http.ServeMux, reconciliationService *reconciliation.Service) {
+	// The statement is sent either as the raw request body or as the "file" field of a multipart form.
+	// Matching rules are taken from the query string.
+	mux.HandleFunc("POST /reconciliations", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		accountId := query.Get("account_id")
+		if accountId == "" {
+			http.Error(w, "account_id is a mandatory field", http.StatusBadRequest)
+			return
+		}
+		format := query.Get("format")
+		if format == "" {
+			format = "csv"
+		}
+
+		rules, err := parseReconciliationRules(query.Get("date_window"), query.Get("amount_tolerance"), query.Get("require_reference"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+		var body io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, "multipart request must contain a file field", http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			body = file
+		}
+
+		lines, err := reconciliation.ParseStatement(format, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := reconciliationService.Reconcile(r.Context(), accountId, lines, rules)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+func parseReconciliationRules(dateWindow, amountTolerance, requireReference string) (reconciliation.Rules, error) {
+	rules := reconciliation.DefaultRules()
+
+	if dateWindow != "" {
+		d, err := time.ParseDuration(dateWindow)
+		if err != nil || d < 0 {
+			return rules, errors.New("date_window must be a non-negative duration such as 48h")
+		}
+		rules.DateWindow = d
+	}
+	if amountTolerance != "" {
+		tolerance, err := decimal.NewFromString(amountTolerance)
+		if err != nil || tolerance.IsNegative() {
+			return rules, errors.New("amount_tolerance must be a non-negative decimal")
+		}
+		rules.AmountTolerance = tolerance
+	}
+	if requireReference != "" {
+		required, err := strconv.ParseBool(requireReference)
+		if err != nil {
+			return rules, errors.New("require_reference must be true or false")
+		}
+		rules.RequireReference = required
+	}
+	return rules, nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/reports"
+)
+
+func registerReportRoutes(mux *http.ServeMux, reportService *reports.Service) {
+	mux.HandleFunc("GET /reports/trial-balance", func(w http.ResponseWriter, r *http.Request) {
+		trialBalance, err := reportService.TrialBalance(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(trialBalance)
+	})
+
+	mux.HandleFunc("GET /reports/chart-of-accounts", func(w http.ResponseWriter, r *http.Request) {
+		summaries, err := reportService.ChartOfAccounts(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, summaries)
+	})
+
+	mux.HandleFunc("GET /reports/invariants", func(w http.ResponseWriter, r *http.Request) {
+		report, err := reportService.VerifyInvariants(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// registerDailyReportRoutes serves daily totals from the projection; date defaults to today (UTC)
+func registerDailyReportRoutes(mux *http.ServeMux, projection *reports.DailyProjection, clk clock.Clock) {
+	mux.HandleFunc("GET /reports/daily", func(w http.ResponseWriter, r *http.Request) {
+		date := r.URL.Query().Get("date")
+		if date == "" {
+			date = clk.Now().UTC().Format("2006-01-02")
+		}
+		top := 10
+		if value := r.URL.Query().Get("top"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				http.Error(w, "top must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			top = parsed
+		}
+
+		report, err := projection.Report(r.Context(), date, top)
+		if errors.Is(err, reports.ErrInvalidDate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// registerDiscrepancyRoutes lists what the balance checker found and repairs it, for callers
+// with the admin token. Filters: status (open or resolved), account_id, limit
+func registerDiscrepancyRoutes(mux *http.ServeMux, checker *reports.BalanceChecker, token string) {
+	// Rebuilds the balance from the account's entries and reports the delta corrected
+	mux.HandleFunc("POST /admin/accounts/{id}/recompute-balance", requireAdmin(token, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repair, err := checker.Repair(r.Context(), r.PathValue("id"))
+		if errors.Is(err, reports.ErrBalanceNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, repair)
+	})))
+
+	mux.HandleFunc("GET /admin/discrepancies", requireAdmin(token, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := models.DiscrepancyFilter{AccountID: query.Get("account_id")}
+		switch status := query.Get("status"); status {
+		case "":
+		case "open", "resolved":
+			open := status == "open"
+			filter.Open = &open
+		default:
+			http.Error(w, "status must be open or resolved", http.StatusBadRequest)
+			return
+		}
+		if limit := query.Get("limit"); limit != "" {
+			parsed, err := strconv.Atoi(limit)
+			if err != nil || parsed < 1 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			filter.Limit = parsed
+		}
+
+		discrepancies, err := checker.List(r.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, discrepancies)
+	})))
+}
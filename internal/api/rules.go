@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func ruleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrRuleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrInvalidRule):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrRulesNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerRuleRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger) {
+	mux.HandleFunc("GET /rules", func(w http.ResponseWriter, r *http.Request) {
+		rules, err := ledgerService.ListRules(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), ruleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, rules)
+	})
+
+	saveRule := func(w http.ResponseWriter, r *http.Request) {
+		var rule models.FraudRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if id := r.PathValue("id"); id != "" {
+			rule.ID = id
+		}
+
+		saved, err := ledgerService.SaveRule(r.Context(), rule)
+		if err != nil {
+			http.Error(w, err.Error(), ruleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}
+	mux.HandleFunc("POST /rules", saveRule)
+	mux.HandleFunc("PUT /rules/{id}", saveRule)
+
+	mux.HandleFunc("DELETE /rules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := ledgerService.DeleteRule(r.Context(), r.PathValue("id")); err != nil {
+			http.Error(w, err.Error(), ruleErrorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Dry run: shows which rules a transaction would trigger without posting it
+	mux.HandleFunc("POST /rules/evaluate", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			FromAccount string          `json:"from_account"`
+			ToAccount   string          `json:"to_account"`
+			Amount      decimal.Decimal `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		decision, err := ledgerService.EvaluateRules(r.Context(), models.Transaction{
+			FromAccount: req.FromAccount,
+			ToAccount:   req.ToAccount,
+			Amount:      req.Amount,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), ruleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, decision)
+	})
+}
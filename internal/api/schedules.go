@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+)
+
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, schedules.ErrScheduleNotFound), errors.Is(err, schedules.ErrPendingNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, schedules.ErrInvalidSchedule), errors.Is(err, schedules.ErrIdempotencyKey):
+		return http.StatusBadRequest
+	case errors.Is(err, schedules.ErrNotActive), errors.Is(err, schedules.ErrPendingNotPending):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registerScheduleRoutes(mux *http.ServeMux, scheduleService *schedules.Service) {
+	mux.HandleFunc("POST /schedules", func(w http.ResponseWriter, r *http.Request) {
+		var schedule models.Schedule
+		if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		created, err := scheduleService.Create(r.Context(), schedule)
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	})
+
+	mux.HandleFunc("GET /schedules", func(w http.ResponseWriter, r *http.Request) {
+		list, err := scheduleService.List(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("GET /schedules/{id}", func(w http.ResponseWriter, r *http.Request) {
+		schedule, err := scheduleService.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, schedule)
+	})
+
+	mux.HandleFunc("POST /schedules/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		schedule, err := scheduleService.Cancel(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, schedule)
+	})
+
+	// Future-dated transactions, optionally filtered by ?status=pending
+	mux.HandleFunc("GET /transactions/pending", func(w http.ResponseWriter, r *http.Request) {
+		list, err := scheduleService.ListPayments(r.Context(), r.URL.Query().Get("status"))
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+
+	mux.HandleFunc("GET /transactions/pending/{id}", func(w http.ResponseWriter, r *http.Request) {
+		pending, err := scheduleService.GetPayment(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, pending)
+	})
+
+	mux.HandleFunc("POST /transactions/pending/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		pending, err := scheduleService.CancelPayment(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, pending)
+	})
+}
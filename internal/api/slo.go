@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/slo"
+)
+
+func registerSLORoutes(mux *http.ServeMux, tracker *slo.Tracker) {
+	// Success rate, p99 and burn rates of postings over rolling windows, with the alerts
+	// firing. Kept per process: behind a load balancer each replica reports its own share.
+	mux.HandleFunc("GET /admin/slo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, tracker.Summary(time.Now()))
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/statements"
+)
+
+// parseDateParam accepts either a date (2006-01-02, midnight UTC) or an RFC3339 timestamp
+func parseDateParam(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+type statementFormat struct {
+	contentType string
+	extension   string
+	render      func(io.Writer, statements.Statement) error
+}
+
+// statementFormats lists the formats a statement can be downloaded in; mt940 and camt053
+// are the bank formats ERP and treasury systems import directly
+var statementFormats = map[string]statementFormat{
+	"csv":     {"text/csv", "csv", statements.WriteCSV},
+	"pdf":     {"application/pdf", "pdf", statements.WritePDF},
+	"mt940":   {"text/plain", "sta", statements.WriteMT940},
+	"camt053": {"application/xml", "xml", statements.WriteCAMT053},
+}
+
+func registerStatementRoutes(mux *http.ServeMux, statementService *statements.Service, clk clock.Clock, appLogger *slog.Logger) {
+	// from defaults to 30 days before to, which defaults to now; include_archived=true also
+	// lists entries already moved to cold storage
+	mux.HandleFunc("GET /accounts/{id}/statement", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		accountId := r.PathValue("id")
+
+		to := clk.Now().UTC()
+		if value := query.Get("to"); value != "" {
+			parsed, err := parseDateParam(value)
+			if err != nil {
+				http.Error(w, "to must be a date or RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			to = parsed
+		}
+		from := to.AddDate(0, 0, -30)
+		if value := query.Get("from"); value != "" {
+			parsed, err := parseDateParam(value)
+			if err != nil {
+				http.Error(w, "from must be a date or RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			from = parsed
+		}
+
+		format := query.Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		write, ok := statementFormats[format]
+		if !ok {
+			http.Error(w, "format must be csv, pdf, mt940 or camt053", http.StatusBadRequest)
+			return
+		}
+
+		includeArchived := query.Get("include_archived") == "true"
+		statement, err := statementService.Generate(r.Context(), accountId, from, to, includeArchived)
+		if errors.Is(err, statements.ErrInvalidRange) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		filename := fmt.Sprintf("statement-%s-%s-%s.%s", accountId, from.Format("20060102"), to.Format("20060102"), write.extension)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Content-Type", write.contentType)
+		if err := write.render(w, statement); err != nil {
+			// Headers are already sent; all we can do is log
+			appLogger.Error("failed to write statement", "account_id", accountId, "error", err)
+		}
+	})
+}
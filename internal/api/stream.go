@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/stream"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+const sseHeartbeat = 15 * time.Second
+
+func registerStreamRoutes(mux *http.ServeMux, hub *stream.Hub) {
+	// Server-sent events of committed entries with the account balance after each one.
+	// Without account_id every account is streamed. Events carry no id: the hub keeps no
+	// history to resume from, so a client that reconnects re-reads its balances instead.
+	mux.HandleFunc("GET /stream/entries", func(w http.ResponseWriter, r *http.Request) {
+		controller := http.NewResponseController(w)
+
+		sub := hub.Subscribe(r.URL.Query().Get("account_id"))
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		if err := controller.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				// Comment lines keep proxies from closing an idle connection
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case update, open := <-sub.Updates:
+				if !open {
+					// Dropped for falling behind; the client reconnects and re-reads balances
+					return
+				}
+				if tenantId := tenant.FromContext(r.Context()); update.Entry.TenantID != tenantId && (tenantId != "" || livemode.IsTestTenant(update.Entry.TenantID)) {
+					continue
+				}
+				data, err := json.Marshal(update)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: entry\ndata: %s\n\n", data)
+			}
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+)
+
+func suspenseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrInvalidSuspenseItem):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrSuspenseItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrSuspenseItemNotOpen):
+		return http.StatusConflict
+	case errors.Is(err, ledger.ErrSuspenseNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// registerSuspenseRoutes lets operators see what failed credits left in the suspense
+// account and release each item to its final destination. All of them need the admin token.
+func registerSuspenseRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, adminToken string) {
+	mux.HandleFunc("GET /admin/suspense", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items, err := ledgerService.ListSuspenseItems(r.Context(), r.URL.Query().Get("status"))
+		if err != nil {
+			http.Error(w, err.Error(), suspenseErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, items)
+	})))
+
+	mux.HandleFunc("GET /admin/suspense/{id}", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		item, err := ledgerService.GetSuspenseItem(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), suspenseErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	})))
+
+	// The body is optional: without an account_id the item goes to the account it was meant for
+	mux.HandleFunc("POST /admin/suspense/{id}/resolve", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			AccountID string `json:"account_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		item, err := ledgerService.ResolveSuspenseItem(r.Context(), r.PathValue("id"), req.AccountID)
+		if err != nil {
+			status := suspenseErrorStatus(err)
+			if status == http.StatusInternalServerError {
+				// The release itself was rejected, e.g. the destination is still closed
+				writePostingError(w, err)
+				return
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	})))
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+)
+
+// requestAccounts returns the accounts a request reads or changes: the {id} of /accounts/{id}/...
+// routes and the account_id query parameter used by balance, export and stream endpoints
+func requestAccounts(r *http.Request) []string {
+	var ids []string
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/accounts/"); ok {
+		if id, _, _ := strings.Cut(rest, "/"); id != "" && id != "balance" {
+			ids = append(ids, id)
+		}
+	}
+	if id := r.URL.Query().Get("account_id"); id != "" {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// TenantAccountGuard answers 404 for accounts of another tenant, so tenants cannot even
+// learn which account IDs exist elsewhere. Runs after tenant.Middleware has resolved the tenant.
+func TenantAccountGuard(ledgerService *ledger.Ledger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, id := range requestAccounts(r) {
+			err := ledgerService.CheckAccountAccess(r.Context(), id)
+			if errors.Is(err, ledger.ErrTenantMismatch) {
+				http.Error(w, "account not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PlatformAccess tells tenant.Middleware which requests may run without a tenant, and so
+// see every tenant's data: the health and metrics probes, peer instances calling the
+// /internal/2pc routes with the two-phase token, and anything made with the admin token.
+func PlatformAccess(adminToken, twoPhaseToken string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		switch {
+		case r.URL.Path == "/health" || r.URL.Path == "/metrics":
+			return true
+		case strings.HasPrefix(r.URL.Path, "/internal/2pc/"):
+			return twoPhaseToken != "" && subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(twoPhaseToken)) == 1
+		default:
+			return adminAuthorized(r, adminToken)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/postgres"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/validation"
+	"github.com/shopspring/decimal"
+)
+
+func registerTransactionRoutes(mux *http.ServeMux, ledgerService *ledger.Ledger, adminToken string) {
+	// Search newest first, e.g. /transactions?reference=INV-1&metadata.order_id=42&tag=payroll-2024-06
+	// or /transactions?account=wallet-7&min_amount=100&from=2024-06-01&to=2024-07-01&status=posted.
+	// When more match than limit, X-Next-Cursor holds the cursor parameter of the next page.
+	mux.HandleFunc("GET /transactions", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := transactionFilter(r.URL.Query())
+		if writeValidationError(w, err) {
+			return
+		}
+
+		transactions, next, err := ledgerService.SearchTransactions(r.Context(), filter)
+		if writeValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, ledger.ErrSearchNotSupported) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if next != nil {
+			w.Header().Set("X-Next-Cursor", next.String())
+		}
+		writeJSON(w, http.StatusOK, transactions)
+	})
+
+	mux.HandleFunc("POST /transactions/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		tx, err := ledgerService.TagTransaction(r.Context(), r.PathValue("id"), req.Tags)
+		if err != nil {
+			http.Error(w, err.Error(), tagErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, tx)
+	})
+
+	// Posts the mirror image of the transaction, within the reversal window and role rules
+	mux.HandleFunc("POST /transactions/{id}/reverse", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+
+		reversal, err := ledgerService.ReverseTransaction(r.Context(), r.PathValue("id"), req.Reason)
+		if err != nil {
+			writeReversalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, reversal)
+	})
+
+	// Incident remediation: reverses the listed transactions and those matching filter, given in
+	// the query syntax of GET /transactions (e.g. "reference=batch-42&from=2024-06-01").
+	// Needs the admin token.
+	mux.HandleFunc("POST /admin/reversals", requireAdmin(adminToken, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TransactionIDs      []string `json:"transaction_ids"`
+			Filter              string   `json:"filter"`
+			AdjustmentReference string   `json:"adjustment_reference"`
+			Reason              string   `json:"reason"`
+			DryRun              bool     `json:"dry_run"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		batch := models.ReversalBatch{
+			TransactionIDs:      req.TransactionIDs,
+			AdjustmentReference: req.AdjustmentReference,
+			Reason:              req.Reason,
+			DryRun:              req.DryRun,
+		}
+		if req.Filter != "" {
+			query, err := url.ParseQuery(req.Filter)
+			if err != nil {
+				http.Error(w, "filter must be a query string", http.StatusBadRequest)
+				return
+			}
+			// The batch pages through every match itself
+			query.Del("cursor")
+			query.Del("limit")
+			filter, err := transactionFilter(query)
+			if writeValidationError(w, err) {
+				return
+			}
+			batch.Filter = &filter
+		}
+
+		result, err := ledgerService.ReverseTransactions(r.Context(), batch)
+		if writeValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, ledger.ErrInvalidReversalBatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeReversalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})))
+
+	mux.HandleFunc("DELETE /transactions/{id}/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := ledgerService.UntagTransaction(r.Context(), r.PathValue("id"), r.PathValue("tag"))
+		if err != nil {
+			http.Error(w, err.Error(), tagErrorStatus(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, tx)
+	})
+}
+
+// writePostingError answers a rejected or failed posting with the status that tells the
+// client whether changing the request, waiting or retrying can help
+func writePostingError(w http.ResponseWriter, err error) {
+	if writeValidationError(w, err) {
+		return
+	}
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, ledger.ErrPeriodClosed), errors.Is(err, ledger.ErrPossibleDuplicate), errors.Is(err, ledger.ErrCurrencyChanged),
+		errors.Is(err, ledger.ErrPaymentRequestNotOpen), errors.Is(err, postgres.ErrPaymentRequestClosed),
+		errors.Is(err, postgres.ErrPeriodClosed):
+		status = http.StatusConflict
+	case errors.Is(err, ledger.ErrAccountFrozen), errors.Is(err, ledger.ErrAccountClosed),
+		errors.Is(err, ledger.ErrTransactionBlocked), errors.Is(err, ledger.ErrTenantMismatch),
+		errors.Is(err, ledger.ErrCrossTenantTransfer), errors.Is(err, ledger.ErrLiveAccount):
+		status = http.StatusForbidden
+	case errors.Is(err, postgres.ErrRetriesExhausted):
+		// The database kept failing transiently; the client may safely retry with the same key
+		w.Header().Set("Retry-After", "1")
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, ledger.ErrLimitExceeded), errors.Is(err, usage.ErrQuotaExceeded):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, ledger.ErrInsufficientFunds), errors.Is(err, ledger.ErrRateUnavailable),
+		errors.Is(err, ledger.ErrInvalidFXRate), errors.Is(err, ledger.ErrFXRateTolerance), errors.Is(err, ledger.ErrAbnormalBalance),
+		errors.Is(err, ledger.ErrPaymentRequestMismatch), errors.Is(err, ledger.ErrFutureDated):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, ledger.ErrPaymentRequestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ledger.ErrPaymentRequestsNotSupported):
+		status = http.StatusNotImplemented
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// transactionFilter reads the search parameters, reporting every malformed one
+func transactionFilter(query url.Values) (models.TransactionFilter, error) {
+	filter := models.TransactionFilter{
+		Reference:      query.Get("reference"),
+		Metadata:       make(map[string]string),
+		Tags:           query["tag"],
+		Account:        query.Get("account"),
+		Status:         query.Get("status"),
+		IdempotencyKey: query.Get("idempotency_key"),
+	}
+	for key, values := range query {
+		if name, ok := strings.CutPrefix(key, "metadata."); ok && name != "" {
+			filter.Metadata[name] = values[0]
+		}
+	}
+
+	var errs validation.Errors
+	for field, amount := range map[string]*decimal.Decimal{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if value := query.Get(field); value != "" {
+			parsed, err := decimal.NewFromString(value)
+			if err != nil {
+				errs = append(errs, validation.FieldError{Field: field, Message: "must be a number"})
+				continue
+			}
+			*amount = parsed
+		}
+	}
+	for field, at := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(field); value != "" {
+			parsed, err := parseSearchTime(value)
+			if err != nil {
+				errs = append(errs, validation.FieldError{Field: field, Message: "must be a date (2006-01-02) or an RFC 3339 time"})
+				continue
+			}
+			*at = parsed
+		}
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := models.ParseTransactionCursor(cursor)
+		if err != nil {
+			errs = append(errs, validation.FieldError{Field: "cursor", Message: err.Error()})
+		} else {
+			filter.After = &after
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			errs = append(errs, validation.FieldError{Field: "limit", Message: "must be a number"})
+		}
+		filter.Limit = n
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b validation.FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return filter, errs
+	}
+	return filter, nil
+}
+
+func parseSearchTime(value string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// writeValidationError answers with every invalid field, so the client can fix them in one go.
+// It reports false when err is not a validation error.
+func writeValidationError(w http.ResponseWriter, err error) bool {
+	var invalid validation.Errors
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]any{
+		"error":  validation.ErrInvalid.Error(),
+		"fields": invalid,
+	})
+	return true
+}
+
+// writeReversalError answers a refused reversal; anything the policy did not catch was
+// rejected by the posting itself
+func writeReversalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ledger.ErrTransactionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ledger.ErrReversalNotPermitted):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ledger.ErrReversalWindowExpired), errors.Is(err, ledger.ErrReversalPeriodClosed),
+		errors.Is(err, ledger.ErrCannotReverse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ledger.ErrSearchNotSupported):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		writePostingError(w, err)
+	}
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrInvalidTag), errors.Is(err, ledger.ErrTooManyTags):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledger.ErrTagsNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
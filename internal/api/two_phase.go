@@ -0,0 +1,144 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/twophase"
+	"github.com/shopspring/decimal"
+)
+
+func twoPhaseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, twophase.ErrInvalidLeg), errors.Is(err, twophase.ErrInvalidTransfer),
+		errors.Is(err, twophase.ErrUnknownPeer):
+		return http.StatusBadRequest
+	case errors.Is(err, twophase.ErrUnknownLeg), errors.Is(err, twophase.ErrTransferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, twophase.ErrLegConflict):
+		return http.StatusConflict
+	default:
+		return 0
+	}
+}
+
+// writeTwoPhaseError answers with the two-phase status of err, or as a rejected posting
+func writeTwoPhaseError(w http.ResponseWriter, err error) {
+	if status := twoPhaseErrorStatus(err); status != 0 {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	writePostingError(w, err)
+}
+
+// registerTwoPhaseRoutes serves this instance's legs to coordinators on other instances,
+// and cross-instance transfers to clients. The /internal routes require token and are
+// refused while it is empty.
+func registerTwoPhaseRoutes(mux *http.ServeMux, participant *twophase.Participant, coordinator *twophase.Coordinator, token string) {
+	internal := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if token == "" || subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			handler(w, r)
+		}
+	}
+
+	mux.HandleFunc("POST /internal/2pc/prepare", internal(func(w http.ResponseWriter, r *http.Request) {
+		var leg models.TwoPhaseLeg
+		if err := json.NewDecoder(r.Body).Decode(&leg); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		prepared, err := participant.Prepare(r.Context(), leg)
+		if err != nil {
+			writeTwoPhaseError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, prepared)
+	}))
+
+	mux.HandleFunc("POST /internal/2pc/{xid}/commit", internal(func(w http.ResponseWriter, r *http.Request) {
+		leg, err := participant.Commit(r.Context(), r.PathValue("xid"))
+		if err != nil {
+			writeTwoPhaseError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, leg)
+	}))
+
+	mux.HandleFunc("POST /internal/2pc/{xid}/abort", internal(func(w http.ResponseWriter, r *http.Request) {
+		leg, err := participant.Abort(r.Context(), r.PathValue("xid"))
+		if err != nil {
+			writeTwoPhaseError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, leg)
+	}))
+
+	mux.HandleFunc("GET /internal/2pc/{xid}", internal(func(w http.ResponseWriter, r *http.Request) {
+		leg, err := participant.Get(r.Context(), r.PathValue("xid"))
+		if err != nil {
+			writeTwoPhaseError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, leg)
+	}))
+
+	// 201 when the transfer committed, 422 with the reason when it was aborted
+	mux.HandleFunc("POST /transfers/cross-instance", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IdempotencyKey string          `json:"idempotency_key"`
+			FromAccount    string          `json:"from_account"`
+			ToInstance     string          `json:"to_instance"`
+			ToAccount      string          `json:"to_account"`
+			Amount         decimal.Decimal `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		transfer, err := coordinator.Transfer(r.Context(), models.CrossInstanceTransfer{
+			IdempotencyKey: req.IdempotencyKey,
+			FromAccount:    req.FromAccount,
+			ToInstance:     req.ToInstance,
+			ToAccount:      req.ToAccount,
+			Amount:         req.Amount,
+		})
+		if err != nil {
+			if status := twoPhaseErrorStatus(err); status != 0 {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		switch transfer.Status {
+		case models.TwoPhaseCommitted:
+			writeJSON(w, http.StatusCreated, transfer)
+		case models.TwoPhaseAborted:
+			writeJSON(w, http.StatusUnprocessableEntity, transfer)
+		default:
+			// Still preparing under an earlier request with the same key
+			writeJSON(w, http.StatusAccepted, transfer)
+		}
+	})
+
+	mux.HandleFunc("GET /transfers/cross-instance/{id}", func(w http.ResponseWriter, r *http.Request) {
+		transfer, err := coordinator.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			if status := twoPhaseErrorStatus(err); status != 0 {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, transfer)
+	})
+}
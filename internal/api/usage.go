@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+)
+
+func registerUsageRoutes(mux *http.ServeMux, meter *usage.Meter, clk clock.Clock) {
+	// Daily API calls and posted transactions per tenant and API key. from and to are dates,
+	// to excluded; they default to the current month. A tenant only ever sees its own usage;
+	// platform calls may filter by tenant_id. key_id filters by API key fingerprint.
+	mux.HandleFunc("GET /usage", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		now := clk.Now().UTC()
+		filter := models.UsageFilter{
+			TenantID: query.Get("tenant_id"),
+			KeyID:    query.Get("key_id"),
+			From:     time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		}
+		for name, field := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+			value := query.Get(name)
+			if value == "" {
+				continue
+			}
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				http.Error(w, name+" must be a date written as YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			*field = parsed
+		}
+
+		records, err := meter.Usage(r.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, records)
+	})
+}
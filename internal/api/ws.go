@@ -0,0 +1,36 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/stream"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ws"
+)
+
+// bearerToken reads the token from the Authorization header, or from the token query
+// parameter since browsers cannot set headers on WebSocket requests.
+func bearerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// registerWebSocketRoutes requires token and is refused while it is empty
+func registerWebSocketRoutes(mux *http.ServeMux, hub *stream.Hub, token string, maxSubscriptions int) {
+	// Clients send {"action":"subscribe","account_ids":[...]} and receive balance_changed messages
+	mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		stream.NewBalanceSession(conn, hub, maxSubscriptions).Run()
+	})
+}
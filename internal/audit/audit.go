@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/httputil"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// maxRecordedBody caps how much of a request payload is copied into the audit log
+const maxRecordedBody = 64 << 10
+
+// personalFields are request fields and path wildcards that may identify a person. The audit
+// log is append-only, so they are masked before recording rather than erased afterwards.
+var personalFields = []string{"holder_name", "holder_email", "alias", "from_alias", "to_alias"}
+
+type requestInfoKey struct{}
+
+// RequestInfo identifies who made an API call, carried through the request context
+type RequestInfo struct {
+	RequestID string
+	Actor     string
+	Roles     []string // what the actor may do, e.g. "ops" or "finance"
+}
+
+// HasRole reports whether the caller holds any of roles
+func (i RequestInfo) HasRole(roles ...string) bool {
+	return slices.ContainsFunc(roles, func(role string) bool { return slices.Contains(i.Roles, role) })
+}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// FromContext returns the caller of the current request, or a "system" actor for background jobs
+func FromContext(ctx context.Context) RequestInfo {
+	if info, ok := ctx.Value(requestInfoKey{}).(RequestInfo); ok {
+		return info
+	}
+	return RequestInfo{Actor: "system"}
+}
+
+// Log records state changes into the audit store
+type Log struct {
+	store     interfaces.AuditStore
+	clock     clock.Clock
+	appLogger *slog.Logger
+}
+
+func NewLog(store interfaces.AuditStore, appLogger *slog.Logger) *Log {
+	return &Log{
+		store:     store,
+		clock:     clock.System{},
+		appLogger: appLogger,
+	}
+}
+
+// SetClock stamps records by c, normally the ledger's clock, so they line up with the
+// changes they record
+func (a *Log) SetClock(c clock.Clock) {
+	a.clock = c
+}
+
+// Record appends a domain-level change with before/after snapshots of the resource.
+// Failures are logged but never fail the operation being audited.
+func (a *Log) Record(ctx context.Context, action, resource string, before, after any) {
+	info := FromContext(ctx)
+	record := models.AuditRecord{
+		RequestID: info.RequestID,
+		Actor:     info.Actor,
+		Action:    action,
+		Resource:  resource,
+		Before:    marshal(before),
+		After:     marshal(after),
+		CreatedAt: a.clock.Now().UTC(),
+	}
+	a.append(ctx, record)
+}
+
+func (a *Log) Query(ctx context.Context, filter models.AuditFilter) ([]models.AuditRecord, error) {
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	return a.store.QueryAudit(ctx, filter)
+}
+
+func (a *Log) append(ctx context.Context, record models.AuditRecord) {
+	// The audit row must be written even if the client went away mid-request
+	if err := a.store.AppendAudit(context.WithoutCancel(ctx), record); err != nil {
+		a.appLogger.Error("failed to write audit record",
+			"action", record.Action,
+			"resource", record.Resource,
+			"request_id", record.RequestID,
+			"error", err,
+		)
+	}
+}
+
+func marshal(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// redactPayload masks the personal fields of a JSON object; anything else is kept as sent
+func redactPayload(body []byte) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+	redacted := false
+	for _, field := range personalFields {
+		if value, ok := fields[field]; ok && string(value) != `""` && string(value) != "null" {
+			fields[field] = json.RawMessage(`"[redacted]"`)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	masked, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return masked
+}
+
+// errReader fails every read with err, or reports EOF when err is nil
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	return 0, io.EOF
+}
+
+// Middleware tags every request with a request ID and actor, and writes an audit
+// record for every state-changing call (anything but GET, HEAD and OPTIONS).
+// The actor and its roles are taken from the X-Actor and X-Actor-Roles (comma-separated)
+// headers until real authentication exists.
+func (a *Log) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := RequestInfo{
+			RequestID: r.Header.Get("X-Request-ID"),
+			Actor:     r.Header.Get("X-Actor"),
+		}
+		for role := range strings.SplitSeq(r.Header.Get("X-Actor-Roles"), ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				info.Roles = append(info.Roles, role)
+			}
+		}
+		if info.RequestID == "" {
+			info.RequestID = uuid.New().String()
+		}
+		if info.Actor == "" {
+			info.Actor = "anonymous"
+		}
+		w.Header().Set("X-Request-ID", info.RequestID)
+		r = r.WithContext(WithRequestInfo(r.Context(), info))
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Keep a copy of the payload and hand the handler an untouched body
+		var payload []byte
+		if r.Body != nil && !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			// A read error (such as a body over the size limit) is handed on to the handler
+			r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+			if len(body) <= maxRecordedBody && json.Valid(body) {
+				payload = redactPayload(body)
+			}
+		}
+
+		recorder := httputil.NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		action := r.Pattern
+		if action == "" {
+			action = r.Method + " " + r.URL.Path
+		}
+		resource := r.URL.Path
+		for _, field := range personalFields {
+			if value := r.PathValue(field); value != "" {
+				resource = strings.ReplaceAll(resource, value, "[redacted]")
+			}
+		}
+		a.append(r.Context(), models.AuditRecord{
+			RequestID: info.RequestID,
+			Actor:     info.Actor,
+			Action:    action,
+			Resource:  resource,
+			After:     payload,
+			Status:    recorder.Status,
+			CreatedAt: a.clock.Now().UTC(),
+		})
+	})
+}
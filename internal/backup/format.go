@@ -0,0 +1,232 @@
+// Package backup writes and reads ledger backups: gzip-compressed JSON lines with every
+// transaction and entry. Each section ends with a marker holding the running counts and a
+// SHA-256 over every line before it, so a restore can prove nothing was lost or altered.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const Version = 1
+
+const (
+	SectionTransactions = "transactions"
+	SectionEntries      = "entries"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	ErrCorrupt            = errors.New("backup is corrupt")
+	ErrTruncated          = errors.New("backup is truncated")
+)
+
+// Marker closes a section. The entries marker is the last line of a complete backup.
+type Marker struct {
+	Section      string    `json:"section"`
+	Transactions int64     `json:"transactions"`
+	Entries      int64     `json:"entries"`
+	SHA256       string    `json:"sha256"`
+	SnapshotAt   time.Time `json:"snapshot_at,omitzero"` // the database snapshot the backup was read from
+	MaxSequence  int64     `json:"max_seq,omitempty"`
+}
+
+// entry is the line format of a ledger entry; models.LedgerEntry carries no JSON tags
+type entry struct {
+	ID            string          `json:"id"`
+	Sequence      int64           `json:"seq"`
+	TransactionID string          `json:"transaction_id"`
+	AccountID     string          `json:"account_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PrevHash      string          `json:"prev_hash"`
+	Hash          string          `json:"hash"`
+	TenantID      string          `json:"tenant_id"`
+}
+
+type record struct {
+	Type        string              `json:"type"` // header, transaction, entry or marker
+	Version     int                 `json:"version,omitempty"`
+	CreatedAt   time.Time           `json:"created_at,omitzero"`
+	Transaction *models.Transaction `json:"transaction,omitempty"`
+	Entry       *entry              `json:"entry,omitempty"`
+	Marker      *Marker             `json:"marker,omitempty"`
+}
+
+// Writer writes a backup; transactions must all come before the first entry
+type Writer struct {
+	zw      *gzip.Writer
+	sum     hash.Hash
+	out     io.Writer
+	section string
+	marker  Marker
+}
+
+func NewWriter(w io.Writer) (*Writer, error) {
+	zw := gzip.NewWriter(w)
+	sum := sha256.New()
+	bw := &Writer{zw: zw, sum: sum, out: io.MultiWriter(zw, sum), section: SectionTransactions}
+	return bw, bw.write(record{Type: "header", Version: Version, CreatedAt: time.Now().UTC()})
+}
+
+func (w *Writer) write(r record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(append(line, '\n'))
+	return err
+}
+
+func (w *Writer) Transaction(tx models.Transaction) error {
+	if w.section != SectionTransactions {
+		return fmt.Errorf("backup: transaction %s written after the entries", tx.ID)
+	}
+	w.marker.Transactions++
+	return w.write(record{Type: "transaction", Transaction: &tx})
+}
+
+func (w *Writer) Entry(e models.LedgerEntry) error {
+	if w.section == SectionTransactions {
+		if err := w.closeSection(); err != nil {
+			return err
+		}
+		w.section = SectionEntries
+	}
+	w.marker.Entries++
+	w.marker.MaxSequence = max(w.marker.MaxSequence, e.Sequence)
+	return w.write(record{Type: "entry", Entry: &entry{
+		ID:            e.ID,
+		Sequence:      e.Sequence,
+		TransactionID: e.TransactionID,
+		AccountID:     e.AccountID,
+		Amount:        e.Amount,
+		CreatedAt:     e.CreatedAt,
+		PrevHash:      e.PrevHash,
+		Hash:          e.Hash,
+		TenantID:      e.TenantID,
+	}})
+}
+
+func (w *Writer) closeSection() error {
+	w.marker.Section = w.section
+	w.marker.SHA256 = hex.EncodeToString(w.sum.Sum(nil))
+	return w.write(record{Type: "marker", Marker: &w.marker})
+}
+
+// Close writes the final marker and flushes the compressor; it does not close the
+// underlying writer. It returns the final marker.
+func (w *Writer) Close(snapshotAt time.Time) (Marker, error) {
+	if w.section == SectionTransactions {
+		if err := w.closeSection(); err != nil {
+			return Marker{}, err
+		}
+		w.section = SectionEntries
+	}
+	w.marker.SnapshotAt = snapshotAt
+	if err := w.closeSection(); err != nil {
+		return Marker{}, err
+	}
+	return w.marker, w.zw.Close()
+}
+
+// Scan reads a backup, verifying every marker, and calls the visitors (either may be nil)
+// for each transaction and entry. It returns the final marker; a backup without one is
+// reported as ErrTruncated. Visitors may already have run when verification fails, so
+// callers that load data should Scan once without visitors first.
+func Scan(r io.Reader, transaction func(models.Transaction) error, visit func(models.LedgerEntry) error) (Marker, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return Marker{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	defer zr.Close()
+
+	sum := sha256.New()
+	var counts Marker
+	var final *Marker
+	reader := bufio.NewReader(zr)
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(raw) == 0 {
+			break
+		}
+		if err != nil {
+			return Marker{}, fmt.Errorf("%w: line %d: %v", ErrTruncated, line, err)
+		}
+		if final != nil {
+			return Marker{}, fmt.Errorf("%w: line %d follows the final marker", ErrCorrupt, line)
+		}
+
+		var rec record
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return Marker{}, fmt.Errorf("%w: line %d: %v", ErrCorrupt, line, err)
+		}
+
+		switch {
+		case line == 1:
+			if rec.Type != "header" {
+				return Marker{}, fmt.Errorf("%w: missing header", ErrCorrupt)
+			}
+			if rec.Version != Version {
+				return Marker{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, rec.Version)
+			}
+		case rec.Type == "transaction" && rec.Transaction != nil:
+			counts.Transactions++
+			if transaction != nil {
+				if err := transaction(*rec.Transaction); err != nil {
+					return Marker{}, err
+				}
+			}
+		case rec.Type == "entry" && rec.Entry != nil:
+			counts.Entries++
+			if visit != nil {
+				e := rec.Entry
+				err := visit(models.LedgerEntry{
+					ID:            e.ID,
+					TransactionID: e.TransactionID,
+					AccountID:     e.AccountID,
+					Amount:        e.Amount,
+					CreatedAt:     e.CreatedAt,
+					Sequence:      e.Sequence,
+					PrevHash:      e.PrevHash,
+					Hash:          e.Hash,
+					TenantID:      e.TenantID,
+				})
+				if err != nil {
+					return Marker{}, err
+				}
+			}
+		case rec.Type == "marker" && rec.Marker != nil:
+			marker := rec.Marker
+			if marker.SHA256 != hex.EncodeToString(sum.Sum(nil)) {
+				return Marker{}, fmt.Errorf("%w: checksum of the %s section does not match", ErrCorrupt, marker.Section)
+			}
+			if marker.Transactions != counts.Transactions || marker.Entries != counts.Entries {
+				return Marker{}, fmt.Errorf("%w: %s marker counts %d transactions and %d entries, read %d and %d", ErrCorrupt,
+					marker.Section, marker.Transactions, marker.Entries, counts.Transactions, counts.Entries)
+			}
+			if marker.Section == SectionEntries {
+				final = marker
+			}
+		default:
+			return Marker{}, fmt.Errorf("%w: line %d has unknown record type %q", ErrCorrupt, line, rec.Type)
+		}
+		sum.Write(raw)
+	}
+
+	if final == nil {
+		return Marker{}, ErrTruncated
+	}
+	return *final, nil
+}
@@ -0,0 +1,258 @@
+// Package calendar knows which days each currency settles on - every day but its weekend
+// and its holidays - and rolls dates that fall on other days onto one that does.
+//
+// Dates are calendar days, carried as midnight UTC whatever the calendar's time zone; the
+// zone only decides which day an instant belongs to.
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const DateLayout = "2006-01-02"
+
+var (
+	ErrInvalidHoliday    = errors.New("invalid holiday")
+	ErrHolidayNotFound   = errors.New("holiday not found")
+	ErrInvalidConvention = errors.New("invalid business day convention")
+)
+
+// Convention says where a date that is not a business day moves to
+type Convention string
+
+const (
+	Following         Convention = "following"          // the next business day
+	ModifiedFollowing Convention = "modified_following" // the next one, unless it is in the next month: then the previous one
+	Preceding         Convention = "preceding"          // the previous business day
+)
+
+func ParseConvention(s string) (Convention, error) {
+	switch convention := Convention(strings.ToLower(strings.TrimSpace(s))); convention {
+	case Following, ModifiedFollowing, Preceding:
+		return convention, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidConvention, s)
+}
+
+// maxRoll bounds the search for a business day, so a calendar with every day off cannot loop forever
+const maxRoll = 366
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWeekends reads the weekend of every currency: "sat,sun;AED=sat,sun;ILS=fri,sat".
+// An item without a currency is the default; it is Saturday and Sunday when left out.
+func ParseWeekends(spec string) (map[string][]time.Weekday, error) {
+	weekends := map[string][]time.Weekday{"": {time.Saturday, time.Sunday}}
+	for item := range strings.SplitSeq(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		code, days, ok := strings.Cut(item, "=")
+		if !ok {
+			code, days = "", item
+		}
+		var weekend []time.Weekday
+		for day := range strings.SplitSeq(days, ",") {
+			if day = strings.ToLower(strings.TrimSpace(day)); day == "" {
+				continue
+			}
+			weekday, ok := weekdays[day]
+			if !ok {
+				return nil, fmt.Errorf("unknown weekday %q in %q", day, item)
+			}
+			weekend = append(weekend, weekday)
+		}
+		weekends[strings.ToUpper(strings.TrimSpace(code))] = weekend
+	}
+	return weekends, nil
+}
+
+// Calendar holds the weekends and a copy of the stored holidays
+type Calendar struct {
+	store     interfaces.HolidayStore
+	location  *time.Location
+	weekends  map[string][]time.Weekday // by currency; "" for every other currency
+	appLogger *slog.Logger
+
+	mu       sync.RWMutex
+	holidays map[string]map[string]string // currency -> date -> name
+}
+
+func New(store interfaces.HolidayStore, location *time.Location, weekends map[string][]time.Weekday, appLogger *slog.Logger) *Calendar {
+	return &Calendar{
+		store:     store,
+		location:  location,
+		weekends:  weekends,
+		appLogger: appLogger,
+		holidays:  map[string]map[string]string{},
+	}
+}
+
+// Load replaces the holidays held with the stored ones
+func (c *Calendar) Load(ctx context.Context) error {
+	stored, err := c.store.ListHolidays(ctx)
+	if err != nil {
+		return err
+	}
+	holidays := map[string]map[string]string{}
+	for _, holiday := range stored {
+		if holidays[holiday.Currency] == nil {
+			holidays[holiday.Currency] = map[string]string{}
+		}
+		holidays[holiday.Currency][holiday.Date] = holiday.Name
+	}
+	c.mu.Lock()
+	c.holidays = holidays
+	c.mu.Unlock()
+	return nil
+}
+
+// Run reloads the holidays on every tick, picking up the ones changed on other replicas
+func (c *Calendar) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Load(ctx); err != nil {
+				c.appLogger.Error("failed to reload the business day calendar", "error", err)
+			}
+		}
+	}
+}
+
+// Holidays lists the stored holidays, of one currency when it is given
+func (c *Calendar) Holidays(ctx context.Context, code string) ([]models.Holiday, error) {
+	holidays, err := c.store.ListHolidays(ctx)
+	if err != nil || code == "" {
+		return holidays, err
+	}
+	code = strings.ToUpper(code)
+	matched := []models.Holiday{}
+	for _, holiday := range holidays {
+		if holiday.Currency == code {
+			matched = append(matched, holiday)
+		}
+	}
+	return matched, nil
+}
+
+// AddHoliday stores a holiday, replacing the name of one already on that date
+func (c *Calendar) AddHoliday(ctx context.Context, holiday models.Holiday) (models.Holiday, error) {
+	holiday.Currency = strings.ToUpper(strings.TrimSpace(holiday.Currency))
+	if len(holiday.Currency) != 3 {
+		return models.Holiday{}, fmt.Errorf("%w: currency must be a three-letter code", ErrInvalidHoliday)
+	}
+	if _, err := time.Parse(DateLayout, holiday.Date); err != nil {
+		return models.Holiday{}, fmt.Errorf("%w: date must be written as YYYY-MM-DD", ErrInvalidHoliday)
+	}
+	if err := c.store.SaveHoliday(ctx, holiday); err != nil {
+		return models.Holiday{}, err
+	}
+	return holiday, c.Load(ctx)
+}
+
+func (c *Calendar) RemoveHoliday(ctx context.Context, code, date string) error {
+	code = strings.ToUpper(code)
+	deleted, err := c.store.DeleteHoliday(ctx, code, date)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s %s", ErrHolidayNotFound, code, date)
+	}
+	return c.Load(ctx)
+}
+
+// Date is the calendar day an instant falls on in the calendar's time zone
+func (c *Calendar) Date(t time.Time) time.Time {
+	year, month, day := t.In(c.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsBusinessDay reports whether every one of the currencies settles on the date
+func (c *Calendar) IsBusinessDay(date time.Time, currencies ...string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isBusinessDay(date, currencies)
+}
+
+func (c *Calendar) isBusinessDay(date time.Time, currencies []string) bool {
+	key := date.Format(DateLayout)
+	for _, code := range currencies {
+		code = strings.ToUpper(code)
+		weekend, ok := c.weekends[code]
+		if !ok {
+			weekend = c.weekends[""]
+		}
+		for _, day := range weekend {
+			if date.Weekday() == day {
+				return false
+			}
+		}
+		if _, holiday := c.holidays[code][key]; holiday {
+			return false
+		}
+	}
+	return true
+}
+
+// Roll moves a date onto a business day of every one of the currencies by the convention;
+// a business day is returned unchanged
+func (c *Calendar) Roll(date time.Time, convention Convention, currencies ...string) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	switch convention {
+	case Preceding:
+		return c.step(date, -1, currencies)
+	case ModifiedFollowing:
+		if rolled := c.step(date, 1, currencies); rolled.Month() == date.Month() {
+			return rolled
+		}
+		return c.step(date, -1, currencies)
+	default:
+		return c.step(date, 1, currencies)
+	}
+}
+
+// AddBusinessDays moves n business days forward, or back when n is negative; a start that
+// is not a business day is rolled forward first, so T+0 is the next business day
+func (c *Calendar) AddBusinessDays(date time.Time, n int, currencies ...string) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	date = c.step(date, 1, currencies)
+	direction := 1
+	if n < 0 {
+		direction, n = -1, -n
+	}
+	for range n {
+		date = c.step(date.AddDate(0, 0, direction), direction, currencies)
+	}
+	return date
+}
+
+// step returns the first business day from date on in the direction given
+func (c *Calendar) step(date time.Time, direction int, currencies []string) time.Time {
+	for range maxRoll {
+		if c.isBusinessDay(date, currencies) {
+			return date
+		}
+		date = date.AddDate(0, 0, direction)
+	}
+	return date
+}
@@ -0,0 +1,81 @@
+// Package chaos injects faults - slow requests, failed event publishes, failed database
+// commits - so the retry, dead-letter and idempotency machinery can be seen to hold up.
+// It is meant for development and staging only.
+package chaos
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+var ErrInjectedFault = errors.New("injected fault")
+
+var injected = metrics.NewCounterVec("chaos_faults_injected_total",
+	"Faults injected for resilience testing", "fault")
+
+// Config sets how often each fault strikes; rates are fractions between 0 and 1
+type Config struct {
+	Latency            time.Duration // added to the requests hit by LatencyRate
+	LatencyRate        float64
+	PublishFailureRate float64 // event publishes that fail as if the broker were down
+	CommitFailureRate  float64 // posting transactions rolled back with a transient error
+}
+
+// Enabled reports whether any fault is configured
+func (c Config) Enabled() bool {
+	return (c.Latency > 0 && c.LatencyRate > 0) || c.PublishFailureRate > 0 || c.CommitFailureRate > 0
+}
+
+// Hit decides whether a fault with the given rate strikes this time
+func Hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// Injected counts a fault that struck, so test runs can compare it with what recovered
+func Injected(fault string) {
+	injected.With(fault).Inc()
+}
+
+// Middleware delays a share of requests before they are handled
+func Middleware(config Config, next http.Handler) http.Handler {
+	if config.Latency <= 0 || config.LatencyRate <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Hit(config.LatencyRate) {
+			Injected("latency")
+			select {
+			case <-time.After(config.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Publisher fails a share of publishes without reaching the broker, so callers take
+// the same path as during a broker outage
+type Publisher struct {
+	next interfaces.EventPublisher
+	rate float64
+}
+
+func NewPublisher(next interfaces.EventPublisher, rate float64) *Publisher {
+	return &Publisher{next: next, rate: rate}
+}
+
+func (p *Publisher) Publish(topic string, event any) error {
+	if Hit(p.rate) {
+		Injected("publish")
+		return ErrInjectedFault
+	}
+	return p.next.Publish(topic, event)
+}
+
+var _ interfaces.EventPublisher = (*Publisher)(nil)
@@ -0,0 +1,161 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expression is a parsed five-field cron expression (minute hour day-of-month month day-of-week),
+// evaluated in UTC. Fields accept *, lists, ranges and steps, e.g. "*/15 9-17 * * 1-5".
+type Expression struct {
+	spec    string
+	minute  [60]bool
+	hour    [24]bool
+	dom     [32]bool
+	month   [13]bool
+	dow     [7]bool
+	domStar bool
+	dowStar bool
+	every   time.Duration // set by "@every <duration>", which fires at fixed intervals instead
+}
+
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Parse reads a cron expression, one of @hourly, @daily, @weekly, @monthly, @yearly,
+// or "@every <duration>" such as "@every 5m"
+func Parse(spec string) (*Expression, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 2 && fields[0] == "@every" {
+		every, err := time.ParseDuration(fields[1])
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", fields[1])
+		}
+		return &Expression{spec: spec, every: every}, nil
+	}
+	if len(fields) == 1 {
+		if expanded, ok := descriptors[fields[0]]; ok {
+			fields = strings.Fields(expanded)
+		}
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+
+	e := &Expression{spec: spec, domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	if err := parseField(fields[0], 0, 59, e.minute[:]); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if err := parseField(fields[1], 0, 23, e.hour[:]); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if err := parseField(fields[2], 1, 31, e.dom[:]); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if err := parseField(fields[3], 1, 12, e.month[:]); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	// Day of week accepts 0-7, both 0 and 7 meaning Sunday
+	var dow [8]bool
+	if err := parseField(fields[4], 0, 7, dow[:]); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	copy(e.dow[:], dow[:7])
+	e.dow[0] = e.dow[0] || dow[7]
+	return e, nil
+}
+
+func parseField(field string, min, max int, set []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return fmt.Errorf("invalid value %q", from)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return fmt.Errorf("invalid value %q", to)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+func (e *Expression) String() string {
+	return e.spec
+}
+
+// dayMatches follows cron semantics: when both day fields are restricted, either may match
+func (e *Expression) dayMatches(t time.Time) bool {
+	dom, dow := e.dom[t.Day()], e.dow[t.Weekday()]
+	switch {
+	case e.domStar && e.dowStar:
+		return true
+	case e.domStar:
+		return dow
+	case e.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first time strictly after t that matches, or the zero time
+// when nothing matches within five years (e.g. "0 0 30 2 *").
+func (e *Expression) Next(t time.Time) time.Time {
+	if e.every > 0 {
+		return t.UTC().Add(e.every)
+	}
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !e.month[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !e.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !e.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
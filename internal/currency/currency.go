@@ -0,0 +1,45 @@
+// Package currency knows how many decimal places each currency is settled in (its ISO 4217
+// minor unit), so amounts never carry fractions of the smallest coin.
+package currency
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultScale is the minor unit of every currency not listed below
+const DefaultScale = 2
+
+// scales lists the ISO 4217 currencies whose minor unit is not two decimals
+var scales = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Register sets the scale of a currency, e.g. for a non-ISO unit such as XAU in grams.
+// Not safe for concurrent use: call it at startup, before any amount is handled.
+func Register(code string, scale int32) {
+	scales[strings.ToUpper(code)] = scale
+}
+
+// Scale returns the number of decimal places of the currency
+func Scale(code string) int32 {
+	if scale, ok := scales[strings.ToUpper(code)]; ok {
+		return scale
+	}
+	return DefaultScale
+}
+
+// Round rounds an amount to the currency's minor unit using banker's rounding (half to even),
+// so repeated rounding of fees and conversions does not drift in one direction
+func Round(amount decimal.Decimal, code string) decimal.Decimal {
+	return amount.RoundBank(Scale(code))
+}
+
+// ExcessPrecision reports whether the amount has digits below the currency's minor unit
+func ExcessPrecision(amount decimal.Decimal, code string) bool {
+	return !amount.Equal(amount.Truncate(Scale(code)))
+}
@@ -0,0 +1,139 @@
+// Package decorate wraps a LedgerStore or an EventPublisher in middleware - logging,
+// metrics, tracing, retries - so a new backend gets them without copying any of it.
+//
+//	store := decorate.Store(pgStore, decorate.WithLogging(appLogger, 250*time.Millisecond), decorate.WithMetrics())
+//
+// The first option is the outermost layer.
+package decorate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+var (
+	calls = metrics.NewCounterVec("decorated_calls_total",
+		"Calls through decorated stores and publishers", "component", "op", "outcome")
+	callSeconds = metrics.NewCounterVec("decorated_call_seconds_total",
+		"Time spent in calls through decorated stores and publishers", "component", "op")
+)
+
+// Middleware runs around one call; it must call next, with ctx or one derived from it, to proceed
+type Middleware func(ctx context.Context, call Call, next func(ctx context.Context) error) error
+
+// Call describes the call a Middleware runs around
+type Call struct {
+	Component string // "store" or "publisher"
+	Op        string // the method, e.g. "SaveTransactionChecked"
+	ReadOnly  bool   // a failed read-only call leaves nothing behind, so it is safe to repeat
+}
+
+type Option func(*options)
+
+type options struct {
+	middleware []Middleware
+}
+
+func build(opts []Option) Middleware {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	chain := o.middleware
+	return func(ctx context.Context, call Call, next func(ctx context.Context) error) error {
+		// Wrap from the innermost layer outwards so the first option runs first
+		for i := len(chain) - 1; i >= 0; i-- {
+			inner, mw := next, chain[i]
+			next = func(ctx context.Context) error { return mw(ctx, call, inner) }
+		}
+		return next(ctx)
+	}
+}
+
+// With adds a custom middleware
+func With(mw Middleware) Option {
+	return func(o *options) { o.middleware = append(o.middleware, mw) }
+}
+
+// WithLogging logs failed calls, and calls slower than slow when it is positive
+func WithLogging(appLogger *slog.Logger, slow time.Duration) Option {
+	return With(func(ctx context.Context, call Call, next func(ctx context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+		elapsed := time.Since(start)
+		switch {
+		case err != nil:
+			appLogger.Warn("call failed", "component", call.Component, "op", call.Op, "elapsed", elapsed.String(), "error", err)
+		case slow > 0 && elapsed >= slow:
+			appLogger.Warn("slow call", "component", call.Component, "op", call.Op, "elapsed", elapsed.String())
+		}
+		return err
+	})
+}
+
+// WithMetrics counts calls by outcome and the time spent in them
+func WithMetrics() Option {
+	return With(func(ctx context.Context, call Call, next func(ctx context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+		callSeconds.With(call.Component, call.Op).Add(time.Since(start).Seconds())
+		outcome := "ok"
+		if err != nil {
+			outcome = "error"
+		}
+		calls.With(call.Component, call.Op, outcome).Inc()
+		return err
+	})
+}
+
+// Tracer starts a span for a call; end is called with the outcome once it returns.
+// It is the seam for a tracing backend.
+type Tracer interface {
+	Start(ctx context.Context, name string) (_ context.Context, end func(err error))
+}
+
+// WithTracing opens a span named "<component>.<op>" around every call
+func WithTracing(tracer Tracer) Option {
+	return With(func(ctx context.Context, call Call, next func(ctx context.Context) error) error {
+		ctx, end := tracer.Start(ctx, call.Component+"."+call.Op)
+		err := next(ctx)
+		end(err)
+		return err
+	})
+}
+
+// RetryPolicy retries failed read-only calls with exponential backoff. Writes are never
+// retried here: only the store can tell whether a failed write left anything behind.
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// WithRetry repeats failed read-only calls until they succeed, the attempts run out or ctx ends
+func WithRetry(policy RetryPolicy) Option {
+	attempts := max(policy.Attempts, 1)
+	return With(func(ctx context.Context, call Call, next func(ctx context.Context) error) error {
+		delay := policy.BaseDelay
+		for attempt := 1; ; attempt++ {
+			err := next(ctx)
+			if err == nil || !call.ReadOnly || attempt >= attempts ||
+				errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return err
+			}
+			delay *= 2
+			if policy.MaxDelay > 0 {
+				delay = min(delay, policy.MaxDelay)
+			}
+		}
+	})
+}
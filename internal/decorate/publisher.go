@@ -0,0 +1,26 @@
+package decorate
+
+import (
+	"context"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// Publisher wraps an EventPublisher. Publishing has no context, so middleware sees a
+// background one; a publish counts as a write and is never retried here.
+func Publisher(inner interfaces.EventPublisher, opts ...Option) interfaces.EventPublisher {
+	return &publisher{inner: inner, run: build(opts)}
+}
+
+type publisher struct {
+	inner interfaces.EventPublisher
+	run   Middleware
+}
+
+func (p *publisher) Publish(topic string, event any) error {
+	return p.run(context.Background(), Call{Component: "publisher", Op: "Publish"}, func(context.Context) error {
+		return p.inner.Publish(topic, event)
+	})
+}
+
+var _ interfaces.EventPublisher = (*publisher)(nil)
@@ -0,0 +1,131 @@
+package decorate
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Store wraps the core LedgerStore methods and, when the store has them, the posting and
+// balance paths (MultiLegStore, BalanceStore). Every other optional capability is reached
+// through Unwrap, undecorated; interfaces.Capability looks for it there.
+func Store(inner interfaces.LedgerStore, opts ...Option) interfaces.LedgerStore {
+	s := &store{inner: inner, run: build(opts)}
+	multiLeg, hasLegs := inner.(interfaces.MultiLegStore)
+	balanceStore, hasBalances := inner.(interfaces.BalanceStore)
+
+	// One type per combination, so type assertions on the result stay truthful
+	switch {
+	case hasLegs && hasBalances:
+		return struct {
+			*store
+			legs
+			balances
+		}{s, legs{s, multiLeg}, balances{s, balanceStore}}
+	case hasLegs:
+		return struct {
+			*store
+			legs
+		}{s, legs{s, multiLeg}}
+	case hasBalances:
+		return struct {
+			*store
+			balances
+		}{s, balances{s, balanceStore}}
+	default:
+		return s
+	}
+}
+
+type store struct {
+	inner interfaces.LedgerStore
+	run   Middleware
+}
+
+// Unwrap returns the decorated store
+func (s *store) Unwrap() interfaces.LedgerStore {
+	return s.inner
+}
+
+func (s *store) call(ctx context.Context, op string, readOnly bool, fn func(ctx context.Context) error) error {
+	return s.run(ctx, Call{Component: "store", Op: op, ReadOnly: readOnly}, fn)
+}
+
+func (s *store) SaveTransactionWithEntries(ctx context.Context, tx models.Transaction, debit models.LedgerEntry, credit models.LedgerEntry) error {
+	return s.call(ctx, "SaveTransactionWithEntries", false, func(ctx context.Context) error {
+		return s.inner.SaveTransactionWithEntries(ctx, tx, debit, credit)
+	})
+}
+
+func (s *store) GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := s.call(context.Background(), "GetEntriesByAccount", true, func(context.Context) error {
+		var err error
+		entries, err = s.inner.GetEntriesByAccount(accountId)
+		return err
+	})
+	return entries, err
+}
+
+func (s *store) GetLedgerEntries() ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := s.call(context.Background(), "GetLedgerEntries", true, func(context.Context) error {
+		var err error
+		entries, err = s.inner.GetLedgerEntries()
+		return err
+	})
+	return entries, err
+}
+
+func (s *store) TransactionExists(tenantId, idempotencyKey string) (bool, error) {
+	var exists bool
+	err := s.call(context.Background(), "TransactionExists", true, func(context.Context) error {
+		var err error
+		exists, err = s.inner.TransactionExists(tenantId, idempotencyKey)
+		return err
+	})
+	return exists, err
+}
+
+func (s *store) SaveTransaction(tx models.Transaction, dbTx *sql.Tx) error {
+	return s.call(context.Background(), "SaveTransaction", false, func(context.Context) error {
+		return s.inner.SaveTransaction(tx, dbTx)
+	})
+}
+
+type legs struct {
+	s     *store
+	inner interfaces.MultiLegStore
+}
+
+func (l legs) SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	return l.s.call(ctx, "SaveTransactionWithLegs", false, func(ctx context.Context) error {
+		return l.inner.SaveTransactionWithLegs(ctx, tx, entries)
+	})
+}
+
+type balances struct {
+	s     *store
+	inner interfaces.BalanceStore
+}
+
+func (b balances) GetMaterializedBalance(ctx context.Context, accountId string) (decimal.Decimal, bool, error) {
+	var balance decimal.Decimal
+	var found bool
+	err := b.s.call(ctx, "GetMaterializedBalance", true, func(ctx context.Context) error {
+		var err error
+		balance, found, err = b.inner.GetMaterializedBalance(ctx, accountId)
+		return err
+	})
+	return balance, found, err
+}
+
+func (b balances) SaveTransactionChecked(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+	check func(balances map[string]decimal.Decimal) error) error {
+	return b.s.call(ctx, "SaveTransactionChecked", false, func(ctx context.Context) error {
+		return b.inner.SaveTransactionChecked(ctx, tx, entries, check)
+	})
+}
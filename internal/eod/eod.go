@@ -0,0 +1,188 @@
+package eod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+const dateLayout = "2006-01-02"
+
+// maxCatchUp bounds how many missed business days are closed in a single run
+const maxCatchUp = 31
+
+var (
+	ErrInvalidCutoff = errors.New("cutoff must be a time of day formatted as HH:MM")
+	ErrDayNotFound   = errors.New("business day not found")
+	ErrInvalidDate   = errors.New("date must be formatted as YYYY-MM-DD")
+)
+
+// Cutoff is the time of day at which a business day ends. Transactions after the
+// cutoff belong to the next business day.
+type Cutoff struct {
+	hour, minute int // hour 24 is midnight at the end of the day
+	location     *time.Location
+}
+
+// ParseCutoff reads a cutoff such as "17:30" in the given IANA time zone. "00:00" is
+// midnight at the end of the day, so each business day is exactly one calendar day.
+func ParseCutoff(clock, zone string) (Cutoff, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return Cutoff{}, fmt.Errorf("%w: %q", ErrInvalidCutoff, clock)
+	}
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return Cutoff{}, err
+	}
+
+	cutoff := Cutoff{hour: t.Hour(), minute: t.Minute(), location: location}
+	if cutoff.hour == 0 && cutoff.minute == 0 {
+		cutoff.hour = 24
+	}
+	return cutoff, nil
+}
+
+// end returns the instant the business day of date ends
+func (c Cutoff) end(date time.Time) time.Time {
+	// Built from wall-clock fields so the cutoff stays at the same local time across DST changes
+	return time.Date(date.Year(), date.Month(), date.Day(), c.hour, c.minute, 0, 0, c.location)
+}
+
+// Service closes business days at the cutoff: it summarises the day, checkpoints balances
+// and announces the close with a day.closed event
+type Service struct {
+	ledger    *ledger.Ledger
+	store     interfaces.BusinessDayStore
+	publisher interfaces.EventPublisher
+	cutoff    Cutoff
+	appLogger *slog.Logger
+}
+
+func NewService(ledgerService *ledger.Ledger, store interfaces.BusinessDayStore, publisher interfaces.EventPublisher,
+	cutoff Cutoff, appLogger *slog.Logger) *Service {
+	return &Service{
+		ledger:    ledgerService,
+		store:     store,
+		publisher: publisher,
+		cutoff:    cutoff,
+		appLogger: appLogger,
+	}
+}
+
+// CloseDue closes every business day whose cutoff has passed since the last closed day.
+// The first run only closes the most recent day; history before it is not backfilled.
+func (s *Service) CloseDue(ctx context.Context, now time.Time) ([]models.BusinessDay, error) {
+	latest, err := s.store.GetLatestBusinessDay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var date time.Time
+	if latest != nil {
+		last, err := time.ParseInLocation(dateLayout, latest.Date, s.cutoff.location)
+		if err != nil {
+			return nil, err
+		}
+		date = last.AddDate(0, 0, 1)
+	} else {
+		local := now.In(s.cutoff.location)
+		date = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.cutoff.location)
+		if s.cutoff.end(date).After(now) {
+			date = date.AddDate(0, 0, -1)
+		}
+	}
+
+	var closed []models.BusinessDay
+	for i := 0; i < maxCatchUp && !s.cutoff.end(date).After(now); i++ {
+		day, err := s.closeDay(ctx, date)
+		if err != nil {
+			return closed, err
+		}
+		closed = append(closed, day)
+		date = date.AddDate(0, 0, 1)
+	}
+	return closed, nil
+}
+
+// closeDay summarises the day and records it closed in one database transaction, then takes
+// balance snapshots and publishes day.closed. Only the replica that closed the day publishes.
+func (s *Service) closeDay(ctx context.Context, date time.Time) (models.BusinessDay, error) {
+	closedAt := s.ledger.Now().UTC().Truncate(time.Microsecond)
+	day, err := s.store.CloseBusinessDay(ctx, models.BusinessDay{
+		Date:     date.Format(dateLayout),
+		StartsAt: s.cutoff.end(date.AddDate(0, 0, -1)).UTC(),
+		EndsAt:   s.cutoff.end(date).UTC(),
+		ClosedAt: closedAt,
+	})
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	if !day.ClosedAt.Equal(closedAt) {
+		return day, nil
+	}
+
+	snapshots, err := s.ledger.TakeSnapshots(ctx)
+	if err != nil && !errors.Is(err, ledger.ErrSnapshotsNotSupported) {
+		s.appLogger.Error("failed to snapshot balances at end of day", "date", day.Date, "error", err)
+	}
+
+	s.appLogger.Info("business day closed",
+		"date", day.Date,
+		"transactions", day.TransactionCount,
+		"volume", day.Volume.String(),
+		"accounts", day.Accounts,
+	)
+	event := events.DayClosed{
+		Date:             day.Date,
+		StartsAt:         day.StartsAt,
+		EndsAt:           day.EndsAt,
+		TransactionCount: day.TransactionCount,
+		Volume:           day.Volume,
+		Accounts:         day.Accounts,
+		SnapshotsTaken:   snapshots,
+		ClosedAt:         day.ClosedAt,
+	}
+	if err := s.publisher.Publish("day.closed", event); err != nil {
+		s.appLogger.Error("failed to publish kafka event", "date", day.Date, "error", err)
+	}
+	return day, nil
+}
+
+// GetDay returns a closed business day with the movement of every account
+func (s *Service) GetDay(ctx context.Context, date string) (models.BusinessDay, []models.DailyAccountSummary, error) {
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return models.BusinessDay{}, nil, ErrInvalidDate
+	}
+	day, err := s.store.GetBusinessDay(ctx, date)
+	if err != nil {
+		return models.BusinessDay{}, nil, err
+	}
+	if day == nil {
+		return models.BusinessDay{}, nil, ErrDayNotFound
+	}
+	summaries, err := s.store.ListDailyAccountSummaries(ctx, date)
+	if err != nil {
+		return models.BusinessDay{}, nil, err
+	}
+	return *day, summaries, nil
+}
+
+// LatestDay returns the most recently closed business day
+func (s *Service) LatestDay(ctx context.Context) (models.BusinessDay, error) {
+	day, err := s.store.GetLatestBusinessDay(ctx)
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	if day == nil {
+		return models.BusinessDay{}, ErrDayNotFound
+	}
+	return *day, nil
+}
@@ -0,0 +1,130 @@
+// Package breaker keeps a slow or unreachable broker from stalling postings: after
+// repeated failures it stops calling the broker for a while and hands events to a fallback.
+package breaker
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+// Breaker states, also the values of the state gauge
+const (
+	Closed   = 0 // events go to the broker
+	Open     = 1 // events go straight to the fallback
+	HalfOpen = 2 // one trial event goes to the broker after the cooldown
+)
+
+var (
+	stateGauge = metrics.NewGauge("event_publisher_breaker_state",
+		"State of the event publisher circuit breaker: 0 closed, 1 open, 2 half-open")
+	fallbacks = metrics.NewCounterVec("event_publisher_fallbacks_total",
+		"Events handed to the fallback publisher", "reason")
+)
+
+// Publisher wraps the broker publisher with a circuit breaker
+type Publisher struct {
+	next      interfaces.EventPublisher
+	fallback  interfaces.EventPublisher
+	threshold int           // consecutive failures that open the breaker
+	cooldown  time.Duration // how long the breaker stays open before a trial
+	appLogger *slog.Logger
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+}
+
+func NewPublisher(next, fallback interfaces.EventPublisher, threshold int, cooldown time.Duration, logger *slog.Logger) *Publisher {
+	return &Publisher{
+		next:      next,
+		fallback:  fallback,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		appLogger: logger,
+	}
+}
+
+// Publish sends the event to the broker unless the breaker is open. Events the broker
+// does not take go to the fallback, so the caller only sees an error when both fail.
+func (p *Publisher) Publish(topic string, event any) error {
+	if !p.allow() {
+		fallbacks.With("open").Inc()
+		return p.fallback.Publish(topic, event)
+	}
+
+	err := p.next.Publish(topic, event)
+	p.record(err)
+	if err == nil {
+		return nil
+	}
+
+	p.appLogger.Warn("broker publish failed, using fallback", "topic", topic, "error", err)
+	fallbacks.With("error").Inc()
+	if fallbackErr := p.fallback.Publish(topic, event); fallbackErr != nil {
+		return errors.Join(err, fallbackErr)
+	}
+	return nil
+}
+
+// State returns Closed, Open or HalfOpen
+func (p *Publisher) State() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state
+}
+
+// allow reports whether the broker may be called, moving an open breaker to half-open
+// once the cooldown has passed. While half-open only the trial call is let through.
+func (p *Publisher) allow() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case Closed:
+		return true
+	case Open:
+		if time.Since(p.openedAt) < p.cooldown {
+			return false
+		}
+		p.setState(HalfOpen)
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Publisher) record(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		p.failures = 0
+		if p.state != Closed {
+			p.appLogger.Info("event publisher breaker closed")
+			p.setState(Closed)
+		}
+		return
+	}
+
+	p.failures++
+	if p.state == HalfOpen || p.failures >= p.threshold {
+		if p.state != Open {
+			p.appLogger.Error("event publisher breaker opened", "failures", p.failures, "cooldown", p.cooldown)
+		}
+		p.openedAt = time.Now()
+		p.setState(Open)
+	}
+}
+
+func (p *Publisher) setState(state int) {
+	p.state = state
+	stateGauge.Set(float64(state))
+}
+
+var _ interfaces.EventPublisher = (*Publisher)(nil)
@@ -0,0 +1,180 @@
+// Package bus fans every event out to a configurable set of sinks - the Kafka publisher,
+// webhooks, the audit log - so the ledger publishes once without knowing where events go.
+// Sinks are isolated from each other: one failing, slow or panicking does not keep the
+// event from the rest.
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+var ErrQueueFull = errors.New("sink queue is full")
+
+var (
+	deliveries = metrics.NewCounterVec("event_bus_deliveries_total",
+		"Events handed to each sink, by outcome", "sink", "outcome")
+	queued = metrics.NewGaugeVec("event_bus_queued",
+		"Events waiting for an asynchronous sink", "sink")
+	asyncFailures = metrics.NewCounterVec("event_bus_async_failures_total",
+		"Events an asynchronous sink accepted but failed to deliver", "sink")
+)
+
+// Sink is one destination of the bus
+type Sink struct {
+	Name      string
+	Publisher interfaces.EventPublisher
+}
+
+// Bus publishes each event to every sink in turn
+type Bus struct {
+	sinks     []Sink
+	appLogger *slog.Logger
+}
+
+func New(appLogger *slog.Logger, sinks ...Sink) *Bus {
+	return &Bus{sinks: sinks, appLogger: appLogger}
+}
+
+// Sinks names the configured sinks in the order events reach them
+func (b *Bus) Sinks() []string {
+	names := make([]string, len(b.sinks))
+	for i, sink := range b.sinks {
+		names[i] = sink.Name
+	}
+	return names
+}
+
+// Publish delivers the event to every sink, even after one has failed. The error joins
+// those of the failed sinks, so callers can keep logging a failed publish as before.
+func (b *Bus) Publish(topic string, event any) error {
+	var errs []error
+	for _, sink := range b.sinks {
+		if err := deliver(sink, topic, event); err != nil {
+			deliveries.With(sink.Name, "error").Inc()
+			b.appLogger.Warn("event sink failed",
+				"sink", sink.Name,
+				"topic", topic,
+				"error", err,
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name, err))
+			continue
+		}
+		deliveries.With(sink.Name, "ok").Inc()
+	}
+	return errors.Join(errs...)
+}
+
+// deliver turns a panicking sink into an error
+func deliver(sink Sink, topic string, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sink panicked: %v", r)
+		}
+	}()
+	return sink.Publisher.Publish(topic, event)
+}
+
+var _ interfaces.EventPublisher = (*Bus)(nil)
+
+// Async hands events to a sink from a background goroutine, so a slow sink such as a
+// webhook adds nothing to the request path. When its queue is full, events are refused
+// with ErrQueueFull rather than waited for.
+type Async struct {
+	name      string
+	next      interfaces.EventPublisher
+	appLogger *slog.Logger
+
+	queue chan asyncEvent
+	done  chan struct{}
+
+	mu     sync.RWMutex // held for writing only to close the queue
+	closed bool
+}
+
+type asyncEvent struct {
+	topic string
+	event any
+}
+
+func NewAsync(name string, next interfaces.EventPublisher, buffer int, appLogger *slog.Logger) *Async {
+	a := &Async{
+		name:      name,
+		next:      next,
+		appLogger: appLogger,
+		queue:     make(chan asyncEvent, max(buffer, 1)),
+		done:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *Async) Publish(topic string, event any) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrQueueFull
+	}
+	select {
+	case a.queue <- asyncEvent{topic: topic, event: event}:
+		queued.With(a.name).Set(float64(len(a.queue)))
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting events and returns once the queue has been delivered
+func (a *Async) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	<-a.done
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+	for item := range a.queue {
+		queued.With(a.name).Set(float64(len(a.queue)))
+		if err := deliver(Sink{Name: a.name, Publisher: a.next}, item.topic, item.event); err != nil {
+			asyncFailures.With(a.name).Inc()
+			a.appLogger.Warn("event sink failed",
+				"sink", a.name,
+				"topic", item.topic,
+				"error", err,
+			)
+		}
+	}
+}
+
+var _ interfaces.EventPublisher = (*Async)(nil)
+
+// AuditSink records every event in the audit log under the action "event.<topic>"
+type AuditSink struct {
+	Log *audit.Log
+}
+
+func (s AuditSink) Publish(topic string, event any) error {
+	s.Log.Record(context.Background(), "event."+topic, "topic:"+topic, nil, event)
+	return nil
+}
+
+// LogSink writes every event to the application log, for development
+type LogSink struct {
+	Logger *slog.Logger
+}
+
+func (s LogSink) Publish(topic string, event any) error {
+	s.Logger.Info("event published", "topic", topic, "event", event)
+	return nil
+}
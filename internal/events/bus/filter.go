@@ -0,0 +1,166 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+var ErrInvalidEventFilter = errors.New("invalid event filter")
+
+var suppressed = metrics.NewCounterVec("event_bus_suppressed_total",
+	"Events the event filter kept from the sinks", "topic")
+
+// Filter sits in front of the bus and drops the events its models.EventFilter suppresses.
+// The filter is kept in the store; every replica reloads it on an interval, so a change
+// made through one replica reaches the others within that interval.
+type Filter struct {
+	next      interfaces.EventPublisher
+	store     interfaces.EventFilterStore
+	appLogger *slog.Logger
+
+	mu       sync.RWMutex
+	filter   models.EventFilter
+	system   []string // the ledger's system accounts
+	topics   map[string]bool
+	tenants  map[string]bool
+	accounts map[string]bool
+}
+
+func NewFilter(next interfaces.EventPublisher, store interfaces.EventFilterStore, appLogger *slog.Logger) *Filter {
+	f := &Filter{next: next, store: store, appLogger: appLogger}
+	f.apply(models.EventFilter{})
+	return f
+}
+
+// SetSystemAccounts names the accounts EventFilter.SystemAccounts stands for
+func (f *Filter) SetSystemAccounts(ids []string) {
+	f.mu.Lock()
+	f.system = ids
+	f.mu.Unlock()
+	f.apply(f.Current())
+}
+
+// Current returns the filter in force
+func (f *Filter) Current() models.EventFilter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.filter
+}
+
+// Update validates and saves a filter, and applies it at once on this replica
+func (f *Filter) Update(ctx context.Context, filter models.EventFilter) (models.EventFilter, error) {
+	for _, topic := range filter.Topics {
+		if _, ok := events.Topics[topic]; !ok {
+			return models.EventFilter{}, fmt.Errorf("%w: unknown topic %q", ErrInvalidEventFilter, topic)
+		}
+	}
+	filter.Topics = nonEmpty(filter.Topics)
+	filter.Tenants = nonEmpty(filter.Tenants)
+	filter.Accounts = nonEmpty(filter.Accounts)
+	filter.UpdatedAt = time.Now().UTC()
+	if err := f.store.SaveEventFilter(ctx, filter); err != nil {
+		return models.EventFilter{}, err
+	}
+	f.apply(filter)
+	f.appLogger.Info("event filter updated",
+		"topics", filter.Topics,
+		"tenants", filter.Tenants,
+		"accounts", filter.Accounts,
+		"system_accounts", filter.SystemAccounts,
+	)
+	return filter, nil
+}
+
+// Load applies the stored filter; without one nothing is suppressed
+func (f *Filter) Load(ctx context.Context) error {
+	filter, err := f.store.GetEventFilter(ctx)
+	if err != nil {
+		return err
+	}
+	if filter == nil {
+		filter = &models.EventFilter{}
+	}
+	f.apply(*filter)
+	return nil
+}
+
+// Run reloads the filter every interval until ctx ends
+func (f *Filter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.Load(ctx); err != nil {
+				f.appLogger.Error("failed to reload the event filter", "error", err)
+			}
+		}
+	}
+}
+
+func (f *Filter) apply(filter models.EventFilter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filter = filter
+	f.topics = set(filter.Topics)
+	f.tenants = set(filter.Tenants)
+	f.accounts = set(filter.Accounts)
+	if filter.SystemAccounts {
+		for _, id := range f.system {
+			f.accounts[id] = true
+		}
+	}
+}
+
+func (f *Filter) Publish(topic string, event any) error {
+	if f.suppresses(topic, event) {
+		suppressed.With(topic).Inc()
+		return nil
+	}
+	return f.next.Publish(topic, event)
+}
+
+func (f *Filter) suppresses(topic string, event any) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.topics[topic] {
+		return true
+	}
+	tenant, accounts := events.Subject(event)
+	if tenant != "" && f.tenants[tenant] {
+		return true
+	}
+	return len(accounts) > 0 && !slices.ContainsFunc(accounts, func(id string) bool { return !f.accounts[id] })
+}
+
+var _ interfaces.EventPublisher = (*Filter)(nil)
+
+func set(values []string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+func nonEmpty(values []string) []string {
+	kept := []string{}
+	for _, v := range values {
+		if v != "" && !slices.Contains(kept, v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
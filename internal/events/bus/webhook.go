@@ -0,0 +1,112 @@
+package bus
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// Webhook POSTs each event as {"topic": ..., "event": ...} to a URL. With a secret, the
+// X-Signature header carries "sha256=" and the hex HMAC of the body, so the receiver can
+// tell the ledger sent it.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: []byte(secret), client: &http.Client{Timeout: timeout}}
+}
+
+func (w *Webhook) Publish(topic string, event any) error {
+	body, err := json.Marshal(map[string]any{"topic": topic, "event": event})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Topic", topic)
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s answered %s", w.url, resp.Status)
+	}
+	return nil
+}
+
+// Webhooks POSTs events to URLs chosen per event, such as the one registered with a
+// balance alert, from a background queue. Every URL shares the secret and timeout.
+type Webhooks struct {
+	secret  string
+	timeout time.Duration
+	queue   *Async
+
+	mu    sync.Mutex
+	hooks map[string]*Webhook
+}
+
+// addressed is an event queued for one URL
+type addressed struct {
+	url   string
+	event any
+}
+
+func NewWebhooks(secret string, timeout time.Duration, buffer int, appLogger *slog.Logger) *Webhooks {
+	w := &Webhooks{secret: secret, timeout: timeout, hooks: map[string]*Webhook{}}
+	w.queue = NewAsync("webhooks", publisherFunc(w.deliver), buffer, appLogger)
+	return w
+}
+
+// Send queues the event for the URL; it returns ErrQueueFull rather than wait for room
+func (w *Webhooks) Send(url, topic string, event any) error {
+	return w.queue.Publish(topic, addressed{url: url, event: event})
+}
+
+// Close returns once the queued events have been delivered
+func (w *Webhooks) Close() {
+	w.queue.Close()
+}
+
+func (w *Webhooks) deliver(topic string, event any) error {
+	item := event.(addressed)
+	w.mu.Lock()
+	hook, ok := w.hooks[item.url]
+	if !ok {
+		hook = NewWebhook(item.url, w.secret, w.timeout)
+		w.hooks[item.url] = hook
+	}
+	w.mu.Unlock()
+	return hook.Publish(topic, item.event)
+}
+
+var _ interfaces.WebhookSender = (*Webhooks)(nil)
+
+type publisherFunc func(topic string, event any) error
+
+func (f publisherFunc) Publish(topic string, event any) error {
+	return f(topic, event)
+}
@@ -0,0 +1,161 @@
+// Package consumer reads a Kafka topic into a read model with effectively-exactly-once
+// processing: each message's effects are written in the same database transaction as its
+// offset, so a crash or redelivery neither loses nor repeats a message.
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// Handler applies one message to the read model within tx. It must write only through
+// tx, or its effects escape the exactly-once guarantee.
+type Handler func(ctx context.Context, tx *sql.Tx, message kafka.Message) error
+
+type Config struct {
+	Name    string // identifies the read model; its offsets are kept under this name
+	Brokers []string
+	Topic   string
+
+	// RetryDelay is the first wait after a failed message; it doubles up to MaxRetryDelay.
+	// A failed message is retried, never skipped, so messages apply in order.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Consumer reads every partition of a topic from the offsets stored with the read model.
+// It does not use a Kafka consumer group: the database, not the broker, is the record of
+// what has been processed, so run one instance per read model.
+type Consumer struct {
+	config    Config
+	offsets   interfaces.ConsumerOffsetStore
+	handler   Handler
+	appLogger *slog.Logger
+}
+
+func New(config Config, offsets interfaces.ConsumerOffsetStore, handler Handler, appLogger *slog.Logger) *Consumer {
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = time.Second
+	}
+	if config.MaxRetryDelay < config.RetryDelay {
+		config.MaxRetryDelay = max(30*time.Second, config.RetryDelay)
+	}
+	return &Consumer{
+		config:    config,
+		offsets:   offsets,
+		handler:   handler,
+		appLogger: appLogger,
+	}
+}
+
+// Run consumes until ctx ends
+func (c *Consumer) Run(ctx context.Context) error {
+	partitions, err := c.partitions(ctx)
+	if err != nil {
+		return err
+	}
+	stored, err := c.offsets.ConsumerOffsets(ctx, c.config.Name, c.config.Topic)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(partitions))
+	for i, partition := range partitions {
+		offset, ok := stored[partition]
+		if !ok {
+			offset = kafka.FirstOffset
+		}
+		wg.Go(func() { errs[i] = c.consume(ctx, partition, offset) })
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Consumer) partitions(ctx context.Context) ([]int, error) {
+	var lastErr error
+	for _, broker := range c.config.Brokers {
+		conn, err := (&kafka.Dialer{}).DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		found, err := conn.ReadPartitions(c.config.Topic)
+		conn.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ids := make([]int, len(found))
+		for i, partition := range found {
+			ids[i] = partition.ID
+		}
+		return ids, nil
+	}
+	return nil, fmt.Errorf("listing partitions of %s: %w", c.config.Topic, lastErr)
+}
+
+func (c *Consumer) consume(ctx context.Context, partition int, offset int64) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   c.config.Brokers,
+		Topic:     c.config.Topic,
+		Partition: partition,
+		MaxBytes:  10 << 20,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(offset); err != nil {
+		return err
+	}
+
+	for {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if err := c.process(ctx, message); err != nil {
+			return err
+		}
+	}
+}
+
+// process applies a message, retrying until it succeeds or ctx ends
+func (c *Consumer) process(ctx context.Context, message kafka.Message) error {
+	delay := c.config.RetryDelay
+	for {
+		applied, err := c.offsets.ConsumeOnce(ctx, c.config.Name, message.Topic, message.Partition, message.Offset,
+			func(tx *sql.Tx) error { return c.handler(ctx, tx, message) })
+		if err == nil {
+			if !applied {
+				c.appLogger.Debug("skipped message consumed before",
+					"consumer", c.config.Name, "partition", message.Partition, "offset", message.Offset)
+			}
+			return nil
+		}
+
+		c.appLogger.Error("failed to consume message, retrying",
+			"consumer", c.config.Name,
+			"topic", message.Topic,
+			"partition", message.Partition,
+			"offset", message.Offset,
+			"retry_in", delay.String(),
+			"error", err,
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay = min(delay*2, c.config.MaxRetryDelay)
+	}
+}
@@ -0,0 +1,186 @@
+// Package deadletter parks events the broker did not accept in the database and
+// redrives them once the broker is reachable again.
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+// How many letters one redrive run sends at most
+const redriveBatch = 500
+
+var ErrNotFound = errors.New("dead letter not found")
+
+var (
+	parked    = metrics.NewCounter("event_dead_letters_total", "Events parked in the dead-letter table")
+	redriven  = metrics.NewCounter("event_dead_letters_redriven_total", "Dead-lettered events published on redrive")
+	discarded = metrics.NewCounter("event_dead_letters_discarded_total", "Dead-lettered events discarded by an operator")
+	failures  = metrics.NewCounter("event_dead_letters_redrive_failures_total", "Redrive attempts the broker did not accept")
+	backlog   = metrics.NewGauge("event_dead_letters_backlog", "Events waiting in the dead-letter table")
+	lag       = metrics.NewGauge("event_dead_letters_lag_seconds", "Age of the oldest event waiting in the dead-letter table")
+)
+
+// Queue is an EventPublisher that stores events instead of sending them
+type Queue struct {
+	store interfaces.DeadLetterStore
+	clock clock.Clock
+}
+
+func NewQueue(store interfaces.DeadLetterStore) *Queue {
+	return &Queue{store: store, clock: clock.System{}}
+}
+
+// SetClock stamps letters and measures the lag by c, normally the ledger's clock, so both
+// agree with the events' own timestamps
+func (q *Queue) SetClock(c clock.Clock) {
+	q.clock = c
+}
+
+// Publish parks the event. Events are stored as JSON regardless of the publisher format;
+// the redrive decodes them back into their Go type before publishing.
+func (q *Queue) Publish(topic string, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	letter := models.DeadLetter{Topic: topic, Payload: payload, CreatedAt: q.clock.Now().UTC()}
+	if err := q.store.SaveDeadLetter(context.Background(), letter); err != nil {
+		return err
+	}
+	parked.Inc()
+	return nil
+}
+
+// List returns parked events, oldest first
+func (q *Queue) List(ctx context.Context, filter models.DeadLetterFilter) ([]models.DeadLetter, error) {
+	letters, err := q.store.ListDeadLetters(ctx, filter)
+	if letters == nil {
+		letters = []models.DeadLetter{}
+	}
+	return letters, err
+}
+
+func (q *Queue) Get(ctx context.Context, id int64) (models.DeadLetter, error) {
+	letter, err := q.store.GetDeadLetter(ctx, id)
+	if err != nil {
+		return models.DeadLetter{}, err
+	}
+	if letter == nil {
+		return models.DeadLetter{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return *letter, nil
+}
+
+// Stats sizes the backlog and refreshes the backlog and lag gauges
+func (q *Queue) Stats(ctx context.Context) (models.DeadLetterStats, error) {
+	return q.refreshGauges(ctx)
+}
+
+func (q *Queue) refreshGauges(ctx context.Context) (models.DeadLetterStats, error) {
+	stats, err := q.store.DeadLetterStats(ctx)
+	if err != nil {
+		return models.DeadLetterStats{}, err
+	}
+	backlog.Set(float64(stats.Backlog))
+	if stats.OldestAt != nil {
+		lag.Set(q.clock.Now().Sub(*stats.OldestAt).Seconds())
+	} else {
+		lag.Set(0)
+	}
+	return stats, nil
+}
+
+// Retry publishes one letter now, ahead of older ones, and deletes it once sent
+func (q *Queue) Retry(ctx context.Context, id int64, target interfaces.EventPublisher) (models.DeadLetter, error) {
+	letter, err := q.Get(ctx, id)
+	if err != nil {
+		return models.DeadLetter{}, err
+	}
+	if err := q.send(ctx, letter, target); err != nil {
+		return models.DeadLetter{}, err
+	}
+	return letter, nil
+}
+
+// Discard deletes a letter without publishing it, e.g. an event no consumer should see
+func (q *Queue) Discard(ctx context.Context, id int64) error {
+	deleted, err := q.store.DeleteDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	discarded.Inc()
+	return nil
+}
+
+// Redrive publishes parked events oldest first, deleting each once sent. It stops at the
+// first failure so the order of the remaining letters is kept. The backlog and lag gauges
+// are refreshed when it returns, whatever the outcome.
+func (q *Queue) Redrive(ctx context.Context, target interfaces.EventPublisher) (sent int, err error) {
+	defer func() {
+		if _, statsErr := q.refreshGauges(ctx); statsErr != nil {
+			err = errors.Join(err, fmt.Errorf("sizing the backlog: %w", statsErr))
+		}
+	}()
+
+	letters, err := q.store.ListDeadLetters(ctx, models.DeadLetterFilter{Limit: redriveBatch})
+	if err != nil {
+		return 0, err
+	}
+	for _, letter := range letters {
+		if err := q.send(ctx, letter, target); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// send publishes a letter and deletes it; a failure is recorded on the letter
+func (q *Queue) send(ctx context.Context, letter models.DeadLetter, target interfaces.EventPublisher) error {
+	event, err := decode(letter)
+	if err == nil {
+		err = target.Publish(letter.Topic, event)
+	}
+	if err != nil {
+		failures.Inc()
+		if recordErr := q.store.RecordDeadLetterFailure(ctx, letter.ID, err.Error(), q.clock.Now().UTC()); recordErr != nil {
+			err = errors.Join(err, recordErr)
+		}
+		return fmt.Errorf("dead letter %d: %w", letter.ID, err)
+	}
+	if _, err := q.store.DeleteDeadLetter(ctx, letter.ID); err != nil {
+		return err
+	}
+	redriven.Inc()
+	return nil
+}
+
+// decode turns the stored JSON back into the event type of the topic, so it can be
+// encoded in any publisher format. Unknown topics are sent as raw JSON.
+func decode(letter models.DeadLetter) (any, error) {
+	sample, ok := events.Topics[letter.Topic]
+	if !ok {
+		return letter.Payload, nil
+	}
+	event := reflect.New(reflect.TypeOf(sample))
+	if err := json.Unmarshal(letter.Payload, event.Interface()); err != nil {
+		return nil, err
+	}
+	return event.Elem().Interface(), nil
+}
+
+var _ interfaces.EventPublisher = (*Queue)(nil)
@@ -0,0 +1,184 @@
+package kafka
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+// What Publish does when the buffer is full
+const (
+	PolicyBlock = "block" // wait for room
+	PolicyDrop  = "drop"  // fail at once with ErrBufferFull
+)
+
+var (
+	ErrBufferFull      = errors.New("event buffer is full")
+	ErrPublisherClosed = errors.New("event publisher is closed")
+)
+
+var (
+	bufferedEvents = metrics.NewGauge("event_publisher_buffered",
+		"Events waiting in the publish buffer")
+	batchesWritten = metrics.NewCounterVec("event_publisher_batches_total",
+		"Batches written to the broker", "result")
+	eventsDropped = metrics.NewCounter("event_publisher_dropped_total",
+		"Events refused because the publish buffer was full")
+)
+
+// BufferedOptions configures a BufferedPublisher
+type BufferedOptions struct {
+	BufferSize    int           // events held before the policy applies
+	BatchSize     int           // a batch is written as soon as it holds this many events
+	FlushInterval time.Duration // and at the latest after this long
+	Policy        string        // PolicyBlock or PolicyDrop
+}
+
+type pendingEvent struct {
+	topic   string
+	event   any
+	message kafka.Message
+}
+
+// BufferedPublisher queues events and writes them to the broker in batches from a
+// background goroutine, so callers never wait for a broker round trip. Batches the
+// broker rejects go to the fallback.
+type BufferedPublisher struct {
+	publisher *Publisher
+	fallback  interfaces.EventPublisher
+	options   BufferedOptions
+	appLogger *slog.Logger
+
+	queue chan pendingEvent
+	done  chan struct{}
+
+	mu     sync.RWMutex // held for writing only to close the queue
+	closed bool
+}
+
+func NewBufferedPublisher(publisher *Publisher, fallback interfaces.EventPublisher, options BufferedOptions, logger *slog.Logger) *BufferedPublisher {
+	options.BufferSize = max(options.BufferSize, 1)
+	options.BatchSize = max(options.BatchSize, 1)
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = 100 * time.Millisecond
+	}
+
+	b := &BufferedPublisher{
+		publisher: publisher,
+		fallback:  fallback,
+		options:   options,
+		appLogger: logger,
+		queue:     make(chan pendingEvent, options.BufferSize),
+		done:      make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Publish encodes the event and queues it. Encoding errors are returned right away;
+// broker errors happen later and send the event to the fallback.
+func (b *BufferedPublisher) Publish(topic string, event any) error {
+	message, err := b.publisher.message(topic, event)
+	if err != nil {
+		return err
+	}
+	item := pendingEvent{topic: topic, event: event, message: message}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrPublisherClosed
+	}
+
+	if b.options.Policy == PolicyDrop {
+		select {
+		case b.queue <- item:
+		default:
+			eventsDropped.Inc()
+			return ErrBufferFull
+		}
+	} else {
+		b.queue <- item
+	}
+	bufferedEvents.Set(float64(len(b.queue)))
+	return nil
+}
+
+// Close stops accepting events and returns once everything buffered has been written
+func (b *BufferedPublisher) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+	<-b.done
+}
+
+func (b *BufferedPublisher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pendingEvent, 0, b.options.BatchSize)
+	for {
+		select {
+		case item, ok := <-b.queue:
+			if !ok {
+				b.flush(batch)
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) >= b.options.BatchSize {
+				b.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			b.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (b *BufferedPublisher) flush(batch []pendingEvent) {
+	bufferedEvents.Set(float64(len(b.queue)))
+	if len(batch) == 0 {
+		return
+	}
+
+	messages := make([]kafka.Message, len(batch))
+	for i, item := range batch {
+		messages[i] = item.message
+	}
+	err := b.publisher.write(messages...)
+	if err == nil {
+		batchesWritten.With("ok").Inc()
+		return
+	}
+
+	batchesWritten.With("failed").Inc()
+	b.appLogger.Error("failed to write event batch, using fallback", "events", len(batch), "error", err)
+
+	// On a partial failure only the messages the broker rejected are resent
+	var writeErrors kafka.WriteErrors
+	partial := errors.As(err, &writeErrors) && len(writeErrors) == len(batch)
+	for i, item := range batch {
+		if partial && writeErrors[i] == nil {
+			continue
+		}
+		if err := b.fallback.Publish(item.topic, item.event); err != nil {
+			b.appLogger.Error("failed to publish kafka event",
+				"topic", item.topic,
+				"error", err,
+			)
+		}
+	}
+}
+
+var _ interfaces.EventPublisher = (*BufferedPublisher)(nil)
@@ -3,34 +3,133 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/protobuf"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/registry"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+// Formats events can be serialized in
+const (
+	FormatJSON     = "json"
+	FormatProtobuf = "protobuf" // schemas in api/proto/events
+)
+
+var (
+	ErrUnknownFormat     = errors.New("unknown event format")
+	ErrRegistryNeedsJSON = errors.New("the schema registry is only supported for json events")
+	ErrUnregisteredTopic = errors.New("topic has no registered schema")
 )
 
 type Publisher struct {
-	writer *kafka.Writer
+	writer    *kafka.Writer
+	format    string
+	timeout   time.Duration  // per publish, so a stalled broker cannot hold up the caller
+	schemaIds map[string]int // per topic; nil when no schema registry is used
 }
 
 func NewPublisher(brokers []string) *Publisher {
 	return &Publisher{
 		writer: &kafka.Writer{
 			Addr:     kafka.TCP(brokers...),
-			Topic:    "transaction_completed",
 			Balancer: &kafka.LeastBytes{},
 		},
+		format:  FormatJSON,
+		timeout: 5 * time.Second,
+	}
+}
+
+// SetTimeout bounds how long a single publish may wait for the broker
+func (p *Publisher) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// SetFormat selects how events are serialized; the default is JSON
+func (p *Publisher) SetFormat(format string) error {
+	if format != FormatJSON && format != FormatProtobuf {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	p.format = format
+	return nil
+}
+
+// UseSchemaRegistry registers the schema of every topic and from then on frames messages
+// in the registry wire format. It fails when a schema is incompatible with the registered one.
+func (p *Publisher) UseSchemaRegistry(ctx context.Context, client *registry.Client) error {
+	if p.format != FormatJSON {
+		return ErrRegistryNeedsJSON
 	}
+	ids, err := client.RegisterAll(ctx, events.Topics)
+	if err != nil {
+		return err
+	}
+	p.schemaIds = ids
+	return nil
 }
 
 func (p *Publisher) Publish(topic string, event any) error {
-	data, err := json.Marshal(event)
+	message, err := p.message(topic, event)
 	if err != nil {
 		return err
 	}
+	return p.write(message)
+}
+
+// message builds the Kafka message of an event, framed for the schema registry when one is used
+func (p *Publisher) message(topic string, event any) (kafka.Message, error) {
+	message, err := p.encode(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	message.Topic = topic
 
-	return p.writer.WriteMessages(
-		context.Background(),
-		kafka.Message{
+	if p.schemaIds != nil {
+		schemaId, ok := p.schemaIds[topic]
+		if !ok {
+			return kafka.Message{}, fmt.Errorf("%w: %s", ErrUnregisteredTopic, topic)
+		}
+		message.Value = registry.Frame(schemaId, message.Value)
+	}
+	return message, nil
+}
+
+func (p *Publisher) write(messages ...kafka.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	return p.writer.WriteMessages(ctx, messages...)
+}
+
+// encode serializes the event. Protobuf messages carry their schema name in a header,
+// so consumers know which message to decode without inspecting the payload.
+func (p *Publisher) encode(event any) (kafka.Message, error) {
+	if p.format == FormatProtobuf {
+		name, err := protobuf.MessageName(event)
+		if err != nil {
+			return kafka.Message{}, err
+		}
+		data, err := protobuf.Marshal(event)
+		if err != nil {
+			return kafka.Message{}, err
+		}
+		return kafka.Message{
 			Value: data,
-		},
-	)
+			Headers: []kafka.Header{
+				{Key: "content-type", Value: []byte("application/x-protobuf")},
+				{Key: "message-type", Value: []byte(name)},
+			},
+		}, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	return kafka.Message{
+		Value:   data,
+		Headers: []kafka.Header{{Key: "content-type", Value: []byte("application/json")}},
+	}, nil
 }
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/protobuf"
+)
+
+// Replay hands every message of a topic published at or after since to handle, or every
+// message when since is zero. Each partition is read up to its end as it was when the
+// replay reached it, so the replay finishes even while the ledger keeps publishing.
+func Replay(ctx context.Context, brokers []string, topic string, since time.Time, handle func(kafka.Message) error) error {
+	partitions, err := readPartitions(ctx, brokers, topic)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		if err := replayPartition(ctx, brokers, partition, since, handle); err != nil {
+			return fmt.Errorf("replaying %s partition %d: %w", topic, partition.ID, err)
+		}
+	}
+	return nil
+}
+
+func readPartitions(ctx context.Context, brokers []string, topic string) ([]kafka.Partition, error) {
+	var lastErr error
+	for _, broker := range brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		partitions, err := conn.ReadPartitions(topic)
+		conn.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return partitions, nil
+	}
+	return nil, fmt.Errorf("listing partitions of %s: %w", topic, lastErr)
+}
+
+func replayPartition(ctx context.Context, brokers []string, partition kafka.Partition, since time.Time, handle func(kafka.Message) error) error {
+	leader := net.JoinHostPort(partition.Leader.Host, strconv.Itoa(partition.Leader.Port))
+	conn, err := kafka.DialLeader(ctx, "tcp", leader, partition.Topic, partition.ID)
+	if err != nil {
+		return err
+	}
+	start, end, err := conn.ReadOffsets()
+	if err == nil && !since.IsZero() {
+		start, err = conn.ReadOffset(since)
+	}
+	conn.Close()
+	if err != nil {
+		return err
+	}
+	if start >= end {
+		return nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   brokers,
+		Topic:     partition.Topic,
+		Partition: partition.ID,
+		MaxBytes:  10 << 20,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(start); err != nil {
+		return err
+	}
+	for {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if err := handle(message); err != nil {
+			return err
+		}
+		if message.Offset >= end-1 {
+			return nil
+		}
+	}
+}
+
+// TransactionID reads the transaction ID of an event in whichever format the publisher
+// wrote it: JSON, JSON framed for the schema registry, or protobuf
+func TransactionID(message kafka.Message) (string, error) {
+	for _, header := range message.Headers {
+		if header.Key == "content-type" && string(header.Value) == "application/x-protobuf" {
+			return protobuf.StringField(message.Value, 1)
+		}
+	}
+	value := message.Value
+	if len(value) >= 5 && value[0] == 0 {
+		value = value[5:]
+	}
+	var event struct {
+		TransactionID string `json:"transaction_id"`
+	}
+	if err := json.Unmarshal(value, &event); err != nil {
+		return "", err
+	}
+	return event.TransactionID, nil
+}
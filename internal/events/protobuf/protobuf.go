@@ -0,0 +1,148 @@
+// Package protobuf encodes published events in the protobuf wire format, following the
+// schemas in api/proto/events. The encoding is written by hand so the service needs no
+// generated code or protobuf runtime.
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+// Package of the event schemas in api/proto/events
+const schemaPackage = "ledger.events.v1"
+
+var ErrUnsupportedEvent = errors.New("event has no protobuf schema")
+
+// MessageName returns the fully qualified schema name of an event, e.g. ledger.events.v1.DayClosed
+func MessageName(event any) (string, error) {
+	var name string
+	switch event.(type) {
+	case events.TransactionCompleted:
+		name = "TransactionCompleted"
+	case events.TransactionFlagged:
+		name = "TransactionFlagged"
+	case events.AccountStatusChanged:
+		name = "AccountStatusChanged"
+	case events.AccountOverdraftEntered:
+		name = "AccountOverdraftEntered"
+	case events.AccountThresholdCrossed:
+		name = "AccountThresholdCrossed"
+	case events.PendingTransactionFailed:
+		name = "PendingTransactionFailed"
+	case events.ScheduleExecutionFailed:
+		name = "ScheduleExecutionFailed"
+	case events.DayClosed:
+		name = "DayClosed"
+	default:
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedEvent, event)
+	}
+	return schemaPackage + "." + name, nil
+}
+
+// Marshal encodes an event. Field numbers must match api/proto/events; only ever add
+// fields there, never renumber or reuse them.
+func Marshal(event any) ([]byte, error) {
+	var e encoder
+	switch ev := event.(type) {
+	case events.TransactionCompleted:
+		e.string(1, ev.TransactionID)
+		e.string(2, ev.FromAccount)
+		e.string(3, ev.ToAccount)
+		e.decimal(4, ev.Amount)
+		e.string(5, ev.Reference)
+		e.stringMap(6, ev.Metadata)
+		for _, fee := range ev.Fees {
+			e.message(7, func(m *encoder) { feeLine(m, fee) })
+		}
+		if ev.FX != nil {
+			e.message(8, func(m *encoder) { fxConversion(m, *ev.FX) })
+		}
+		e.timestamp(9, ev.OccurredAt)
+		e.string(10, ev.TenantID)
+		e.string(11, ev.ValueDate)
+	case events.TransactionFlagged:
+		e.string(1, ev.TransactionID)
+		e.string(2, ev.FromAccount)
+		e.string(3, ev.ToAccount)
+		e.decimal(4, ev.Amount)
+		for _, match := range ev.Matches {
+			e.message(5, func(m *encoder) { ruleMatch(m, match) })
+		}
+		e.timestamp(6, ev.OccurredAt)
+	case events.AccountStatusChanged:
+		e.string(1, ev.AccountID)
+		e.string(2, ev.PreviousStatus)
+		e.string(3, ev.Status)
+		e.string(4, ev.Reason)
+		e.timestamp(5, ev.OccurredAt)
+	case events.AccountOverdraftEntered:
+		e.string(1, ev.AccountID)
+		e.string(2, ev.TransactionID)
+		e.decimal(3, ev.Balance)
+		e.decimal(4, ev.OverdraftLimit)
+		e.timestamp(5, ev.OccurredAt)
+	case events.AccountThresholdCrossed:
+		e.string(1, ev.AlertID)
+		e.string(2, ev.AccountID)
+		e.string(3, ev.Type)
+		e.decimal(4, ev.Threshold)
+		e.decimal(5, ev.Balance)
+		e.decimal(6, ev.Amount)
+		e.string(7, ev.TransactionID)
+		e.timestamp(8, ev.OccurredAt)
+	case events.PendingTransactionFailed:
+		e.string(1, ev.TransactionID)
+		e.string(2, ev.FromAccount)
+		e.string(3, ev.ToAccount)
+		e.decimal(4, ev.Amount)
+		e.timestamp(5, ev.ExecuteAt)
+		e.string(6, ev.Error)
+		e.timestamp(7, ev.OccurredAt)
+	case events.ScheduleExecutionFailed:
+		e.string(1, ev.ScheduleID)
+		e.string(2, ev.FromAccount)
+		e.string(3, ev.ToAccount)
+		e.decimal(4, ev.Amount)
+		e.timestamp(5, ev.DueAt)
+		e.int64(6, int64(ev.Failures))
+		e.string(7, ev.Error)
+		e.timestamp(8, ev.OccurredAt)
+	case events.DayClosed:
+		e.string(1, ev.Date)
+		e.timestamp(2, ev.StartsAt)
+		e.timestamp(3, ev.EndsAt)
+		e.int64(4, ev.TransactionCount)
+		e.decimal(5, ev.Volume)
+		e.int64(6, int64(ev.Accounts))
+		e.int64(7, int64(ev.SnapshotsTaken))
+		e.timestamp(8, ev.ClosedAt)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedEvent, event)
+	}
+	return e.buf, nil
+}
+
+func feeLine(e *encoder, fee models.FeeLine) {
+	e.string(1, fee.ScheduleID)
+	e.string(2, fee.Account)
+	e.decimal(3, fee.Amount)
+}
+
+func fxConversion(e *encoder, fx models.FXConversion) {
+	e.string(1, fx.FromCurrency)
+	e.string(2, fx.ToCurrency)
+	e.decimal(3, fx.Rate)
+	e.decimal(4, fx.ReferenceRate)
+	e.string(5, fx.RateSource)
+	e.decimal(6, fx.ConvertedAmount)
+	e.decimal(7, fx.GainLoss)
+}
+
+func ruleMatch(e *encoder, match models.RuleMatch) {
+	e.string(1, match.RuleID)
+	e.string(2, match.Action)
+	e.string(3, match.Reason)
+}
@@ -0,0 +1,237 @@
+package protobuf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb" // registers google/protobuf/timestamp.proto
+)
+
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func repeated(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return f
+}
+
+const (
+	typeString    = descriptorpb.FieldDescriptorProto_TYPE_STRING
+	typeInt64     = descriptorpb.FieldDescriptorProto_TYPE_INT64
+	typeInt32     = descriptorpb.FieldDescriptorProto_TYPE_INT32
+	typeMessage   = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	timestampType = ".google.protobuf.Timestamp"
+)
+
+// schemas describes the messages of api/proto/events under test, field for field, so the
+// reference implementation can decode what Marshal writes
+func schemas(t *testing.T) *protoregistry.Files {
+	t.Helper()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("ledger/events/v1/events.proto"),
+		Package:    proto.String(schemaPackage),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("FeeLine"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("schedule_id", 1, typeString, ""),
+					field("account", 2, typeString, ""),
+					field("amount", 3, typeString, ""),
+				},
+			},
+			{
+				Name: proto.String("FXConversion"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("from_currency", 1, typeString, ""),
+					field("to_currency", 2, typeString, ""),
+					field("rate", 3, typeString, ""),
+					field("reference_rate", 4, typeString, ""),
+					field("rate_source", 5, typeString, ""),
+					field("converted_amount", 6, typeString, ""),
+					field("gain_loss", 7, typeString, ""),
+				},
+			},
+			{
+				Name: proto.String("TransactionCompleted"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("transaction_id", 1, typeString, ""),
+					field("from_account", 2, typeString, ""),
+					field("to_account", 3, typeString, ""),
+					field("amount", 4, typeString, ""),
+					field("reference", 5, typeString, ""),
+					repeated(field("metadata", 6, typeMessage, ".ledger.events.v1.TransactionCompleted.MetadataEntry")),
+					repeated(field("fees", 7, typeMessage, ".ledger.events.v1.FeeLine")),
+					field("fx", 8, typeMessage, ".ledger.events.v1.FXConversion"),
+					field("occurred_at", 9, typeMessage, timestampType),
+					field("tenant_id", 10, typeString, ""),
+					field("value_date", 11, typeString, ""),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("MetadataEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, typeString, ""),
+						field("value", 2, typeString, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+			{
+				Name: proto.String("DayClosed"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("date", 1, typeString, ""),
+					field("starts_at", 2, typeMessage, timestampType),
+					field("ends_at", 3, typeMessage, timestampType),
+					field("transaction_count", 4, typeInt64, ""),
+					field("volume", 5, typeString, ""),
+					field("accounts", 6, typeInt32, ""),
+					field("snapshots_taken", 7, typeInt32, ""),
+					field("closed_at", 8, typeMessage, timestampType),
+				},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// decode unmarshals an encoded event with the reference implementation. The message must
+// hold no unknown fields, and encoding it again must give back the same bytes.
+func decode(t *testing.T, files *protoregistry.Files, event any) protoreflect.Message {
+	t.Helper()
+	name, err := MessageName(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if unknown := msg.GetUnknown(); len(unknown) > 0 {
+		t.Errorf("%s: %d bytes of unknown fields", name, len(unknown))
+	}
+	again, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Errorf("%s: re-encoded as %x, want %x", name, again, data)
+	}
+	return msg
+}
+
+func get(msg protoreflect.Message, name string) protoreflect.Value {
+	return msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name)))
+}
+
+func seconds(msg protoreflect.Message, name string) (int64, int32) {
+	ts := get(msg, name).Message()
+	return get(ts, "seconds").Int(), int32(get(ts, "nanos").Int())
+}
+
+func TestMarshalDecodesWithReferenceImplementation(t *testing.T) {
+	files := schemas(t)
+	occurredAt := time.Date(2024, 6, 1, 12, 30, 0, 500, time.UTC)
+
+	msg := decode(t, files, events.TransactionCompleted{
+		TransactionID: "tx-1",
+		FromAccount:   "a",
+		ToAccount:     "b",
+		Amount:        decimal.RequireFromString("100.50"),
+		Reference:     "inv-7",
+		Metadata:      map[string]string{"order": "42", "channel": "web"},
+		Fees: []models.FeeLine{
+			{ScheduleID: "standard", Account: "fee-revenue", Amount: decimal.RequireFromString("1.25")},
+			{ScheduleID: "fx", Account: "fee-revenue", Amount: decimal.RequireFromString("0.40")},
+		},
+		FX: &models.FXConversion{
+			FromCurrency: "EUR", ToCurrency: "USD",
+			Rate: decimal.RequireFromString("1.08"), ReferenceRate: decimal.RequireFromString("1.09"),
+			RateSource: "caller", ConvertedAmount: decimal.RequireFromString("108.54"), GainLoss: decimal.RequireFromString("1.00"),
+		},
+		OccurredAt: occurredAt,
+		TenantID:   "acme",
+		ValueDate:  "2024-06-03",
+	})
+	for name, want := range map[string]string{
+		"transaction_id": "tx-1", "from_account": "a", "to_account": "b", "amount": "100.5",
+		"reference": "inv-7", "tenant_id": "acme", "value_date": "2024-06-03",
+	} {
+		if got := get(msg, name).String(); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	metadata := get(msg, "metadata").Map()
+	if metadata.Len() != 2 || metadata.Get(protoreflect.ValueOfString("order").MapKey()).String() != "42" {
+		t.Errorf("metadata has %d entries, order = %q", metadata.Len(), metadata.Get(protoreflect.ValueOfString("order").MapKey()).String())
+	}
+	fees := get(msg, "fees").List()
+	if fees.Len() != 2 || get(fees.Get(1).Message(), "amount").String() != "0.4" {
+		t.Errorf("fees = %d lines, want 2 with the second at 0.4", fees.Len())
+	}
+	fx := get(msg, "fx").Message()
+	if get(fx, "from_currency").String() != "EUR" || get(fx, "rate").String() != "1.08" || get(fx, "gain_loss").String() != "1" {
+		t.Errorf("fx = %v", fx)
+	}
+	if sec, nanos := seconds(msg, "occurred_at"); sec != occurredAt.Unix() || nanos != 500 {
+		t.Errorf("occurred_at = %d.%09d, want %d.000000500", sec, nanos, occurredAt.Unix())
+	}
+
+	msg = decode(t, files, events.DayClosed{
+		Date:             "2024-06-01",
+		StartsAt:         time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:           time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC),
+		TransactionCount: 1 << 40,
+		Volume:           decimal.RequireFromString("12345.67"),
+		Accounts:         300,
+		SnapshotsTaken:   7,
+		ClosedAt:         time.Date(2024, 6, 2, 0, 5, 0, 0, time.UTC),
+	})
+	if got := get(msg, "transaction_count").Int(); got != 1<<40 {
+		t.Errorf("transaction_count = %d, want %d", got, int64(1<<40))
+	}
+	if got := get(msg, "accounts").Int(); got != 300 {
+		t.Errorf("accounts = %d, want 300", got)
+	}
+	if sec, _ := seconds(msg, "ends_at"); sec != time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("ends_at = %d", sec)
+	}
+}
@@ -0,0 +1,128 @@
+package protobuf
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Wire types of the protobuf encoding
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errMalformed = errors.New("malformed protobuf message")
+
+// encoder appends fields in the protobuf wire format. Like proto3, it leaves out
+// fields holding their default value.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field<<3|wireType))
+}
+
+func (e *encoder) int64(field int, v int64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, uint64(v))
+}
+
+func (e *encoder) bytes(field int, b []byte) {
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) string(field int, s string) {
+	if s == "" {
+		return
+	}
+	e.bytes(field, []byte(s))
+}
+
+func (e *encoder) decimal(field int, d decimal.Decimal) {
+	e.string(field, d.String())
+}
+
+// message encodes a nested message; it is always written, even when empty, so presence is kept
+func (e *encoder) message(field int, encode func(*encoder)) {
+	var nested encoder
+	encode(&nested)
+	e.bytes(field, nested.buf)
+}
+
+// timestamp encodes a google.protobuf.Timestamp
+func (e *encoder) timestamp(field int, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	e.message(field, func(m *encoder) {
+		m.int64(1, t.Unix())
+		m.int64(2, int64(t.Nanosecond()))
+	})
+}
+
+// stringMap encodes a map<string, string> as its repeated key/value entries, in key order
+// so the same event always encodes to the same bytes
+func (e *encoder) stringMap(field int, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		e.message(field, func(m *encoder) {
+			m.string(1, key)
+			m.string(2, values[key])
+		})
+	}
+}
+
+// StringField reads a top-level string field of an encoded message, "" when it is absent.
+// Other fields are skipped, so it reads any of the schemas in api/proto/events.
+func StringField(data []byte, field int) (string, error) {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return "", errMalformed
+		}
+		data = data[n:]
+		var value []byte
+		switch tag & 7 {
+		case wireVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return "", errMalformed
+			}
+		case wireBytes:
+			length, m := binary.Uvarint(data)
+			if m <= 0 || length > uint64(len(data)-m) {
+				return "", errMalformed
+			}
+			value, n = data[m:m+int(length)], m+int(length)
+		case wireFixed64:
+			n = 8
+		case wireFixed32:
+			n = 4
+		default:
+			return "", errMalformed
+		}
+		if n > len(data) {
+			return "", errMalformed
+		}
+		data = data[n:]
+		if int(tag>>3) == field && tag&7 == wireBytes {
+			return string(value), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	decimalType = reflect.TypeFor[decimal.Decimal]()
+)
+
+// JSONSchema derives the JSON Schema of an event from its Go type and json tags, so the
+// registered schema always matches what the publisher actually sends
+func JSONSchema(event any) (string, error) {
+	schema := typeSchema(reflect.TypeOf(event))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = reflect.TypeOf(event).Name()
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == decimalType:
+		// Decimals are encoded as strings so no precision is lost
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema lists every json-tagged field; fields without omitempty are required
+func structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = typeSchema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
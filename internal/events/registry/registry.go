@@ -0,0 +1,141 @@
+// Package registry registers event schemas with a Confluent Schema Registry and frames
+// messages in its wire format, so consumers can look up the schema of every message.
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrIncompatibleSchema = errors.New("event schema is incompatible with the registered version")
+	ErrRegistry           = errors.New("schema registry request failed")
+)
+
+// Registry error code for a subject that has no versions yet
+const subjectNotFound = 40401
+
+// Client talks to the Schema Registry REST API
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Subject names the registry subject of a topic's values (the default TopicNameStrategy)
+func Subject(topic string) string {
+	return topic + "-value"
+}
+
+// Register checks the schema against the latest registered version of the subject and
+// registers it. An incompatible change returns ErrIncompatibleSchema instead of
+// registering, so a deploy fails before it can break consumers.
+func (c *Client) Register(ctx context.Context, subject, schema string) (int, error) {
+	request := map[string]string{"schemaType": "JSON", "schema": schema}
+
+	var compatibility struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	err := c.post(ctx, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest", request, &compatibility)
+	var registryErr *registryError
+	switch {
+	case errors.As(err, &registryErr) && registryErr.Code == subjectNotFound:
+		// First version of the subject; there is nothing to be compatible with
+	case err != nil:
+		return 0, err
+	case !compatibility.IsCompatible:
+		return 0, fmt.Errorf("%w: %s", ErrIncompatibleSchema, subject)
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err := c.post(ctx, "/subjects/"+url.PathEscape(subject)+"/versions", request, &registered); err != nil {
+		return 0, err
+	}
+	return registered.ID, nil
+}
+
+// RegisterAll registers the schema of the event published on every topic and returns
+// the schema ID of each topic
+func (c *Client) RegisterAll(ctx context.Context, topics map[string]any) (map[string]int, error) {
+	ids := make(map[string]int, len(topics))
+	for topic, event := range topics {
+		schema, err := JSONSchema(event)
+		if err != nil {
+			return nil, err
+		}
+		id, err := c.Register(ctx, Subject(topic), schema)
+		if err != nil {
+			return nil, err
+		}
+		ids[topic] = id
+	}
+	return ids, nil
+}
+
+// Frame prefixes a payload with the registry wire format header: a zero magic byte
+// followed by the big-endian schema ID
+func Frame(schemaId int, payload []byte) []byte {
+	framed := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(framed[1:], uint32(schemaId))
+	return append(framed, payload...)
+}
+
+type registryError struct {
+	Status  int
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("%s: %d %s", ErrRegistry, e.Code, e.Message)
+}
+
+func (e *registryError) Unwrap() error {
+	return ErrRegistry
+}
+
+func (c *Client) post(ctx context.Context, path string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRegistry, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		registryErr := &registryError{Status: resp.StatusCode}
+		payload, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(payload, registryErr) != nil || registryErr.Code == 0 {
+			registryErr.Code = resp.StatusCode
+			registryErr.Message = strings.TrimSpace(string(payload))
+		}
+		return registryErr
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,57 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// StaticRates is a RateProvider backed by a fixed table, e.g. from configuration.
+// A rate for EUR/USD also answers USD/EUR with its inverse.
+type StaticRates struct {
+	rates map[string]decimal.Decimal
+}
+
+// ParseStaticRates reads "EUR/USD=1.08,GBP/USD=1.27" style configuration
+func ParseStaticRates(spec string) (*StaticRates, error) {
+	s := &StaticRates{rates: make(map[string]decimal.Decimal)}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		currencies, value, ok := strings.Cut(pair, "=")
+		from, to, okPair := strings.Cut(currencies, "/")
+		if !ok || !okPair {
+			return nil, fmt.Errorf("invalid rate %q, expected FROM/TO=RATE", pair)
+		}
+		rate, err := decimal.NewFromString(value)
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("invalid rate %q: must be a positive number", pair)
+		}
+		s.rates[key(from, to)] = rate
+	}
+	return s, nil
+}
+
+func key(from, to string) string {
+	return strings.ToUpper(strings.TrimSpace(from)) + "/" + strings.ToUpper(strings.TrimSpace(to))
+}
+
+func (s *StaticRates) GetRate(_ context.Context, from, to string) (decimal.Decimal, error) {
+	if rate, ok := s.rates[key(from, to)]; ok {
+		return rate, nil
+	}
+	if rate, ok := s.rates[key(to, from)]; ok {
+		return decimal.NewFromInt(1).DivRound(rate, 8), nil
+	}
+	return decimal.Zero, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+var _ interfaces.RateProvider = (*StaticRates)(nil)
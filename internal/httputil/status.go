@@ -0,0 +1,25 @@
+// Package httputil holds the small pieces shared by the HTTP middlewares.
+package httputil
+
+import "net/http"
+
+// StatusRecorder captures the status code written by the handler
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+}
+
+// NewStatusRecorder wraps w; Status stays 200 unless the handler writes another code
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (flushing, deadlines)
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,161 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidRate  = errors.New("annual rate must not be negative")
+	ErrInvalidScope = errors.New("scope must be account or product")
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	daysPerYear = 365
+	// maxCatchUpDays bounds how far back a restarted job backfills missed days
+	maxCatchUpDays = 31
+)
+
+var accrualsPosted = metrics.NewCounterVec("interest_accruals_total",
+	"Daily interest accrual postings by outcome", "outcome")
+
+// Service accrues daily interest by posting from the interest expense account through the ledger
+type Service struct {
+	ledger         *ledger.Ledger
+	store          interfaces.InterestStore
+	appLogger      *slog.Logger
+	expenseAccount string
+}
+
+func NewService(ledgerService *ledger.Ledger, store interfaces.InterestStore, appLogger *slog.Logger) *Service {
+	expenseAccount := os.Getenv("INTEREST_EXPENSE_ACCOUNT")
+	if expenseAccount == "" {
+		expenseAccount = "interest-expense"
+	}
+	return &Service{
+		ledger:         ledgerService,
+		store:          store,
+		appLogger:      appLogger,
+		expenseAccount: expenseAccount,
+	}
+}
+
+// AccrueDue accrues every completed day up to yesterday (UTC) that has not been accrued yet.
+// Postings are keyed by account and day, so running it again never double-accrues.
+func (s *Service) AccrueDue(ctx context.Context) (int, error) {
+	accounts, err := s.store.ListInterestAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	yesterday := s.ledger.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	posted := 0
+	for _, account := range accounts {
+		accrued, err := s.store.GetAccruedInterest(ctx, account.AccountID)
+		if err != nil {
+			return posted, err
+		}
+
+		day := yesterday
+		if accrued.LastAccrualDate != nil {
+			day = accrued.LastAccrualDate.UTC().AddDate(0, 0, 1)
+		}
+		if earliest := yesterday.AddDate(0, 0, -maxCatchUpDays); day.Before(earliest) {
+			day = earliest
+		}
+		for ; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+			ok, err := s.accrueDay(ctx, account, day)
+			if err != nil {
+				// One failing account must not stop accruals for the rest
+				accrualsPosted.With("failed").Inc()
+				s.appLogger.Error("interest accrual failed",
+					"account_id", account.AccountID,
+					"date", day.Format(dateLayout),
+					"error", err,
+				)
+				break
+			}
+			if ok {
+				posted++
+			}
+		}
+	}
+	return posted, nil
+}
+
+// accrueDay posts one day of interest on the balance at the end of that day by value date,
+// so a payment booked on a Friday but valued on Monday earns nothing over the weekend
+func (s *Service) accrueDay(ctx context.Context, account models.InterestAccount, day time.Time) (bool, error) {
+	balance, err := s.ledger.GetValueDatedBalance(ctx, account.AccountID, day)
+	if err != nil {
+		return false, err
+	}
+	// Accruals keep full ledger precision; negative balances earn nothing
+	amount := balance.Mul(account.AnnualRate).Div(decimal.NewFromInt(100 * daysPerYear)).Round(8)
+	if !amount.IsPositive() {
+		accrualsPosted.With("skipped").Inc()
+		return false, nil
+	}
+
+	date := day.Format(dateLayout)
+	_, err = s.ledger.PostTransaction(ctx, models.Transaction{
+		ID:             s.ledger.NewID(),
+		IdempotencyKey: "interest-" + account.AccountID + "-" + date,
+		FromAccount:    s.expenseAccount,
+		ToAccount:      account.AccountID,
+		Amount:         amount,
+		CreatedAt:      s.ledger.Now(),
+		Internal:       true,
+		Reference:      "interest:" + date,
+		Description:    "Interest accrual for " + date,
+		ValueDate:      date,
+		Metadata: map[string]string{
+			"type":         "interest_accrual",
+			"accrual_date": date,
+			"annual_rate":  account.AnnualRate.String(),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	accrualsPosted.With("posted").Inc()
+	return true, nil
+}
+
+// AccruedInterest returns the interest accrued to date on an account
+func (s *Service) AccruedInterest(ctx context.Context, accountId string) (models.AccruedInterest, error) {
+	return s.store.GetAccruedInterest(ctx, accountId)
+}
+
+func (s *Service) ListRates(ctx context.Context) ([]models.InterestRate, error) {
+	return s.store.ListInterestRates(ctx)
+}
+
+// SetRate sets the annual rate of an account or a product
+func (s *Service) SetRate(ctx context.Context, rate models.InterestRate) (models.InterestRate, error) {
+	if rate.Scope != models.InterestScopeAccount && rate.Scope != models.InterestScopeProduct {
+		return models.InterestRate{}, ErrInvalidScope
+	}
+	if rate.AnnualRate.IsNegative() {
+		return models.InterestRate{}, ErrInvalidRate
+	}
+	rate.UpdatedAt = s.ledger.Now().UTC()
+	if err := s.store.SaveInterestRate(ctx, rate); err != nil {
+		return models.InterestRate{}, err
+	}
+	return rate, nil
+}
+
+func (s *Service) DeleteRate(ctx context.Context, scope, id string) error {
+	return s.store.DeleteInterestRate(ctx, scope, id)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// AccountHierarchyStore is implemented by stores that can walk parent/child account relationships
+type AccountHierarchyStore interface {
+	// GetSubtreeBalances returns the balance of the account and of every descendant
+	GetSubtreeBalances(ctx context.Context, accountId string) ([]models.AccountBalance, error)
+}
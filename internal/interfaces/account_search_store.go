@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type AccountSearchStore interface {
+	// SearchAccounts lists the accounts of the caller's tenant matching filter, in filter.Sort order
+	SearchAccounts(ctx context.Context, filter models.AccountFilter) ([]models.Account, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type AccountStore interface {
+	// GetAccount returns nil without an error when the account has no row yet
+	GetAccount(ctx context.Context, id string) (*models.Account, error)
+	SaveAccount(ctx context.Context, account models.Account) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// AliasStore keeps the external identifiers of accounts, scoped to the tenant of ctx
+type AliasStore interface {
+	GetAlias(ctx context.Context, alias string) (*models.AccountAlias, error)
+	ListAliases(ctx context.Context, accountId string) ([]models.AccountAlias, error)
+	// CreateAlias reports false when the tenant already uses the alias
+	CreateAlias(ctx context.Context, alias models.AccountAlias) (bool, error)
+	DeleteAlias(ctx context.Context, alias string) error
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// AnalyticsStore feeds the Parquet export and remembers which days were exported
+type AnalyticsStore interface {
+	// GetLastAnalyticsExport returns the newest exported day, or nil when nothing was exported yet
+	GetLastAnalyticsExport(ctx context.Context) (*models.AnalyticsExport, error)
+	SaveAnalyticsExport(ctx context.Context, export models.AnalyticsExport) error
+
+	// GetFirstTransactionTime returns when the oldest transaction was created, or nil when there is none
+	GetFirstTransactionTime(ctx context.Context) (*time.Time, error)
+
+	// GetTransactionsBetween and GetEntriesBetween return rows with from <= created_at < to
+	GetTransactionsBetween(ctx context.Context, from, to time.Time) ([]models.Transaction, error)
+	GetEntriesBetween(ctx context.Context, from, to time.Time) ([]models.LedgerEntry, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// AppendOnlyStore is implemented by stores that enforce append-only history themselves
+type AppendOnlyStore interface {
+	// UnprotectedTables lists the ledger tables whose append-only guard is missing or disabled
+	UnprotectedTables(ctx context.Context) ([]string, error)
+}
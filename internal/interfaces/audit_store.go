@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// AuditStore persists the append-only audit log; records are never updated or deleted
+type AuditStore interface {
+	AppendAudit(ctx context.Context, record models.AuditRecord) error
+	QueryAudit(ctx context.Context, filter models.AuditFilter) ([]models.AuditRecord, error)
+}
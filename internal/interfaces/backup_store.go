@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// BackupStore is implemented by stores that can dump and reload their transactions and entries
+type BackupStore interface {
+	// DumpLedger visits every transaction, then every entry (live, archived and cold), all read
+	// from one consistent snapshot. It returns the time of that snapshot.
+	DumpLedger(ctx context.Context, transaction func(models.Transaction) error, entry func(models.LedgerEntry) error) (time.Time, error)
+
+	// BeginRestore starts loading a backup into an empty ledger; nothing is visible until Commit
+	BeginRestore(ctx context.Context) (LedgerRestore, error)
+}
+
+// LedgerRestore loads transactions and entries exactly as they were dumped
+type LedgerRestore interface {
+	RestoreTransaction(tx models.Transaction) error
+	RestoreEntry(entry models.LedgerEntry) error
+	Commit() error
+	Rollback() error
+}
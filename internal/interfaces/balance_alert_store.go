@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type BalanceAlertStore interface {
+	// ListBalanceAlerts returns the alerts of the accounts given, enabled or not
+	ListBalanceAlerts(ctx context.Context, accountIds ...string) ([]models.BalanceAlert, error)
+	GetBalanceAlert(ctx context.Context, id string) (*models.BalanceAlert, error)
+	SaveBalanceAlert(ctx context.Context, alert models.BalanceAlert) error
+	DeleteBalanceAlert(ctx context.Context, id string) error
+}
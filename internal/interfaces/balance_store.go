@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// BalanceStore keeps a materialized balance per account, updated in the database
+// transaction that stores the entries
+type BalanceStore interface {
+	// GetMaterializedBalance reports false when the account has no balance row yet
+	GetMaterializedBalance(ctx context.Context, accountId string) (decimal.Decimal, bool, error)
+
+	// SaveTransactionChecked locks the balance rows of every account the legs touch, in account
+	// order, hands the locked balances to check and stores the transaction only if check passes
+	SaveTransactionChecked(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+		check func(balances map[string]decimal.Decimal) error) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// BatchBalanceStore reads the balances of many accounts in a single query
+type BatchBalanceStore interface {
+	// GetBalances returns one balance per ID, in the order given; accounts without
+	// entries have a zero balance
+	GetBalances(ctx context.Context, accountIds []string) ([]models.BatchBalance, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type BusinessDayStore interface {
+	// GetLatestBusinessDay returns nil without an error before the first day is closed
+	GetLatestBusinessDay(ctx context.Context) (*models.BusinessDay, error)
+	GetBusinessDay(ctx context.Context, date string) (*models.BusinessDay, error)
+	ListDailyAccountSummaries(ctx context.Context, date string) ([]models.DailyAccountSummary, error)
+
+	// CloseBusinessDay aggregates the entries and transactions between day.StartsAt and day.EndsAt
+	// into daily summaries and records the day as closed, atomically. Closing a day twice
+	// returns the existing record.
+	CloseBusinessDay(ctx context.Context, day models.BusinessDay) (models.BusinessDay, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// CheckpointStore keeps signed Merkle checkpoints over the entries
+type CheckpointStore interface {
+	// LastCheckpoint returns nil without an error before the first checkpoint
+	LastCheckpoint(ctx context.Context) (*models.Checkpoint, error)
+	// SaveCheckpoint sets the ID; it reports false when a checkpoint starting at the same
+	// sequence already exists, e.g. made by another instance
+	SaveCheckpoint(ctx context.Context, checkpoint *models.Checkpoint) (bool, error)
+	// CheckpointCovering returns nil without an error when no checkpoint covers seq yet
+	CheckpointCovering(ctx context.Context, seq int64) (*models.Checkpoint, error)
+	ListCheckpoints(ctx context.Context, limit int) ([]models.Checkpoint, error)
+
+	MaxEntrySeq(ctx context.Context) (int64, error)
+	// EntriesBetween returns up to limit live and archived entries with from <= seq <= to, by seq
+	EntriesBetween(ctx context.Context, from, to int64, limit int) ([]models.LedgerEntry, error)
+	// GetEntry returns a live or archived entry, or nil without an error
+	GetEntry(ctx context.Context, id string) (*models.LedgerEntry, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ColdStorageStore is implemented by stores that can move archived entries into compressed
+// cold storage. Balance snapshots are never touched, so balances stay correct.
+type ColdStorageStore interface {
+	// FreezeEntriesBefore compresses archived entries created before cutoff into cold batches
+	FreezeEntriesBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// GetColdEntries returns cold entries of the account with from <= created_at < to, ordered
+	// by sequence. A zero to means no upper bound.
+	GetColdEntries(ctx context.Context, accountId string, from, to time.Time) ([]models.LedgerEntry, error)
+
+	// GetColdChainHead returns the hash of the account's newest cold entry, or "" if it has none
+	GetColdChainHead(ctx context.Context, accountId string) (string, error)
+}
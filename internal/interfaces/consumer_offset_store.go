@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ConsumerOffsetStore keeps Kafka offsets next to the read models built from the topic,
+// so a message's effects and its offset are committed together or not at all
+type ConsumerOffsetStore interface {
+	// ConsumerOffsets returns the next offset to read of every partition the consumer has
+	// processed messages from
+	ConsumerOffsets(ctx context.Context, consumer, topic string) (map[int]int64, error)
+	// ConsumeOnce runs apply and advances the partition's offset past offset in one
+	// database transaction. It reports false without running apply when the message was
+	// consumed before.
+	ConsumeOnce(ctx context.Context, consumer, topic string, partition int, offset int64, apply func(tx *sql.Tx) error) (bool, error)
+}
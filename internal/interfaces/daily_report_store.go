@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// DailyReportStore maintains per-day aggregates as entries are committed and serves reports from them
+type DailyReportStore interface {
+	// ProjectDailyAggregates folds up to limit not yet projected transactions into the
+	// daily aggregates and returns how many it projected. Each transaction is counted once.
+	ProjectDailyAggregates(ctx context.Context, limit int) (int, error)
+
+	// GetDailyReport reads the aggregates of a date (YYYY-MM-DD) with its top largest transactions
+	GetDailyReport(ctx context.Context, date string, top int) (models.DailyReport, error)
+}
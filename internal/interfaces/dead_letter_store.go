@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// DeadLetterStore keeps events the broker did not accept
+type DeadLetterStore interface {
+	SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error
+	// ListDeadLetters returns the oldest letters first
+	ListDeadLetters(ctx context.Context, filter models.DeadLetterFilter) ([]models.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id int64) (*models.DeadLetter, error)
+	// DeleteDeadLetter reports false when the letter was already gone
+	DeleteDeadLetter(ctx context.Context, id int64) (bool, error)
+	RecordDeadLetterFailure(ctx context.Context, id int64, cause string, at time.Time) error
+	DeadLetterStats(ctx context.Context) (models.DeadLetterStats, error)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// DiscrepancyStore compares materialized balances with their entries and keeps what differs
+type DiscrepancyStore interface {
+	// CheckBalances compares sample accounts picked at random, or every account when sample is
+	// zero, in a single snapshot. It records the discrepancies found and resolves the open
+	// ones of accounts that now agree.
+	CheckBalances(ctx context.Context, sample int, at time.Time) (models.BalanceCheck, error)
+
+	// ListBalanceDiscrepancies returns the most recently detected first
+	ListBalanceDiscrepancies(ctx context.Context, filter models.DiscrepancyFilter) ([]models.BalanceDiscrepancy, error)
+
+	// RepairBalance sets the materialized balance to the sum of the account's entries, with
+	// the balance row locked, and resolves its open discrepancy. It returns nil without an
+	// error when the account has no balance row.
+	RepairBalance(ctx context.Context, accountId string, at time.Time) (*models.BalanceRepair, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type DuplicateStore interface {
+	// FindRecentTransaction returns the latest transaction with the same parties and amount
+	// recorded at or after since, or nil when there is none
+	FindRecentTransaction(ctx context.Context, from, to string, amount decimal.Decimal, since time.Time) (*models.Transaction, error)
+}
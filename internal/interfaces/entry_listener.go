@@ -0,0 +1,9 @@
+package interfaces
+
+import "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+
+// EntryListener is notified by the ledger after entries are committed.
+// Implementations must not block: they are called while account locks are held.
+type EntryListener interface {
+	EntriesCommitted(updates []models.BalanceUpdate)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// EntryStreamer is implemented by stores that can iterate entries straight off a
+// database cursor instead of loading the whole result set into memory.
+type EntryStreamer interface {
+	// StreamLedgerEntries calls fn for each entry in sequence order, optionally filtered by account.
+	// Iteration stops at the first error returned by fn.
+	StreamLedgerEntries(ctx context.Context, accountId string, fn func(models.LedgerEntry) error) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// EventFilterStore keeps the event filter, so every replica applies the same one
+type EventFilterStore interface {
+	// GetEventFilter returns nil without an error before a filter is first saved
+	GetEventFilter(ctx context.Context) (*models.EventFilter, error)
+	SaveEventFilter(ctx context.Context, filter models.EventFilter) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type FeeStore interface {
+	ListFeeSchedules(ctx context.Context) ([]models.FeeSchedule, error)
+	GetFeeSchedule(ctx context.Context, id string) (*models.FeeSchedule, error)
+	SaveFeeSchedule(ctx context.Context, schedule models.FeeSchedule) error
+	DeleteFeeSchedule(ctx context.Context, id string) error
+}
+
+// MultiLegStore can post transactions with more than one debit/credit pair in a single database transaction
+type MultiLegStore interface {
+	SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ErrChainHeadMoved means an account's chain gained an entry after the new entries were
+// linked onto it, e.g. from another instance; they must be linked again
+var ErrChainHeadMoved = errors.New("hash chain head moved since the entries were linked")
+
+// HashChainStore is implemented by stores that persist the per-account entry hash chain
+type HashChainStore interface {
+	// GetLastEntryHash returns the hash of the account's newest entry, or "" if it has none
+	GetLastEntryHash(accountId string) (string, error)
+
+	// GetAccountChain returns live and archived entries of the account ordered by sequence
+	GetAccountChain(ctx context.Context, accountId string) ([]models.LedgerEntry, error)
+	ListAccountIDs(ctx context.Context) ([]string, error)
+}
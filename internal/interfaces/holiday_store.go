@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// HolidayStore keeps the holidays of the business-day calendar, so every replica rolls
+// value dates the same way
+type HolidayStore interface {
+	// ListHolidays returns every holiday, ordered by currency and date
+	ListHolidays(ctx context.Context) ([]models.Holiday, error)
+	SaveHoliday(ctx context.Context, holiday models.Holiday) error
+	// DeleteHoliday reports whether the holiday existed
+	DeleteHoliday(ctx context.Context, currency, date string) (bool, error)
+}
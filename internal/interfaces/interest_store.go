@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type InterestStore interface {
+	ListInterestRates(ctx context.Context) ([]models.InterestRate, error)
+	SaveInterestRate(ctx context.Context, rate models.InterestRate) error
+	DeleteInterestRate(ctx context.Context, scope, id string) error
+
+	// ListInterestAccounts returns every open account with a positive effective rate
+	ListInterestAccounts(ctx context.Context) ([]models.InterestAccount, error)
+	// GetAccruedInterest sums the accrual postings made to the account
+	GetAccruedInterest(ctx context.Context, accountId string) (models.AccruedInterest, error)
+}
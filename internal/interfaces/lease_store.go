@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseStore backs leader election between replicas
+type LeaseStore interface {
+	// TryAcquireLease takes or renews the named lease for holder; it fails while another holder's lease is live
+	TryAcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
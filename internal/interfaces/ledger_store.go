@@ -12,6 +12,30 @@ type LedgerStore interface {
 	GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error)
 	GetLedgerEntries() ([]models.LedgerEntry, error)
 
-	TransactionExists(idempotencyKey string) (bool, error)
+	// TransactionExists looks the key up among the transactions of tenantId, as keys are
+	// unique per tenant. The platform ("") sees the keys of every live tenant; test mode
+	// runs as a test tenant, so its keys never settle a live posting.
+	TransactionExists(tenantId, idempotencyKey string) (bool, error)
 	SaveTransaction(tx models.Transaction, dbTx *sql.Tx) error
 }
+
+// StoreWrapper is a store decorating another one
+type StoreWrapper interface {
+	Unwrap() LedgerStore
+}
+
+// Capability finds an optional interface on the store or, failing that, on the stores it
+// decorates, so a decorator need not re-implement every capability it does not cover
+func Capability[T any](store LedgerStore) (T, bool) {
+	for {
+		if capability, ok := store.(T); ok {
+			return capability, true
+		}
+		wrapper, ok := store.(StoreWrapper)
+		if !ok {
+			var none T
+			return none, false
+		}
+		store = wrapper.Unwrap()
+	}
+}
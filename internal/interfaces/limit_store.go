@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type LimitStore interface {
+	GetLimitProfile(ctx context.Context, id string) (*models.LimitProfile, error)
+	ListLimitProfiles(ctx context.Context) ([]models.LimitProfile, error)
+	SaveLimitProfile(ctx context.Context, profile models.LimitProfile) error
+
+	// GetDebitTotals sums and counts the account's outgoing entries created at or after since
+	GetDebitTotals(ctx context.Context, accountId string, since time.Time) (decimal.Decimal, int, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type NettingStore interface {
+	SaveNettingObligation(ctx context.Context, obligation models.NettingObligation) error
+	ListNettingObligations(ctx context.Context, windowStart time.Time) ([]models.NettingObligation, error)
+
+	// ListDueNettingWindows returns the start of every window that ended by now and still has pending obligations
+	ListDueNettingWindows(ctx context.Context, now time.Time) ([]time.Time, error)
+
+	// SettleNettingObligations marks the given pending obligations as settled by transactionId
+	SettleNettingObligations(ctx context.Context, ids []string, transactionId string, settledAt time.Time) error
+}
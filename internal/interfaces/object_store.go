@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// ObjectStore keeps whole objects under a key, e.g. files in an S3 or GCS bucket
+type ObjectStore interface {
+	// PutObject creates or replaces the object at key
+	PutObject(ctx context.Context, key string, body []byte, contentType string) error
+}
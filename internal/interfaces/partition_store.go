@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// PartitionStore is implemented by stores that split ledger entries into monthly partitions
+type PartitionStore interface {
+	// EnsureEntryPartitions creates the partitions of the month of from and the months after it,
+	// returning the names of the partitions it created
+	EnsureEntryPartitions(ctx context.Context, from time.Time, monthsAhead int) ([]string, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// PaymentRequestStore keeps payment requests. A request is marked paid by the store in the
+// same database transaction that saves the transaction referencing it.
+type PaymentRequestStore interface {
+	SavePaymentRequest(ctx context.Context, request models.PaymentRequest) error
+	GetPaymentRequest(ctx context.Context, id string) (*models.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, payeeAccount string) ([]models.PaymentRequest, error)
+	// CancelPaymentRequest reports false when the request is no longer open
+	CancelPaymentRequest(ctx context.Context, id string, at time.Time) (bool, error)
+}
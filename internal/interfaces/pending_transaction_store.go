@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type PendingTransactionStore interface {
+	// SavePendingTransaction inserts a pending transaction; when its tenant already has its
+	// idempotency key pending it returns the existing record instead
+	SavePendingTransaction(ctx context.Context, pending models.PendingTransaction) (models.PendingTransaction, error)
+	GetPendingTransaction(ctx context.Context, id string) (*models.PendingTransaction, error)
+	ListPendingTransactions(ctx context.Context, status string) ([]models.PendingTransaction, error)
+	ListDuePendingTransactions(ctx context.Context, now time.Time) ([]models.PendingTransaction, error)
+
+	// TransitionPendingTransaction moves the record from one status to another only if it is
+	// still in from, so cancellation and execution can never both win; now stamps the change
+	TransitionPendingTransaction(ctx context.Context, id, from, to, lastError string, now time.Time) (bool, error)
+}
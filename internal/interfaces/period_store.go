@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type PeriodStore interface {
+	// GetPeriod returns nil without an error when the period was never created, which means it is open
+	GetPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error)
+	ListPeriods(ctx context.Context) ([]models.AccountingPeriod, error)
+	SavePeriod(ctx context.Context, period models.AccountingPeriod) error
+}
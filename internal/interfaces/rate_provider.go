@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateProvider supplies the exchange rate to convert one unit of from into to
+type RateProvider interface {
+	GetRate(ctx context.Context, from, to string) (decimal.Decimal, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type ReportStore interface {
+	// GetTrialBalance aggregates live and archived entries per account
+	GetTrialBalance(ctx context.Context) ([]models.TrialBalanceLine, error)
+
+	// FindUnbalancedTransactions returns the IDs of transactions whose legs do not
+	// sum to zero or lack the debit on the sender / credit on the receiver.
+	FindUnbalancedTransactions(ctx context.Context) ([]string, error)
+}
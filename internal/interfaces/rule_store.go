@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type RuleStore interface {
+	ListRules(ctx context.Context) ([]models.FraudRule, error)
+	GetRule(ctx context.Context, id string) (*models.FraudRule, error)
+	SaveRule(ctx context.Context, rule models.FraudRule) error
+	DeleteRule(ctx context.Context, id string) error
+
+	// HasPaidCounterparty reports whether any transaction from -> to has been posted before
+	HasPaidCounterparty(ctx context.Context, from, to string) (bool, error)
+	// CountDebitsSince counts the account's outgoing entries created at or after since
+	CountDebitsSince(ctx context.Context, accountId string, since time.Time) (int, error)
+}
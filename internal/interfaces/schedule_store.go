@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type ScheduleStore interface {
+	SaveSchedule(ctx context.Context, schedule models.Schedule) error
+	GetSchedule(ctx context.Context, id string) (*models.Schedule, error)
+	ListSchedules(ctx context.Context) ([]models.Schedule, error)
+	// ListDueSchedules returns active schedules whose next run is at or before now
+	ListDueSchedules(ctx context.Context, now time.Time) ([]models.Schedule, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// SnapshotStore is implemented by stores that can checkpoint account balances
+// and compact raw entries that are already covered by a checkpoint.
+type SnapshotStore interface {
+	GetLatestSnapshot(accountId string) (*models.BalanceSnapshot, error)
+	GetLatestSnapshotBefore(accountId string, asOf time.Time) (*models.BalanceSnapshot, error)
+	GetEntriesByAccountAfter(accountId string, afterSequence int64) ([]models.LedgerEntry, error)
+	GetAccountsWithEntriesAfterSnapshot(ctx context.Context) ([]string, error)
+	SaveBalanceSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error
+
+	// ArchiveEntriesBefore moves entries created before cutoff into the archive table,
+	// but only entries already covered by the account's latest snapshot.
+	ArchiveEntriesBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
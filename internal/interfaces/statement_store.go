@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type StatementStore interface {
+	// GetEntriesByAccountBetween returns live and archived entries with from <= created_at < to, ordered by sequence
+	GetEntriesByAccountBetween(ctx context.Context, accountId string, from, to time.Time) ([]models.LedgerEntry, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// StreamReconciliationStore answers which transactions the database holds, for checking
+// them against the events published about them
+type StreamReconciliationStore interface {
+	// TransactionIDsBetween lists the transactions created in [from, to); a zero from means
+	// since the first one
+	TransactionIDsBetween(ctx context.Context, from, to time.Time) ([]string, error)
+	// MissingTransactions returns those of the IDs that have no transaction row
+	MissingTransactions(ctx context.Context, ids []string) ([]string, error)
+}
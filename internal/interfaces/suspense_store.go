@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// SuspenseStore keeps the items parked in the suspense account
+type SuspenseStore interface {
+	SaveSuspenseItem(ctx context.Context, item models.SuspenseItem) error
+	GetSuspenseItem(ctx context.Context, id string) (*models.SuspenseItem, error)
+	// ListSuspenseItems lists items with the status, or all of them when it is empty, newest first
+	ListSuspenseItems(ctx context.Context, status string) ([]models.SuspenseItem, error)
+	// ResolveSuspenseItem reports false when the item is no longer open
+	ResolveSuspenseItem(ctx context.Context, id, account, transactionId string, at time.Time) (bool, error)
+}
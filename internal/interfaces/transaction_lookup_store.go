@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// TransactionLookupStore loads many transactions by ID at once, to show them next to
+// their entries
+type TransactionLookupStore interface {
+	// GetTransactions returns those of the IDs that exist, in no particular order
+	GetTransactions(ctx context.Context, ids []string) ([]models.Transaction, error)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type TransactionSearchStore interface {
+	SearchTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error)
+}
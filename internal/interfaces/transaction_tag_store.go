@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// TransactionTagStore changes the tags of a posted transaction; nothing else on it ever changes
+type TransactionTagStore interface {
+	UpdateTransactionTags(ctx context.Context, id string, update func(tags []string) ([]string, error)) (*models.Transaction, error)
+}
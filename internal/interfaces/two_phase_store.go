@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// TwoPhaseParticipantStore keeps this instance's legs of cross-instance transfers
+type TwoPhaseParticipantStore interface {
+	// SaveTwoPhaseLeg reports false when a leg with the same XID already exists
+	SaveTwoPhaseLeg(ctx context.Context, leg models.TwoPhaseLeg) (bool, error)
+	GetTwoPhaseLeg(ctx context.Context, xid string) (*models.TwoPhaseLeg, error)
+	// DecideTwoPhaseLeg moves a prepared leg to committed or aborted; it reports false when
+	// the leg was no longer prepared
+	DecideTwoPhaseLeg(ctx context.Context, xid, status, transactionId string, at time.Time) (bool, error)
+}
+
+// TwoPhaseCoordinatorStore keeps the transfers this instance coordinates
+type TwoPhaseCoordinatorStore interface {
+	// SaveCrossInstanceTransfer reports false when the idempotency key is already taken
+	SaveCrossInstanceTransfer(ctx context.Context, transfer models.CrossInstanceTransfer) (bool, error)
+	GetCrossInstanceTransfer(ctx context.Context, id string) (*models.CrossInstanceTransfer, error)
+	// GetCrossInstanceTransferByKey looks the key up among the transfers of the tenant of ctx
+	GetCrossInstanceTransferByKey(ctx context.Context, idempotencyKey string) (*models.CrossInstanceTransfer, error)
+	// DecideCrossInstanceTransfer records the outcome of a preparing transfer; it reports
+	// false when another run decided first
+	DecideCrossInstanceTransfer(ctx context.Context, id, status, reason string, at time.Time) (bool, error)
+	CompleteCrossInstanceTransfer(ctx context.Context, id string, at time.Time) error
+	// ListUnfinishedCrossInstanceTransfers returns transfers created before the cutoff that
+	// are still preparing or whose decision has not reached both legs
+	ListUnfinishedCrossInstanceTransfers(ctx context.Context, createdBefore time.Time) ([]models.CrossInstanceTransfer, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+type UsageStore interface {
+	// AddUsage adds the counts to those already stored for the same tenant, key and day
+	AddUsage(ctx context.Context, records []models.UsageRecord) error
+	ListUsage(ctx context.Context, filter models.UsageFilter) ([]models.UsageRecord, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ValueDateStore turns booked balances into value-dated ones
+type ValueDateStore interface {
+	// ValueDateAdjustment is what to add to an account's balance booked up to bookedBy to get
+	// its balance at the end of value date date (YYYY-MM-DD): entries booked by then but
+	// valued later are taken out, entries booked later but valued by then are added
+	ValueDateAdjustment(ctx context.Context, accountId, date string, bookedBy time.Time) (decimal.Decimal, error)
+}
@@ -0,0 +1,6 @@
+package interfaces
+
+// WebhookSender POSTs an event to a URL chosen per call rather than configured up front
+type WebhookSender interface {
+	Send(url, topic string, event any) error
+}
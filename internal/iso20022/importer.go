@@ -0,0 +1,199 @@
+package iso20022
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/schedules"
+)
+
+// reasonCodes maps ledger rejections onto ISO 20022 external status reason codes
+var reasonCodes = []struct {
+	err  error
+	code string
+}{
+	{ledger.ErrInsufficientFunds, "AM04"},
+	{ledger.ErrAccountClosed, "AC04"},
+	{ledger.ErrAccountFrozen, "AC06"},
+	{ledger.ErrLimitExceeded, "AM02"},
+	{ledger.ErrPossibleDuplicate, "DUPL"},
+	{ledger.ErrRateUnavailable, "AM03"},
+	{ledger.ErrPeriodClosed, "DT01"},
+	{ledger.ErrTransactionBlocked, "AG01"},
+	{ledger.ErrTenantMismatch, "AG01"},
+	{ledger.ErrCrossTenantTransfer, "AG01"},
+	{ledger.ErrAbnormalBalance, "AG01"},
+}
+
+func reasonFor(err error) *StatusReason {
+	for _, reason := range reasonCodes {
+		if errors.Is(err, reason.err) {
+			return &StatusReason{Code: reason.code, Additional: err.Error()}
+		}
+	}
+	return &StatusReason{Code: "NARR", Additional: err.Error()}
+}
+
+// Importer posts the credit transfers of pain.001 files and reports the outcome as pain.002.
+// The end-to-end ID is the idempotency key, so importing the same file twice posts nothing new.
+type Importer struct {
+	ledger    *ledger.Ledger
+	schedules *schedules.Service // nil to post future-dated transfers immediately
+	appLogger *slog.Logger
+}
+
+func NewImporter(ledgerService *ledger.Ledger, scheduleService *schedules.Service, appLogger *slog.Logger) *Importer {
+	return &Importer{
+		ledger:    ledgerService,
+		schedules: scheduleService,
+		appLogger: appLogger,
+	}
+}
+
+// Import processes every credit transfer of the document independently; one rejected
+// transfer does not stop the others
+func (i *Importer) Import(ctx context.Context, doc Pain001) Pain002 {
+	var report Pain002
+	report.Xmlns = pain002Namespace
+	report.Report.GroupHeader.MessageID = "STS-" + doc.GroupHeader.MessageID
+	report.Report.GroupHeader.CreatedAt = i.ledger.Now().UTC().Format(time.RFC3339)
+
+	count, sum := doc.Transfers()
+	group := &report.Report.OriginalGroup
+	group.MessageID = doc.GroupHeader.MessageID
+	group.MessageNameID = doc.MessageName()
+	group.NumberOfTxs = doc.GroupHeader.NumberOfTxs
+
+	// A header that does not match its content means the file is damaged; nothing is posted
+	if doc.GroupHeader.NumberOfTxs != count {
+		group.GroupStatus = StatusRejected
+		group.Reason = &StatusReason{Code: "AM18", Additional: "NbOfTxs does not match the number of transfers"}
+		return report
+	}
+	if !doc.GroupHeader.ControlSum.IsZero() && !doc.GroupHeader.ControlSum.Equal(sum) {
+		group.GroupStatus = StatusRejected
+		group.Reason = &StatusReason{Code: "AM10", Additional: "CtrlSum does not match the sum of the transfers"}
+		return report
+	}
+
+	accepted, pending := 0, 0
+	for _, info := range doc.PaymentInfos {
+		status := PaymentInfoStatus{PaymentInfoID: info.ID}
+		for _, transfer := range info.CreditTransfer {
+			txStatus := i.transfer(ctx, doc.GroupHeader, info, transfer)
+			switch txStatus.Status {
+			case StatusAccepted:
+				accepted++
+			case StatusPending:
+				pending++
+			}
+			status.Transactions = append(status.Transactions, txStatus)
+		}
+		report.Report.PaymentsStatus = append(report.Report.PaymentsStatus, status)
+	}
+
+	switch {
+	case accepted == count:
+		group.GroupStatus = StatusAccepted
+	case accepted+pending == count:
+		group.GroupStatus = StatusCustomer
+	case accepted+pending == 0:
+		group.GroupStatus = StatusRejected
+	default:
+		group.GroupStatus = StatusPartially
+	}
+	i.appLogger.Info("pain.001 imported",
+		"message_id", doc.GroupHeader.MessageID,
+		"transfers", count,
+		"accepted", accepted,
+		"pending", pending,
+	)
+	return report
+}
+
+func (i *Importer) transfer(ctx context.Context, header GroupHeader, info PaymentInfo, transfer CreditTransfer) TransactionStatus {
+	status := TransactionStatus{
+		InstructionID: transfer.InstructionID,
+		EndToEndID:    transfer.EndToEndID,
+		Status:        StatusRejected,
+	}
+	reject := func(code, reason string) TransactionStatus {
+		status.Reason = &StatusReason{Code: code, Additional: reason}
+		return status
+	}
+
+	from, to := info.DebtorAccount.LedgerAccount(), transfer.CreditorAccount.LedgerAccount()
+	switch {
+	case info.Method != "" && info.Method != "TRF":
+		return reject("NARR", "only credit transfers (PmtMtd TRF) are supported")
+	case transfer.EndToEndID == "" || transfer.EndToEndID == "NOTPROVIDED":
+		return reject("NARR", "a unique EndToEndId is required")
+	case from == "":
+		return reject("AC02", "debtor account is missing")
+	case to == "":
+		return reject("AC03", "creditor account is missing")
+	case transfer.Amount.Value.IsZero():
+		return reject("AM01", "amount is zero")
+	case transfer.Amount.Value.IsNegative():
+		return reject("AM12", "amount is negative")
+	}
+
+	// Amounts are debited in the debtor account's currency; conversion happens on the credit side
+	currency, err := i.ledger.AccountCurrency(ctx, from)
+	if err != nil {
+		status.Reason = reasonFor(err)
+		return status
+	}
+	if transfer.Amount.Currency != "" && transfer.Amount.Currency != currency {
+		return reject("AM03", "instructed currency "+transfer.Amount.Currency+" differs from the debtor account currency "+currency)
+	}
+
+	tx := models.Transaction{
+		ID:             i.ledger.NewID(),
+		IdempotencyKey: transfer.EndToEndID,
+		FromAccount:    from,
+		ToAccount:      to,
+		Amount:         transfer.Amount.Value,
+		CreatedAt:      i.ledger.Now(),
+		Reference:      transfer.EndToEndID,
+		Description:    transfer.Remittance,
+		Metadata: map[string]string{
+			"source":          "pain.001",
+			"message_id":      header.MessageID,
+			"payment_info_id": info.ID,
+			"instruction_id":  transfer.InstructionID,
+			"debtor_name":     info.DebtorName,
+			"creditor_name":   transfer.CreditorName,
+		},
+	}
+
+	// Transfers requested for a later date wait for it as future-dated transactions
+	executeAt, err := time.Parse("2006-01-02", info.ExecutionDate.String())
+	if err == nil && executeAt.After(i.ledger.Now()) && i.schedules != nil {
+		held, err := i.schedules.SchedulePayment(ctx, tx, executeAt)
+		if err != nil {
+			status.Reason = reasonFor(err)
+			return status
+		}
+		status.StatusID = held.ID
+		status.Status = StatusPending
+		return status
+	}
+
+	posted, exists, err := i.ledger.PostTransactionDetailed(ctx, tx)
+	if err != nil {
+		status.Reason = reasonFor(err)
+		return status
+	}
+	status.Status = StatusAccepted
+	if exists {
+		status.Reason = &StatusReason{Code: "NARR", Additional: "already processed in an earlier import"}
+		return status
+	}
+	status.StatusID = posted.ID
+	return status
+}
@@ -0,0 +1,113 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidDocument = errors.New("invalid pain.001 document")
+
+// Pain001 is a customer credit transfer initiation. Elements are matched by local name,
+// so pain.001.001.03 and later versions decode alike.
+type Pain001 struct {
+	XMLName      xml.Name
+	GroupHeader  GroupHeader   `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	PaymentInfos []PaymentInfo `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type GroupHeader struct {
+	MessageID       string          `xml:"MsgId"`
+	CreatedAt       string          `xml:"CreDtTm"`
+	NumberOfTxs     int             `xml:"NbOfTxs"`
+	ControlSum      decimal.Decimal `xml:"CtrlSum"`
+	InitiatingParty string          `xml:"InitgPty>Nm"`
+}
+
+// PaymentInfo groups the credit transfers debited from one account
+type PaymentInfo struct {
+	ID             string           `xml:"PmtInfId"`
+	Method         string           `xml:"PmtMtd"`
+	ExecutionDate  ExecutionDate    `xml:"ReqdExctnDt"`
+	DebtorName     string           `xml:"Dbtr>Nm"`
+	DebtorAccount  AccountID        `xml:"DbtrAcct"`
+	CreditTransfer []CreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+// ExecutionDate is a plain date up to pain.001.001.08 and wrapped in <Dt> from version 09
+type ExecutionDate struct {
+	Value string `xml:",chardata"`
+	Date  string `xml:"Dt"`
+}
+
+func (d ExecutionDate) String() string {
+	if d.Date != "" {
+		return strings.TrimSpace(d.Date)
+	}
+	return strings.TrimSpace(d.Value)
+}
+
+// AccountID holds either an IBAN or a proprietary identifier
+type AccountID struct {
+	IBAN     string `xml:"Id>IBAN"`
+	Other    string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+// LedgerAccount is the ledger account ID the identifier maps to
+func (a AccountID) LedgerAccount() string {
+	if a.IBAN != "" {
+		return strings.TrimSpace(a.IBAN)
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+type CreditTransfer struct {
+	InstructionID   string        `xml:"PmtId>InstrId"`
+	EndToEndID      string        `xml:"PmtId>EndToEndId"`
+	Amount          InstructedAmt `xml:"Amt>InstdAmt"`
+	CreditorName    string        `xml:"Cdtr>Nm"`
+	CreditorAccount AccountID     `xml:"CdtrAcct"`
+	Remittance      string        `xml:"RmtInf>Ustrd"`
+}
+
+type InstructedAmt struct {
+	Value    decimal.Decimal `xml:",chardata"`
+	Currency string          `xml:"Ccy,attr"`
+}
+
+// ParsePain001 decodes a pain.001 document and checks its group header totals
+func ParsePain001(r io.Reader) (Pain001, error) {
+	var doc Pain001
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return Pain001{}, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if doc.GroupHeader.MessageID == "" {
+		return Pain001{}, fmt.Errorf("%w: GrpHdr/MsgId is missing", ErrInvalidDocument)
+	}
+	return doc, nil
+}
+
+// MessageName is the message identifier of the document, e.g. pain.001.001.09
+func (d Pain001) MessageName() string {
+	if name, ok := strings.CutPrefix(d.XMLName.Space, "urn:iso:std:iso:20022:tech:xsd:"); ok {
+		return name
+	}
+	return "pain.001.001.03"
+}
+
+// Transfers returns the number of credit transfers and their total amount
+func (d Pain001) Transfers() (int, decimal.Decimal) {
+	count, sum := 0, decimal.Zero
+	for _, info := range d.PaymentInfos {
+		for _, transfer := range info.CreditTransfer {
+			count++
+			sum = sum.Add(transfer.Amount.Value)
+		}
+	}
+	return count, sum
+}
@@ -0,0 +1,68 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// Transaction and group statuses used in the status report
+const (
+	StatusAccepted  = "ACSC" // posted to the ledger
+	StatusCustomer  = "ACCP" // group status when every transfer was accepted but some are still pending
+	StatusPending   = "PDNG" // held until the requested execution date
+	StatusRejected  = "RJCT"
+	StatusPartially = "PART" // group status when only some transfers were accepted
+)
+
+// Pain002 is a customer payment status report answering a pain.001
+type Pain002 struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Report  struct {
+		GroupHeader struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		OriginalGroup  OriginalGroup       `xml:"OrgnlGrpInfAndSts"`
+		PaymentsStatus []PaymentInfoStatus `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+type OriginalGroup struct {
+	MessageID     string        `xml:"OrgnlMsgId"`
+	MessageNameID string        `xml:"OrgnlMsgNmId"`
+	NumberOfTxs   int           `xml:"OrgnlNbOfTxs"`
+	GroupStatus   string        `xml:"GrpSts"`
+	Reason        *StatusReason `xml:"StsRsnInf,omitempty"`
+}
+
+type PaymentInfoStatus struct {
+	PaymentInfoID string              `xml:"OrgnlPmtInfId"`
+	Transactions  []TransactionStatus `xml:"TxInfAndSts"`
+}
+
+type TransactionStatus struct {
+	StatusID      string        `xml:"StsId,omitempty"` // ledger transaction ID when posted
+	InstructionID string        `xml:"OrgnlInstrId,omitempty"`
+	EndToEndID    string        `xml:"OrgnlEndToEndId"`
+	Status        string        `xml:"TxSts"`
+	Reason        *StatusReason `xml:"StsRsnInf,omitempty"`
+}
+
+// StatusReason carries an ISO 20022 external status reason code such as AM04 (insufficient funds)
+type StatusReason struct {
+	Code       string `xml:"Rsn>Cd"`
+	Additional string `xml:"AddtlInf,omitempty"`
+}
+
+// Write encodes the report with an XML declaration
+func (p Pain002) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(p)
+}
@@ -0,0 +1,351 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/validation"
+)
+
+var (
+	ErrAccountFrozen        = errors.New("account is frozen")
+	ErrAccountClosed        = errors.New("account is closed")
+	ErrNonZeroBalance       = errors.New("account balance must be zero to close")
+	ErrAccountsNotSupported = errors.New("store does not support account controls")
+	ErrInvalidStatusChange  = errors.New("invalid account status change")
+	ErrAccountIDRequired    = errors.New("account id is required")
+	ErrAccountExists        = errors.New("account already exists")
+)
+
+func envBool(key string, def bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// getAccount returns the stored account or a default active one when it has no row
+func (l *Ledger) getAccount(ctx context.Context, id string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{ID: id, Status: models.AccountActive}, nil
+	}
+	account, err := l.accounts.GetAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if account == nil {
+		now := l.clock.Now().UTC()
+		return models.Account{ID: id, Status: models.AccountActive, CreatedAt: now, UpdatedAt: now}, nil
+	}
+	return *account, nil
+}
+
+// CreateAccount stores the controls of a new account up front instead of waiting for its
+// first posting. The account belongs to the tenant of the request.
+func (l *Ledger) CreateAccount(ctx context.Context, account models.Account) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if account.ID == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	if err := l.validation.Account(account); err != nil {
+		return models.Account{}, err
+	}
+	if account.Class != "" && models.NormalBalance(account.Class) == "" {
+		return models.Account{}, fmt.Errorf("%w: %q", ErrInvalidAccountClass, account.Class)
+	}
+	if account.Currency != "" {
+		account.Currency = strings.ToUpper(account.Currency)
+		if !currencyCode.MatchString(account.Currency) {
+			return models.Account{}, ErrInvalidCurrency
+		}
+	}
+	if account.OverdraftLimit.IsNegative() {
+		return models.Account{}, ErrInvalidOverdraftLimit
+	}
+
+	mu := l.getAccountLock(account.ID)
+	mu.Lock()
+	defer mu.Unlock()
+
+	existing, err := l.accounts.GetAccount(ctx, account.ID)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if existing != nil {
+		return *existing, fmt.Errorf("%w: %s", ErrAccountExists, account.ID)
+	}
+	// Accounts only get a row once their controls change, so one already holding money exists too
+	balance, err := l.GetBalance(account.ID)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if !balance.IsZero() {
+		return models.Account{}, fmt.Errorf("%w: %s", ErrAccountExists, account.ID)
+	}
+
+	if !l.isSystemAccount(account.ID) {
+		account.TenantID = tenant.FromContext(ctx)
+	}
+	if account.ParentID != "" {
+		if err := l.checkParent(ctx, account, account.ParentID); err != nil {
+			return models.Account{}, err
+		}
+	}
+
+	now := l.clock.Now().UTC()
+	account.Status = models.AccountActive
+	account.CreatedAt = now
+	account.UpdatedAt = now
+	if err := l.accounts.SaveAccount(ctx, account); err != nil {
+		return models.Account{}, err
+	}
+
+	l.recordAudit(ctx, "account.create", "account:"+account.ID, nil, account.Redacted())
+	return account, nil
+}
+
+// checkAccountStatus rejects debits from frozen accounts, and credits too unless configured otherwise.
+// Must be called while holding both account locks.
+func (l *Ledger) checkAccountStatus(ctx context.Context, tx models.Transaction) error {
+	if l.accounts == nil {
+		return nil
+	}
+
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		return err
+	}
+	if from.Status == models.AccountClosed {
+		return fmt.Errorf("%w: %s", ErrAccountClosed, from.ID)
+	}
+	if from.Status == models.AccountFrozen {
+		return fmt.Errorf("%w: %s", ErrAccountFrozen, from.ID)
+	}
+
+	to, err := l.getAccount(ctx, tx.ToAccount)
+	if err != nil {
+		return err
+	}
+	if to.Status == models.AccountClosed {
+		return fmt.Errorf("%w: %s", ErrAccountClosed, to.ID)
+	}
+	if to.Status == models.AccountFrozen && !l.frozenAcceptsCredits {
+		return fmt.Errorf("%w: %s", ErrAccountFrozen, to.ID)
+	}
+	return nil
+}
+
+// FreezeAccount blocks debits from the account until it is unfrozen
+func (l *Ledger) FreezeAccount(ctx context.Context, id, reason string) (models.Account, error) {
+	return l.changeAccountStatus(ctx, id, models.AccountFrozen, reason)
+}
+
+// UnfreezeAccount makes a frozen account active again
+func (l *Ledger) UnfreezeAccount(ctx context.Context, id, reason string) (models.Account, error) {
+	return l.changeAccountStatus(ctx, id, models.AccountActive, reason)
+}
+
+func (l *Ledger) changeAccountStatus(ctx context.Context, id, status, reason string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+
+	// Serialise with postings on the same account so a status change is never interleaved with one
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	return l.changeAccountStatusLocked(ctx, id, status, reason)
+}
+
+// changeAccountStatusLocked must be called while holding the account lock
+func (l *Ledger) changeAccountStatusLocked(ctx context.Context, id, status, reason string) (models.Account, error) {
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if !validStatusChange(before.Status, status) {
+		return before, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusChange, before.Status, status)
+	}
+
+	if status == models.AccountClosed {
+		balance, err := l.GetBalance(id)
+		if err != nil {
+			return models.Account{}, err
+		}
+		if !balance.IsZero() {
+			return before, fmt.Errorf("%w: balance is %s", ErrNonZeroBalance, balance)
+		}
+	}
+
+	after := before
+	after.Status = status
+	after.StatusReason = reason
+	after.UpdatedAt = l.clock.Now().UTC()
+	if status == models.AccountClosed {
+		after.ClosedAt = &after.UpdatedAt
+	}
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+
+	l.recordAudit(ctx, "account.status_change", "account:"+id, before.Redacted(), after.Redacted())
+	l.publish("accounts.status_changed", events.AccountStatusChanged{
+		AccountID:      id,
+		PreviousStatus: before.Status,
+		Status:         status,
+		Reason:         reason,
+		OccurredAt:     after.UpdatedAt,
+	})
+
+	l.appLogger.Info("account status changed",
+		"account_id", id,
+		"from", before.Status,
+		"to", status,
+	)
+	return after, nil
+}
+
+func validStatusChange(from, to string) bool {
+	switch to {
+	case models.AccountClosed:
+		return from == models.AccountActive || from == models.AccountFrozen
+	case models.AccountFrozen:
+		return from == models.AccountActive
+	case models.AccountActive:
+		return from == models.AccountFrozen
+	}
+	return false
+}
+
+// publish sends an event and only logs failures; the state change is already committed
+func (l *Ledger) publish(topic string, event any) {
+	if err := l.publisher.Publish(topic, event); err != nil {
+		l.appLogger.Error("failed to publish kafka event",
+			"topic", topic,
+			"error", err,
+		)
+	}
+}
+
+// CloseAccount permanently closes an account whose balance is exactly zero.
+// When sweepTo is set, any residue is first moved to (or drawn from) that account.
+func (l *Ledger) CloseAccount(ctx context.Context, id, sweepTo, reason string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+
+	if sweepTo != "" && sweepTo != id {
+		if err := l.sweepBalance(ctx, id, sweepTo); err != nil {
+			return models.Account{}, err
+		}
+	}
+
+	// The balance is re-checked under the lock, so a posting that slipped in after the
+	// sweep makes the close fail instead of stranding money on a closed account.
+	return l.changeAccountStatus(ctx, id, models.AccountClosed, reason)
+}
+
+func (l *Ledger) sweepBalance(ctx context.Context, id, sweepTo string) error {
+	balance, err := l.GetBalance(id)
+	if err != nil || balance.IsZero() {
+		return err
+	}
+
+	tx := models.Transaction{
+		ID:             l.NewID(),
+		IdempotencyKey: "account-close-sweep-" + uuid.New().String(),
+		FromAccount:    id,
+		ToAccount:      sweepTo,
+		Amount:         balance,
+		CreatedAt:      l.clock.Now(),
+		Description:    "Residual balance sweep on account closure",
+	}
+	// A negative balance is topped up from the sweep account instead
+	if balance.IsNegative() {
+		tx.FromAccount, tx.ToAccount = sweepTo, id
+		tx.Amount = balance.Neg()
+	}
+
+	_, err = l.PostTransaction(ctx, tx)
+	return err
+}
+
+var ErrAccountSearchNotSupported = errors.New("store does not support account search")
+
+// SearchAccounts lists the caller's accounts matching every filter set. When more match
+// than the limit, it also returns the cursor to pass as filter.After for the next page.
+func (l *Ledger) SearchAccounts(ctx context.Context, filter models.AccountFilter) ([]models.Account, *models.AccountCursor, error) {
+	search, ok := interfaces.Capability[interfaces.AccountSearchStore](l.store)
+	if !ok {
+		return nil, nil, ErrAccountSearchNotSupported
+	}
+	if filter.Sort == "" {
+		filter.Sort = "id"
+	}
+	filter.Currency = strings.ToUpper(filter.Currency)
+	if err := validateAccountFilter(filter); err != nil {
+		return nil, nil, err
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+
+	// One extra row tells whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+	accounts, err := search.SearchAccounts(ctx, filter)
+	if err != nil || len(accounts) <= limit {
+		return accounts, nil, err
+	}
+	accounts = accounts[:limit]
+	last := accounts[limit-1]
+	next := &models.AccountCursor{Sort: filter.Sort, ID: last.ID}
+	switch strings.TrimPrefix(filter.Sort, "-") {
+	case "created_at":
+		next.At = last.CreatedAt
+	case "updated_at":
+		next.At = last.UpdatedAt
+	}
+	return accounts, next, nil
+}
+
+func validateAccountFilter(filter models.AccountFilter) error {
+	var errs validation.Errors
+	switch filter.Status {
+	case "", models.AccountActive, models.AccountFrozen, models.AccountClosed:
+	default:
+		errs = append(errs, validation.FieldError{Field: "status", Message: "must be active, frozen or closed"})
+	}
+	if filter.Currency != "" && !currencyCode.MatchString(filter.Currency) {
+		errs = append(errs, validation.FieldError{Field: "currency", Message: "must be an ISO 4217 code"})
+	}
+	if !slices.Contains(models.AccountSorts, filter.Sort) {
+		errs = append(errs, validation.FieldError{Field: "sort", Message: "must be one of " + strings.Join(models.AccountSorts, ", ")})
+	} else if filter.After != nil && filter.After.Sort != filter.Sort {
+		errs = append(errs, validation.FieldError{Field: "cursor", Message: "was made for sort " + filter.After.Sort})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrAliasesNotSupported = errors.New("store does not support account aliases")
+	ErrInvalidAlias        = errors.New("invalid alias")
+	ErrAliasExists         = errors.New("alias already in use")
+	ErrAliasNotFound       = errors.New("alias not found")
+)
+
+// normalizeAlias puts the alias in its stored form and validates it for its type
+func normalizeAlias(aliasType, alias string) (string, error) {
+	switch aliasType {
+	case models.AliasIBAN:
+		iban := strings.ToUpper(strings.ReplaceAll(alias, " ", ""))
+		if !validIBAN(iban) {
+			return "", fmt.Errorf("%w: %q is not a valid IBAN", ErrInvalidAlias, alias)
+		}
+		return iban, nil
+	case models.AliasCardToken, models.AliasPartner:
+		if alias == "" || len(alias) > 128 || strings.ContainsFunc(alias, unicode.IsSpace) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidAlias, alias)
+		}
+		return alias, nil
+	default:
+		return "", fmt.Errorf("%w: unknown type %q", ErrInvalidAlias, aliasType)
+	}
+}
+
+// validIBAN checks the shape and the ISO 13616 mod-97 check digits
+func validIBAN(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	remainder := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			remainder = (remainder*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// CreateAlias points an external identifier at an account of the caller's tenant
+func (l *Ledger) CreateAlias(ctx context.Context, alias models.AccountAlias) (models.AccountAlias, error) {
+	if l.aliases == nil {
+		return models.AccountAlias{}, ErrAliasesNotSupported
+	}
+	if alias.AccountID == "" {
+		return models.AccountAlias{}, ErrAccountIDRequired
+	}
+	normalized, err := normalizeAlias(alias.Type, alias.Alias)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+	if err := l.CheckAccountAccess(ctx, alias.AccountID); err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	alias.Alias = normalized
+	alias.TenantID = tenant.FromContext(ctx)
+	alias.CreatedAt = l.clock.Now().UTC()
+	created, err := l.aliases.CreateAlias(ctx, alias)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+	if !created {
+		return models.AccountAlias{}, fmt.Errorf("%w: %s", ErrAliasExists, alias.Alias)
+	}
+	l.recordAudit(ctx, "alias.create", "account:"+alias.AccountID, nil, alias.Redacted())
+	return alias, nil
+}
+
+// GetAlias looks an alias up as given; IBANs may be written with spaces or in lower case
+func (l *Ledger) GetAlias(ctx context.Context, alias string) (models.AccountAlias, error) {
+	if l.aliases == nil {
+		return models.AccountAlias{}, ErrAliasesNotSupported
+	}
+	found, err := l.aliases.GetAlias(ctx, alias)
+	if err == nil && found == nil {
+		// Retry in the stored form of an IBAN
+		if iban, ibanErr := normalizeAlias(models.AliasIBAN, alias); ibanErr == nil && iban != alias {
+			found, err = l.aliases.GetAlias(ctx, iban)
+		}
+	}
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+	if found == nil {
+		return models.AccountAlias{}, fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	return *found, nil
+}
+
+// ResolveAlias returns the account behind an alias of the caller's tenant
+func (l *Ledger) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	found, err := l.GetAlias(ctx, alias)
+	if err != nil {
+		return "", err
+	}
+	return found.AccountID, nil
+}
+
+func (l *Ledger) ListAliases(ctx context.Context, accountId string) ([]models.AccountAlias, error) {
+	if l.aliases == nil {
+		return nil, ErrAliasesNotSupported
+	}
+	return l.aliases.ListAliases(ctx, accountId)
+}
+
+func (l *Ledger) DeleteAlias(ctx context.Context, alias string) error {
+	before, err := l.GetAlias(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if err := l.aliases.DeleteAlias(ctx, before.Alias); err != nil {
+		return err
+	}
+	l.recordAudit(ctx, "alias.delete", "account:"+before.AccountID, before.Redacted(), nil)
+	return nil
+}
@@ -0,0 +1,181 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrBalanceAlertsNotSupported = errors.New("store does not support balance alerts")
+	ErrBalanceAlertNotFound      = errors.New("balance alert not found")
+	ErrInvalidBalanceAlert       = errors.New("invalid balance alert")
+)
+
+// SetWebhookSender plugs in what POSTs crossed alerts to the URL registered with them.
+// Without one, alerts are only published as events.
+func (l *Ledger) SetWebhookSender(sender interfaces.WebhookSender) {
+	l.webhooks = sender
+}
+
+func validateBalanceAlert(alert models.BalanceAlert) error {
+	switch alert.Type {
+	case models.AlertTypeBalanceBelow:
+	case models.AlertTypeDebitAbove:
+		if !alert.Threshold.IsPositive() {
+			return fmt.Errorf("%w: threshold must be positive", ErrInvalidBalanceAlert)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidBalanceAlert, alert.Type)
+	}
+	if alert.WebhookURL != "" {
+		u, err := url.Parse(alert.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook_url must be an http or https URL", ErrInvalidBalanceAlert)
+		}
+	}
+	return nil
+}
+
+// ListBalanceAlerts returns the alerts registered on an account
+func (l *Ledger) ListBalanceAlerts(ctx context.Context, accountId string) ([]models.BalanceAlert, error) {
+	if l.alerts == nil {
+		return nil, ErrBalanceAlertsNotSupported
+	}
+	return l.alerts.ListBalanceAlerts(ctx, accountId)
+}
+
+// SaveBalanceAlert registers an alert, or replaces one of the same account
+func (l *Ledger) SaveBalanceAlert(ctx context.Context, alert models.BalanceAlert) (models.BalanceAlert, error) {
+	if l.alerts == nil {
+		return models.BalanceAlert{}, ErrBalanceAlertsNotSupported
+	}
+	if alert.AccountID == "" {
+		return models.BalanceAlert{}, ErrAccountIDRequired
+	}
+	if alert.ID == "" {
+		alert.ID = uuid.New().String()
+	}
+	if err := validateBalanceAlert(alert); err != nil {
+		return models.BalanceAlert{}, err
+	}
+
+	before, err := l.alerts.GetBalanceAlert(ctx, alert.ID)
+	if err != nil {
+		return models.BalanceAlert{}, err
+	}
+	// An alert cannot be moved to another account through the path of this one
+	if before != nil && before.AccountID != alert.AccountID {
+		return models.BalanceAlert{}, fmt.Errorf("%w: %s", ErrBalanceAlertNotFound, alert.ID)
+	}
+	alert.UpdatedAt = l.clock.Now().UTC()
+	if err := l.alerts.SaveBalanceAlert(ctx, alert); err != nil {
+		return models.BalanceAlert{}, err
+	}
+	l.recordAudit(ctx, "balance_alert.save", "balance_alert:"+alert.ID, before, alert)
+	return alert, nil
+}
+
+func (l *Ledger) DeleteBalanceAlert(ctx context.Context, accountId, id string) error {
+	if l.alerts == nil {
+		return ErrBalanceAlertsNotSupported
+	}
+	before, err := l.alerts.GetBalanceAlert(ctx, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || before.AccountID != accountId {
+		return fmt.Errorf("%w: %s", ErrBalanceAlertNotFound, id)
+	}
+	if err := l.alerts.DeleteBalanceAlert(ctx, id); err != nil {
+		return err
+	}
+	l.recordAudit(ctx, "balance_alert.delete", "balance_alert:"+id, before, nil)
+	return nil
+}
+
+// notifyThresholds checks the alerts of every account the posting touched. It runs after
+// the commit while the account locks are still held, so the balance read is the one the
+// posting left and the balance before it is that minus the posting's legs. A failure is
+// logged: the posting stands whether or not its alerts went out.
+func (l *Ledger) notifyThresholds(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) {
+	if l.alerts == nil {
+		return
+	}
+	var accountIds []string
+	changes := map[string]decimal.Decimal{}
+	debits := map[string]decimal.Decimal{}
+	for _, entry := range entries {
+		if _, seen := changes[entry.AccountID]; !seen {
+			accountIds = append(accountIds, entry.AccountID)
+		}
+		changes[entry.AccountID] = changes[entry.AccountID].Add(entry.Amount)
+		if entry.Amount.IsNegative() {
+			debits[entry.AccountID] = debits[entry.AccountID].Sub(entry.Amount)
+		}
+	}
+
+	alerts, err := l.alerts.ListBalanceAlerts(ctx, accountIds...)
+	if err != nil {
+		l.appLogger.Error("failed to load balance alerts", "transaction_id", tx.ID, "error", err)
+		return
+	}
+	balances := map[string]decimal.Decimal{}
+	for _, alert := range alerts {
+		if !alert.Enabled {
+			continue
+		}
+		balance, ok := balances[alert.AccountID]
+		if !ok {
+			if balance, err = l.GetBalance(alert.AccountID); err != nil {
+				l.appLogger.Error("failed to read balance for balance alert",
+					"alert_id", alert.ID,
+					"account_id", alert.AccountID,
+					"error", err,
+				)
+				continue
+			}
+			balances[alert.AccountID] = balance
+		}
+
+		var crossed bool
+		switch alert.Type {
+		case models.AlertTypeBalanceBelow:
+			before := balance.Sub(changes[alert.AccountID])
+			crossed = !before.LessThan(alert.Threshold) && balance.LessThan(alert.Threshold)
+		case models.AlertTypeDebitAbove:
+			crossed = debits[alert.AccountID].GreaterThan(alert.Threshold)
+		}
+		if !crossed {
+			continue
+		}
+
+		event := events.AccountThresholdCrossed{
+			AlertID:       alert.ID,
+			AccountID:     alert.AccountID,
+			Type:          alert.Type,
+			Threshold:     alert.Threshold,
+			Balance:       balance,
+			Amount:        debits[alert.AccountID],
+			TransactionID: tx.ID,
+			OccurredAt:    l.clock.Now(),
+		}
+		l.publish("accounts.threshold_crossed", event)
+		if alert.WebhookURL != "" && l.webhooks != nil {
+			if err := l.webhooks.Send(alert.WebhookURL, "accounts.threshold_crossed", event); err != nil {
+				l.appLogger.Error("failed to queue balance alert webhook",
+					"alert_id", alert.ID,
+					"account_id", alert.AccountID,
+					"error", err,
+				)
+			}
+		}
+	}
+}
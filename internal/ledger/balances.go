@@ -0,0 +1,81 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/validation"
+)
+
+// MaxBatchBalances caps how many accounts one batch lookup may name
+const MaxBatchBalances = 1000
+
+// GetBalances looks up many balances at once, optionally keeping only accounts held in one
+// of currencies. Balances come back sorted by account ID, each once; accounts of another tenant
+// are reported as not found, by the same rule as CheckAccountAccess.
+func (l *Ledger) GetBalances(ctx context.Context, accountIds, currencies []string) (models.BalanceBatch, error) {
+	if err := l.validation.AccountIDs("account_ids", accountIds); err != nil {
+		return models.BalanceBatch{}, err
+	}
+	ids := slices.Compact(slices.Sorted(slices.Values(accountIds)))
+	if len(ids) > MaxBatchBalances {
+		return models.BalanceBatch{}, validation.Errors{{Field: "account_ids", Message: fmt.Sprintf("must name at most %d accounts", MaxBatchBalances)}}
+	}
+	wanted := make([]string, len(currencies))
+	for i, code := range currencies {
+		wanted[i] = strings.ToUpper(code)
+	}
+
+	balances, err := l.batchBalances(ctx, ids)
+	if err != nil {
+		return models.BalanceBatch{}, err
+	}
+
+	tenantId := tenant.FromContext(ctx)
+	batch := models.BalanceBatch{Balances: make([]models.BatchBalance, 0, len(balances))}
+	for _, balance := range balances {
+		if l.accounts != nil && !accountVisible(tenantId, balance.AccountID, balance.TenantID) {
+			batch.NotFound = append(batch.NotFound, balance.AccountID)
+			continue
+		}
+		if balance.Currency == "" {
+			balance.Currency = l.baseCurrency
+		}
+		if len(wanted) > 0 && !slices.Contains(wanted, balance.Currency) {
+			continue
+		}
+		batch.Balances = append(batch.Balances, balance)
+	}
+	return batch, nil
+}
+
+// batchBalances reads every balance in one query when the store can, otherwise one by one
+func (l *Ledger) batchBalances(ctx context.Context, ids []string) ([]models.BatchBalance, error) {
+	if batch, ok := interfaces.Capability[interfaces.BatchBalanceStore](l.store); ok {
+		return batch.GetBalances(ctx, ids)
+	}
+
+	balances := make([]models.BatchBalance, 0, len(ids))
+	for _, id := range ids {
+		account, err := l.getAccount(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := l.GetBalance(id)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, models.BatchBalance{
+			AccountID: id,
+			TenantID:  account.TenantID,
+			Currency:  account.Currency,
+			Balance:   balance,
+		})
+	}
+	return balances, nil
+}
@@ -0,0 +1,179 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidAccountClass = errors.New("account class must be asset, liability, equity, revenue or expense")
+	ErrAbnormalBalance     = errors.New("posting leaves account on the wrong side of its normal balance")
+)
+
+// NormalBalancePolicy decides what happens to a posting that leaves a classified
+// account on the wrong side of its normal balance
+type NormalBalancePolicy string
+
+const (
+	// NormalBalanceWarn logs the posting and lets it through
+	NormalBalanceWarn NormalBalancePolicy = "warn"
+	// NormalBalanceReject refuses the posting with ErrAbnormalBalance
+	NormalBalanceReject NormalBalancePolicy = "reject"
+	// NormalBalanceOff skips the check
+	NormalBalanceOff NormalBalancePolicy = "off"
+)
+
+func normalBalancePolicyFromEnv() NormalBalancePolicy {
+	switch policy := NormalBalancePolicy(os.Getenv("NORMAL_BALANCE_POLICY")); policy {
+	case NormalBalanceReject, NormalBalanceOff:
+		return policy
+	}
+	return NormalBalanceWarn
+}
+
+func envString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// systemAccountsFromEnv lists the internal accounts with the class each one is kept in
+func systemAccountsFromEnv(feeAccount string) []models.SystemAccount {
+	return []models.SystemAccount{
+		{Role: models.SystemAccountFees, AccountID: feeAccount, Class: models.ClassRevenue},
+		{Role: models.SystemAccountSuspense, AccountID: envString("SUSPENSE_ACCOUNT", "suspense"), Class: models.ClassLiability},
+		{Role: models.SystemAccountSettlement, AccountID: envString("SETTLEMENT_ACCOUNT", "settlement"), Class: models.ClassAsset},
+	}
+}
+
+// SystemAccounts lists the internal accounts the ledger posts to on its own
+func (l *Ledger) SystemAccounts() []models.SystemAccount {
+	return append([]models.SystemAccount(nil), l.systemAccounts...)
+}
+
+// SystemAccount returns the account ID configured for a role, or "" for an unknown role
+func (l *Ledger) SystemAccount(role string) string {
+	for _, account := range l.systemAccounts {
+		if account.Role == role {
+			return account.AccountID
+		}
+	}
+	return ""
+}
+
+func (l *Ledger) isSystemAccount(id string) bool {
+	for _, account := range l.systemAccounts {
+		if account.AccountID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureSystemAccounts gives every system account its class unless an operator already set one
+func (l *Ledger) EnsureSystemAccounts(ctx context.Context) error {
+	if l.accounts == nil {
+		return nil
+	}
+	for _, system := range l.systemAccounts {
+		mu := l.getAccountLock(system.AccountID)
+		mu.Lock()
+		account, err := l.getAccount(ctx, system.AccountID)
+		if err == nil && account.Class == "" {
+			account.Class = system.Class
+			account.UpdatedAt = l.clock.Now().UTC()
+			err = l.accounts.SaveAccount(ctx, account)
+		}
+		mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("system account %s: %w", system.AccountID, err)
+		}
+	}
+	return nil
+}
+
+// SetAccountClass places an account in the chart of accounts; an empty class unclassifies it
+func (l *Ledger) SetAccountClass(ctx context.Context, id, class string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	if class != "" && models.NormalBalance(class) == "" {
+		return models.Account{}, fmt.Errorf("%w: %q", ErrInvalidAccountClass, class)
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	after := before
+	after.Class = class
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+	l.recordAudit(ctx, "account.class", "account:"+id, before.Redacted(), after.Redacted())
+	return after, nil
+}
+
+// checkNormalBalance looks at the balances the two main legs leave behind. Credit-normal
+// accounts may still dip into an agreed overdraft. Must be called under the account locks.
+func (l *Ledger) checkNormalBalance(ctx context.Context, tx models.Transaction) error {
+	if l.accounts == nil || l.normalBalance == NormalBalanceOff {
+		return nil
+	}
+
+	received := tx.Amount
+	if tx.FX != nil {
+		received = tx.FX.ConvertedAmount
+	}
+	movements := []struct {
+		accountId string
+		amount    decimal.Decimal
+	}{
+		{tx.FromAccount, tx.Amount.Add(tx.TotalFees()).Neg()},
+		{tx.ToAccount, received},
+	}
+
+	for _, movement := range movements {
+		account, err := l.getAccount(ctx, movement.accountId)
+		if err != nil {
+			return err
+		}
+		if account.Class == "" {
+			continue
+		}
+		balance, err := l.GetBalance(movement.accountId)
+		if err != nil {
+			return err
+		}
+		after := balance.Add(movement.amount)
+		if models.IsNormalBalance(account.Class, after) ||
+			(models.NormalBalance(account.Class) == models.NormalCredit && !after.LessThan(account.OverdraftLimit.Neg())) {
+			continue
+		}
+
+		if l.normalBalance == NormalBalanceReject {
+			return fmt.Errorf("%w: %s (%s) would be at %s", ErrAbnormalBalance, account.ID, account.Class, after)
+		}
+		l.appLogger.Warn("posting leaves account on the wrong side of its normal balance",
+			"transaction_id", tx.ID,
+			"account_id", account.ID,
+			"class", account.Class,
+			"balance_after", after.String(),
+		)
+	}
+	return nil
+}
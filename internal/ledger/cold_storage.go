@@ -0,0 +1,26 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrColdStorageNotSupported = errors.New("store does not support cold storage")
+
+// FreezeEntries moves archived entries older than after into compressed cold storage.
+// Only entries already compacted into the archive are moved, so balances and snapshots
+// are unaffected.
+func (l *Ledger) FreezeEntries(ctx context.Context, after time.Duration) (int64, error) {
+	if l.cold == nil {
+		return 0, ErrColdStorageNotSupported
+	}
+
+	frozen, err := l.cold.FreezeEntriesBefore(ctx, l.clock.Now().Add(-after))
+	if err != nil {
+		return 0, err
+	}
+
+	l.appLogger.Info("ledger entries moved to cold storage", "entries", frozen)
+	return frozen, nil
+}
@@ -0,0 +1,41 @@
+package ledger
+
+import (
+	"context"
+	"slices"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// describeBatch bounds the transaction IDs loaded per query
+const describeBatch = 1000
+
+// DescribeEntries adds the counterparty, direction and narrative of every entry, loading
+// their transactions in batches. On a store that cannot look transactions up, or for an
+// entry whose transaction is gone, only the direction is filled in.
+func (l *Ledger) DescribeEntries(ctx context.Context, entries []models.LedgerEntry) ([]models.DescribedEntry, error) {
+	transactions := map[string]*models.Transaction{}
+	if lookup, ok := interfaces.Capability[interfaces.TransactionLookupStore](l.store); ok {
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.TransactionID)
+		}
+		ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+		for batch := range slices.Chunk(ids, describeBatch) {
+			found, err := lookup.GetTransactions(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+			for i := range found {
+				transactions[found[i].ID] = &found[i]
+			}
+		}
+	}
+
+	described := make([]models.DescribedEntry, len(entries))
+	for i, entry := range entries {
+		described[i] = models.DescribeEntry(entry, transactions[entry.TransactionID])
+	}
+	return described, nil
+}
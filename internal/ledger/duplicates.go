@@ -0,0 +1,40 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// AutoKeyPrefix starts the idempotency key the ledger gives a posting that came without one
+const AutoKeyPrefix = "auto-"
+
+// ErrPossibleDuplicate soft-blocks a payment that repeats a recent one; resubmit with Force to post it anyway
+var ErrPossibleDuplicate = errors.New("possible duplicate payment")
+
+func envDuration(key string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// checkDuplicate catches callers that retry without an idempotency key: the same sender,
+// receiver and amount within the duplicate window. Must be called under the account locks.
+func (l *Ledger) checkDuplicate(ctx context.Context, tx models.Transaction) error {
+	if l.duplicates == nil || l.duplicateWindow <= 0 || tx.Force || tx.Internal {
+		return nil
+	}
+
+	previous, err := l.duplicates.FindRecentTransaction(ctx, tx.FromAccount, tx.ToAccount, tx.Amount, l.clock.Now().Add(-l.duplicateWindow))
+	if err != nil || previous == nil {
+		return err
+	}
+	return fmt.Errorf("%w of transaction %s at %s; resubmit with force=true to post it",
+		ErrPossibleDuplicate, previous.ID, previous.CreatedAt.Format(time.RFC3339))
+}
@@ -0,0 +1,70 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// EraseAccount answers a right-to-erasure request: it wipes the holder's name and email and
+// removes every alias pointing at the account. Entries, balances and the audit trail stay as
+// they are; they only ever refer to the account ID, which identifies no one on its own.
+// Erasing an account twice is harmless.
+func (l *Ledger) EraseAccount(ctx context.Context, id, reason string) (models.AccountErasure, error) {
+	if l.accounts == nil {
+		return models.AccountErasure{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.AccountErasure{}, ErrAccountIDRequired
+	}
+	if err := l.CheckAccountAccess(ctx, id); err != nil {
+		return models.AccountErasure{}, err
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.AccountErasure{}, err
+	}
+
+	now := l.clock.Now().UTC()
+	erasure := models.AccountErasure{
+		AccountID:    id,
+		HolderErased: before.HolderName != "" || before.HolderEmail != "",
+		Reason:       reason,
+		ErasedAt:     now,
+	}
+
+	after := before
+	after.HolderName = ""
+	after.HolderEmail = ""
+	after.UpdatedAt = now
+	after.ErasedAt = &now
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.AccountErasure{}, err
+	}
+
+	if l.aliases != nil {
+		aliases, err := l.aliases.ListAliases(ctx, id)
+		if err != nil {
+			return models.AccountErasure{}, err
+		}
+		for _, alias := range aliases {
+			if err := l.aliases.DeleteAlias(ctx, alias.Alias); err != nil {
+				return models.AccountErasure{}, err
+			}
+			erasure.AliasesRemoved++
+		}
+	}
+
+	// The erasure itself is recorded, but nothing it erased
+	l.recordAudit(ctx, "account.erase", "account:"+id, before.Redacted(), erasure)
+	// The app log keeps a trace even when the store has no audit log
+	l.appLogger.Info("account personal data erased", "account_id", id, "aliases_removed", erasure.AliasesRemoved,
+		"actor", audit.FromContext(ctx).Actor, "reason", reason)
+	return erasure, nil
+}
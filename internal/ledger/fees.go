@@ -0,0 +1,188 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/currency"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrFeesNotSupported    = errors.New("store does not support fees")
+	ErrFeeScheduleNotFound = errors.New("fee schedule not found")
+	ErrInvalidFeeSchedule  = errors.New("invalid fee schedule")
+)
+
+const defaultFeeRevenueAccount = "fee-revenue"
+
+var hundred = decimal.NewFromInt(100)
+
+func feeAccountFromEnv() string {
+	if account := os.Getenv("FEE_REVENUE_ACCOUNT"); account != "" {
+		return account
+	}
+	return defaultFeeRevenueAccount
+}
+
+// computeFees returns one fee line per schedule matching the sender's account type and the amount.
+// Fees are rounded half to even to the sender's currency and zero fees are dropped.
+func (l *Ledger) computeFees(ctx context.Context, tx models.Transaction) ([]models.FeeLine, error) {
+	feeAccount := livemode.AccountID(ctx, l.feeAccount)
+	if l.fees == nil || tx.Internal || tx.FromAccount == feeAccount {
+		return nil, nil
+	}
+
+	schedules, err := l.fees.ListFeeSchedules(ctx)
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []models.FeeLine
+	for _, schedule := range schedules {
+		if !schedule.Matches(from.Type, tx.Amount) {
+			continue
+		}
+		fee := currency.Round(schedule.FlatFee.Add(tx.Amount.Mul(schedule.Percentage).Div(hundred)), l.accountCurrency(from))
+		if !fee.IsPositive() {
+			continue
+		}
+		lines = append(lines, models.FeeLine{ScheduleID: schedule.ID, Account: feeAccount, Amount: fee})
+	}
+	return lines, nil
+}
+
+// feeEntries builds the debit/credit legs of every fee on the transaction
+func feeEntries(tx models.Transaction) []models.LedgerEntry {
+	entries := make([]models.LedgerEntry, 0, 2*len(tx.Fees))
+	for i, fee := range tx.Fees {
+		prefix := fmt.Sprintf("%s-fee-%d", tx.ID, i+1)
+		entries = append(entries,
+			models.LedgerEntry{
+				ID:            prefix + "-debit",
+				TransactionID: tx.ID,
+				AccountID:     tx.FromAccount,
+				Amount:        fee.Amount.Neg(),
+				CreatedAt:     tx.CreatedAt,
+			},
+			models.LedgerEntry{
+				ID:            prefix + "-credit",
+				TransactionID: tx.ID,
+				AccountID:     fee.Account,
+				Amount:        fee.Amount,
+				CreatedAt:     tx.CreatedAt,
+			},
+		)
+	}
+	return entries
+}
+
+// QuoteFees shows the fees a transaction would be charged without posting it
+func (l *Ledger) QuoteFees(ctx context.Context, tx models.Transaction) ([]models.FeeLine, error) {
+	if l.fees == nil {
+		return nil, ErrFeesNotSupported
+	}
+	lines, err := l.computeFees(ctx, tx)
+	if lines == nil {
+		lines = []models.FeeLine{}
+	}
+	return lines, err
+}
+
+func validateFeeSchedule(schedule models.FeeSchedule) error {
+	if schedule.FlatFee.IsNegative() || schedule.Percentage.IsNegative() {
+		return fmt.Errorf("%w: fees must not be negative", ErrInvalidFeeSchedule)
+	}
+	if schedule.MinAmount.IsNegative() || schedule.MaxAmount.IsNegative() {
+		return fmt.Errorf("%w: amount band must not be negative", ErrInvalidFeeSchedule)
+	}
+	if !schedule.MaxAmount.IsZero() && schedule.MaxAmount.LessThan(schedule.MinAmount) {
+		return fmt.Errorf("%w: max_amount is below min_amount", ErrInvalidFeeSchedule)
+	}
+	return nil
+}
+
+// SaveFeeSchedule creates a fee schedule, or replaces it when the ID already exists
+func (l *Ledger) SaveFeeSchedule(ctx context.Context, schedule models.FeeSchedule) (models.FeeSchedule, error) {
+	if l.fees == nil {
+		return models.FeeSchedule{}, ErrFeesNotSupported
+	}
+	if schedule.ID == "" {
+		schedule.ID = uuid.New().String()
+	}
+	if err := validateFeeSchedule(schedule); err != nil {
+		return models.FeeSchedule{}, err
+	}
+
+	before, err := l.fees.GetFeeSchedule(ctx, schedule.ID)
+	if err != nil {
+		return models.FeeSchedule{}, err
+	}
+	schedule.UpdatedAt = l.clock.Now().UTC()
+	if err := l.fees.SaveFeeSchedule(ctx, schedule); err != nil {
+		return models.FeeSchedule{}, err
+	}
+	l.recordAudit(ctx, "fee_schedule.save", "fee_schedule:"+schedule.ID, before, schedule)
+	return schedule, nil
+}
+
+func (l *Ledger) ListFeeSchedules(ctx context.Context) ([]models.FeeSchedule, error) {
+	if l.fees == nil {
+		return nil, ErrFeesNotSupported
+	}
+	return l.fees.ListFeeSchedules(ctx)
+}
+
+func (l *Ledger) DeleteFeeSchedule(ctx context.Context, id string) error {
+	if l.fees == nil {
+		return ErrFeesNotSupported
+	}
+	before, err := l.fees.GetFeeSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return ErrFeeScheduleNotFound
+	}
+	if err := l.fees.DeleteFeeSchedule(ctx, id); err != nil {
+		return err
+	}
+	l.recordAudit(ctx, "fee_schedule.delete", "fee_schedule:"+id, before, nil)
+	return nil
+}
+
+// SetAccountType sets the product type an account's fee schedules are selected by
+func (l *Ledger) SetAccountType(ctx context.Context, id, accountType string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	after := before
+	after.Type = accountType
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+	l.recordAudit(ctx, "account.type", "account:"+id, before.Redacted(), after.Redacted())
+	return after, nil
+}
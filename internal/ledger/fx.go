@@ -0,0 +1,254 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/currency"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrRateUnavailable = errors.New("no exchange rate available")
+	ErrInvalidFXRate   = errors.New("exchange rate must be positive")
+	ErrInvalidCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrFXRateTolerance = errors.New("exchange rate is too far from the reference rate")
+	ErrCurrencyChanged = errors.New("account currency changed while the transfer was prepared")
+)
+
+const (
+	fxPositionAccountPrefix = "fx-position-"  // one per currency, holds the ledger's currency exposure
+	fxGainLossAccountPrefix = "fx-gain-loss-" // one per currency, receives the spread against the reference rate
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// fxToleranceFromEnv is how far, as a fraction of the reference rate, a caller's rate may stray
+func fxToleranceFromEnv() decimal.Decimal {
+	if tolerance, err := decimal.NewFromString(os.Getenv("FX_RATE_TOLERANCE")); err == nil && !tolerance.IsNegative() {
+		return tolerance
+	}
+	return decimal.RequireFromString("0.02")
+}
+
+func baseCurrencyFromEnv() string {
+	if currency := strings.ToUpper(os.Getenv("BASE_CURRENCY")); currency != "" {
+		return currency
+	}
+	return "USD"
+}
+
+// SetRateProvider plugs in where exchange rates come from when the caller does not fix one
+func (l *Ledger) SetRateProvider(provider interfaces.RateProvider) {
+	l.rates = provider
+}
+
+func (l *Ledger) accountCurrency(account models.Account) string {
+	if account.Currency == "" {
+		return l.baseCurrency
+	}
+	return account.Currency
+}
+
+// BaseCurrency is the currency of accounts that have none set
+func (l *Ledger) BaseCurrency() string {
+	return l.baseCurrency
+}
+
+// AccountCurrency returns the currency an account is held in, defaulting to the base currency
+func (l *Ledger) AccountCurrency(ctx context.Context, id string) (string, error) {
+	account, err := l.getAccount(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return l.accountCurrency(account), nil
+}
+
+// transferCurrencies returns the currencies of the sender and the receiver
+func (l *Ledger) transferCurrencies(ctx context.Context, tx models.Transaction) (string, string, error) {
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		return "", "", err
+	}
+	to, err := l.getAccount(ctx, tx.ToAccount)
+	if err != nil {
+		return "", "", err
+	}
+	return l.accountCurrency(from), l.accountCurrency(to), nil
+}
+
+// prepareFX fills in tx.FX when the two accounts hold different currencies, and clears it otherwise.
+// It runs before the locks, since the FX accounts are among those to lock; confirmFX checks
+// the currencies again once they are held.
+func (l *Ledger) prepareFX(ctx context.Context, tx *models.Transaction) error {
+	fromCurrency, toCurrency, err := l.transferCurrencies(ctx, *tx)
+	if err != nil {
+		return err
+	}
+	if fromCurrency == toCurrency {
+		tx.FX = nil
+		return nil
+	}
+
+	fx := models.FXConversion{FromCurrency: fromCurrency, ToCurrency: toCurrency}
+	if tx.FX != nil && !tx.FX.Rate.IsZero() {
+		if !tx.FX.Rate.IsPositive() {
+			return ErrInvalidFXRate
+		}
+		fx.Rate, fx.RateSource = tx.FX.Rate, "caller"
+	}
+
+	if l.rates != nil {
+		reference, err := l.rates.GetRate(ctx, fromCurrency, toCurrency)
+		switch {
+		case err == nil:
+			fx.ReferenceRate = reference
+		case fx.Rate.IsZero():
+			return fmt.Errorf("%w: %v", ErrRateUnavailable, err)
+		}
+	}
+	if fx.Rate.IsZero() {
+		if fx.ReferenceRate.IsZero() {
+			return fmt.Errorf("%w: %s/%s", ErrRateUnavailable, fromCurrency, toCurrency)
+		}
+		fx.Rate, fx.RateSource = fx.ReferenceRate, "provider"
+	}
+	// Without a reference the caller's rate is taken as market, so there is no gain or loss
+	if fx.ReferenceRate.IsZero() {
+		fx.ReferenceRate = fx.Rate
+	}
+	// The spread is booked as gain or loss, so a caller cannot be allowed to pick any rate
+	if err := checkRateTolerance(fx.Rate, fx.ReferenceRate, l.fxTolerance); err != nil {
+		return fmt.Errorf("%w: %s/%s", err, fromCurrency, toCurrency)
+	}
+
+	fx.ConvertedAmount = currency.Round(tx.Amount.Mul(fx.Rate), toCurrency)
+	fx.GainLoss = currency.Round(tx.Amount.Mul(fx.ReferenceRate), toCurrency).Sub(fx.ConvertedAmount)
+	tx.FX = &fx
+	return nil
+}
+
+// checkRateTolerance rejects a rate further than tolerance, as a fraction, from the reference
+func checkRateTolerance(rate, reference, tolerance decimal.Decimal) error {
+	if reference.IsZero() {
+		return nil
+	}
+	if deviation := rate.Sub(reference).Abs().Div(reference); deviation.GreaterThan(tolerance) {
+		return fmt.Errorf("%w: %s against %s", ErrFXRateTolerance, rate, reference)
+	}
+	return nil
+}
+
+// confirmFX checks, under the account locks, that the currencies prepareFX converted
+// between are still those of the two accounts
+func (l *Ledger) confirmFX(ctx context.Context, tx models.Transaction) error {
+	fromCurrency, toCurrency, err := l.transferCurrencies(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if tx.FX == nil {
+		if fromCurrency != toCurrency {
+			return ErrCurrencyChanged
+		}
+		return nil
+	}
+	if tx.FX.FromCurrency != fromCurrency || tx.FX.ToCurrency != toCurrency {
+		return ErrCurrencyChanged
+	}
+	return nil
+}
+
+// fxAccounts lists the internal accounts a conversion posts to, so they can be locked
+func fxAccounts(ctx context.Context, tx models.Transaction) []string {
+	if tx.FX == nil {
+		return nil
+	}
+	return []string{
+		livemode.AccountID(ctx, fxPositionAccountPrefix+tx.FX.FromCurrency),
+		livemode.AccountID(ctx, fxPositionAccountPrefix+tx.FX.ToCurrency),
+		livemode.AccountID(ctx, fxGainLossAccountPrefix+tx.FX.ToCurrency),
+	}
+}
+
+// fxEntries moves the sender's amount into the source currency position and pays the receiver
+// out of the target currency position, so every currency balances on its own
+func fxEntries(ctx context.Context, tx models.Transaction) []models.LedgerEntry {
+	if tx.FX == nil {
+		return nil
+	}
+	accounts := fxAccounts(ctx, tx)
+	atReference := tx.FX.ConvertedAmount.Add(tx.FX.GainLoss)
+
+	entries := []models.LedgerEntry{
+		{
+			ID:            tx.ID + "-fx-position-credit",
+			TransactionID: tx.ID,
+			AccountID:     accounts[0],
+			Amount:        tx.Amount,
+			CreatedAt:     tx.CreatedAt,
+		},
+		{
+			ID:            tx.ID + "-fx-position-debit",
+			TransactionID: tx.ID,
+			AccountID:     accounts[1],
+			Amount:        atReference.Neg(),
+			CreatedAt:     tx.CreatedAt,
+		},
+	}
+	if !tx.FX.GainLoss.IsZero() {
+		entries = append(entries, models.LedgerEntry{
+			ID:            tx.ID + "-fx-gain-loss",
+			TransactionID: tx.ID,
+			AccountID:     accounts[2],
+			Amount:        tx.FX.GainLoss,
+			CreatedAt:     tx.CreatedAt,
+		})
+	}
+	return entries
+}
+
+// SetAccountCurrency sets the currency of an account; only allowed while its balance is zero
+func (l *Ledger) SetAccountCurrency(ctx context.Context, id, currency string) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	currency = strings.ToUpper(currency)
+	if !currencyCode.MatchString(currency) {
+		return models.Account{}, ErrInvalidCurrency
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	balance, err := l.GetBalance(id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if !balance.IsZero() {
+		return before, fmt.Errorf("%w: balance is %s", ErrNonZeroBalance, balance)
+	}
+
+	after := before
+	after.Currency = currency
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+	l.recordAudit(ctx, "account.currency", "account:"+id, before.Redacted(), after.Redacted())
+	return after, nil
+}
@@ -0,0 +1,29 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckRateTolerance(t *testing.T) {
+	tolerance := decimal.RequireFromString("0.02")
+	tests := []struct {
+		rate, reference string
+		want            error
+	}{
+		{"1.08", "1.08", nil},
+		{"1.1016", "1.08", nil}, // exactly 2% above
+		{"1.0584", "1.08", nil}, // exactly 2% below
+		{"1.11", "1.08", ErrFXRateTolerance},
+		{"1.05", "1.08", ErrFXRateTolerance},
+		{"100", "0", nil}, // no reference to hold the rate to
+	}
+	for _, tt := range tests {
+		err := checkRateTolerance(decimal.RequireFromString(tt.rate), decimal.RequireFromString(tt.reference), tolerance)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("checkRateTolerance(%s, %s) = %v, want %v", tt.rate, tt.reference, err, tt.want)
+		}
+	}
+}
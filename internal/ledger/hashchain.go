@@ -0,0 +1,140 @@
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var ErrHashChainNotSupported = errors.New("store does not support hash-chained entries")
+
+// ChainVerification is the result of walking one account's hash chain
+type ChainVerification struct {
+	AccountID     string `json:"account_id"`
+	EntriesWalked int    `json:"entries_walked"`
+	Valid         bool   `json:"valid"`
+	BrokenEntryID string `json:"broken_entry_id,omitempty"` // first entry whose link does not verify
+	Reason        string `json:"reason,omitempty"`
+}
+
+// HashEntry computes the chain hash of an entry over the previous hash and the entry contents.
+// Amounts and timestamps are normalised to what Postgres stores so the hash survives a round trip.
+func HashEntry(prevHash string, entry models.LedgerEntry) string {
+	h := sha256.New()
+	for _, field := range []string{
+		prevHash,
+		entry.ID,
+		entry.TransactionID,
+		entry.AccountID,
+		entry.Amount.StringFixed(8),
+		entry.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0}) // separator so field boundaries can't be shifted
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chainEntries links the new entries onto the current head of each account's chain.
+// Must be called while holding the locks of every account involved.
+func (l *Ledger) chainEntries(entries []*models.LedgerEntry) error {
+	if l.chain == nil {
+		return nil
+	}
+
+	heads := make(map[string]string)
+	for _, entry := range entries {
+		prevHash, seen := heads[entry.AccountID]
+		if !seen {
+			var err error
+			prevHash, err = l.chain.GetLastEntryHash(entry.AccountID)
+			if err != nil {
+				return err
+			}
+		}
+		entry.PrevHash = prevHash
+		entry.Hash = HashEntry(prevHash, *entry)
+		heads[entry.AccountID] = entry.Hash
+	}
+	return nil
+}
+
+// VerifyChain walks an account's entries in order and reports the first broken link.
+// Cold entries are only walked with includeArchived; otherwise the walk starts from
+// the hash of the newest cold entry.
+func (l *Ledger) VerifyChain(ctx context.Context, accountId string, includeArchived bool) (ChainVerification, error) {
+	if l.chain == nil {
+		return ChainVerification{}, ErrHashChainNotSupported
+	}
+
+	entries, err := l.chain.GetAccountChain(ctx, accountId)
+	if err != nil {
+		return ChainVerification{}, err
+	}
+
+	prevHash := ""
+	if l.cold != nil && includeArchived {
+		cold, err := l.cold.GetColdEntries(ctx, accountId, time.Time{}, time.Time{})
+		if err != nil {
+			return ChainVerification{}, err
+		}
+		entries = append(cold, entries...)
+	} else if l.cold != nil {
+		if prevHash, err = l.cold.GetColdChainHead(ctx, accountId); err != nil {
+			return ChainVerification{}, err
+		}
+	}
+
+	result := ChainVerification{AccountID: accountId, Valid: true}
+	for _, entry := range entries {
+		result.EntriesWalked++
+		if entry.PrevHash != prevHash {
+			result.Valid = false
+			result.BrokenEntryID = entry.ID
+			result.Reason = "prev_hash does not match the previous entry"
+			break
+		}
+		if HashEntry(prevHash, entry) != entry.Hash {
+			result.Valid = false
+			result.BrokenEntryID = entry.ID
+			result.Reason = "hash does not match entry contents"
+			break
+		}
+		prevHash = entry.Hash
+	}
+
+	if !result.Valid {
+		l.appLogger.Error("ALERT: ledger hash chain broken",
+			"account_id", accountId,
+			"entry_id", result.BrokenEntryID,
+			"reason", result.Reason,
+		)
+	}
+	return result, nil
+}
+
+// VerifyAllChains verifies the chain of every account that has entries
+func (l *Ledger) VerifyAllChains(ctx context.Context, includeArchived bool) ([]ChainVerification, error) {
+	if l.chain == nil {
+		return nil, ErrHashChainNotSupported
+	}
+
+	accountIds, err := l.chain.ListAccountIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ChainVerification, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		result, err := l.VerifyChain(ctx, accountId, includeArchived)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,92 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// maxHierarchyDepth bounds the ancestor walk and the roll-up query alike
+const maxHierarchyDepth = 64
+
+var (
+	ErrHierarchyNotSupported = errors.New("store does not support account hierarchies")
+	ErrInvalidParent         = errors.New("invalid parent account")
+)
+
+// SetParent moves an account under parentId, or to the top level when parentId is empty.
+// Cycles are rejected by walking up from the new parent.
+func (l *Ledger) SetParent(ctx context.Context, id, parentId string) (models.Account, error) {
+	if l.accounts == nil || l.hierarchy == nil {
+		return models.Account{}, ErrHierarchyNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if parentId != "" {
+		if err := l.checkParent(ctx, before, parentId); err != nil {
+			return models.Account{}, err
+		}
+	}
+
+	after := before
+	after.ParentID = parentId
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+	l.recordAudit(ctx, "account.parent", "account:"+id, before.Redacted(), after.Redacted())
+	return after, nil
+}
+
+func (l *Ledger) checkParent(ctx context.Context, account models.Account, parentId string) error {
+	ancestor := parentId
+	for depth := 0; ancestor != ""; depth++ {
+		if ancestor == account.ID {
+			return fmt.Errorf("%w: %s is a descendant of %s", ErrInvalidParent, parentId, account.ID)
+		}
+		if depth >= maxHierarchyDepth {
+			return fmt.Errorf("%w: hierarchy deeper than %d levels", ErrInvalidParent, maxHierarchyDepth)
+		}
+		parent, err := l.getAccount(ctx, ancestor)
+		if err != nil {
+			return err
+		}
+		if depth == 0 && parent.TenantID != account.TenantID {
+			return fmt.Errorf("%w: %s belongs to another tenant", ErrInvalidParent, parentId)
+		}
+		ancestor = parent.ParentID
+	}
+	return nil
+}
+
+// GetRollupBalance sums the balances of the account and every account beneath it
+func (l *Ledger) GetRollupBalance(ctx context.Context, id string) (models.RollupBalance, error) {
+	if l.hierarchy == nil {
+		return models.RollupBalance{}, ErrHierarchyNotSupported
+	}
+	if id == "" {
+		return models.RollupBalance{}, ErrAccountIDRequired
+	}
+
+	accounts, err := l.hierarchy.GetSubtreeBalances(ctx, id)
+	if err != nil {
+		return models.RollupBalance{}, err
+	}
+	rollup := models.RollupBalance{AccountID: id, Accounts: accounts}
+	for _, account := range accounts {
+		rollup.Balance = rollup.Balance.Add(account.Balance)
+	}
+	return rollup, nil
+}
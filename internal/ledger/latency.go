@@ -0,0 +1,65 @@
+package ledger
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+// Phases of a posting, in the order they run
+const (
+	phaseIdempotency = "idempotency" // looking up the idempotency key
+	phaseValidation  = "validation"  // checks needing no lock: payment request, precision, fees, FX
+	phaseLockWait    = "lock_wait"   // waiting for the account locks
+	phaseChecks      = "checks"      // checks under the locks: status, funds, limits, rules, hash chain
+	phaseCommit      = "commit"      // the database transaction
+	phaseNotify      = "notify"      // audit, listeners, overdraft and flag events
+	phasePublish     = "publish"     // the transactions.completed event
+)
+
+var postPhaseSeconds = metrics.NewHistogramVec("ledger_post_phase_seconds",
+	"Time PostTransaction spends in each phase", nil, "phase")
+
+// phaseTimer attributes the time of one posting to its phases, so a regression shows up
+// in the phase that caused it rather than only in the total
+type phaseTimer struct {
+	start     time.Time
+	entered   time.Time
+	phase     string
+	durations map[string]time.Duration
+}
+
+func newPhaseTimer() *phaseTimer {
+	now := time.Now()
+	return &phaseTimer{start: now, entered: now, phase: phaseIdempotency, durations: map[string]time.Duration{}}
+}
+
+// enter ends the current phase and starts the next
+func (t *phaseTimer) enter(phase string) {
+	now := time.Now()
+	t.durations[t.phase] += now.Sub(t.entered)
+	t.phase, t.entered = phase, now
+}
+
+// finish records the phases reached. A posting slower than slow, when it is positive, is
+// logged with the time of every phase.
+func (t *phaseTimer) finish(appLogger *slog.Logger, transactionId string, slow time.Duration) {
+	t.enter("")
+	for phase, d := range t.durations {
+		if phase != "" {
+			postPhaseSeconds.With(phase).Observe(d.Seconds())
+		}
+	}
+	total := time.Since(t.start)
+	if slow <= 0 || total < slow {
+		return
+	}
+	attrs := []any{"transaction_id", transactionId, "total_ms", total.Milliseconds()}
+	for _, phase := range []string{phaseIdempotency, phaseValidation, phaseLockWait, phaseChecks, phaseCommit, phaseNotify, phasePublish} {
+		if d, ok := t.durations[phase]; ok {
+			attrs = append(attrs, phase+"_ms", float64(d.Microseconds())/1000)
+		}
+	}
+	appLogger.Warn("slow transaction", attrs...)
+}
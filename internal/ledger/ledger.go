@@ -4,34 +4,163 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ids"
 	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/usage"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/validation"
 	"github.com/shopspring/decimal"
 )
 
 // Ledger is the main struct representing our ledger system
 // It holds a reference to the storage layer and a mutex for concurrency control
 type Ledger struct {
-	store     interfaces.LedgerStore // Interface to save ledger entries, can be any storage implementation
-	muMap     map[string]*sync.Mutex //stores the *sync.Mutex for each account in a map
-	mapMu     sync.Mutex             // protects the muMap itself
-	appLogger *slog.Logger
-	publisher interfaces.EventPublisher
+	store      interfaces.LedgerStore // Interface to save ledger entries, can be any storage implementation
+	muMap      map[string]*sync.Mutex //stores the *sync.Mutex for each account in a map
+	mapMu      sync.Mutex             // protects the muMap itself
+	periodMu   sync.RWMutex           // held shared by postings from the period check to the save, exclusively by ClosePeriod
+	appLogger  *slog.Logger
+	publisher  interfaces.EventPublisher
+	snapshots  interfaces.SnapshotStore         // nil when the store cannot checkpoint balances
+	balances   interfaces.BalanceStore          // nil when the store keeps no materialized balances
+	chain      interfaces.HashChainStore        // nil when the store does not persist entry hashes
+	cold       interfaces.ColdStorageStore      // nil when the store has no cold storage
+	periods    interfaces.PeriodStore           // nil when the store does not track accounting periods
+	accounts   interfaces.AccountStore          // nil when the store has no account controls
+	hierarchy  interfaces.AccountHierarchyStore // nil when the store cannot walk account subtrees
+	aliases    interfaces.AliasStore            // nil when the store keeps no external account identifiers
+	requests   interfaces.PaymentRequestStore   // nil when the store has no payment requests
+	suspense   interfaces.SuspenseStore         // nil when failed credits cannot be parked in suspense
+	limits     interfaces.LimitStore            // nil when the store cannot enforce velocity limits
+	rules      interfaces.RuleStore             // nil when the store has no fraud rules
+	alerts     interfaces.BalanceAlertStore     // nil when the store cannot keep balance alerts
+	duplicates interfaces.DuplicateStore        // nil when the store cannot look up recent transactions
+	fees       interfaces.FeeStore              // nil when the store cannot post fee legs
+	rates      interfaces.RateProvider          // nil when only caller-supplied exchange rates are accepted
+	valueDates interfaces.ValueDateStore        // nil when balances can only be read by booking date
+	calendar   *calendar.Calendar               // nil when every day is a business day
+	webhooks   interfaces.WebhookSender         // nil when crossed alerts are only published as events
+	audit      *audit.Log                       // nil when the store has no audit log
+	listeners  []interfaces.EntryListener
+
+	backdating           BackdatingPolicy
+	reversals            ReversalPolicy
+	frozenAcceptsCredits bool
+	fundsCheck           bool // reject debits beyond the sender's balance plus overdraft limit
+	duplicateWindow      time.Duration
+	feeAccount           string          // credited with every fee leg
+	baseCurrency         string          // currency of accounts that have none set
+	fxTolerance          decimal.Decimal // largest fraction a caller's exchange rate may stray from the reference
+	crossTenant          bool            // let a tenant pay into accounts owned by another tenant
+	normalBalance        NormalBalancePolicy
+	precision            PrecisionPolicy
+	validation           validation.Rules
+	systemAccounts       []models.SystemAccount
+	slowPost             time.Duration // postings slower than this are logged with their phases
+	maxSkew              time.Duration // how far past now a transaction may be dated
+	ids                  ids.Generator // transaction IDs; entry IDs are derived from them
+	clock                clock.Clock
+	valueDateConvention  calendar.Convention
 }
 
 // NewLedger is a constructor function that creates a new Ledger instance
 // We pass in a storage implementation (MemoryLedgerStore, DB, etc.)
 func NewLedger(store interfaces.LedgerStore, appLogger *slog.Logger, publisher interfaces.EventPublisher) *Ledger {
-	return &Ledger{
+	l := &Ledger{
 		store:     store, // Assign the storage implementation to the ledger's store field
 		appLogger: appLogger,
 		publisher: publisher,
 		muMap:     make(map[string]*sync.Mutex),
+		ids:       ids.UUID{},
+		clock:     clock.System{},
+
+		backdating:           backdatingPolicyFromEnv(),
+		reversals:            reversalPolicyFromEnv(),
+		frozenAcceptsCredits: envBool("FROZEN_ACCOUNTS_ACCEPT_CREDITS", true),
+		fundsCheck:           envBool("FUNDS_CHECK_ENABLED", false),
+		duplicateWindow:      envDuration("DUPLICATE_PAYMENT_WINDOW", 0),
+		feeAccount:           feeAccountFromEnv(),
+		baseCurrency:         baseCurrencyFromEnv(),
+		fxTolerance:          fxToleranceFromEnv(),
+		crossTenant:          envBool("ALLOW_CROSS_TENANT_TRANSFERS", false),
+		normalBalance:        normalBalancePolicyFromEnv(),
+		precision:            precisionPolicyFromEnv(),
+		slowPost:             envDuration("SLOW_TRANSACTION_THRESHOLD", 500*time.Millisecond),
+		maxSkew:              envDuration("MAX_CLOCK_SKEW", 5*time.Minute),
+	}
+	l.systemAccounts = systemAccountsFromEnv(l.feeAccount)
+	checks, err := validation.RulesFromEnv()
+	if err != nil {
+		appLogger.Error("invalid validation settings, using the defaults", "error", err)
+	}
+	l.validation = checks
+	// Optional capabilities are discovered from the store itself, or the one it decorates
+	if snapshots, ok := interfaces.Capability[interfaces.SnapshotStore](store); ok {
+		l.snapshots = snapshots
+	}
+	if balances, ok := interfaces.Capability[interfaces.BalanceStore](store); ok {
+		l.balances = balances
+	}
+	if chain, ok := interfaces.Capability[interfaces.HashChainStore](store); ok {
+		l.chain = chain
+	}
+	if cold, ok := interfaces.Capability[interfaces.ColdStorageStore](store); ok {
+		l.cold = cold
+	}
+	if periods, ok := interfaces.Capability[interfaces.PeriodStore](store); ok {
+		l.periods = periods
+	}
+	if accounts, ok := interfaces.Capability[interfaces.AccountStore](store); ok {
+		l.accounts = accounts
+	}
+	if hierarchy, ok := interfaces.Capability[interfaces.AccountHierarchyStore](store); ok {
+		l.hierarchy = hierarchy
+	}
+	if aliases, ok := interfaces.Capability[interfaces.AliasStore](store); ok {
+		l.aliases = aliases
+	}
+	if requests, ok := interfaces.Capability[interfaces.PaymentRequestStore](store); ok {
+		l.requests = requests
 	}
+	if suspense, ok := interfaces.Capability[interfaces.SuspenseStore](store); ok {
+		l.suspense = suspense
+	}
+	if limits, ok := interfaces.Capability[interfaces.LimitStore](store); ok {
+		l.limits = limits
+	}
+	if rules, ok := interfaces.Capability[interfaces.RuleStore](store); ok {
+		l.rules = rules
+	}
+	if alerts, ok := interfaces.Capability[interfaces.BalanceAlertStore](store); ok {
+		l.alerts = alerts
+	}
+	if duplicates, ok := interfaces.Capability[interfaces.DuplicateStore](store); ok {
+		l.duplicates = duplicates
+	}
+	if valueDates, ok := interfaces.Capability[interfaces.ValueDateStore](store); ok {
+		l.valueDates = valueDates
+	}
+	// Fee legs need a store that can save more than one debit/credit pair atomically
+	if fees, ok := interfaces.Capability[interfaces.FeeStore](store); ok {
+		if _, multiLeg := interfaces.Capability[interfaces.MultiLegStore](store); multiLeg {
+			l.fees = fees
+		}
+	}
+	if auditStore, ok := interfaces.Capability[interfaces.AuditStore](store); ok {
+		l.audit = audit.NewLog(auditStore, appLogger)
+		l.audit.SetClock(l)
+	}
+	return l
 }
 
 func (l *Ledger) getAccountLock(accountId string) *sync.Mutex {
@@ -45,60 +174,286 @@ func (l *Ledger) getAccountLock(accountId string) *sync.Mutex {
 	return l.muMap[accountId]
 }
 
+// lockAccounts locks every distinct account in a fixed order to avoid deadlocks
+// and returns the function that releases them
+func (l *Ledger) lockAccounts(accountIds ...string) func() {
+	ids := slices.Clone(accountIds)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	locks := make([]*sync.Mutex, len(ids))
+	for i, id := range ids {
+		locks[i] = l.getAccountLock(id)
+		locks[i].Lock()
+	}
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
+
+// SetClock replaces the wall clock as the ledger's source of the current time.
+// It must be called before serving traffic.
+func (l *Ledger) SetClock(c clock.Clock) {
+	l.clock = c
+}
+
+// Now is the current time by the ledger's clock. Code working alongside the ledger reads
+// it too, so a simulated clock moves everything forward together.
+func (l *Ledger) Now() time.Time {
+	return l.clock.Now()
+}
+
+// SetIDGenerator replaces the UUIDs given to new transactions, e.g. with time-ordered ULIDs.
+// It must be called before serving traffic.
+func (l *Ledger) SetIDGenerator(generator ids.Generator) {
+	l.ids = generator
+}
+
+// NewID returns an ID for a new transaction
+func (l *Ledger) NewID() string {
+	return l.ids.New()
+}
+
 // PostTransaction is the core method that processes a transaction
 // It converts a Transaction (intent) into two LedgerEntry objects (debit and credit)
 // ensuring double-entry accounting, and then saves them to the store
 func (l *Ledger) PostTransaction(ctx context.Context, tx models.Transaction) (bool, error) {
+	_, exists, err := l.PostTransactionDetailed(ctx, tx)
+	return exists, err
+}
+
+// PostTransactionDetailed posts like PostTransaction and also returns the transaction as
+// stored, including the fees charged and any period adjustment
+func (l *Ledger) PostTransactionDetailed(ctx context.Context, tx models.Transaction) (models.Transaction, bool, error) {
 	l.appLogger.Info("received transaction request",
 		"idempotency_key", tx.IdempotencyKey,
 		"from_account", tx.FromAccount,
 		"to_account", tx.ToAccount,
 		"amount", tx.Amount.String(),
 	)
+	return l.postTransaction(ctx, tx, nil)
+}
+
+// postTransaction runs every check and stores the transaction. A non-nil sim makes it a
+// dry run: nothing is claimed, audited, stored or published, and sim receives the
+// entries and balances the posting would produce.
+func (l *Ledger) postTransaction(ctx context.Context, tx models.Transaction, sim *Simulation) (models.Transaction, bool, error) {
+	// Dry runs are left out so they do not skew the latency of real postings
+	timer := newPhaseTimer()
+	if sim == nil {
+		defer func() { timer.finish(l.appLogger, tx.ID, l.slowPost) }()
+	}
+
+	if err := checkReversalMarkers(&tx); err != nil {
+		return tx, false, err
+	}
+	// A posting without an idempotency key gets one of its own, so it is not taken for a
+	// repeat of every other keyless posting; checkDuplicate catches its retries instead
+	if tx.IdempotencyKey == "" {
+		tx.IdempotencyKey = AutoKeyPrefix + l.NewID()
+	}
+
 	// Idempotency check
-	exists, err := l.store.TransactionExists(tx.IdempotencyKey)
+	exists, err := l.store.TransactionExists(tenant.FromContext(ctx), tx.IdempotencyKey)
 	if err != nil {
 		l.appLogger.Error("transaction failed",
 			"error", err.Error(),
 			"transaction_id", tx.ID,
 		)
-		return false, err
+		return tx, false, err
 	}
 
 	if exists {
-		return true, nil
+		return tx, true, nil
+	}
+	// A tenant past its monthly quota is refused before anything else runs
+	if sim == nil {
+		if err := usage.AllowTransaction(ctx); err != nil {
+			return tx, false, err
+		}
+	}
+	timer.enter(phaseValidation)
+	// A payment request supplies the payee and amount when the payer left them out
+	if err := l.applyPaymentRequest(ctx, &tx); err != nil {
+		l.appLogger.Error("transaction rejected by payment request",
+			"transaction_id", tx.ID,
+			"payment_request_id", tx.PaymentRequestID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+	// Basic validation needs neither the store nor the locks
+	if err := l.validation.Transaction(tx); err != nil {
+		l.appLogger.Error("transaction failed validation",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+	// Backdating is allowed, future dating is not: the scheduler holds those payments
+	if err := l.checkNotFutureDated(tx); err != nil {
+		l.appLogger.Error("transaction rejected as future dated",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+	// Amounts finer than the sender's currency are rejected or rounded before anything is derived from them
+	if err := l.checkPrecision(ctx, &tx); err != nil {
+		l.appLogger.Error("transaction rejected by currency precision",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+	// Fees decide whether the fee revenue account takes part in the posting
+	tx.Fees, err = l.computeFees(ctx, tx)
+	if err != nil {
+		l.appLogger.Error("failed to compute fees",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
 	}
-	//Get Locks for both accounts
-	debitMutex := l.getAccountLock(tx.FromAccount)
-	creditMutex := l.getAccountLock(tx.ToAccount)
 
-	// Lock in order to avoid deadlocks
-	if tx.FromAccount < tx.ToAccount {
-		debitMutex.Lock()
-		creditMutex.Lock()
-	} else {
-		creditMutex.Lock()
-		debitMutex.Lock()
+	// Cross-currency transfers are converted and also post to the FX position accounts
+	if err := l.prepareFX(ctx, &tx); err != nil {
+		l.appLogger.Error("failed to convert transaction",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
 	}
 
-	defer debitMutex.Unlock()
-	defer creditMutex.Unlock()
+	// The value date depends on the currencies, known once FX is settled
+	if err := l.assignValueDate(ctx, &tx); err != nil {
+		l.appLogger.Error("failed to assign value date",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
 
-	// Basic validation: the transaction amount must be positive
-	if tx.Amount.Cmp(decimal.Zero) <= 0 {
-		l.appLogger.Error("amount must be positive")
-		return false, errors.New("amount must be positive")
+	//Get Locks for every account involved
+	accountIds := append([]string{tx.FromAccount, tx.ToAccount}, fxAccounts(ctx, tx)...)
+	if len(tx.Fees) > 0 {
+		accountIds = append(accountIds, livemode.AccountID(ctx, l.feeAccount))
 	}
+	timer.enter(phaseLockWait)
+	defer l.lockAccounts(accountIds...)()
+	timer.enter(phaseChecks)
+
+	// The currencies were read before the locks; a change since then would leave the
+	// conversion, and the FX accounts just locked, wrong
+	if err := l.confirmFX(ctx, tx); err != nil {
+		l.appLogger.Error("transaction rejected by currency check",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	if tx.Tags, err = normalizeTags(tx.Tags); err != nil {
+		return tx, false, err
+	}
+	// A tenant may only move money out of its own accounts
+	receiverTenant, err := l.checkTenant(ctx, &tx, sim != nil)
+	if err != nil {
+		l.appLogger.Error("transaction rejected by tenant isolation",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Frozen accounts cannot send money (and optionally cannot receive it)
+	if err := l.checkAccountStatus(ctx, tx); err != nil {
+		l.appLogger.Error("transaction rejected by account status",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Same parties and amount moments ago is most likely a client retry without an idempotency key
+	if err := l.checkDuplicate(ctx, tx); err != nil {
+		l.appLogger.Warn("transaction soft-blocked as possible duplicate",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// The sender must stay above -OverdraftLimit
+	balanceBefore, err := l.checkFunds(ctx, tx)
+	if err != nil {
+		l.appLogger.Error("transaction rejected by funds check",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Classified accounts should stay on their normal balance side
+	if err := l.checkNormalBalance(ctx, tx); err != nil {
+		l.appLogger.Error("transaction rejected by chart of accounts",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Rolling-window velocity limits of the sender's limit profile
+	if err := l.checkVelocityLimits(ctx, tx); err != nil {
+		l.appLogger.Error("transaction rejected by velocity limits",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Fraud rules may block the transaction outright or flag it for review once posted
+	flags, err := l.checkRules(ctx, tx, sim != nil)
+	if err != nil {
+		l.appLogger.Error("transaction rejected by rules",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// A period close waits for postings already past this check, and those after it see the
+	// period closed. The lock is let go once the entries are saved.
+	l.periodMu.RLock()
+	unlockPeriods := sync.OnceFunc(l.periodMu.RUnlock)
+	defer unlockPeriods()
+
+	// Late transactions dated inside a closed period are rejected or moved into the open period
+	if err := l.applyPeriodRules(ctx, &tx); err != nil {
+		l.appLogger.Error("transaction rejected by period rules",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
+	}
+
+	// Entries are stored in UTC at microsecond precision, which is what the hash chain covers
+	tx.CreatedAt = tx.CreatedAt.UTC().Truncate(time.Microsecond)
+
 	// Create the debit entry (money leaving the sender's account)
 	// - ID: unique entry ID based on transaction ID + "-debit"
 	// - AccountID: from which account money is taken
 	// - Amount: negative because it's a debit
 	// - CreatedAt: timestamp of the transaction
 	debit := models.LedgerEntry{
-		ID:        tx.ID + "-debit",
-		AccountID: tx.FromAccount,
-		Amount:    tx.Amount.Neg(),
-		CreatedAt: tx.CreatedAt,
+		ID:            tx.ID + "-debit",
+		TransactionID: tx.ID,
+		AccountID:     tx.FromAccount,
+		Amount:        tx.Amount.Neg(),
+		CreatedAt:     tx.CreatedAt,
+		TenantID:      tx.TenantID,
 	}
 
 	// Create the credit entry (money entering the receiver's account)
@@ -107,21 +462,78 @@ func (l *Ledger) PostTransaction(ctx context.Context, tx models.Transaction) (bo
 	// - Amount: positive because it's a credit
 	// - CreatedAt: timestamp of the transaction
 	credit := models.LedgerEntry{
-		ID:        tx.ID + "-credit",
-		AccountID: tx.ToAccount,
-		Amount:    tx.Amount,
-		CreatedAt: tx.CreatedAt,
+		ID:            tx.ID + "-credit",
+		TransactionID: tx.ID,
+		AccountID:     tx.ToAccount,
+		Amount:        tx.Amount,
+		CreatedAt:     tx.CreatedAt,
+		TenantID:      receiverTenant,
+	}
+	if tx.FX != nil {
+		credit.Amount = tx.FX.ConvertedAmount
+	}
+	entries := append([]models.LedgerEntry{debit, credit}, fxEntries(ctx, tx)...)
+	entries = append(entries, feeEntries(tx)...)
+	// FX and fee legs land on the platform's accounts on behalf of the posting tenant
+	for i := 2; i < len(entries); i++ {
+		entries[i].TenantID = tx.TenantID
+	}
+
+	// Link every entry onto its account's hash chain while the locks are held
+	links := make([]*models.LedgerEntry, len(entries))
+	for i := range entries {
+		links[i] = &entries[i]
+	}
+	if err := l.chainEntries(links); err != nil {
+		l.appLogger.Error("failed to read hash chain head",
+			"transaction_id", tx.ID,
+			"error", err,
+		)
+		return tx, false, err
 	}
-	l.store.SaveTransactionWithEntries(ctx, tx, debit, credit)
+
+	if sim != nil {
+		return tx, false, l.project(sim, entries, flags)
+	}
+
+	timer.enter(phaseCommit)
+	if err := l.saveEntries(ctx, tx, entries); err != nil {
+		// A writer elsewhere may have stored the key since it was checked, e.g. a retry the
+		// replication log applied first; the posting is then already processed
+		if exists, existsErr := l.store.TransactionExists(tenant.FromContext(ctx), tx.IdempotencyKey); existsErr == nil && exists {
+			return tx, true, nil
+		}
+		l.appLogger.Error("transaction failed",
+			"error", err.Error(),
+			"transaction_id", tx.ID,
+		)
+		return tx, false, err
+	}
+	unlockPeriods()
+	timer.enter(phaseNotify)
+	l.recordAudit(ctx, "transaction.post", "transaction:"+tx.ID, nil, tx)
+	l.notifyListeners(entries...)
+	l.notifyOverdraft(ctx, tx, balanceBefore)
+	l.notifyThresholds(ctx, tx, entries)
+	l.notifyFlagged(ctx, tx, flags)
+	usage.TransactionPosted(ctx)
+
 	//Kafka Event
 	event := events.TransactionCompleted{
 		TransactionID: tx.ID,
 		FromAccount:   tx.FromAccount,
 		ToAccount:     tx.ToAccount,
 		Amount:        tx.Amount,
-		OccurredAt:    time.Now(),
+		Reference:     tx.Reference,
+		Metadata:      tx.Metadata,
+		Fees:          tx.Fees,
+		FX:            tx.FX,
+		OccurredAt:    l.clock.Now(),
+		TenantID:      tx.TenantID,
+		ValueDate:     tx.ValueDate,
 	}
 
+	timer.enter(phasePublish)
 	if err := l.publisher.Publish("transactions.completed", event); err != nil {
 		l.appLogger.Error("failed to publish kafka event",
 			"transaction_id", tx.ID,
@@ -129,10 +541,55 @@ func (l *Ledger) PostTransaction(ctx context.Context, tx models.Transaction) (bo
 		)
 	}
 	// If everything succeeded, return nil indicating no error
-	return false, nil
+	return tx, false, nil
+}
+
+// chainRelinks caps how often entries are linked again after losing a chain head to another instance
+const chainRelinks = 3
+
+// saveEntries stores the transaction with its legs in one database transaction. The store
+// checks the chain heads under its own locks; when another instance moved one, the
+// entries are linked onto the new head and saved again.
+func (l *Ledger) saveEntries(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	for attempt := 1; ; attempt++ {
+		err := l.saveLinkedEntries(ctx, tx, entries)
+		if !errors.Is(err, interfaces.ErrChainHeadMoved) || attempt > chainRelinks {
+			return err
+		}
+		links := make([]*models.LedgerEntry, len(entries))
+		for i := range entries {
+			links[i] = &entries[i]
+		}
+		if err := l.chainEntries(links); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *Ledger) saveLinkedEntries(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	if l.balances != nil {
+		guard, err := l.fundsGuard(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return l.balances.SaveTransactionChecked(ctx, tx, entries, guard)
+	}
+	if multiLeg, ok := interfaces.Capability[interfaces.MultiLegStore](l.store); ok {
+		return multiLeg.SaveTransactionWithLegs(ctx, tx, entries)
+	}
+	return l.store.SaveTransactionWithEntries(ctx, tx, entries[0], entries[1])
 }
 
 func (l *Ledger) GetBalance(accountId string) (decimal.Decimal, error) {
+	if l.balances != nil {
+		balance, found, err := l.balances.GetMaterializedBalance(context.Background(), accountId)
+		if err != nil || found {
+			return balance, err
+		}
+	}
+	if l.snapshots != nil {
+		return l.getBalanceFromSnapshot(accountId)
+	}
 	ledgerEntries, err := l.store.GetEntriesByAccount(accountId)
 
 	if err != nil {
@@ -153,3 +610,139 @@ func (l *Ledger) GetLedgerEntries() ([]models.LedgerEntry, error) {
 	}
 	return ledgerEntries, nil
 }
+
+// recordAudit writes a before/after audit record when the store keeps an audit log
+func (l *Ledger) recordAudit(ctx context.Context, action, resource string, before, after any) {
+	if l.audit == nil {
+		return
+	}
+	l.audit.Record(ctx, action, resource, before, after)
+}
+
+// StreamLedgerEntries iterates entries without materialising them all.
+// Stores without cursor support fall back to loading the full list.
+func (l *Ledger) StreamLedgerEntries(ctx context.Context, accountId string, fn func(models.LedgerEntry) error) error {
+	if streamer, ok := interfaces.Capability[interfaces.EntryStreamer](l.store); ok {
+		return streamer.StreamLedgerEntries(ctx, accountId, fn)
+	}
+
+	var entries []models.LedgerEntry
+	var err error
+	if accountId != "" {
+		entries, err = l.store.GetEntriesByAccount(accountId)
+	} else {
+		entries, err = l.store.GetLedgerEntries()
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddEntryListener registers a listener that is notified after every committed posting
+func (l *Ledger) AddEntryListener(listener interfaces.EntryListener) {
+	l.listeners = append(l.listeners, listener)
+}
+
+// notifyListeners pushes the committed entries with the resulting balances.
+// Called while the account locks are still held, so the balances are exact.
+func (l *Ledger) notifyListeners(entries ...models.LedgerEntry) {
+	if len(l.listeners) == 0 {
+		return
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	updates := make([]models.BalanceUpdate, 0, len(entries))
+	for _, entry := range entries {
+		balance, seen := balances[entry.AccountID]
+		if !seen {
+			var err error
+			balance, err = l.GetBalance(entry.AccountID)
+			if err != nil {
+				l.appLogger.Error("failed to compute balance for listeners",
+					"account_id", entry.AccountID,
+					"error", err,
+				)
+				return
+			}
+			balances[entry.AccountID] = balance
+		}
+		updates = append(updates, models.BalanceUpdate{AccountID: entry.AccountID, Entry: entry, Balance: balance})
+	}
+
+	for _, listener := range l.listeners {
+		listener.EntriesCommitted(updates)
+	}
+}
+
+var ErrSearchNotSupported = errors.New("store does not support transaction search")
+
+// SearchTransactions finds transactions matching every filter set, newest first. When more
+// match than the limit, it also returns the cursor to pass as filter.After for the next page.
+func (l *Ledger) SearchTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, *models.TransactionCursor, error) {
+	search, ok := interfaces.Capability[interfaces.TransactionSearchStore](l.store)
+	if !ok {
+		return nil, nil, ErrSearchNotSupported
+	}
+	if err := validateTransactionFilter(filter); err != nil {
+		return nil, nil, err
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+
+	// One extra row tells whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+	transactions, err := search.SearchTransactions(ctx, filter)
+	if err != nil || len(transactions) <= limit {
+		return transactions, nil, err
+	}
+	transactions = transactions[:limit]
+	last := transactions[limit-1]
+	return transactions, &models.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+// findTransaction returns the one transaction matching filter, typically by ID or idempotency key
+func (l *Ledger) findTransaction(ctx context.Context, filter models.TransactionFilter) (models.Transaction, error) {
+	search, ok := interfaces.Capability[interfaces.TransactionSearchStore](l.store)
+	if !ok {
+		return models.Transaction{}, ErrSearchNotSupported
+	}
+	filter.Limit = 1
+	found, err := search.SearchTransactions(ctx, filter)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	if len(found) == 0 {
+		return models.Transaction{}, ErrTransactionNotFound
+	}
+	return found[0], nil
+}
+
+func validateTransactionFilter(filter models.TransactionFilter) error {
+	var errs validation.Errors
+	if filter.MinAmount.IsNegative() {
+		errs = append(errs, validation.FieldError{Field: "min_amount", Message: "must not be negative"})
+	}
+	if !filter.MinAmount.IsZero() && !filter.MaxAmount.IsZero() && filter.MinAmount.GreaterThan(filter.MaxAmount) {
+		errs = append(errs, validation.FieldError{Field: "max_amount", Message: "must not be below min_amount"})
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		errs = append(errs, validation.FieldError{Field: "to", Message: "must be after from"})
+	}
+	switch filter.Status {
+	case "", models.TransactionPosted, models.TransactionAdjustment:
+	default:
+		errs = append(errs, validation.FieldError{Field: "status", Message: "must be posted or adjustment; pending transactions are listed at /transactions/pending"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
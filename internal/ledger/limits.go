@@ -0,0 +1,146 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var (
+	ErrLimitExceeded         = errors.New("transaction limit exceeded")
+	ErrLimitsNotSupported    = errors.New("store does not support transaction limits")
+	ErrLimitProfileNotFound  = errors.New("limit profile not found")
+	ErrInvalidLimitProfile   = errors.New("invalid limit profile")
+	ErrLimitProfileIDMissing = errors.New("limit profile id is required")
+)
+
+// checkVelocityLimits applies the sender's limit profile to the outgoing amount.
+// Must be called under the account locks so concurrent debits cannot both fit the same window.
+func (l *Ledger) checkVelocityLimits(ctx context.Context, tx models.Transaction) error {
+	if l.limits == nil || tx.Internal {
+		return nil
+	}
+
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil || from.LimitProfileID == "" {
+		return err
+	}
+	profile, err := l.limits.GetLimitProfile(ctx, from.LimitProfileID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		// A dangling profile reference must not silently disable limits
+		return fmt.Errorf("%w: %s", ErrLimitProfileNotFound, from.LimitProfileID)
+	}
+
+	now := l.clock.Now()
+	for _, rule := range profile.Rules {
+		window := time.Duration(rule.Window)
+		total, count, err := l.limits.GetDebitTotals(ctx, tx.FromAccount, now.Add(-window))
+		if err != nil {
+			return err
+		}
+		if rule.MaxAmount.IsPositive() && total.Add(tx.Amount).GreaterThan(rule.MaxAmount) {
+			return fmt.Errorf("%w: %s would exceed %s per %s", ErrLimitExceeded, total.Add(tx.Amount), rule.MaxAmount, window)
+		}
+		if rule.MaxCount > 0 && count+1 > rule.MaxCount {
+			return fmt.Errorf("%w: more than %d payments per %s", ErrLimitExceeded, rule.MaxCount, window)
+		}
+	}
+	return nil
+}
+
+func validateLimitProfile(profile models.LimitProfile) error {
+	if profile.ID == "" {
+		return ErrLimitProfileIDMissing
+	}
+	for _, rule := range profile.Rules {
+		if rule.Window <= 0 {
+			return fmt.Errorf("%w: window must be positive", ErrInvalidLimitProfile)
+		}
+		if rule.MaxAmount.IsNegative() || rule.MaxCount < 0 {
+			return fmt.Errorf("%w: limits must not be negative", ErrInvalidLimitProfile)
+		}
+	}
+	return nil
+}
+
+// SaveLimitProfile creates or replaces a limit profile
+func (l *Ledger) SaveLimitProfile(ctx context.Context, profile models.LimitProfile) (models.LimitProfile, error) {
+	if l.limits == nil {
+		return models.LimitProfile{}, ErrLimitsNotSupported
+	}
+	if err := validateLimitProfile(profile); err != nil {
+		return models.LimitProfile{}, err
+	}
+
+	before, err := l.limits.GetLimitProfile(ctx, profile.ID)
+	if err != nil {
+		return models.LimitProfile{}, err
+	}
+
+	profile.UpdatedAt = l.clock.Now().UTC()
+	if err := l.limits.SaveLimitProfile(ctx, profile); err != nil {
+		return models.LimitProfile{}, err
+	}
+	l.recordAudit(ctx, "limit_profile.save", "limit_profile:"+profile.ID, before, profile)
+	return profile, nil
+}
+
+func (l *Ledger) GetLimitProfile(ctx context.Context, id string) (models.LimitProfile, error) {
+	if l.limits == nil {
+		return models.LimitProfile{}, ErrLimitsNotSupported
+	}
+	profile, err := l.limits.GetLimitProfile(ctx, id)
+	if err != nil {
+		return models.LimitProfile{}, err
+	}
+	if profile == nil {
+		return models.LimitProfile{}, ErrLimitProfileNotFound
+	}
+	return *profile, nil
+}
+
+func (l *Ledger) ListLimitProfiles(ctx context.Context) ([]models.LimitProfile, error) {
+	if l.limits == nil {
+		return nil, ErrLimitsNotSupported
+	}
+	return l.limits.ListLimitProfiles(ctx)
+}
+
+// AssignLimitProfile applies a profile to an account; an empty profileId removes its limits
+func (l *Ledger) AssignLimitProfile(ctx context.Context, accountId, profileId string) (models.Account, error) {
+	if l.accounts == nil || l.limits == nil {
+		return models.Account{}, ErrLimitsNotSupported
+	}
+	if accountId == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	if profileId != "" {
+		if _, err := l.GetLimitProfile(ctx, profileId); err != nil {
+			return models.Account{}, err
+		}
+	}
+
+	mu := l.getAccountLock(accountId)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, accountId)
+	if err != nil {
+		return models.Account{}, err
+	}
+	after := before
+	after.LimitProfileID = profileId
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+
+	l.recordAudit(ctx, "account.limit_profile", "account:"+accountId, before.Redacted(), after.Redacted())
+	return after, nil
+}
@@ -0,0 +1,134 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+	ErrInvalidOverdraftLimit = errors.New("overdraft limit must not be negative")
+)
+
+// checkFunds rejects a debit that would take the sender below -OverdraftLimit and
+// returns the sender's balance before the posting. Must be called under the account locks.
+func (l *Ledger) checkFunds(ctx context.Context, tx models.Transaction) (decimal.Decimal, error) {
+	if !l.fundsCheck || tx.Internal {
+		return decimal.Zero, nil
+	}
+
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	balance, err := l.GetBalance(tx.FromAccount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, coversDebit(tx, from, balance)
+}
+
+// coversDebit reports ErrInsufficientFunds when balance cannot cover the transaction.
+// Fees are debited from the sender too.
+func coversDebit(tx models.Transaction, from models.Account, balance decimal.Decimal) error {
+	debited := tx.Amount.Add(tx.TotalFees())
+	if balance.Sub(debited).LessThan(from.OverdraftLimit.Neg()) {
+		return fmt.Errorf("%w: balance %s, overdraft limit %s, amount %s",
+			ErrInsufficientFunds, balance, from.OverdraftLimit, debited)
+	}
+	return nil
+}
+
+// fundsGuard repeats the funds check against the sender's balance row while the store holds
+// its lock, so replicas debiting the same account cannot both spend the same money.
+// It returns nil when no check applies.
+func (l *Ledger) fundsGuard(ctx context.Context, tx models.Transaction) (func(map[string]decimal.Decimal) error, error) {
+	if !l.fundsCheck || tx.Internal {
+		return nil, nil
+	}
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		return nil, err
+	}
+	return func(balances map[string]decimal.Decimal) error {
+		return coversDebit(tx, from, balances[tx.FromAccount])
+	}, nil
+}
+
+// notifyOverdraft publishes an event when the posting moved the sender into its overdraft
+func (l *Ledger) notifyOverdraft(ctx context.Context, tx models.Transaction, balanceBefore decimal.Decimal) {
+	if !l.fundsCheck || tx.Internal {
+		return
+	}
+	balanceAfter := balanceBefore.Sub(tx.Amount).Sub(tx.TotalFees())
+	if balanceBefore.IsNegative() || !balanceAfter.IsNegative() {
+		return
+	}
+
+	from, err := l.getAccount(ctx, tx.FromAccount)
+	if err != nil {
+		l.appLogger.Error("failed to load account for overdraft event", "account_id", tx.FromAccount, "error", err)
+		return
+	}
+	l.publish("accounts.overdraft_entered", events.AccountOverdraftEntered{
+		AccountID:      tx.FromAccount,
+		TransactionID:  tx.ID,
+		Balance:        balanceAfter,
+		OverdraftLimit: from.OverdraftLimit,
+		OccurredAt:     l.clock.Now(),
+	})
+}
+
+// SetOverdraftLimit sets how far below zero an account's balance may go
+func (l *Ledger) SetOverdraftLimit(ctx context.Context, id string, limit decimal.Decimal) (models.Account, error) {
+	if l.accounts == nil {
+		return models.Account{}, ErrAccountsNotSupported
+	}
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	if limit.IsNegative() {
+		return models.Account{}, ErrInvalidOverdraftLimit
+	}
+
+	mu := l.getAccountLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	before, err := l.getAccount(ctx, id)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if before.Status == models.AccountClosed {
+		return before, fmt.Errorf("%w: %s", ErrAccountClosed, id)
+	}
+
+	after := before
+	after.OverdraftLimit = limit
+	after.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, after); err != nil {
+		return models.Account{}, err
+	}
+
+	l.recordAudit(ctx, "account.overdraft_limit", "account:"+id, before.Redacted(), after.Redacted())
+	l.appLogger.Info("overdraft limit changed",
+		"account_id", id,
+		"from", before.OverdraftLimit.String(),
+		"to", limit.String(),
+	)
+	return after, nil
+}
+
+// GetAccount returns the account controls, defaulting to an active account without overdraft
+func (l *Ledger) GetAccount(ctx context.Context, id string) (models.Account, error) {
+	if id == "" {
+		return models.Account{}, ErrAccountIDRequired
+	}
+	return l.getAccount(ctx, id)
+}
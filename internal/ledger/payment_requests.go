@@ -0,0 +1,165 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrPaymentRequestsNotSupported = errors.New("store does not support payment requests")
+	ErrPaymentRequestNotFound      = errors.New("payment request not found")
+	ErrInvalidPaymentRequest       = errors.New("invalid payment request")
+	ErrPaymentRequestNotOpen       = errors.New("payment request is not open")
+	ErrPaymentRequestMismatch      = errors.New("transaction does not match the payment request")
+)
+
+// defaultPaymentRequestTTL applies when a request is created without an expiry
+const defaultPaymentRequestTTL = 7 * 24 * time.Hour
+
+// CreatePaymentRequest opens a request for the payee account of the caller's tenant
+func (l *Ledger) CreatePaymentRequest(ctx context.Context, request models.PaymentRequest) (models.PaymentRequest, error) {
+	if l.requests == nil {
+		return models.PaymentRequest{}, ErrPaymentRequestsNotSupported
+	}
+	now := l.clock.Now().UTC()
+	if request.PayeeAccount == "" {
+		return models.PaymentRequest{}, ErrAccountIDRequired
+	}
+	if !request.Amount.IsPositive() {
+		return models.PaymentRequest{}, fmt.Errorf("%w: amount must be positive", ErrInvalidPaymentRequest)
+	}
+	if request.ExpiresAt.IsZero() {
+		request.ExpiresAt = now.Add(defaultPaymentRequestTTL)
+	}
+	if !request.ExpiresAt.After(now) {
+		return models.PaymentRequest{}, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidPaymentRequest)
+	}
+	if err := l.CheckAccountAccess(ctx, request.PayeeAccount); err != nil {
+		return models.PaymentRequest{}, err
+	}
+	// The request is paid in the payee's currency
+	code, err := l.AccountCurrency(ctx, request.PayeeAccount)
+	if err != nil {
+		return models.PaymentRequest{}, err
+	}
+	if request.Amount, err = l.holdPrecision(request.Amount, code); err != nil {
+		return models.PaymentRequest{}, err
+	}
+
+	request = models.PaymentRequest{
+		ID:           uuid.New().String(),
+		TenantID:     tenant.FromContext(ctx),
+		PayeeAccount: request.PayeeAccount,
+		Amount:       request.Amount,
+		Reference:    request.Reference,
+		Description:  request.Description,
+		Status:       models.PaymentRequestOpen,
+		ExpiresAt:    request.ExpiresAt.UTC().Truncate(time.Microsecond),
+		CreatedAt:    now,
+	}
+	if err := l.requests.SavePaymentRequest(ctx, request); err != nil {
+		return models.PaymentRequest{}, err
+	}
+	l.recordAudit(ctx, "payment_request.create", "payment_request:"+request.ID, nil, request)
+	return request, nil
+}
+
+// GetPaymentRequest returns a request of the caller's tenant; open requests past their
+// expiry are reported as expired
+func (l *Ledger) GetPaymentRequest(ctx context.Context, id string) (models.PaymentRequest, error) {
+	if l.requests == nil {
+		return models.PaymentRequest{}, ErrPaymentRequestsNotSupported
+	}
+	request, err := l.requests.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return models.PaymentRequest{}, err
+	}
+	tenantId := tenant.FromContext(ctx)
+	if request == nil || (tenantId != "" && request.TenantID != tenantId) {
+		return models.PaymentRequest{}, fmt.Errorf("%w: %s", ErrPaymentRequestNotFound, id)
+	}
+	return l.withExpiry(*request), nil
+}
+
+func (l *Ledger) ListPaymentRequests(ctx context.Context, payeeAccount string) ([]models.PaymentRequest, error) {
+	if l.requests == nil {
+		return nil, ErrPaymentRequestsNotSupported
+	}
+	requests, err := l.requests.ListPaymentRequests(ctx, payeeAccount)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		requests[i] = l.withExpiry(requests[i])
+	}
+	return requests, nil
+}
+
+// CancelPaymentRequest withdraws an open request so it can no longer be paid
+func (l *Ledger) CancelPaymentRequest(ctx context.Context, id string) (models.PaymentRequest, error) {
+	before, err := l.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return models.PaymentRequest{}, err
+	}
+	if before.Status != models.PaymentRequestOpen {
+		return models.PaymentRequest{}, fmt.Errorf("%w: %s is %s", ErrPaymentRequestNotOpen, id, before.Status)
+	}
+
+	now := l.clock.Now().UTC()
+	cancelled, err := l.requests.CancelPaymentRequest(ctx, id, now)
+	if err != nil {
+		return models.PaymentRequest{}, err
+	}
+	if !cancelled {
+		// Paid in the meantime
+		return models.PaymentRequest{}, fmt.Errorf("%w: %s", ErrPaymentRequestNotOpen, id)
+	}
+	after := before
+	after.Status = models.PaymentRequestCancelled
+	after.CancelledAt = &now
+	l.recordAudit(ctx, "payment_request.cancel", "payment_request:"+id, before, after)
+	return after, nil
+}
+
+func (l *Ledger) withExpiry(request models.PaymentRequest) models.PaymentRequest {
+	if request.Status == models.PaymentRequestOpen && !l.clock.Now().Before(request.ExpiresAt) {
+		request.Status = models.PaymentRequestExpired
+	}
+	return request
+}
+
+// applyPaymentRequest checks a transaction paying a request against it, filling in the payee,
+// amount and reference when they were left out. The store marks the request paid in the
+// database transaction that saves the payment, which settles races between payers.
+func (l *Ledger) applyPaymentRequest(ctx context.Context, tx *models.Transaction) error {
+	if tx.PaymentRequestID == "" {
+		return nil
+	}
+	request, err := l.GetPaymentRequest(ctx, tx.PaymentRequestID)
+	if err != nil {
+		return err
+	}
+	if request.Status != models.PaymentRequestOpen {
+		return fmt.Errorf("%w: %s is %s", ErrPaymentRequestNotOpen, request.ID, request.Status)
+	}
+
+	if tx.ToAccount == "" {
+		tx.ToAccount = request.PayeeAccount
+	}
+	if tx.Amount.IsZero() {
+		tx.Amount = request.Amount
+	}
+	if tx.Reference == "" {
+		tx.Reference = request.Reference
+	}
+	if tx.ToAccount != request.PayeeAccount || !tx.Amount.Equal(request.Amount) {
+		return fmt.Errorf("%w: expected %s to %s", ErrPaymentRequestMismatch, request.Amount, request.PayeeAccount)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var (
+	ErrPeriodClosed          = errors.New("accounting period is closed")
+	ErrInvalidPeriod         = errors.New("period id must be a month formatted as YYYY-MM")
+	ErrPeriodsNotSupported   = errors.New("store does not support accounting periods")
+	ErrPeriodAlreadyClosed   = errors.New("accounting period is already closed")
+	ErrCannotCloseOpenPeriod = errors.New("cannot close the current or a future period")
+	ErrFutureDated           = errors.New("transaction is dated in the future")
+)
+
+const periodLayout = "2006-01"
+
+// BackdatingPolicy decides what happens to a transaction dated inside a closed period
+type BackdatingPolicy string
+
+const (
+	// BackdateAdjust moves late transactions into the current open period and flags them as adjustments
+	BackdateAdjust BackdatingPolicy = "adjust"
+	// BackdateReject refuses late transactions with ErrPeriodClosed
+	BackdateReject BackdatingPolicy = "reject"
+)
+
+func backdatingPolicyFromEnv() BackdatingPolicy {
+	if BackdatingPolicy(os.Getenv("PERIOD_BACKDATING")) == BackdateReject {
+		return BackdateReject
+	}
+	return BackdateAdjust
+}
+
+// periodFor builds the monthly period containing t
+func periodFor(t time.Time) models.AccountingPeriod {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return models.AccountingPeriod{
+		ID:     start.Format(periodLayout),
+		Start:  start,
+		End:    start.AddDate(0, 1, 0),
+		Status: models.PeriodOpen,
+	}
+}
+
+func (l *Ledger) isPeriodClosed(ctx context.Context, t time.Time) (bool, error) {
+	period, err := l.periods.GetPeriod(ctx, periodFor(t).ID)
+	if err != nil {
+		return false, err
+	}
+	return period != nil && period.Status == models.PeriodClosed, nil
+}
+
+// applyPeriodRules checks the transaction date against closed periods and,
+// depending on the backdating policy, either rejects it or re-dates it into the current period.
+func (l *Ledger) applyPeriodRules(ctx context.Context, tx *models.Transaction) error {
+	if l.periods == nil {
+		return nil
+	}
+
+	closed, err := l.isPeriodClosed(ctx, tx.CreatedAt)
+	if err != nil || !closed {
+		return err
+	}
+	if l.backdating == BackdateReject {
+		return ErrPeriodClosed
+	}
+
+	now := l.clock.Now().UTC()
+	closed, err = l.isPeriodClosed(ctx, now)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrPeriodClosed
+	}
+
+	original := tx.CreatedAt
+	tx.OriginalCreatedAt = &original
+	tx.CreatedAt = now
+	tx.Adjustment = true
+
+	l.appLogger.Info("transaction moved into current period",
+		"transaction_id", tx.ID,
+		"requested_date", original,
+		"period", periodFor(now).ID,
+	)
+	return nil
+}
+
+// checkNotFutureDated refuses dates beyond now plus MAX_CLOCK_SKEW. A posting dated ahead
+// would sit in a period that is still open when every posting around it is checked.
+func (l *Ledger) checkNotFutureDated(tx models.Transaction) error {
+	if latest := l.clock.Now().Add(l.maxSkew); tx.CreatedAt.After(latest) {
+		return fmt.Errorf("%w: %s is after %s", ErrFutureDated,
+			tx.CreatedAt.UTC().Format(time.RFC3339), latest.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ClosePeriod closes a past month so that no more entries can be dated inside it. It waits
+// for postings already past their period check; the store keeps other instances out.
+func (l *Ledger) ClosePeriod(ctx context.Context, id string) (models.AccountingPeriod, error) {
+	if l.periods == nil {
+		return models.AccountingPeriod{}, ErrPeriodsNotSupported
+	}
+	l.periodMu.Lock()
+	defer l.periodMu.Unlock()
+
+	start, err := time.Parse(periodLayout, id)
+	if err != nil {
+		return models.AccountingPeriod{}, ErrInvalidPeriod
+	}
+	period := periodFor(start)
+	if period.End.After(l.clock.Now().UTC()) {
+		return models.AccountingPeriod{}, ErrCannotCloseOpenPeriod
+	}
+
+	existing, err := l.periods.GetPeriod(ctx, period.ID)
+	if err != nil {
+		return models.AccountingPeriod{}, err
+	}
+	if existing != nil && existing.Status == models.PeriodClosed {
+		return *existing, ErrPeriodAlreadyClosed
+	}
+
+	closedAt := l.clock.Now().UTC()
+	period.Status = models.PeriodClosed
+	period.ClosedAt = &closedAt
+	if err := l.periods.SavePeriod(ctx, period); err != nil {
+		return models.AccountingPeriod{}, err
+	}
+
+	l.recordAudit(ctx, "period.close", "period:"+period.ID, existing, period)
+	l.appLogger.Info("accounting period closed", "period", period.ID)
+	return period, nil
+}
+
+// ListPeriods returns every period that has been explicitly recorded; unlisted months are open
+func (l *Ledger) ListPeriods(ctx context.Context) ([]models.AccountingPeriod, error) {
+	if l.periods == nil {
+		return nil, ErrPeriodsNotSupported
+	}
+	return l.periods.ListPeriods(ctx)
+}
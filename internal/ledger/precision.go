@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/currency"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrExcessPrecision = errors.New("amount has more decimal places than its currency")
+
+// PrecisionPolicy decides what happens to an amount finer than its currency's minor unit
+type PrecisionPolicy string
+
+const (
+	// PrecisionReject refuses the amount with ErrExcessPrecision
+	PrecisionReject PrecisionPolicy = "reject"
+	// PrecisionRound rounds the amount half to even
+	PrecisionRound PrecisionPolicy = "round"
+)
+
+func precisionPolicyFromEnv() PrecisionPolicy {
+	if PrecisionPolicy(os.Getenv("AMOUNT_PRECISION")) == PrecisionRound {
+		return PrecisionRound
+	}
+	return PrecisionReject
+}
+
+// checkPrecision holds the amount to the sender's currency. Internal postings are exempt:
+// interest accruals deliberately keep full ledger precision.
+func (l *Ledger) checkPrecision(ctx context.Context, tx *models.Transaction) error {
+	if tx.Internal {
+		return nil
+	}
+	code, err := l.AccountCurrency(ctx, tx.FromAccount)
+	if err != nil {
+		return err
+	}
+	tx.Amount, err = l.holdPrecision(tx.Amount, code)
+	return err
+}
+
+// holdPrecision applies the precision policy to an amount in the given currency
+func (l *Ledger) holdPrecision(amount decimal.Decimal, code string) (decimal.Decimal, error) {
+	if !currency.ExcessPrecision(amount, code) {
+		return amount, nil
+	}
+	if l.precision == PrecisionRound {
+		return currency.Round(amount, code), nil
+	}
+	return amount, fmt.Errorf("%w: %s has at most %d decimals, got %s", ErrExcessPrecision, code, currency.Scale(code), amount)
+}
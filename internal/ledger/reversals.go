@@ -0,0 +1,277 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/audit"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrReversalWindowExpired = errors.New("transaction is too old to reverse")
+	ErrReversalNotPermitted  = errors.New("caller may not reverse transactions")
+	ErrReversalPeriodClosed  = errors.New("transaction belongs to a closed accounting period")
+	ErrCannotReverse         = errors.New("transaction cannot be reversed")
+	ErrInvalidReversalBatch  = errors.New("invalid reversal batch")
+	ErrReservedKey           = errors.New("idempotency key uses a prefix reserved for the ledger")
+)
+
+// ReversalKeyPrefix starts the idempotency key of every reversal, followed by the original's ID
+const ReversalKeyPrefix = "reversal-"
+
+// maxReversalBatch caps how many transactions one batch may reverse
+const maxReversalBatch = 1000
+
+// ReversalPolicy limits who may reverse a transaction and for how long after posting
+type ReversalPolicy struct {
+	Window time.Duration // zero means no limit
+	Roles  []string      // any one of them is required; empty lets every caller reverse
+}
+
+// reversalPolicyFromEnv reads REVERSAL_WINDOW (e.g. "720h") and REVERSAL_ROLES ("ops,finance")
+func reversalPolicyFromEnv() ReversalPolicy {
+	policy := ReversalPolicy{Window: envDuration("REVERSAL_WINDOW", 0)}
+	for role := range strings.SplitSeq(os.Getenv("REVERSAL_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			policy.Roles = append(policy.Roles, role)
+		}
+	}
+	return policy
+}
+
+// ReverseTransaction posts the mirror image of a transaction, returning the money to the
+// sender. It is refused after the reversal window, to callers without a reversal role,
+// and once the period the transaction was posted in has closed: closed books only change
+// through new postings in the open period. Fees charged on the original are kept.
+// The reversal is keyed by the original, so it is posted once however often it is retried.
+func (l *Ledger) ReverseTransaction(ctx context.Context, id, reason string) (models.Transaction, error) {
+	if err := l.checkReversalRole(ctx); err != nil {
+		return models.Transaction{}, err
+	}
+	return l.reverseTransaction(ctx, id, reason, "")
+}
+
+func (l *Ledger) checkReversalRole(ctx context.Context) error {
+	if len(l.reversals.Roles) > 0 && !audit.FromContext(ctx).HasRole(l.reversals.Roles...) {
+		return fmt.Errorf("%w: one of the roles %s is required", ErrReversalNotPermitted, strings.Join(l.reversals.Roles, ", "))
+	}
+	return nil
+}
+
+// checkReversalMarkers keeps a caller's posting from passing for a reversal: a reversal-<id>
+// key would stop <id> from ever being reversed, and a reversal_of entry would make the
+// posting itself irreversible. The auto- keys of keyless postings are reserved as well.
+func checkReversalMarkers(tx *models.Transaction) error {
+	if tx.Internal {
+		return nil
+	}
+	for _, prefix := range []string{ReversalKeyPrefix, AutoKeyPrefix} {
+		if strings.HasPrefix(tx.IdempotencyKey, prefix) {
+			return fmt.Errorf("%w: %q", ErrReservedKey, prefix)
+		}
+	}
+	if _, ok := tx.Metadata["reversal_of"]; ok {
+		tx.Metadata = maps.Clone(tx.Metadata)
+		delete(tx.Metadata, "reversal_of")
+	}
+	return nil
+}
+
+// reverseTransaction applies the window and period rules and posts the reversal; adjustment,
+// when set, is the reference of the batch it belongs to
+func (l *Ledger) reverseTransaction(ctx context.Context, id, reason, adjustment string) (models.Transaction, error) {
+	original, err := l.findTransaction(ctx, models.TransactionFilter{ID: id})
+	if err != nil {
+		if errors.Is(err, ErrTransactionNotFound) {
+			return models.Transaction{}, fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+		}
+		return models.Transaction{}, err
+	}
+	if reversed := original.Metadata["reversal_of"]; reversed != "" {
+		return models.Transaction{}, fmt.Errorf("%w: %s is itself the reversal of %s", ErrCannotReverse, id, reversed)
+	}
+
+	// Replays skip the window and period checks: the reversal was posted while they passed
+	key := ReversalKeyPrefix + id
+	if reversal, err := l.findTransaction(ctx, models.TransactionFilter{IdempotencyKey: key}); err == nil {
+		return reversal, nil
+	} else if !errors.Is(err, ErrTransactionNotFound) {
+		return models.Transaction{}, err
+	}
+
+	// A transaction moved into a later period by backdating is judged by the date it was booked on
+	now := l.clock.Now().UTC()
+	if l.reversals.Window > 0 && now.Sub(original.CreatedAt) > l.reversals.Window {
+		return models.Transaction{}, fmt.Errorf("%w: posted %s ago, the window is %s",
+			ErrReversalWindowExpired, now.Sub(original.CreatedAt).Truncate(time.Second), l.reversals.Window)
+	}
+	if l.periods != nil {
+		closed, err := l.isPeriodClosed(ctx, original.CreatedAt)
+		if err != nil {
+			return models.Transaction{}, err
+		}
+		if closed {
+			return models.Transaction{}, fmt.Errorf("%w: %s", ErrReversalPeriodClosed, periodFor(original.CreatedAt).ID)
+		}
+	}
+
+	tx := models.Transaction{
+		ID:             l.NewID(),
+		IdempotencyKey: key,
+		FromAccount:    original.ToAccount,
+		ToAccount:      original.FromAccount,
+		Amount:         original.Amount,
+		CreatedAt:      now,
+		Internal:       true, // a correction: funds, limits and rules must not block it
+		Reference:      original.Reference,
+		Description:    "Reversal of transaction " + id,
+		Metadata: map[string]string{
+			"type":        "reversal",
+			"reversal_of": id,
+		},
+	}
+	if reason != "" {
+		tx.Metadata["reversal_reason"] = reason
+	}
+	if adjustment != "" {
+		tx.Metadata["adjustment_reference"] = adjustment
+	}
+	// A conversion is undone at the inverse of the original rate, so the sender gets back what left
+	if original.FX != nil {
+		tx.Amount = original.FX.ConvertedAmount
+		tx.FX = &models.FXConversion{Rate: decimal.NewFromInt(1).Div(original.FX.Rate)}
+	}
+
+	posted, exists, err := l.PostTransactionDetailed(ctx, tx)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	if exists {
+		// A concurrent call won the race
+		return l.findTransaction(ctx, models.TransactionFilter{IdempotencyKey: key})
+	}
+
+	l.recordAudit(ctx, "transaction.reverse", "transaction:"+id, original, posted)
+	l.appLogger.Info("transaction reversed",
+		"transaction_id", id,
+		"reversal_id", posted.ID,
+		"reason", reason,
+	)
+	return posted, nil
+}
+
+// ReverseTransactions reverses a batch under one adjustment reference, which is stored on
+// every reversal so the whole remediation can be found with a metadata search. Each
+// transaction is reversed on its own: one refused by the policy is reported and the rest
+// go ahead. A rerun of the same batch replays the reversals already posted.
+func (l *Ledger) ReverseTransactions(ctx context.Context, batch models.ReversalBatch) (models.ReversalBatchResult, error) {
+	if batch.AdjustmentReference == "" {
+		return models.ReversalBatchResult{}, fmt.Errorf("%w: an adjustment reference is required", ErrInvalidReversalBatch)
+	}
+	if err := l.checkReversalRole(ctx); err != nil {
+		return models.ReversalBatchResult{}, err
+	}
+	ids, err := l.reversalTargets(ctx, batch)
+	if err != nil {
+		return models.ReversalBatchResult{}, err
+	}
+
+	result := models.ReversalBatchResult{
+		AdjustmentReference: batch.AdjustmentReference,
+		DryRun:              batch.DryRun,
+		Matched:             len(ids),
+		Results:             []models.ReversalResult{},
+	}
+	for _, id := range ids {
+		if batch.DryRun {
+			result.Results = append(result.Results, models.ReversalResult{TransactionID: id})
+			continue
+		}
+		reversal, err := l.reverseTransaction(ctx, id, batch.Reason, batch.AdjustmentReference)
+		if err != nil {
+			result.Failed++
+			result.Results = append(result.Results, models.ReversalResult{TransactionID: id, Error: err.Error()})
+			continue
+		}
+		result.Reversed++
+		result.Results = append(result.Results, models.ReversalResult{TransactionID: id, ReversalID: reversal.ID})
+	}
+
+	if !batch.DryRun {
+		l.appLogger.Info("reversal batch finished",
+			"adjustment_reference", batch.AdjustmentReference,
+			"matched", result.Matched,
+			"reversed", result.Reversed,
+			"failed", result.Failed,
+		)
+	}
+	return result, nil
+}
+
+// reversalTargets lists the IDs named by the batch and those its filter matches, once each.
+// Reversals are left out of the filter matches: undoing a bad batch must not undo earlier fixes.
+func (l *Ledger) reversalTargets(ctx context.Context, batch models.ReversalBatch) ([]string, error) {
+	if len(batch.TransactionIDs) == 0 && batch.Filter == nil {
+		return nil, fmt.Errorf("%w: transaction IDs or a filter are required", ErrInvalidReversalBatch)
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) error {
+		if id == "" || seen[id] {
+			return nil
+		}
+		if len(ids) == maxReversalBatch {
+			return fmt.Errorf("%w: more than %d transactions; split the batch or narrow the filter", ErrInvalidReversalBatch, maxReversalBatch)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		return nil
+	}
+	for _, id := range batch.TransactionIDs {
+		if err := add(id); err != nil {
+			return nil, err
+		}
+	}
+	if batch.Filter == nil {
+		return ids, nil
+	}
+
+	filter := *batch.Filter
+	if !filterNarrowed(filter) {
+		return nil, fmt.Errorf("%w: the filter must set at least one criterion", ErrInvalidReversalBatch)
+	}
+	filter.Limit = 500
+	for {
+		page, next, err := l.SearchTransactions(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page {
+			if tx.Metadata["reversal_of"] != "" {
+				continue
+			}
+			if err := add(tx.ID); err != nil {
+				return nil, err
+			}
+		}
+		if next == nil {
+			return ids, nil
+		}
+		filter.After = next
+	}
+}
+
+// filterNarrowed reports whether a filter selects anything less than every transaction
+func filterNarrowed(filter models.TransactionFilter) bool {
+	return filter.ID != "" || filter.Reference != "" || len(filter.Metadata) > 0 || len(filter.Tags) > 0 ||
+		filter.Account != "" || !filter.MinAmount.IsZero() || !filter.MaxAmount.IsZero() ||
+		!filter.From.IsZero() || !filter.To.IsZero() || filter.Status != "" || filter.IdempotencyKey != ""
+}
@@ -0,0 +1,34 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func TestCheckReversalMarkers(t *testing.T) {
+	for _, key := range []string{"reversal-tx-1", "auto-0190"} {
+		if err := checkReversalMarkers(&models.Transaction{IdempotencyKey: key}); !errors.Is(err, ErrReservedKey) {
+			t.Errorf("key %q: err = %v, want %v", key, err, ErrReservedKey)
+		}
+	}
+
+	metadata := map[string]string{"reversal_of": "tx-1", "order": "42"}
+	tx := models.Transaction{IdempotencyKey: "key-1", Metadata: metadata}
+	if err := checkReversalMarkers(&tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tx.Metadata["reversal_of"]; ok || tx.Metadata["order"] != "42" {
+		t.Errorf("metadata = %v, want only order", tx.Metadata)
+	}
+	if metadata["reversal_of"] != "tx-1" {
+		t.Error("the caller's metadata map was changed")
+	}
+
+	// The ledger's own reversals keep both
+	reversal := models.Transaction{IdempotencyKey: "reversal-tx-1", Internal: true, Metadata: map[string]string{"reversal_of": "tx-1"}}
+	if err := checkReversalMarkers(&reversal); err != nil || reversal.Metadata["reversal_of"] != "tx-1" {
+		t.Errorf("internal reversal: err = %v, metadata = %v", err, reversal.Metadata)
+	}
+}
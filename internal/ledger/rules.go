@@ -0,0 +1,227 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+)
+
+var (
+	ErrTransactionBlocked = errors.New("transaction blocked by rule")
+	ErrRulesNotSupported  = errors.New("store does not support transaction rules")
+	ErrRuleNotFound       = errors.New("rule not found")
+	ErrInvalidRule        = errors.New("invalid rule")
+)
+
+// RuleEvaluator decides whether a rule matches a transaction and explains why.
+// Evaluators run under the account locks, so the data they read cannot change underneath them.
+type RuleEvaluator func(ctx context.Context, store interfaces.RuleStore, rule models.FraudRule, tx models.Transaction) (bool, string, error)
+
+var ruleEvaluators = map[string]RuleEvaluator{
+	models.RuleTypeAmountThreshold: evaluateAmountThreshold,
+	models.RuleTypeNewCounterparty: evaluateNewCounterparty,
+	models.RuleTypeRapidFire:       evaluateRapidFire,
+}
+
+// RegisterRuleType plugs a custom rule type into the pipeline. It must be called before serving traffic.
+func RegisterRuleType(ruleType string, evaluator RuleEvaluator) {
+	ruleEvaluators[ruleType] = evaluator
+}
+
+func evaluateAmountThreshold(_ context.Context, _ interfaces.RuleStore, rule models.FraudRule, tx models.Transaction) (bool, string, error) {
+	if tx.Amount.LessThan(rule.Threshold) {
+		return false, "", nil
+	}
+	return true, fmt.Sprintf("amount %s is at or above %s", tx.Amount, rule.Threshold), nil
+}
+
+func evaluateNewCounterparty(ctx context.Context, store interfaces.RuleStore, _ models.FraudRule, tx models.Transaction) (bool, string, error) {
+	paid, err := store.HasPaidCounterparty(ctx, tx.FromAccount, tx.ToAccount)
+	if err != nil || paid {
+		return false, "", err
+	}
+	return true, "first payment to " + tx.ToAccount, nil
+}
+
+func evaluateRapidFire(ctx context.Context, store interfaces.RuleStore, rule models.FraudRule, tx models.Transaction) (bool, string, error) {
+	window := time.Duration(rule.Window)
+	count, err := store.CountDebitsSince(ctx, tx.FromAccount, clock.FromContext(ctx).Now().Add(-window))
+	if err != nil || count+1 <= rule.MaxCount {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("%d payments within %s", count+1, window), nil
+}
+
+func actionSeverity(action string) int {
+	switch action {
+	case models.RuleActionFlag:
+		return 1
+	case models.RuleActionBlock:
+		return 2
+	}
+	return 0
+}
+
+// evaluateRules runs every enabled rule and returns the most severe outcome
+func (l *Ledger) evaluateRules(ctx context.Context, tx models.Transaction) (models.RuleDecision, error) {
+	decision := models.RuleDecision{Action: models.RuleActionAllow}
+	if l.rules == nil {
+		return decision, nil
+	}
+
+	rules, err := l.rules.ListRules(ctx)
+	if err != nil {
+		return decision, err
+	}
+	ctx = clock.WithClock(ctx, l.clock)
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		evaluator, ok := ruleEvaluators[rule.Type]
+		if !ok {
+			l.appLogger.Warn("skipping rule of unknown type", "rule_id", rule.ID, "type", rule.Type)
+			continue
+		}
+		matched, reason, err := evaluator(ctx, l.rules, rule, tx)
+		if err != nil {
+			return decision, err
+		}
+		if !matched {
+			continue
+		}
+		decision.Matches = append(decision.Matches, models.RuleMatch{RuleID: rule.ID, Action: rule.Action, Reason: reason})
+		if actionSeverity(rule.Action) > actionSeverity(decision.Action) {
+			decision.Action = rule.Action
+		}
+	}
+	return decision, nil
+}
+
+// checkRules rejects blocked transactions and returns the matches that should flag it once posted.
+// Blocks are audited unless this is a dry run.
+func (l *Ledger) checkRules(ctx context.Context, tx models.Transaction, dryRun bool) ([]models.RuleMatch, error) {
+	if tx.Internal {
+		return nil, nil
+	}
+	decision, err := l.evaluateRules(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	switch decision.Action {
+	case models.RuleActionBlock:
+		if !dryRun {
+			l.recordAudit(ctx, "transaction.block", "transaction:"+tx.ID, nil, decision)
+		}
+		for _, match := range decision.Matches {
+			if match.Action == models.RuleActionBlock {
+				return nil, fmt.Errorf("%w %s: %s", ErrTransactionBlocked, match.RuleID, match.Reason)
+			}
+		}
+	case models.RuleActionFlag:
+		return decision.Matches, nil
+	}
+	return nil, nil
+}
+
+// notifyFlagged publishes a transaction.flagged event for a posted transaction that matched flag rules
+func (l *Ledger) notifyFlagged(ctx context.Context, tx models.Transaction, matches []models.RuleMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	l.recordAudit(ctx, "transaction.flag", "transaction:"+tx.ID, nil, matches)
+	l.publish("transactions.flagged", events.TransactionFlagged{
+		TransactionID: tx.ID,
+		FromAccount:   tx.FromAccount,
+		ToAccount:     tx.ToAccount,
+		Amount:        tx.Amount,
+		Matches:       matches,
+		OccurredAt:    l.clock.Now(),
+	})
+}
+
+func validateRule(rule models.FraudRule) error {
+	if _, ok := ruleEvaluators[rule.Type]; !ok {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidRule, rule.Type)
+	}
+	switch rule.Action {
+	case models.RuleActionAllow, models.RuleActionFlag, models.RuleActionBlock:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidRule, rule.Action)
+	}
+	switch rule.Type {
+	case models.RuleTypeAmountThreshold:
+		if !rule.Threshold.IsPositive() {
+			return fmt.Errorf("%w: threshold must be positive", ErrInvalidRule)
+		}
+	case models.RuleTypeRapidFire:
+		if rule.Window <= 0 || rule.MaxCount <= 0 {
+			return fmt.Errorf("%w: window and max_count must be positive", ErrInvalidRule)
+		}
+	}
+	return nil
+}
+
+// SaveRule creates a rule, or replaces it when the ID already exists
+func (l *Ledger) SaveRule(ctx context.Context, rule models.FraudRule) (models.FraudRule, error) {
+	if l.rules == nil {
+		return models.FraudRule{}, ErrRulesNotSupported
+	}
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	if err := validateRule(rule); err != nil {
+		return models.FraudRule{}, err
+	}
+
+	before, err := l.rules.GetRule(ctx, rule.ID)
+	if err != nil {
+		return models.FraudRule{}, err
+	}
+	rule.UpdatedAt = l.clock.Now().UTC()
+	if err := l.rules.SaveRule(ctx, rule); err != nil {
+		return models.FraudRule{}, err
+	}
+	l.recordAudit(ctx, "rule.save", "rule:"+rule.ID, before, rule)
+	return rule, nil
+}
+
+func (l *Ledger) ListRules(ctx context.Context) ([]models.FraudRule, error) {
+	if l.rules == nil {
+		return nil, ErrRulesNotSupported
+	}
+	return l.rules.ListRules(ctx)
+}
+
+func (l *Ledger) DeleteRule(ctx context.Context, id string) error {
+	if l.rules == nil {
+		return ErrRulesNotSupported
+	}
+	before, err := l.rules.GetRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return ErrRuleNotFound
+	}
+	if err := l.rules.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	l.recordAudit(ctx, "rule.delete", "rule:"+id, before, nil)
+	return nil
+}
+
+// EvaluateRules dry-runs the pipeline for a transaction without posting it
+func (l *Ledger) EvaluateRules(ctx context.Context, tx models.Transaction) (models.RuleDecision, error) {
+	if l.rules == nil {
+		return models.RuleDecision{}, ErrRulesNotSupported
+	}
+	return l.evaluateRules(ctx, tx)
+}
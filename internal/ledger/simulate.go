@@ -0,0 +1,65 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ProjectedBalance is an account's balance before and after a simulated posting
+type ProjectedBalance struct {
+	AccountID string          `json:"account_id"`
+	Before    decimal.Decimal `json:"before"`
+	After     decimal.Decimal `json:"after"`
+}
+
+// Simulation is what posting a transaction would do, worked out without storing anything
+type Simulation struct {
+	// Transaction as it would be stored, with fees, FX conversion and period adjustment applied
+	Transaction models.Transaction `json:"transaction"`
+
+	// AlreadyPosted means the idempotency key was used before; posting would change nothing
+	AlreadyPosted bool `json:"already_posted"`
+
+	Entries  []models.LedgerEntry `json:"entries"`
+	Balances []ProjectedBalance   `json:"balances"`
+	Flags    []models.RuleMatch   `json:"flags,omitempty"` // flag rules the posting would trip
+}
+
+// SimulateTransaction runs the same validation, fee, FX, limit and rule checks as
+// PostTransaction and reports the outcome without committing anything. A rejection is
+// returned as the error posting would have failed with.
+func (l *Ledger) SimulateTransaction(ctx context.Context, tx models.Transaction) (Simulation, error) {
+	var sim Simulation
+	simulated, exists, err := l.postTransaction(ctx, tx, &sim)
+	if err != nil {
+		return Simulation{}, err
+	}
+	sim.Transaction = simulated
+	sim.AlreadyPosted = exists
+	return sim, nil
+}
+
+// project fills sim with the entries of the posting and the balances they would lead to.
+// Must be called while holding the locks of every account involved.
+func (l *Ledger) project(sim *Simulation, entries []models.LedgerEntry, flags []models.RuleMatch) error {
+	sim.Entries = entries
+	sim.Flags = flags
+
+	positions := make(map[string]int)
+	for _, entry := range entries {
+		i, seen := positions[entry.AccountID]
+		if !seen {
+			balance, err := l.GetBalance(entry.AccountID)
+			if err != nil {
+				return err
+			}
+			i = len(sim.Balances)
+			positions[entry.AccountID] = i
+			sim.Balances = append(sim.Balances, ProjectedBalance{AccountID: entry.AccountID, Before: balance, After: balance})
+		}
+		sim.Balances[i].After = sim.Balances[i].After.Add(entry.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrSnapshotsNotSupported = errors.New("store does not support balance snapshots")
+
+// getBalanceFromSnapshot starts from the latest checkpoint and only sums the entries after it
+func (l *Ledger) getBalanceFromSnapshot(accountId string) (decimal.Decimal, error) {
+	snapshot, err := l.snapshots.GetLatestSnapshot(accountId)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	balance := decimal.Zero
+	var afterSequence int64
+	if snapshot != nil {
+		balance = snapshot.Balance
+		afterSequence = snapshot.AsOfSequence
+	}
+
+	entries, err := l.snapshots.GetEntriesByAccountAfter(accountId, afterSequence)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, entry := range entries {
+		balance = balance.Add(entry.Amount)
+	}
+	return balance, nil
+}
+
+// GetBalanceAsOf returns the balance of an account at a point in time,
+// starting from the latest snapshot taken before asOf.
+func (l *Ledger) GetBalanceAsOf(accountId string, asOf time.Time) (decimal.Decimal, error) {
+	if l.snapshots == nil {
+		entries, err := l.store.GetEntriesByAccount(accountId)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return sumEntriesUntil(entries, asOf), nil
+	}
+
+	snapshot, err := l.snapshots.GetLatestSnapshotBefore(accountId, asOf)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	balance := decimal.Zero
+	var afterSequence int64
+	if snapshot != nil {
+		balance = snapshot.Balance
+		afterSequence = snapshot.AsOfSequence
+	}
+
+	entries, err := l.snapshots.GetEntriesByAccountAfter(accountId, afterSequence)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balance.Add(sumEntriesUntil(entries, asOf)), nil
+}
+
+func sumEntriesUntil(entries []models.LedgerEntry, asOf time.Time) decimal.Decimal {
+	sum := decimal.Zero
+	for _, entry := range entries {
+		if entry.CreatedAt.After(asOf) {
+			continue
+		}
+		sum = sum.Add(entry.Amount)
+	}
+	return sum
+}
+
+// TakeSnapshots writes a new balance checkpoint for every account that has
+// entries after its latest snapshot. It returns the number of snapshots written.
+func (l *Ledger) TakeSnapshots(ctx context.Context) (int, error) {
+	if l.snapshots == nil {
+		return 0, ErrSnapshotsNotSupported
+	}
+
+	accountIds, err := l.snapshots.GetAccountsWithEntriesAfterSnapshot(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for _, accountId := range accountIds {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		if err := l.snapshotAccount(ctx, accountId); err != nil {
+			l.appLogger.Error("failed to snapshot account balance",
+				"account_id", accountId,
+				"error", err,
+			)
+			continue
+		}
+		written++
+	}
+
+	l.appLogger.Info("balance snapshots taken", "accounts", written)
+	return written, nil
+}
+
+func (l *Ledger) snapshotAccount(ctx context.Context, accountId string) error {
+	// Hold the account lock so no posting can commit an entry with a lower
+	// sequence than the one we checkpoint at.
+	mu := l.getAccountLock(accountId)
+	mu.Lock()
+	defer mu.Unlock()
+
+	snapshot, err := l.snapshots.GetLatestSnapshot(accountId)
+	if err != nil {
+		return err
+	}
+
+	next := models.BalanceSnapshot{
+		AccountID: accountId,
+		Balance:   decimal.Zero,
+		CreatedAt: l.clock.Now(),
+	}
+	if snapshot != nil {
+		next.Balance = snapshot.Balance
+		next.AsOfSequence = snapshot.AsOfSequence
+		next.AsOfTime = snapshot.AsOfTime
+	}
+
+	entries, err := l.snapshots.GetEntriesByAccountAfter(accountId, next.AsOfSequence)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		next.Balance = next.Balance.Add(entry.Amount)
+		next.AsOfSequence = entry.Sequence
+		if entry.CreatedAt.After(next.AsOfTime) {
+			next.AsOfTime = entry.CreatedAt
+		}
+	}
+	return l.snapshots.SaveBalanceSnapshot(ctx, next)
+}
+
+// CompactEntries moves entries older than the retention period into the archive.
+// Only entries already covered by a snapshot are moved, so balances are unaffected.
+func (l *Ledger) CompactEntries(ctx context.Context, retention time.Duration) (int64, error) {
+	if l.snapshots == nil {
+		return 0, ErrSnapshotsNotSupported
+	}
+
+	moved, err := l.snapshots.ArchiveEntriesBefore(ctx, l.clock.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	l.appLogger.Info("ledger entries compacted", "archived", moved)
+	return moved, nil
+}
@@ -0,0 +1,208 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrSuspenseNotSupported = errors.New("store does not support suspense items")
+	ErrSuspenseItemNotFound = errors.New("suspense item not found")
+	ErrSuspenseItemNotOpen  = errors.New("suspense item is already resolved")
+	ErrInvalidSuspenseItem  = errors.New("invalid suspense resolution")
+)
+
+// PostOrSuspend posts a transaction for a flow that cannot hand a failure back to whoever
+// started it. When the receiving account cannot take the credit, the money goes to the
+// suspense account instead and an open item records where it was meant to go. The parked
+// posting keeps the idempotency key, so a retried flow does not move the money twice.
+func (l *Ledger) PostOrSuspend(ctx context.Context, tx models.Transaction, source string) (models.Transaction, *models.SuspenseItem, error) {
+	posted, _, err := l.PostTransactionDetailed(ctx, tx)
+	suspenseAccount := livemode.AccountID(ctx, l.SystemAccount(models.SystemAccountSuspense))
+	if err == nil || l.suspense == nil || tx.ToAccount == suspenseAccount {
+		return posted, nil, err
+	}
+	reason := l.CreditRejected(ctx, tx.ToAccount)
+	if reason == nil {
+		return posted, nil, err
+	}
+
+	parked := tx
+	parked.ToAccount = suspenseAccount
+	parked.Metadata = maps.Clone(tx.Metadata)
+	if parked.Metadata == nil {
+		parked.Metadata = map[string]string{}
+	}
+	parked.Metadata["suspense_intended_account"] = tx.ToAccount
+	posted, exists, err := l.PostTransactionDetailed(ctx, parked)
+	if err != nil {
+		return posted, nil, fmt.Errorf("parking in suspense after %v: %w", reason, err)
+	}
+	if exists {
+		return posted, nil, nil
+	}
+
+	amount := posted.Amount
+	if posted.FX != nil {
+		amount = posted.FX.ConvertedAmount
+	}
+	item := models.SuspenseItem{
+		ID:              uuid.New().String(),
+		TenantID:        posted.TenantID,
+		Source:          source,
+		TransactionID:   posted.ID,
+		FromAccount:     posted.FromAccount,
+		IntendedAccount: tx.ToAccount,
+		Amount:          amount,
+		Reason:          reason.Error(),
+		Status:          models.SuspenseOpen,
+		CreatedAt:       l.clock.Now().UTC(),
+	}
+	if err := l.suspense.SaveSuspenseItem(ctx, item); err != nil {
+		// The money is parked either way; only the pointer to its destination is missing
+		l.appLogger.Error("ALERT: money parked in suspense without a suspense item",
+			"transaction_id", posted.ID,
+			"intended_account", tx.ToAccount,
+			"amount", amount.String(),
+			"error", err,
+		)
+		return posted, nil, err
+	}
+	l.appLogger.Warn("credit failed, money parked in suspense",
+		"suspense_item", item.ID,
+		"source", source,
+		"transaction_id", posted.ID,
+		"intended_account", tx.ToAccount,
+		"reason", item.Reason,
+	)
+	l.recordAudit(ctx, "suspense.park", "suspense:"+item.ID, nil, item)
+	return posted, &item, nil
+}
+
+// CreditRejected reports why an account refuses credits whoever sends them, or nil when it takes them
+func (l *Ledger) CreditRejected(ctx context.Context, id string) error {
+	account, err := l.getAccount(ctx, id)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case account.Status == models.AccountClosed:
+		return fmt.Errorf("%w: %s", ErrAccountClosed, id)
+	case account.Status == models.AccountFrozen && !l.frozenAcceptsCredits:
+		return fmt.Errorf("%w: %s", ErrAccountFrozen, id)
+	}
+	return nil
+}
+
+// GetSuspenseItem returns an item parked for the caller's tenant, or any item for the platform
+func (l *Ledger) GetSuspenseItem(ctx context.Context, id string) (models.SuspenseItem, error) {
+	if l.suspense == nil {
+		return models.SuspenseItem{}, ErrSuspenseNotSupported
+	}
+	item, err := l.suspense.GetSuspenseItem(ctx, id)
+	if err != nil {
+		return models.SuspenseItem{}, err
+	}
+	tenantId := tenant.FromContext(ctx)
+	if item == nil || (tenantId != "" && item.TenantID != tenantId) {
+		return models.SuspenseItem{}, fmt.Errorf("%w: %s", ErrSuspenseItemNotFound, id)
+	}
+	return *item, nil
+}
+
+// ListSuspenseItems lists items with the status, or all of them when it is empty
+func (l *Ledger) ListSuspenseItems(ctx context.Context, status string) ([]models.SuspenseItem, error) {
+	if l.suspense == nil {
+		return nil, ErrSuspenseNotSupported
+	}
+	switch status {
+	case "", models.SuspenseOpen, models.SuspenseResolved:
+	default:
+		return nil, fmt.Errorf("%w: status must be open or resolved", ErrInvalidSuspenseItem)
+	}
+	items, err := l.suspense.ListSuspenseItems(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	tenantId := tenant.FromContext(ctx)
+	if tenantId == "" {
+		return items, nil
+	}
+	visible := []models.SuspenseItem{}
+	for _, item := range items {
+		if item.TenantID == tenantId {
+			visible = append(visible, item)
+		}
+	}
+	return visible, nil
+}
+
+// ResolveSuspenseItem moves parked money to its final destination: the intended account
+// when account is empty, the sender to return it, or anywhere else an operator decides.
+// The release is keyed by the item, so it is posted once however often it is retried.
+func (l *Ledger) ResolveSuspenseItem(ctx context.Context, id, account string) (models.SuspenseItem, error) {
+	before, err := l.GetSuspenseItem(ctx, id)
+	if err != nil {
+		return models.SuspenseItem{}, err
+	}
+	if before.Status != models.SuspenseOpen {
+		return models.SuspenseItem{}, fmt.Errorf("%w: %s", ErrSuspenseItemNotOpen, id)
+	}
+	if account == "" {
+		account = before.IntendedAccount
+	}
+	suspenseAccount := livemode.AccountID(ctx, l.SystemAccount(models.SystemAccountSuspense))
+	if account == suspenseAccount {
+		return models.SuspenseItem{}, fmt.Errorf("%w: cannot resolve into the suspense account itself", ErrInvalidSuspenseItem)
+	}
+
+	now := l.clock.Now().UTC()
+	tx := models.Transaction{
+		ID:             l.NewID(),
+		IdempotencyKey: "suspense-release-" + id,
+		FromAccount:    suspenseAccount,
+		ToAccount:      account,
+		Amount:         before.Amount,
+		CreatedAt:      now,
+		Internal:       true, // returns money already received; fees, limits and rules do not apply
+		Reference:      "suspense-" + id,
+		Description:    "Release of suspense item " + id,
+		Metadata: map[string]string{
+			"type":          "suspense_release",
+			"suspense_item": id,
+			"source":        before.Source,
+		},
+	}
+	posted, exists, err := l.PostTransactionDetailed(ctx, tx)
+	if err != nil {
+		return models.SuspenseItem{}, err
+	}
+	if exists {
+		// Released before, but the item was not marked: record where the money actually went
+		if posted, err = l.findTransaction(ctx, models.TransactionFilter{IdempotencyKey: tx.IdempotencyKey}); err != nil {
+			return models.SuspenseItem{}, err
+		}
+	}
+
+	resolved, err := l.suspense.ResolveSuspenseItem(ctx, id, posted.ToAccount, posted.ID, now)
+	if err != nil {
+		return models.SuspenseItem{}, err
+	}
+	if !resolved {
+		return models.SuspenseItem{}, fmt.Errorf("%w: %s", ErrSuspenseItemNotOpen, id)
+	}
+	after := before
+	after.Status = models.SuspenseResolved
+	after.ResolvedAccount = posted.ToAccount
+	after.ResolutionTransactionID = posted.ID
+	after.ResolvedAt = &now
+	l.recordAudit(ctx, "suspense.resolve", "suspense:"+id, before, after)
+	return after, nil
+}
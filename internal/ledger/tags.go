@@ -0,0 +1,79 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var (
+	ErrTagsNotSupported    = errors.New("store does not support transaction tags")
+	ErrInvalidTag          = errors.New("invalid tag")
+	ErrTooManyTags         = errors.New("too many tags")
+	ErrTransactionNotFound = errors.New("transaction not found")
+)
+
+// maxTags keeps tags a label rather than a second metadata map
+const maxTags = 20
+
+var validTag = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]{0,63}$`)
+
+// normalizeTags validates tags and drops repeats, keeping the order they were given in
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		if !validTag.MatchString(tag) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d", ErrTooManyTags, maxTags)
+	}
+	return normalized, nil
+}
+
+// TagTransaction adds tags to a posted transaction; tags it already has are kept once
+func (l *Ledger) TagTransaction(ctx context.Context, id string, tags []string) (models.Transaction, error) {
+	if _, err := normalizeTags(tags); err != nil {
+		return models.Transaction{}, err
+	}
+	return l.updateTags(ctx, id, "transaction.tag", func(current []string) ([]string, error) {
+		return normalizeTags(append(slices.Clone(current), tags...))
+	})
+}
+
+// UntagTransaction removes a tag from a posted transaction; removing a missing tag is a no-op
+func (l *Ledger) UntagTransaction(ctx context.Context, id, tag string) (models.Transaction, error) {
+	return l.updateTags(ctx, id, "transaction.untag", func(current []string) ([]string, error) {
+		return slices.DeleteFunc(slices.Clone(current), func(t string) bool { return t == tag }), nil
+	})
+}
+
+func (l *Ledger) updateTags(ctx context.Context, id, action string, update func([]string) ([]string, error)) (models.Transaction, error) {
+	store, ok := interfaces.Capability[interfaces.TransactionTagStore](l.store)
+	if !ok {
+		return models.Transaction{}, ErrTagsNotSupported
+	}
+
+	var before []string
+	tx, err := store.UpdateTransactionTags(ctx, id, func(current []string) ([]string, error) {
+		before = current
+		return update(current)
+	})
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	if tx == nil {
+		return models.Transaction{}, fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+	}
+	l.recordAudit(ctx, action, "transaction:"+id, before, tx.Tags)
+	return *tx, nil
+}
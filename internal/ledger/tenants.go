@@ -0,0 +1,111 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrTenantMismatch      = errors.New("account belongs to another tenant")
+	ErrCrossTenantTransfer = errors.New("transfers between tenants are not allowed")
+	ErrLiveAccount         = errors.New("test mode only reaches accounts created in test mode")
+)
+
+// claimAccount returns the owner of an account, assigning it to the tenant when
+// nobody owns it yet (or, in a dry run, reporting that it would). Test mode never
+// claims: it only reaches accounts its tenant created and the test counterparts of
+// the platform's accounts. Must be called while holding the account lock.
+func (l *Ledger) claimAccount(ctx context.Context, id, tenantId string, dryRun bool) (string, error) {
+	account, err := l.getAccount(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if livemode.IsTestTenant(tenantId) {
+		if livemode.IsTestTenant(account.TenantID) || (account.TenantID == "" && livemode.IsTestAccount(id)) {
+			return account.TenantID, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrLiveAccount, id)
+	}
+	// System accounts stay with the platform
+	if account.TenantID != "" || tenantId == "" || l.isSystemAccount(id) {
+		return account.TenantID, nil
+	}
+	if dryRun {
+		return tenantId, nil
+	}
+
+	account.TenantID = tenantId
+	account.UpdatedAt = l.clock.Now().UTC()
+	if err := l.accounts.SaveAccount(ctx, account); err != nil {
+		return "", err
+	}
+	return tenantId, nil
+}
+
+// checkTenant stamps the transaction with the tenant of the request and makes sure the
+// sender belongs to it. It returns the tenant owning the receiver, which differs only
+// when cross-tenant transfers are allowed. Must be called while holding the account locks.
+func (l *Ledger) checkTenant(ctx context.Context, tx *models.Transaction, dryRun bool) (string, error) {
+	tx.TenantID = tenant.FromContext(ctx)
+	if l.accounts == nil {
+		return tx.TenantID, nil
+	}
+
+	sender, err := l.claimAccount(ctx, tx.FromAccount, tx.TenantID, dryRun)
+	if err != nil {
+		return "", err
+	}
+	receiver, err := l.claimAccount(ctx, tx.ToAccount, tx.TenantID, dryRun)
+	if err != nil {
+		return "", err
+	}
+	// Platform-level postings (background jobs, operators) act on any account
+	if tx.TenantID == "" {
+		tx.TenantID = sender
+		return receiver, nil
+	}
+
+	if sender != tx.TenantID {
+		return "", fmt.Errorf("%w: %s", ErrTenantMismatch, tx.FromAccount)
+	}
+	// Paying into a platform account (fees, settlement) is not a cross-tenant transfer
+	if receiver != tx.TenantID && receiver != "" && !l.crossTenant {
+		return "", fmt.Errorf("%w: %s", ErrCrossTenantTransfer, tx.ToAccount)
+	}
+	return receiver, nil
+}
+
+// CheckAccountAccess reports ErrTenantMismatch when the account is owned by a tenant
+// other than the one of the request. Unowned accounts are visible to everyone but test
+// mode, which sees no live account at all.
+func (l *Ledger) CheckAccountAccess(ctx context.Context, id string) error {
+	tenantId := tenant.FromContext(ctx)
+	if tenantId == "" || l.accounts == nil {
+		return nil
+	}
+
+	account, err := l.getAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !accountVisible(tenantId, id, account.TenantID) {
+		return fmt.Errorf("%w: %s", ErrTenantMismatch, id)
+	}
+	return nil
+}
+
+// accountVisible is the ownership rule of CheckAccountAccess for an account owned by owner
+func accountVisible(tenantId, id, owner string) bool {
+	if tenantId == "" {
+		return true
+	}
+	if owner == "" {
+		return !livemode.IsTestTenant(tenantId) || livemode.IsTestAccount(id)
+	}
+	return owner == tenantId
+}
@@ -0,0 +1,25 @@
+package ledger
+
+import "testing"
+
+func TestAccountVisible(t *testing.T) {
+	tests := []struct {
+		tenantId, id, owner string
+		want                bool
+	}{
+		{"", "a", "acme", true},
+		{"acme", "a", "acme", true},
+		{"acme", "a", "globex", false},
+		{"acme", "a", "", true},
+		// Test mode sees neither unowned live accounts nor live tenants' accounts
+		{"test:acme", "a", "", false},
+		{"test:acme", "a", "acme", false},
+		{"test:acme", "a", "test:acme", true},
+		{"test:acme", "test:fee-revenue", "", true},
+	}
+	for _, tt := range tests {
+		if got := accountVisible(tt.tenantId, tt.id, tt.owner); got != tt.want {
+			t.Errorf("accountVisible(%q, %q, %q) = %v, want %v", tt.tenantId, tt.id, tt.owner, got, tt.want)
+		}
+	}
+}
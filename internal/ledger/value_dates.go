@@ -0,0 +1,74 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/calendar"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// SetCalendar rolls value dates that are not business days by the convention. Without a
+// calendar every day is a business day, in UTC.
+func (l *Ledger) SetCalendar(businessDays *calendar.Calendar, convention calendar.Convention) {
+	l.calendar = businessDays
+	l.valueDateConvention = convention
+}
+
+func (l *Ledger) ValueDateConvention() calendar.Convention {
+	return l.valueDateConvention
+}
+
+// assignValueDate fills in the value date: the booking date unless the caller chose one,
+// rolled onto a business day of every currency the transfer settles in. Value dates of
+// the ledger's own postings are kept as they are: an interest accrual is valued on the
+// day it covers, weekend or not.
+func (l *Ledger) assignValueDate(ctx context.Context, tx *models.Transaction) error {
+	if tx.Internal && tx.ValueDate != "" {
+		return nil
+	}
+	date := tx.CreatedAt.UTC().Truncate(24 * time.Hour)
+	if l.calendar != nil {
+		date = l.calendar.Date(tx.CreatedAt)
+	}
+	if tx.ValueDate != "" {
+		requested, err := time.Parse(calendar.DateLayout, tx.ValueDate)
+		if err != nil {
+			return err // already refused by validation
+		}
+		date = requested
+	}
+
+	if l.calendar != nil {
+		var currencies []string
+		if tx.FX != nil {
+			currencies = []string{tx.FX.FromCurrency, tx.FX.ToCurrency}
+		} else {
+			code, err := l.AccountCurrency(ctx, tx.FromAccount)
+			if err != nil {
+				return err
+			}
+			currencies = []string{code}
+		}
+		date = l.calendar.Roll(date, l.valueDateConvention, currencies...)
+	}
+	tx.ValueDate = date.Format(calendar.DateLayout)
+	return nil
+}
+
+// GetValueDatedBalance returns the balance of an account at the end of a value date: what
+// was booked by the end of that day, less what is valued later, plus what was booked later
+// but valued on or before it. Without value-dating support in the store it is the booked balance.
+func (l *Ledger) GetValueDatedBalance(ctx context.Context, accountId string, date time.Time) (decimal.Decimal, error) {
+	bookedBy := date.AddDate(0, 0, 1).Add(-time.Microsecond)
+	balance, err := l.GetBalanceAsOf(accountId, bookedBy)
+	if err != nil || l.valueDates == nil {
+		return balance, err
+	}
+	adjustment, err := l.valueDates.ValueDateAdjustment(ctx, accountId, date.Format(calendar.DateLayout), bookedBy)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balance.Add(adjustment), nil
+}
@@ -0,0 +1,79 @@
+// Package livemode separates test-mode traffic from live data. A test API key scopes the
+// request to the test counterpart of its tenant, so everything it creates - accounts,
+// transactions, entries, balances - sits behind the same isolation that keeps tenants
+// apart, and never shows up in live listings or balances.
+package livemode
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+// KeyHeader carries the API key of a request
+const KeyHeader = "X-API-Key"
+
+// Key prefixes; a request without a key is live
+const (
+	TestKeyPrefix = "sk_test_"
+	LiveKeyPrefix = "sk_live_"
+)
+
+// prefix marks test tenants and test accounts. Tenant IDs from the header cannot contain
+// ':', so a live tenant can never be mistaken for a test one.
+const prefix = "test:"
+
+// TenantID is the test counterpart of a tenant; the platform itself ("") becomes "test:"
+func TenantID(tenantId string) string {
+	return prefix + tenantId
+}
+
+// IsTest reports whether ctx runs in test mode. It follows from the tenant, so background
+// work done on behalf of a test tenant, such as a standing order, stays in test mode.
+func IsTest(ctx context.Context) bool {
+	return IsTestTenant(tenant.FromContext(ctx))
+}
+
+// IsTestTenant reports whether tenantId is the test counterpart of a tenant. Reads made
+// without a tenant leave such tenants' data out, so it never shows up as live.
+func IsTestTenant(tenantId string) bool {
+	return strings.HasPrefix(tenantId, prefix)
+}
+
+// IsTestAccount reports whether id is the test counterpart of a platform account
+func IsTestAccount(id string) bool {
+	return strings.HasPrefix(id, prefix)
+}
+
+// TenantPattern is a SQL LIKE pattern matching every test tenant
+const TenantPattern = prefix + "%"
+
+// AccountID is the test counterpart of one of the platform's own accounts, such as fee
+// revenue or an FX position, so test postings never move live platform balances
+func AccountID(ctx context.Context, id string) string {
+	if IsTest(ctx) {
+		return prefix + id
+	}
+	return id
+}
+
+// Middleware moves requests made with a test key into test mode and answers every request
+// with a Livemode header. It must run inside tenant.Middleware, which resolves the tenant.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(KeyHeader)
+		switch {
+		case strings.HasPrefix(key, TestKeyPrefix):
+			w.Header().Set("Livemode", "false")
+			r = r.WithContext(tenant.WithTenant(r.Context(), TenantID(tenant.FromContext(r.Context()))))
+		case key == "" || strings.HasPrefix(key, LiveKeyPrefix):
+			w.Header().Set("Livemode", "true")
+		default:
+			http.Error(w, "unrecognised "+KeyHeader+", want a "+TestKeyPrefix+" or "+LiveKeyPrefix+" key", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,86 @@
+// Package merkle builds Merkle trees as RFC 6962 (Certificate Transparency) defines them,
+// so proofs can be checked with any implementation of that scheme. Leaves and interior
+// nodes are hashed with different prefixes, so a leaf can never pass for a subtree.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// LeafHash hashes the data of one leaf
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// split is the largest power of two below n, where the tree of n leaves divides
+func split(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// Root computes the tree head over leaf hashes made by LeafHash
+func Root(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	case 1:
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return nodeHash(Root(leaves[:k]), Root(leaves[k:]))
+}
+
+// Proof returns the audit path of the leaf at index: the sibling hashes from the leaf up to the root
+func Proof(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return [][]byte{}
+	}
+	k := split(len(leaves))
+	if index < k {
+		return append(Proof(leaves[:k], index), Root(leaves[k:]))
+	}
+	return append(Proof(leaves[k:], index-k), Root(leaves[:k]))
+}
+
+// Verify checks that leaf sits at index of the tree of size leaves with the given root
+// (RFC 9162, section 2.1.3.2)
+func Verify(leaf []byte, index, size int, proof [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	hash := leaf
+	for _, sibling := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			hash = nodeHash(sibling, hash)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(hash, root)
+}
@@ -0,0 +1,130 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// The leaves and roots of the RFC 6962 test vectors used by Certificate Transparency
+// implementations; the root at index n-1 covers the first n leaves
+var (
+	ctLeaves = []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+	ctRoots  = []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+)
+
+func ctLeafHashes(t *testing.T) [][]byte {
+	t.Helper()
+	hashes := make([][]byte, len(ctLeaves))
+	for i, leaf := range ctLeaves {
+		data, err := hex.DecodeString(leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes[i] = LeafHash(data)
+	}
+	return hashes
+}
+
+func TestRootMatchesRFC6962Vectors(t *testing.T) {
+	if got := hex.EncodeToString(Root(nil)); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("empty root = %s, want SHA-256 of nothing", got)
+	}
+	hashes := ctLeafHashes(t)
+	for n := 1; n <= len(hashes); n++ {
+		if got := hex.EncodeToString(Root(hashes[:n])); got != ctRoots[n-1] {
+			t.Errorf("root of %d leaves = %s, want %s", n, got, ctRoots[n-1])
+		}
+	}
+}
+
+func TestProofsVerifyAgainstHandComputedRoot(t *testing.T) {
+	sum := func(parts ...[]byte) []byte {
+		h := sha256.Sum256(bytes.Join(parts, nil))
+		return h[:]
+	}
+	leaf := func(data string) []byte { return sum([]byte{0}, []byte(data)) }
+	node := func(left, right []byte) []byte { return sum([]byte{1}, left, right) }
+
+	// Five leaves split into the four on the left and the fifth on the right:
+	//
+	//            root
+	//           /    \
+	//        n0123    l4
+	//        /   \
+	//     n01     n23
+	//    /  \    /  \
+	//   l0  l1  l2  l3
+	l := [][]byte{leaf("e-1"), leaf("e-2"), leaf("e-3"), leaf("e-4"), leaf("e-5")}
+	n01, n23 := node(l[0], l[1]), node(l[2], l[3])
+	n0123 := node(n01, n23)
+	root := node(n0123, l[4])
+
+	if got := Root(l); !bytes.Equal(got, root) {
+		t.Fatalf("root = %x, want %x", got, root)
+	}
+
+	paths := [][][]byte{
+		{l[1], n23, l[4]},
+		{l[0], n23, l[4]},
+		{l[3], n01, l[4]},
+		{l[2], n01, l[4]},
+		{n0123},
+	}
+	for i, want := range paths {
+		proof := Proof(l, i)
+		if len(proof) != len(want) {
+			t.Fatalf("proof of leaf %d has %d hashes, want %d", i, len(proof), len(want))
+		}
+		for j := range want {
+			if !bytes.Equal(proof[j], want[j]) {
+				t.Errorf("proof of leaf %d, hash %d = %x, want %x", i, j, proof[j], want[j])
+			}
+		}
+		if !Verify(l[i], i, len(l), proof, root) {
+			t.Errorf("proof of leaf %d does not verify", i)
+		}
+	}
+
+	proof := Proof(l, 2)
+	tampered := [][]byte{l[3], n23, l[4]}
+	for name, ok := range map[string]bool{
+		"wrong leaf":       Verify(l[3], 2, 5, proof, root),
+		"wrong index":      Verify(l[2], 3, 5, proof, root),
+		"wrong size":       Verify(l[2], 2, 4, proof, root),
+		"index past size":  Verify(l[2], 5, 5, proof, root),
+		"tampered sibling": Verify(l[2], 2, 5, tampered, root),
+		"short proof":      Verify(l[2], 2, 5, proof[:2], root),
+		"long proof":       Verify(l[2], 2, 5, append(proof, l[0]), root),
+		"wrong root":       Verify(l[2], 2, 5, proof, n0123),
+	} {
+		if ok {
+			t.Errorf("%s: proof verified", name)
+		}
+	}
+}
+
+func TestProofsVerifyForEveryTreeSize(t *testing.T) {
+	hashes := ctLeafHashes(t)
+	for n := 1; n <= len(hashes); n++ {
+		root, err := hex.DecodeString(ctRoots[n-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range n {
+			if !Verify(hashes[i], i, n, Proof(hashes[:n], i), root) {
+				t.Errorf("leaf %d of %d does not verify", i, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A tiny in-process metrics registry that renders the Prometheus text format.
+// It only covers what the ledger needs: counters, gauges and histograms,
+// optionally split by a fixed set of labels.
+
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]collector{}
+)
+
+func register(name string, c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic("metrics: duplicate metric " + name)
+	}
+	registry[name] = c
+}
+
+// Handler serves every registered metric in the Prometheus text exposition format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		registryMu.Lock()
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		collectors := make([]collector, 0, len(names))
+		sort.Strings(names)
+		for _, name := range names {
+			collectors = append(collectors, registry[name])
+		}
+		registryMu.Unlock()
+
+		for _, c := range collectors {
+			c.write(w)
+		}
+	})
+}
+
+// value is a float64 that can be updated atomically
+type value struct {
+	bits atomic.Uint64
+}
+
+func (v *value) add(delta float64) {
+	for {
+		old := v.bits.Load()
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if v.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+func (v *value) set(f float64) { v.bits.Store(math.Float64bits(f)) }
+func (v *value) get() float64  { return math.Float64frombits(v.bits.Load()) }
+
+// family holds one child per distinct combination of label values
+type family[T any] struct {
+	name     string
+	help     string
+	kind     string
+	labels   []string
+	mu       sync.Mutex
+	children map[string]*T
+	order    []string
+	newChild func() *T
+	writeOne func(w io.Writer, name string, labels string, child *T)
+}
+
+func (f *family[T]) with(values ...string) *T {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	child, exists := f.children[key]
+	if !exists {
+		child = f.newChild()
+		f.children[key] = child
+		f.order = append(f.order, key)
+	}
+	return child
+}
+
+func (f *family[T]) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range f.order {
+		f.writeOne(w, f.name, f.labelString(key), f.children[key])
+	}
+}
+
+func (f *family[T]) labelString(key string) string {
+	if len(f.labels) == 0 {
+		return ""
+	}
+	values := strings.Split(key, "\xff")
+	pairs := make([]string, len(f.labels))
+	for i, label := range f.labels {
+		pairs[i] = fmt.Sprintf("%s=%q", label, values[i])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+// Counter only ever goes up
+type Counter struct{ v value }
+
+func (c *Counter) Inc()              { c.v.add(1) }
+func (c *Counter) Add(delta float64) { c.v.add(delta) }
+func (c *Counter) Value() float64    { return c.v.get() }
+
+type CounterVec struct{ f *family[Counter] }
+
+func (c *CounterVec) With(labelValues ...string) *Counter { return c.f.with(labelValues...) }
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	f := &family[Counter]{
+		name: name, help: help, kind: "counter", labels: labels,
+		children: map[string]*Counter{},
+		newChild: func() *Counter { return &Counter{} },
+		writeOne: func(w io.Writer, name, labels string, c *Counter) {
+			fmt.Fprintf(w, "%s%s %g\n", name, braces(labels), c.Value())
+		},
+	}
+	register(name, f)
+	return &CounterVec{f: f}
+}
+
+func NewCounter(name, help string) *Counter {
+	return NewCounterVec(name, help).With()
+}
+
+// Gauge can go up and down
+type Gauge struct{ v value }
+
+func (g *Gauge) Set(f float64)     { g.v.set(f) }
+func (g *Gauge) Add(delta float64) { g.v.add(delta) }
+func (g *Gauge) Inc()              { g.v.add(1) }
+func (g *Gauge) Dec()              { g.v.add(-1) }
+func (g *Gauge) Value() float64    { return g.v.get() }
+
+type GaugeVec struct{ f *family[Gauge] }
+
+func (g *GaugeVec) With(labelValues ...string) *Gauge { return g.f.with(labelValues...) }
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	f := &family[Gauge]{
+		name: name, help: help, kind: "gauge", labels: labels,
+		children: map[string]*Gauge{},
+		newChild: func() *Gauge { return &Gauge{} },
+		writeOne: func(w io.Writer, name, labels string, g *Gauge) {
+			fmt.Fprintf(w, "%s%s %g\n", name, braces(labels), g.Value())
+		},
+	}
+	register(name, f)
+	return &GaugeVec{f: f}
+}
+
+func NewGauge(name, help string) *Gauge {
+	return NewGaugeVec(name, help).With()
+}
+
+// DefaultBuckets suit latencies in seconds, from a millisecond to ten seconds
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct {
+	bounds []float64 // upper bounds, ascending
+	counts []atomic.Uint64
+	sum    value
+	count  atomic.Uint64
+}
+
+func (h *Histogram) Observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i].Add(1)
+			break
+		}
+	}
+	h.sum.add(v)
+	h.count.Add(1)
+}
+
+type HistogramVec struct{ f *family[Histogram] }
+
+func (h *HistogramVec) With(labelValues ...string) *Histogram { return h.f.with(labelValues...) }
+
+// NewHistogramVec registers a histogram with the given bucket upper bounds; nil uses DefaultBuckets
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	f := &family[Histogram]{
+		name: name, help: help, kind: "histogram", labels: labels,
+		children: map[string]*Histogram{},
+		newChild: func() *Histogram { return &Histogram{bounds: bounds, counts: make([]atomic.Uint64, len(bounds))} },
+		writeOne: func(w io.Writer, name, labels string, h *Histogram) {
+			prefix := labels
+			if prefix != "" {
+				prefix += ","
+			}
+			var cumulative uint64
+			for i, bound := range h.bounds {
+				cumulative += h.counts[i].Load()
+				fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, prefix, bound, cumulative)
+			}
+			// An observation may land between the reads; buckets must never exceed the count
+			count := max(h.count.Load(), cumulative)
+			fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, count)
+			fmt.Fprintf(w, "%s_sum%s %g\n", name, braces(labels), h.sum.get())
+			fmt.Fprintf(w, "%s_count%s %d\n", name, braces(labels), count)
+		},
+	}
+	register(name, f)
+	return &HistogramVec{f: f}
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return NewHistogramVec(name, help, buckets).With()
+}
+
+// sampled reads its value from a callback at scrape time, for numbers kept by someone
+// else (such as database/sql pool statistics)
+type sampled struct {
+	name string
+	help string
+	kind string
+	read func() float64
+}
+
+func (s *sampled) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", s.name, s.help, s.name, s.kind, s.name, s.read())
+}
+
+// NewGaugeFunc registers a gauge whose value is read on every scrape
+func NewGaugeFunc(name, help string, read func() float64) {
+	register(name, &sampled{name: name, help: help, kind: "gauge", read: read})
+}
+
+// NewCounterFunc registers a counter whose value is read on every scrape; read must never go down
+func NewCounterFunc(name, help string, read func() float64) {
+	register(name, &sampled{name: name, help: help, kind: "counter", read: read})
+}
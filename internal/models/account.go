@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen"
+	AccountClosed = "closed" // terminal: no posting is ever accepted again
+)
+
+// Account holds the controls of an account. Balances are never stored here;
+// they are always derived from ledger entries. An account without a row is active.
+type Account struct {
+	ID           string `json:"id"`
+	TenantID     string `json:"tenant_id,omitempty"` // owner; empty on single-tenant deployments
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason,omitempty"`
+
+	// Type is the product the account belongs to (e.g. "wallet", "merchant"); fee schedules match on it
+	Type string `json:"type,omitempty"`
+
+	// Class places the account in the chart of accounts (asset, liability, equity, revenue, expense)
+	Class string `json:"class,omitempty"`
+
+	// Currency of the account; empty means the ledger's base currency
+	Currency string `json:"currency,omitempty"`
+
+	// OverdraftLimit is how far below zero the balance may go; zero means no overdraft
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+
+	// ParentID places the account under another one (e.g. a sub-merchant wallet under its merchant)
+	ParentID string `json:"parent_id,omitempty"`
+
+	// Holder details are personal data: the store encrypts them and audit records leave them out
+	HolderName  string `json:"holder_name,omitempty"`
+	HolderEmail string `json:"holder_email,omitempty"`
+
+	// LimitProfileID selects the velocity limits applied to outgoing payments; empty means none
+	LimitProfileID string `json:"limit_profile_id,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"` // set when the holder's personal data was erased
+}
+
+// AccountErasure records what an erasure request removed; ledger entries are never touched
+type AccountErasure struct {
+	AccountID      string    `json:"account_id"`
+	HolderErased   bool      `json:"holder_erased"`
+	AliasesRemoved int       `json:"aliases_removed"`
+	Reason         string    `json:"reason,omitempty"`
+	ErasedAt       time.Time `json:"erased_at"`
+}
+
+// Redacted masks the holder details, for copies kept outside the accounts table
+func (a Account) Redacted() Account {
+	if a.HolderName != "" {
+		a.HolderName = "[redacted]"
+	}
+	if a.HolderEmail != "" {
+		a.HolderEmail = "[redacted]"
+	}
+	return a
+}
+
+// AccountSorts are the orders accounts can be listed in; a leading "-" means descending
+var AccountSorts = []string{"id", "-id", "created_at", "-created_at", "updated_at", "-updated_at"}
+
+// AccountFilter narrows an account listing; zero values are ignored. Only accounts with a
+// row are listed, and holder details are sealed, so Query never matches them.
+type AccountFilter struct {
+	Type     string
+	Status   string
+	Currency string
+	Query    string         // an ID prefix, or an alias of the account
+	Sort     string         // one of AccountSorts; empty means "id"
+	After    *AccountCursor // the last account of the previous page
+	Limit    int
+}
+
+// AccountCursor marks a position in an account listing. It carries the sort it was made
+// for, since a position in one order means nothing in another.
+type AccountCursor struct {
+	Sort string
+	At   time.Time // created_at or updated_at of the last account; zero when sorting by ID
+	ID   string
+}
+
+// String encodes the cursor for use in a URL
+func (c AccountCursor) String() string {
+	at := ""
+	if !c.At.IsZero() {
+		at = c.At.UTC().Format(time.RFC3339Nano)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Sort + "|" + at + "|" + c.ID))
+}
+
+// ParseAccountCursor decodes a cursor made by AccountCursor.String
+func ParseAccountCursor(s string) (AccountCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return AccountCursor{}, ErrInvalidCursor
+	}
+	sort, rest, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return AccountCursor{}, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(rest, "|")
+	if !ok || id == "" || !slices.Contains(AccountSorts, sort) {
+		return AccountCursor{}, ErrInvalidCursor
+	}
+	cursor := AccountCursor{Sort: sort, ID: id}
+	if strings.TrimPrefix(sort, "-") != "id" {
+		if cursor.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+			return AccountCursor{}, ErrInvalidCursor
+		}
+	}
+	return cursor, nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Alias types
+const (
+	AliasIBAN      = "iban"
+	AliasCardToken = "card_token"
+	AliasPartner   = "partner" // an integrating system's own account ID
+)
+
+// AccountAlias maps an identifier used outside the ledger onto an internal account,
+// so integrating systems never need to know internal account IDs
+type AccountAlias struct {
+	Alias     string    `json:"alias"`
+	Type      string    `json:"type"`
+	AccountID string    `json:"account_id"`
+	TenantID  string    `json:"tenant_id,omitempty"` // aliases are unique per tenant
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Redacted masks the alias itself, which may identify a person (an IBAN, a card token)
+func (a AccountAlias) Redacted() AccountAlias {
+	a.Alias = "[redacted]"
+	return a
+}
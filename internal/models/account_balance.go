@@ -0,0 +1,32 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// AccountBalance is the balance of a single account at its position in the hierarchy
+type AccountBalance struct {
+	AccountID string          `json:"account_id"`
+	ParentID  string          `json:"parent_id,omitempty"`
+	Depth     int             `json:"depth"` // 0 for the account the roll-up starts from
+	Balance   decimal.Decimal `json:"balance"`
+}
+
+// RollupBalance aggregates the balances of an account and all of its descendants
+type RollupBalance struct {
+	AccountID string           `json:"account_id"`
+	Balance   decimal.Decimal  `json:"balance"`
+	Accounts  []AccountBalance `json:"accounts"`
+}
+
+// BatchBalance is one account's balance in a batch lookup
+type BatchBalance struct {
+	AccountID string          `json:"account_id"`
+	TenantID  string          `json:"-"`
+	Currency  string          `json:"currency"` // empty from the store means the base currency
+	Balance   decimal.Decimal `json:"balance"`
+}
+
+// BalanceBatch answers a batch lookup; accounts of other tenants are listed as not found
+type BalanceBatch struct {
+	Balances []BatchBalance `json:"balances"`
+	NotFound []string       `json:"not_found,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	PeriodOpen   = "open"
+	PeriodClosed = "closed"
+)
+
+// AccountingPeriod is a calendar month of the ledger, identified as "2006-01".
+// Once closed, no entry dated inside it can be posted.
+type AccountingPeriod struct {
+	ID       string     `json:"id"`
+	Start    time.Time  `json:"start"` // inclusive
+	End      time.Time  `json:"end"`   // exclusive
+	Status   string     `json:"status"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// Contains reports whether t falls inside the period
+func (p AccountingPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// AnalyticsExport records one UTC day written to object storage as Parquet
+type AnalyticsExport struct {
+	Date            time.Time `json:"date"`
+	Entries         int       `json:"entries"`
+	Transactions    int       `json:"transactions"`
+	EntriesKey      string    `json:"entries_key"`
+	TransactionsKey string    `json:"transactions_key"`
+	ExportedAt      time.Time `json:"exported_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditRecord is one append-only row of the audit log.
+// Before/After hold JSON snapshots of the affected resource when known.
+type AuditRecord struct {
+	ID        int64           `json:"id"`
+	RequestID string          `json:"request_id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`   // e.g. "POST /periods/{id}/close" or "period.close"
+	Resource  string          `json:"resource"` // e.g. "/periods/2024-06/close" or "period:2024-06"
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Status    int             `json:"status,omitempty"` // HTTP status for API calls
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows an audit query; zero values are ignored
+type AuditFilter struct {
+	RequestID string
+	Actor     string
+	Action    string
+	Resource  string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
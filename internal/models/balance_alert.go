@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Balance alert types
+const (
+	AlertTypeBalanceBelow = "balance_below" // a posting took the balance from Threshold or above to below it
+	AlertTypeDebitAbove   = "debit_above"   // one posting debited the account more than Threshold
+)
+
+// BalanceAlert is a threshold registered on an account, checked after every posting to it.
+// When it is crossed an accounts.threshold_crossed event is published, and POSTed to
+// WebhookURL when one is set.
+type BalanceAlert struct {
+	ID         string          `json:"id"`
+	AccountID  string          `json:"account_id"`
+	Type       string          `json:"type"`
+	Threshold  decimal.Decimal `json:"threshold"`
+	WebhookURL string          `json:"webhook_url,omitempty"`
+	Enabled    bool            `json:"enabled"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
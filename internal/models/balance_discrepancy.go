@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceDiscrepancy is an account whose materialized balance disagreed with the sum of its
+// entries. It stays open, counting every check that still finds it, until a check agrees again.
+type BalanceDiscrepancy struct {
+	ID              int64           `json:"id"`
+	AccountID       string          `json:"account_id"`
+	Materialized    decimal.Decimal `json:"materialized"`
+	Recomputed      decimal.Decimal `json:"recomputed"`
+	Difference      decimal.Decimal `json:"difference"` // materialized minus recomputed
+	Occurrences     int             `json:"occurrences"`
+	FirstDetectedAt time.Time       `json:"first_detected_at"`
+	LastDetectedAt  time.Time       `json:"last_detected_at"`
+	ResolvedAt      *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// BalanceCheck is the outcome of one run of the balance checker
+type BalanceCheck struct {
+	Checked       int                  `json:"checked"`
+	Discrepancies []BalanceDiscrepancy `json:"discrepancies"`
+	Resolved      int                  `json:"resolved"` // open discrepancies the run found fixed
+	CheckedAt     time.Time            `json:"checked_at"`
+}
+
+// BalanceRepair is the outcome of rebuilding one materialized balance from its entries
+type BalanceRepair struct {
+	AccountID    string          `json:"account_id"`
+	Materialized decimal.Decimal `json:"materialized"` // before the repair
+	Recomputed   decimal.Decimal `json:"recomputed"`
+	Delta        decimal.Decimal `json:"delta"` // recomputed minus materialized: what the repair added
+	Repaired     bool            `json:"repaired"`
+	RepairedAt   time.Time       `json:"repaired_at"`
+}
+
+// DiscrepancyFilter narrows a discrepancy listing; Open nil lists both open and resolved
+type DiscrepancyFilter struct {
+	AccountID string
+	Open      *bool
+	Limit     int
+}
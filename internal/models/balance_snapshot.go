@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceSnapshot is a checkpoint of an account balance.
+// Balance covers every entry of the account up to and including AsOfSequence,
+// so only entries after it need to be summed to get the current balance.
+type BalanceSnapshot struct {
+	AccountID    string
+	Balance      decimal.Decimal
+	AsOfSequence int64     // sequence of the last entry included in Balance
+	AsOfTime     time.Time // created_at of the last entry included in Balance
+	CreatedAt    time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// BalanceUpdate is pushed to live subscribers after an entry is committed
+type BalanceUpdate struct {
+	AccountID string          `json:"account_id"`
+	Entry     LedgerEntry     `json:"entry"`
+	Balance   decimal.Decimal `json:"balance"` // balance of the account right after this entry
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BusinessDay is a closed day of the ledger, running from the previous cutoff up to EndsAt
+type BusinessDay struct {
+	Date             string          `json:"date"` // YYYY-MM-DD in the cutoff's time zone
+	StartsAt         time.Time       `json:"starts_at"`
+	EndsAt           time.Time       `json:"ends_at"`
+	TransactionCount int64           `json:"transaction_count"`
+	Volume           decimal.Decimal `json:"volume"` // sum of transaction amounts
+	Accounts         int             `json:"accounts"`
+	ClosedAt         time.Time       `json:"closed_at"`
+}
+
+// DailyAccountSummary is the movement of one account during a business day
+type DailyAccountSummary struct {
+	Date      string          `json:"date"`
+	AccountID string          `json:"account_id"`
+	Debits    decimal.Decimal `json:"debits"` // as a positive number
+	Credits   decimal.Decimal `json:"credits"`
+	Net       decimal.Decimal `json:"net"`
+	Entries   int64           `json:"entries"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// Account classes of the chart of accounts
+const (
+	ClassAsset     = "asset"
+	ClassLiability = "liability"
+	ClassEquity    = "equity"
+	ClassRevenue   = "revenue"
+	ClassExpense   = "expense"
+)
+
+// Normal balance sides. Entries store credits as positive and debits as negative amounts,
+// so a credit-normal account carries a positive balance and a debit-normal one a negative balance.
+const (
+	NormalDebit  = "debit"
+	NormalCredit = "credit"
+)
+
+// Roles of the internal accounts the ledger posts to on its own
+const (
+	SystemAccountFees       = "fees"
+	SystemAccountSuspense   = "suspense"
+	SystemAccountSettlement = "settlement"
+)
+
+// NormalBalance returns the side an account of the class normally carries its balance on,
+// or "" when the class is unknown
+func NormalBalance(class string) string {
+	switch class {
+	case ClassAsset, ClassExpense:
+		return NormalDebit
+	case ClassLiability, ClassEquity, ClassRevenue:
+		return NormalCredit
+	}
+	return ""
+}
+
+// IsNormalBalance reports whether balance sits on the normal side of the class. Zero always does.
+func IsNormalBalance(class string, balance decimal.Decimal) bool {
+	switch NormalBalance(class) {
+	case NormalDebit:
+		return !balance.IsPositive()
+	case NormalCredit:
+		return !balance.IsNegative()
+	}
+	return true
+}
+
+// SystemAccount is an internal account the ledger itself posts to
+type SystemAccount struct {
+	Role      string `json:"role"`
+	AccountID string `json:"account_id"`
+	Class     string `json:"class"`
+}
+
+// ClassSummary is the trial balance of one account class
+type ClassSummary struct {
+	Class         string             `json:"class"` // "" groups accounts not yet classified
+	NormalBalance string             `json:"normal_balance,omitempty"`
+	Debits        decimal.Decimal    `json:"debits"`
+	Credits       decimal.Decimal    `json:"credits"`
+	Balance       decimal.Decimal    `json:"balance"`
+	Accounts      []TrialBalanceLine `json:"accounts"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Checkpoint is a signed Merkle root over the entries with FromSeq <= seq <= ToSeq, in seq
+// order. Each one names the root before it, so the checkpoints chain like the entries do.
+type Checkpoint struct {
+	ID        int64     `json:"id"`
+	FromSeq   int64     `json:"from_seq"`
+	ToSeq     int64     `json:"to_seq"`
+	Size      int       `json:"size"`      // entries covered; sequence gaps leave it below the range
+	Root      string    `json:"root"`      // hex; leaves are SHA-256(0x00 || entry hash as hex text)
+	PrevRoot  string    `json:"prev_root"` // root of the previous checkpoint; empty for the first
+	KeyID     string    `json:"key_id"`
+	Signature string    `json:"signature"` // base64 Ed25519 signature over SignedPayload
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SignedPayload is the exact text the signature covers
+func (c Checkpoint) SignedPayload() string {
+	return fmt.Sprintf("ledger-checkpoint/v1\n%d\n%d\n%d\n%s\n%s\n%s",
+		c.FromSeq, c.ToSeq, c.Size, c.Root, c.PrevRoot, c.CreatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// InclusionProof shows that an entry is covered by a signed checkpoint. To verify it, hash
+// the entry into its chain hash, check the audit path leads from its leaf to the root
+// (RFC 9162, section 2.1.3.2), and check the signature over the signed payload.
+type InclusionProof struct {
+	Entry         LedgerEntry `json:"entry"`
+	LeafIndex     int         `json:"leaf_index"`
+	TreeSize      int         `json:"tree_size"`
+	LeafHash      string      `json:"leaf_hash"`
+	AuditPath     []string    `json:"audit_path"` // hex, from the leaf upwards
+	Checkpoint    Checkpoint  `json:"checkpoint"`
+	SignedPayload string      `json:"signed_payload"`
+	PublicKey     string      `json:"public_key"` // base64 Ed25519 key the checkpoint was signed with
+}
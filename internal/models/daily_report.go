@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DailyTransaction is one of the largest transactions of a day
+type DailyTransaction struct {
+	TransactionID string          `json:"transaction_id"`
+	FromAccount   string          `json:"from_account"`
+	ToAccount     string          `json:"to_account"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
+// DailyReport is read from the daily aggregates projection, never from raw entries
+type DailyReport struct {
+	Date                string                `json:"date"`
+	TransactionCount    int64                 `json:"transaction_count"`
+	Volume              decimal.Decimal       `json:"volume"`
+	LargestTransactions []DailyTransaction    `json:"largest_transactions"`
+	Accounts            []DailyAccountSummary `json:"accounts"`
+	GeneratedAt         time.Time             `json:"generated_at"`
+}
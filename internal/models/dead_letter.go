@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeadLetterPending = "pending" // not redriven yet
+	DeadLetterFailed  = "failed"  // at least one redrive failed
+)
+
+// DeadLetter is an event that could not be published to the broker and waits to be redriven
+type DeadLetter struct {
+	ID        int64           `json:"id"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+}
+
+// DeadLetterFilter narrows a listing; an empty Status lists every letter
+type DeadLetterFilter struct {
+	Status string
+	Limit  int
+}
+
+// DeadLetterStats sizes the backlog; OldestAt is nil when it is empty
+type DeadLetterStats struct {
+	Backlog  int        `json:"backlog"`
+	Failed   int        `json:"failed"`
+	OldestAt *time.Time `json:"oldest_at,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "strings"
+
+const (
+	DirectionDebit  = "debit"
+	DirectionCredit = "credit"
+)
+
+// DescribedEntry is a ledger entry with what a reader of a statement looks for next to it,
+// derived from its transaction when it is read rather than stored with the entry
+type DescribedEntry struct {
+	LedgerEntry
+	Counterparty string // the other side of the transaction, as seen from the entry's account
+	Direction    string // debit or credit
+	Narrative    string // the transaction's description and reference, or a generated line
+}
+
+// DescribeEntry derives the display fields of an entry. Without its transaction only the
+// direction is known.
+func DescribeEntry(entry LedgerEntry, tx *Transaction) DescribedEntry {
+	described := DescribedEntry{LedgerEntry: entry, Direction: DirectionCredit}
+	if entry.Amount.IsNegative() {
+		described.Direction = DirectionDebit
+	}
+	if tx == nil {
+		return described
+	}
+
+	// Fee, FX and other system legs face whichever party the money came from or went to
+	switch {
+	case entry.AccountID == tx.FromAccount:
+		described.Counterparty = tx.ToAccount
+	case entry.AccountID == tx.ToAccount:
+		described.Counterparty = tx.FromAccount
+	case described.Direction == DirectionCredit:
+		described.Counterparty = tx.FromAccount
+	default:
+		described.Counterparty = tx.ToAccount
+	}
+
+	var parts []string
+	if description := strings.TrimSpace(tx.Description); description != "" {
+		parts = append(parts, description)
+	}
+	if reference := strings.TrimSpace(tx.Reference); reference != "" {
+		parts = append(parts, "Ref "+reference)
+	}
+	switch {
+	case len(parts) > 0:
+		described.Narrative = strings.Join(parts, " / ")
+	case described.Direction == DirectionDebit:
+		described.Narrative = "Transfer to " + described.Counterparty
+	default:
+		described.Narrative = "Transfer from " + described.Counterparty
+	}
+	return described
+}
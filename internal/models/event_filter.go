@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// EventFilter lists what the event bus keeps to itself. An event is suppressed when its
+// topic or tenant is listed, or when every account it names is: a movement between
+// suppressed accounts is internal, while one reaching a customer account still goes out.
+type EventFilter struct {
+	Topics   []string `json:"topics"`
+	Tenants  []string `json:"tenants"`
+	Accounts []string `json:"accounts"`
+	// SystemAccounts adds the ledger's fee, suspense and settlement accounts to Accounts
+	SystemAccounts bool      `json:"system_accounts"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
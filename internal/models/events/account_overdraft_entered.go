@@ -0,0 +1,17 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountOverdraftEntered is published when a debit takes an account from a
+// non-negative balance into its overdraft
+type AccountOverdraftEntered struct {
+	AccountID      string          `json:"account_id"`
+	TransactionID  string          `json:"transaction_id"`
+	Balance        decimal.Decimal `json:"balance"`
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+}
@@ -0,0 +1,11 @@
+package events
+
+import "time"
+
+type AccountStatusChanged struct {
+	AccountID      string    `json:"account_id"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	Reason         string    `json:"reason,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
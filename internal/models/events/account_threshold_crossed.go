@@ -0,0 +1,20 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountThresholdCrossed is published when a posting crosses a balance alert registered
+// on the account. Amount is what the posting debited from the account.
+type AccountThresholdCrossed struct {
+	AlertID       string          `json:"alert_id"`
+	AccountID     string          `json:"account_id"`
+	Type          string          `json:"type"`
+	Threshold     decimal.Decimal `json:"threshold"`
+	Balance       decimal.Decimal `json:"balance"`
+	Amount        decimal.Decimal `json:"amount"`
+	TransactionID string          `json:"transaction_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
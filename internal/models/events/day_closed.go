@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DayClosed is published once a business day has been closed and summarised
+type DayClosed struct {
+	Date             string          `json:"date"`
+	StartsAt         time.Time       `json:"starts_at"`
+	EndsAt           time.Time       `json:"ends_at"`
+	TransactionCount int64           `json:"transaction_count"`
+	Volume           decimal.Decimal `json:"volume"`
+	Accounts         int             `json:"accounts"`
+	SnapshotsTaken   int             `json:"snapshots_taken"`
+	ClosedAt         time.Time       `json:"closed_at"`
+}
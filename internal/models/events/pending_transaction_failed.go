@@ -0,0 +1,18 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PendingTransactionFailed is published when a future-dated transaction could not be posted when due
+type PendingTransactionFailed struct {
+	TransactionID string          `json:"transaction_id"`
+	FromAccount   string          `json:"from_account"`
+	ToAccount     string          `json:"to_account"`
+	Amount        decimal.Decimal `json:"amount"`
+	ExecuteAt     time.Time       `json:"execute_at"`
+	Error         string          `json:"error"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
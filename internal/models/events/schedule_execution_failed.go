@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ScheduleExecutionFailed is published when a standing order occurrence could not be posted
+type ScheduleExecutionFailed struct {
+	ScheduleID  string          `json:"schedule_id"`
+	FromAccount string          `json:"from_account"`
+	ToAccount   string          `json:"to_account"`
+	Amount      decimal.Decimal `json:"amount"`
+	DueAt       time.Time       `json:"due_at"`
+	Failures    int             `json:"failures"`
+	Error       string          `json:"error"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
@@ -0,0 +1,36 @@
+package events
+
+// Topics maps each Kafka topic to the event published on it
+var Topics = map[string]any{
+	"transactions.completed":      TransactionCompleted{},
+	"transactions.flagged":        TransactionFlagged{},
+	"transactions.pending_failed": PendingTransactionFailed{},
+	"accounts.status_changed":     AccountStatusChanged{},
+	"accounts.overdraft_entered":  AccountOverdraftEntered{},
+	"accounts.threshold_crossed":  AccountThresholdCrossed{},
+	"schedules.execution_failed":  ScheduleExecutionFailed{},
+	"day.closed":                  DayClosed{},
+}
+
+// Subject returns the tenant and the accounts an event is about, for filtering. Events
+// that carry no tenant return "".
+func Subject(event any) (tenant string, accounts []string) {
+	switch ev := event.(type) {
+	case TransactionCompleted:
+		return ev.TenantID, []string{ev.FromAccount, ev.ToAccount}
+	case TransactionFlagged:
+		return "", []string{ev.FromAccount, ev.ToAccount}
+	case PendingTransactionFailed:
+		return "", []string{ev.FromAccount, ev.ToAccount}
+	case ScheduleExecutionFailed:
+		return "", []string{ev.FromAccount, ev.ToAccount}
+	case AccountStatusChanged:
+		return "", []string{ev.AccountID}
+	case AccountOverdraftEntered:
+		return "", []string{ev.AccountID}
+	case AccountThresholdCrossed:
+		return "", []string{ev.AccountID}
+	default:
+		return "", nil
+	}
+}
@@ -3,13 +3,20 @@ package events
 import (
 	"time"
 
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
 	"github.com/shopspring/decimal"
 )
 
 type TransactionCompleted struct {
-	TransactionID string          `json:"transaction_id"`
-	FromAccount   string          `json:"from_account"`
-	ToAccount     string          `json:"to_account"`
-	Amount        decimal.Decimal `json:"amount"`
-	OccurredAt    time.Time       `json:"occurred_at"`
+	TransactionID string               `json:"transaction_id"`
+	FromAccount   string               `json:"from_account"`
+	ToAccount     string               `json:"to_account"`
+	Amount        decimal.Decimal      `json:"amount"`
+	Reference     string               `json:"reference,omitempty"`
+	Metadata      map[string]string    `json:"metadata,omitempty"`
+	Fees          []models.FeeLine     `json:"fees,omitempty"`
+	FX            *models.FXConversion `json:"fx,omitempty"`
+	OccurredAt    time.Time            `json:"occurred_at"`
+	TenantID      string               `json:"tenant_id,omitempty"`
+	ValueDate     string               `json:"value_date,omitempty"`
 }
@@ -0,0 +1,18 @@
+package events
+
+import (
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// TransactionFlagged is published when a transaction is posted but matched a rule with the flag action
+type TransactionFlagged struct {
+	TransactionID string             `json:"transaction_id"`
+	FromAccount   string             `json:"from_account"`
+	ToAccount     string             `json:"to_account"`
+	Amount        decimal.Decimal    `json:"amount"`
+	Matches       []models.RuleMatch `json:"matches"`
+	OccurredAt    time.Time          `json:"occurred_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeSchedule charges the sender a flat and/or percentage fee on top of the amount.
+// It applies when the sender's account type and the transaction size both match.
+type FeeSchedule struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	AccountType string          `json:"account_type,omitempty"` // empty matches every account
+	MinAmount   decimal.Decimal `json:"min_amount"`
+	MaxAmount   decimal.Decimal `json:"max_amount"` // zero means no upper bound
+	FlatFee     decimal.Decimal `json:"flat_fee"`
+	Percentage  decimal.Decimal `json:"percentage"` // percent of the amount, e.g. 1.5
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Matches reports whether the schedule applies to a sender of accountType moving amount
+func (f FeeSchedule) Matches(accountType string, amount decimal.Decimal) bool {
+	if f.AccountType != "" && f.AccountType != accountType {
+		return false
+	}
+	if amount.LessThan(f.MinAmount) {
+		return false
+	}
+	return f.MaxAmount.IsZero() || amount.LessThanOrEqual(f.MaxAmount)
+}
+
+// FeeLine is one fee charged on a transaction, posted as its own debit/credit legs
+type FeeLine struct {
+	ScheduleID string          `json:"schedule_id"`
+	Account    string          `json:"account"` // fee revenue account credited
+	Amount     decimal.Decimal `json:"amount"`
+}
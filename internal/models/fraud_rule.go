@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rule actions, in increasing order of severity
+const (
+	RuleActionAllow = "allow"
+	RuleActionFlag  = "flag"
+	RuleActionBlock = "block"
+)
+
+// Built-in rule types
+const (
+	RuleTypeAmountThreshold = "amount_threshold" // amount >= Threshold
+	RuleTypeNewCounterparty = "new_counterparty" // sender has never paid the receiver before
+	RuleTypeRapidFire       = "rapid_fire"       // more than MaxCount debits within Window
+)
+
+// FraudRule is a configurable check evaluated against every transaction before it is posted.
+// Only the parameters relevant to its Type are used.
+type FraudRule struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Action  string `json:"action"`
+	Enabled bool   `json:"enabled"`
+
+	Threshold decimal.Decimal `json:"threshold,omitempty"`
+	Window    Duration        `json:"window,omitempty"`
+	MaxCount  int             `json:"max_count,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RuleMatch is a rule that fired for a transaction
+type RuleMatch struct {
+	RuleID string `json:"rule_id"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// RuleDecision is the outcome of the rules pipeline: the most severe action of all matches
+type RuleDecision struct {
+	Action  string      `json:"action"`
+	Matches []RuleMatch `json:"matches,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// FXConversion records how a cross-currency transfer was converted.
+// The receiver is credited Amount*Rate; the difference to Amount*ReferenceRate is booked as FX gain/loss.
+type FXConversion struct {
+	FromCurrency    string          `json:"from_currency"`
+	ToCurrency      string          `json:"to_currency"`
+	Rate            decimal.Decimal `json:"rate"`           // rate applied to the transfer
+	ReferenceRate   decimal.Decimal `json:"reference_rate"` // provider rate at posting time
+	RateSource      string          `json:"rate_source"`    // "caller" or "provider"
+	ConvertedAmount decimal.Decimal `json:"converted_amount"`
+	GainLoss        decimal.Decimal `json:"gain_loss"` // in ToCurrency; positive is a gain
+}
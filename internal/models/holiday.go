@@ -0,0 +1,8 @@
+package models
+
+// Holiday is a day a currency does not settle on, on top of its weekend
+type Holiday struct {
+	Currency string `json:"currency"`
+	Date     string `json:"date"` // YYYY-MM-DD
+	Name     string `json:"name,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	InterestScopeAccount = "account" // rate for a single account, overrides its product rate
+	InterestScopeProduct = "product" // rate for every account of a type
+)
+
+// InterestRate is an annual interest rate in percent, e.g. 3.5
+type InterestRate struct {
+	Scope      string          `json:"scope"`
+	ID         string          `json:"id"` // account ID or account type, depending on Scope
+	AnnualRate decimal.Decimal `json:"annual_rate"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// InterestAccount is an account that earns interest, with its effective rate resolved
+type InterestAccount struct {
+	AccountID  string
+	AnnualRate decimal.Decimal
+}
+
+// AccruedInterest is the interest accrued on an account so far
+type AccruedInterest struct {
+	AccountID       string          `json:"account_id"`
+	AccruedToDate   decimal.Decimal `json:"accrued_to_date"`
+	LastAccrualDate *time.Time      `json:"last_accrual_date,omitempty"`
+}
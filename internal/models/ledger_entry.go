@@ -8,8 +8,13 @@ import (
 
 // LedgerEntry represents a single ledger record for an account
 type LedgerEntry struct {
-	ID        string          // unique identifier
-	AccountID string          // which account this entry belongs to
-	Amount    decimal.Decimal // in cents (positive or negative)
-	CreatedAt time.Time       // timestamp
+	ID            string          // unique identifier
+	TransactionID string          // transaction that produced this entry
+	AccountID     string          // which account this entry belongs to
+	Amount        decimal.Decimal // in cents (positive or negative)
+	CreatedAt     time.Time       // timestamp
+	Sequence      int64           // monotonically increasing position in the ledger, assigned by the store
+	PrevHash      string          // hash of the previous entry of the same account, empty for the first one
+	Hash          string          // SHA-256 over PrevHash and this entry's contents
+	TenantID      string          // tenant owning the account, empty on single-tenant deployments
 }
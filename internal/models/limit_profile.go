@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// VelocityRule caps the outgoing volume of an account over a rolling window.
+// A zero MaxAmount or MaxCount means that dimension is not limited.
+type VelocityRule struct {
+	Window    Duration        `json:"window"` // e.g. "24h", "168h"
+	MaxAmount decimal.Decimal `json:"max_amount"`
+	MaxCount  int             `json:"max_count"`
+}
+
+// LimitProfile is a named set of velocity rules that accounts can be assigned to
+type LimitProfile struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Rules     []VelocityRule `json:"rules"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Duration is a time.Duration that reads and writes JSON as "24h" style strings
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Duration(d).String() + `"`), nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return &time.ParseError{Value: string(data), Message: ": duration must be a string such as \"24h\""}
+	}
+	parsed, err := time.ParseDuration(string(data[1 : len(data)-1]))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	NettingPending = "pending"
+	NettingSettled = "settled"
+)
+
+// NettingObligation is an inter-institution payment held until its settlement window closes
+type NettingObligation struct {
+	ID              string          `json:"id"`
+	FromInstitution string          `json:"from_institution"`
+	ToInstitution   string          `json:"to_institution"`
+	Amount          decimal.Decimal `json:"amount"`
+	Reference       string          `json:"reference,omitempty"`
+	WindowStart     time.Time       `json:"window_start"`
+	WindowEnd       time.Time       `json:"window_end"`
+	Status          string          `json:"status"`
+
+	// SettlementTransactionID is the net transaction that settled it; empty when the pair netted to zero
+	SettlementTransactionID string     `json:"settlement_transaction_id,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	SettledAt               *time.Time `json:"settled_at,omitempty"`
+}
+
+// NettingPosition is what two institutions owe each other within a window. PartyA sorts before PartyB.
+type NettingPosition struct {
+	PartyA      string          `json:"party_a"`
+	PartyB      string          `json:"party_b"`
+	GrossAToB   decimal.Decimal `json:"gross_a_to_b"`
+	GrossBToA   decimal.Decimal `json:"gross_b_to_a"`
+	Net         decimal.Decimal `json:"net"`             // amount actually moved
+	Payer       string          `json:"payer,omitempty"` // empty when the pair nets to zero
+	Payee       string          `json:"payee,omitempty"`
+	Obligations int             `json:"obligations"`
+
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+}
+
+// NettingReport compares gross obligations with the net settlements of one window
+type NettingReport struct {
+	WindowStart time.Time         `json:"window_start"`
+	WindowEnd   time.Time         `json:"window_end"`
+	Closed      bool              `json:"closed"`
+	Positions   []NettingPosition `json:"positions"`
+	TotalGross  decimal.Decimal   `json:"total_gross"`
+	TotalNet    decimal.Decimal   `json:"total_net"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PaymentRequestOpen      = "open"
+	PaymentRequestPaid      = "paid"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired" // never stored: an open request read after ExpiresAt
+)
+
+// PaymentRequest asks for an exact amount to be paid into the payee account before it expires.
+// It is fulfilled by the one transaction that references it.
+type PaymentRequest struct {
+	ID           string          `json:"id"`
+	TenantID     string          `json:"tenant_id,omitempty"`
+	PayeeAccount string          `json:"payee_account"`
+	Amount       decimal.Decimal `json:"amount"`
+	Reference    string          `json:"reference,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	Status       string          `json:"status"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+
+	// Set once paid
+	TransactionID string     `json:"transaction_id,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
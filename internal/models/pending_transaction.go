@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	PendingScheduled = "pending"
+	PendingExecuting = "executing"
+	PendingExecuted  = "executed"
+	PendingCancelled = "cancelled"
+	PendingFailed    = "failed"
+)
+
+// PendingTransaction is a future-dated transaction held until ExecuteAt
+type PendingTransaction struct {
+	ID          string      `json:"id"` // same as Transaction.ID once posted
+	Transaction Transaction `json:"transaction"`
+	Force       bool        `json:"force,omitempty"`
+	ExecuteAt   time.Time   `json:"execute_at"`
+	Status      string      `json:"status"`
+	LastError   string      `json:"last_error,omitempty"`
+	ExecutedAt  *time.Time  `json:"executed_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
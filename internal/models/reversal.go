@@ -0,0 +1,28 @@
+package models
+
+// ReversalBatch reverses many transactions under one adjustment reference, e.g. to undo a
+// bad batch of payments. Transactions are named by ID, matched by Filter, or both.
+type ReversalBatch struct {
+	TransactionIDs      []string
+	Filter              *TransactionFilter
+	AdjustmentReference string // stored on every reversal as the adjustment_reference metadata key
+	Reason              string
+	DryRun              bool // list what would be reversed without posting anything
+}
+
+// ReversalResult is the outcome for one transaction of a batch
+type ReversalResult struct {
+	TransactionID string `json:"transaction_id"`
+	ReversalID    string `json:"reversal_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ReversalBatchResult reports every transaction of a batch; one failure does not stop the rest
+type ReversalBatchResult struct {
+	AdjustmentReference string           `json:"adjustment_reference"`
+	DryRun              bool             `json:"dry_run,omitempty"`
+	Matched             int              `json:"matched"`
+	Reversed            int              `json:"reversed"`
+	Failed              int              `json:"failed"`
+	Results             []ReversalResult `json:"results"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ScheduleActive    = "active"
+	ScheduleCancelled = "cancelled"
+	ScheduleCompleted = "completed" // EndAt has passed
+)
+
+// Schedule is a standing order: a transfer repeated on a cron expression
+type Schedule struct {
+	ID          string          `json:"id"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	FromAccount string          `json:"from_account"`
+	ToAccount   string          `json:"to_account"`
+	Amount      decimal.Decimal `json:"amount"`
+	Cron        string          `json:"cron"` // e.g. "0 9 1 * *" or "@monthly", in UTC
+	Reference   string          `json:"reference,omitempty"`
+	Description string          `json:"description,omitempty"`
+
+	Status    string     `json:"status"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at,omitempty"`
+	NextRunAt time.Time  `json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+
+	// Failures counts executions that failed since the last success
+	Failures  int    `json:"failures"`
+	LastError string `json:"last_error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
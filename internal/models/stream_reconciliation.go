@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// StreamReconciliation is the outcome of checking a topic of transaction events against
+// the transactions table. Lists of IDs are cut at a maximum; the counts are complete.
+type StreamReconciliation struct {
+	Topic        string    `json:"topic"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Events       int       `json:"events"`       // messages published in the range
+	Duplicates   int       `json:"duplicates"`   // repeats of an event already read: expected from at-least-once publishing
+	Undecodable  int       `json:"undecodable"`  // messages without a readable transaction ID
+	Transactions int       `json:"transactions"` // rows created in the range
+
+	EventsWithoutRows    int       `json:"events_without_rows"`
+	EventsWithoutRowsIDs []string  `json:"events_without_rows_ids"`
+	RowsWithoutEvents    int       `json:"rows_without_events"`
+	RowsWithoutEventsIDs []string  `json:"rows_without_events_ids"`
+	CheckedAt            time.Time `json:"checked_at"`
+}
+
+// Consistent reports whether every event has its row and every row its event
+func (r StreamReconciliation) Consistent() bool {
+	return r.EventsWithoutRows == 0 && r.RowsWithoutEvents == 0
+}
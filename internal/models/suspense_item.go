@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SuspenseOpen     = "open"
+	SuspenseResolved = "resolved"
+)
+
+// SuspenseItem is money parked in the suspense account because an automated flow could not
+// credit its intended account. It stays open until an operator moves it to its final destination.
+type SuspenseItem struct {
+	ID              string          `json:"id"`
+	TenantID        string          `json:"tenant_id,omitempty"`
+	Source          string          `json:"source"`         // the flow that parked it, e.g. "netting"
+	TransactionID   string          `json:"transaction_id"` // the posting into suspense
+	FromAccount     string          `json:"from_account"`
+	IntendedAccount string          `json:"intended_account"`
+	Amount          decimal.Decimal `json:"amount"` // as credited to the suspense account
+	Reason          string          `json:"reason"`
+	Status          string          `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+
+	// Set once resolved
+	ResolvedAccount         string     `json:"resolved_account,omitempty"`
+	ResolutionTransactionID string     `json:"resolution_transaction_id,omitempty"`
+	ResolvedAt              *time.Time `json:"resolved_at,omitempty"`
+}
@@ -1,18 +1,115 @@
 package models
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 // Transaction represents an intent to transfer money
 type Transaction struct {
+	ID             string          `json:"id"`
+	TenantID       string          `json:"tenant_id,omitempty"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	FromAccount    string          `json:"from_account"`
+	ToAccount      string          `json:"to_account"`
+	Amount         decimal.Decimal `json:"amount"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Replayed       bool            `json:"-"`
+	Force          bool            `json:"-"` // skips the duplicate-payment heuristic
+	Internal       bool            `json:"-"` // posted by the ledger itself: no funds, limit, rule, duplicate or fee checks
+
+	// Reference and Description tie the payment back to the caller's order or invoice;
+	// Metadata holds any other caller-defined keys and is searchable.
+	Reference   string            `json:"reference,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+
+	// Tags label the transaction for slicing activity (e.g. "payroll-2024-06"); they can
+	// be added and removed after posting, unlike everything else on a transaction
+	Tags []string `json:"tags,omitempty"`
+
+	// Adjustment is set when the requested date fell in a closed period and the
+	// transaction was moved into the current open period; OriginalCreatedAt keeps the requested date.
+	Adjustment        bool       `json:"adjustment,omitempty"`
+	OriginalCreatedAt *time.Time `json:"original_created_at,omitempty"`
+
+	// PaymentRequestID names the payment request this transaction pays; it is marked paid atomically
+	PaymentRequestID string `json:"payment_request_id,omitempty"`
+
+	// ValueDate is the day (YYYY-MM-DD) the transfer takes economic effect, which interest
+	// follows; it defaults to the booking date and is rolled onto a business day of its currencies
+	ValueDate string `json:"value_date,omitempty"`
+
+	// Fees are charged to the sender on top of Amount and credited to the fee revenue account
+	Fees []FeeLine `json:"fees,omitempty"`
+
+	// FX is set on transfers between accounts of different currencies. A caller may preset
+	// FX.Rate to fix the rate; otherwise it comes from the rate provider.
+	FX *FXConversion `json:"fx,omitempty"`
+}
+
+// TotalFees sums the fees charged on the transaction
+func (t Transaction) TotalFees() decimal.Decimal {
+	total := decimal.Zero
+	for _, fee := range t.Fees {
+		total = total.Add(fee.Amount)
+	}
+	return total
+}
+
+// Search statuses. Every stored transaction is posted; pending ones are listed separately.
+const (
+	TransactionPosted     = "posted"
+	TransactionAdjustment = "adjustment" // posted into the open period because its own was closed
+)
+
+// TransactionFilter narrows a transaction search; zero values are ignored
+type TransactionFilter struct {
 	ID             string
+	Reference      string
+	Metadata       map[string]string // every key/value pair must match
+	Tags           []string          // every tag must be present
+	Account        string            // sender or receiver
+	MinAmount      decimal.Decimal
+	MaxAmount      decimal.Decimal
+	From           time.Time // created at or after
+	To             time.Time // created before
+	Status         string
 	IdempotencyKey string
-	FromAccount    string
-	ToAccount      string
-	Amount         decimal.Decimal
-	CreatedAt      time.Time
-	Replayed       bool
+	After          *TransactionCursor // the last transaction of the previous page
+	Limit          int
+}
+
+// TransactionCursor marks a position in search results, which run newest first
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// String encodes the cursor for use in a URL
+func (c TransactionCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// ParseTransactionCursor decodes a cursor made by TransactionCursor.String
+func ParseTransactionCursor(s string) (TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TransactionCursor{}, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return TransactionCursor{}, ErrInvalidCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return TransactionCursor{}, ErrInvalidCursor
+	}
+	return TransactionCursor{CreatedAt: at, ID: id}, nil
 }
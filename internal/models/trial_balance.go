@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TrialBalanceLine is the debit/credit activity of a single account
+type TrialBalanceLine struct {
+	AccountID string          `json:"account_id"`
+	Class     string          `json:"class,omitempty"`
+	Debits    decimal.Decimal `json:"debits"`  // sum of negative entries, as a positive number
+	Credits   decimal.Decimal `json:"credits"` // sum of positive entries
+	Balance   decimal.Decimal `json:"balance"` // credits - debits
+}
+
+// TrialBalance lists every account; in a healthy ledger total debits equal total credits
+type TrialBalance struct {
+	Lines        []TrialBalanceLine `json:"lines"`
+	TotalDebits  decimal.Decimal    `json:"total_debits"`
+	TotalCredits decimal.Decimal    `json:"total_credits"`
+	Net          decimal.Decimal    `json:"net"`
+	Balanced     bool               `json:"balanced"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+}
+
+// InvariantReport is the outcome of one run of the double-entry verifier
+type InvariantReport struct {
+	LedgerNet              decimal.Decimal `json:"ledger_net"`
+	UnbalancedTransactions []string        `json:"unbalanced_transactions"`
+	Healthy                bool            `json:"healthy"`
+	CheckedAt              time.Time       `json:"checked_at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TwoPhasePreparing = "preparing" // coordinator only: waiting for both legs to prepare
+	TwoPhasePrepared  = "prepared"  // participant only: ready to commit, waiting for the decision
+	TwoPhaseCommitted = "committed"
+	TwoPhaseAborted   = "aborted"
+
+	LegDebit  = "debit"
+	LegCredit = "credit"
+)
+
+// TwoPhaseLeg is one ledger instance's side of a cross-instance transfer. A prepared debit
+// leg has already moved the amount to the settlement account, so it cannot be spent twice;
+// a prepared credit leg posts only once the transfer commits.
+type TwoPhaseLeg struct {
+	XID         string          `json:"xid"`
+	Role        string          `json:"role"` // debit or credit
+	AccountID   string          `json:"account_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	Coordinator string          `json:"coordinator"` // the instance that decides the outcome
+	Status      string          `json:"status"`
+
+	// TransactionID is the leg's last posting: the reservation of a debit leg, its release
+	// when aborted, or the credit of a credit leg once committed
+	TransactionID string     `json:"transaction_id,omitempty"`
+	PreparedAt    time.Time  `json:"prepared_at"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+}
+
+// CrossInstanceTransfer is the coordinator's record of a transfer from one of its accounts to
+// an account on another ledger instance. The decision is persisted before it is sent, so a
+// coordinator restarting mid-way finishes what it decided and aborts what it had not.
+type CrossInstanceTransfer struct {
+	ID             string          `json:"id"` // the transaction ID shared by both legs
+	TenantID       string          `json:"tenant_id,omitempty"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	FromAccount    string          `json:"from_account"`
+	ToInstance     string          `json:"to_instance"`
+	ToAccount      string          `json:"to_account"`
+	Amount         decimal.Decimal `json:"amount"`
+	Status         string          `json:"status"` // preparing, committed or aborted
+	Reason         string          `json:"reason,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DecidedAt      *time.Time      `json:"decided_at,omitempty"`
+
+	// CompletedAt is set once both legs have applied the decision
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UsageRecord counts what one tenant, through one API key, did on one day (UTC)
+type UsageRecord struct {
+	TenantID     string `json:"tenant_id,omitempty"` // empty for platform-level calls
+	KeyID        string `json:"key_id,omitempty"`    // fingerprint of the API key; empty for calls without one
+	Date         string `json:"date"`                // YYYY-MM-DD
+	APICalls     int64  `json:"api_calls"`
+	Transactions int64  `json:"transactions"` // posted, not counting replays and dry runs
+}
+
+type UsageFilter struct {
+	TenantID string
+	KeyID    string
+	From     time.Time // first day included; zero means since the first record
+	To       time.Time // first day excluded; zero means up to today
+}
@@ -0,0 +1,254 @@
+package netting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var ErrInvalidObligation = errors.New("invalid netting obligation")
+
+var settlements = metrics.NewCounterVec("netting_settlements_total",
+	"Net settlement postings per counterparty pair by outcome", "outcome")
+
+// Service collects inter-institution obligations per settlement window and, once the
+// window has closed, settles each counterparty pair with a single net transaction
+type Service struct {
+	ledger    *ledger.Ledger
+	store     interfaces.NettingStore
+	window    time.Duration
+	appLogger *slog.Logger
+}
+
+func NewService(ledgerService *ledger.Ledger, store interfaces.NettingStore, window time.Duration, appLogger *slog.Logger) *Service {
+	return &Service{
+		ledger:    ledgerService,
+		store:     store,
+		window:    window,
+		appLogger: appLogger,
+	}
+}
+
+// WindowStart returns the start of the settlement window containing t
+func (s *Service) WindowStart(t time.Time) time.Time {
+	return t.UTC().Truncate(s.window)
+}
+
+// Submit records an obligation in the currently open window; nothing is posted until the window closes
+func (s *Service) Submit(ctx context.Context, obligation models.NettingObligation) (models.NettingObligation, error) {
+	if obligation.FromInstitution == "" || obligation.ToInstitution == "" || obligation.FromInstitution == obligation.ToInstitution {
+		return models.NettingObligation{}, fmt.Errorf("%w: from_institution and to_institution must be two different accounts", ErrInvalidObligation)
+	}
+	if !obligation.Amount.IsPositive() {
+		return models.NettingObligation{}, fmt.Errorf("%w: amount must be positive", ErrInvalidObligation)
+	}
+
+	now := s.ledger.Now().UTC()
+	obligation.ID = uuid.New().String()
+	obligation.WindowStart = s.WindowStart(now)
+	obligation.WindowEnd = obligation.WindowStart.Add(s.window)
+	obligation.Status = models.NettingPending
+	obligation.SettlementTransactionID = ""
+	obligation.CreatedAt = now
+	obligation.SettledAt = nil
+	if err := s.store.SaveNettingObligation(ctx, obligation); err != nil {
+		return models.NettingObligation{}, err
+	}
+	return obligation, nil
+}
+
+func (s *Service) Obligations(ctx context.Context, windowStart time.Time) ([]models.NettingObligation, error) {
+	return s.store.ListNettingObligations(ctx, s.WindowStart(windowStart))
+}
+
+// Report compares gross obligations with the net positions of a window. Open windows show
+// what would be settled if the window closed now.
+func (s *Service) Report(ctx context.Context, windowStart time.Time) (models.NettingReport, error) {
+	obligations, err := s.Obligations(ctx, windowStart)
+	if err != nil {
+		return models.NettingReport{}, err
+	}
+
+	start := s.WindowStart(windowStart)
+	report := models.NettingReport{
+		WindowStart: start,
+		WindowEnd:   start.Add(s.window),
+		Positions:   positions(obligations),
+	}
+	report.Closed = !report.WindowEnd.After(s.ledger.Now())
+	for _, position := range report.Positions {
+		report.TotalGross = report.TotalGross.Add(position.GrossAToB).Add(position.GrossBToA)
+		report.TotalNet = report.TotalNet.Add(position.Net)
+	}
+	return report, nil
+}
+
+// CloseDue settles every window that has ended, including windows missed while no replica was running.
+// It returns the number of net settlement transactions posted.
+func (s *Service) CloseDue(ctx context.Context, now time.Time) (int, error) {
+	windows, err := s.store.ListDueNettingWindows(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, windowStart := range windows {
+		n, err := s.closeWindow(ctx, windowStart)
+		posted += n
+		if err != nil {
+			return posted, err
+		}
+	}
+	return posted, nil
+}
+
+func (s *Service) closeWindow(ctx context.Context, windowStart time.Time) (int, error) {
+	obligations, err := s.store.ListNettingObligations(ctx, windowStart)
+	if err != nil {
+		return 0, err
+	}
+
+	// Group what is still pending by unordered counterparty pair
+	pairs := make(map[[2]string][]models.NettingObligation)
+	for _, obligation := range obligations {
+		if obligation.Status == models.NettingPending {
+			key := pairKey(obligation.FromInstitution, obligation.ToInstitution)
+			pairs[key] = append(pairs[key], obligation)
+		}
+	}
+
+	posted := 0
+	for _, pending := range pairs {
+		position := positions(pending)[0]
+		transactionId, err := s.settle(ctx, windowStart, position, pending)
+		if err != nil {
+			// Left pending, so the next run retries the pair; other pairs still settle
+			settlements.With("failed").Inc()
+			s.appLogger.Error("net settlement failed",
+				"window_start", windowStart,
+				"party_a", position.PartyA,
+				"party_b", position.PartyB,
+				"error", err,
+			)
+			continue
+		}
+		if transactionId != "" {
+			settlements.With("posted").Inc()
+			posted++
+		} else {
+			settlements.With("netted_to_zero").Inc()
+		}
+	}
+	return posted, nil
+}
+
+// settle posts the net amount of a pair and marks its obligations settled. The idempotency key
+// covers the exact set of obligations, so a retry after a crash between the two steps does not
+// pay twice, while obligations that arrive late are settled by a posting of their own.
+func (s *Service) settle(ctx context.Context, windowStart time.Time, position models.NettingPosition,
+	pending []models.NettingObligation) (string, error) {
+	ids := make([]string, len(pending))
+	for i, obligation := range pending {
+		ids[i] = obligation.ID
+	}
+	slices.Sort(ids)
+
+	transactionId := ""
+	if position.Net.IsPositive() {
+		sum := sha256.Sum256([]byte(strings.Join(ids, ",")))
+		tx := models.Transaction{
+			ID:             s.ledger.NewID(),
+			IdempotencyKey: "netting-" + windowStart.Format(time.RFC3339) + "-" + hex.EncodeToString(sum[:12]),
+			FromAccount:    position.Payer,
+			ToAccount:      position.Payee,
+			Amount:         position.Net,
+			CreatedAt:      s.ledger.Now(),
+			Internal:       true, // settles obligations already agreed; fees, limits and rules do not apply
+			Reference:      "netting-" + windowStart.Format(time.RFC3339),
+			Description:    fmt.Sprintf("Net settlement of %d obligations", len(pending)),
+			Metadata: map[string]string{
+				"type":         "net_settlement",
+				"window_start": windowStart.Format(time.RFC3339),
+				"gross":        position.GrossAToB.Add(position.GrossBToA).String(),
+			},
+		}
+		// The obligations were agreed, so a payee that cannot be credited must not hold up the window
+		if _, _, err := s.ledger.PostOrSuspend(ctx, tx, "netting"); err != nil {
+			return "", err
+		}
+		transactionId = tx.ID
+	}
+
+	if err := s.store.SettleNettingObligations(ctx, ids, transactionId, s.ledger.Now().UTC()); err != nil {
+		return "", err
+	}
+	s.appLogger.Info("netting pair settled",
+		"window_start", windowStart,
+		"payer", position.Payer,
+		"payee", position.Payee,
+		"net", position.Net.String(),
+		"obligations", len(pending),
+	)
+	return transactionId, nil
+}
+
+func pairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// positions nets obligations per unordered counterparty pair, sorted by pair
+func positions(obligations []models.NettingObligation) []models.NettingPosition {
+	byPair := make(map[[2]string]*models.NettingPosition)
+	var keys [][2]string
+	for _, obligation := range obligations {
+		key := pairKey(obligation.FromInstitution, obligation.ToInstitution)
+		position, ok := byPair[key]
+		if !ok {
+			position = &models.NettingPosition{PartyA: key[0], PartyB: key[1]}
+			byPair[key] = position
+			keys = append(keys, key)
+		}
+		if obligation.FromInstitution == position.PartyA {
+			position.GrossAToB = position.GrossAToB.Add(obligation.Amount)
+		} else {
+			position.GrossBToA = position.GrossBToA.Add(obligation.Amount)
+		}
+		position.Obligations++
+		if id := obligation.SettlementTransactionID; id != "" && !slices.Contains(position.TransactionIDs, id) {
+			position.TransactionIDs = append(position.TransactionIDs, id)
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	})
+	result := make([]models.NettingPosition, 0, len(keys))
+	for _, key := range keys {
+		position := byPair[key]
+		net := position.GrossAToB.Sub(position.GrossBToA)
+		switch {
+		case net.IsPositive():
+			position.Payer, position.Payee = position.PartyA, position.PartyB
+		case net.IsNegative():
+			position.Payer, position.Payee = position.PartyB, position.PartyA
+		}
+		position.Net = net.Abs()
+		result = append(result, *position)
+	}
+	return result
+}
@@ -0,0 +1,248 @@
+// Package parquet writes Apache Parquet files without external dependencies. It covers
+// what the analytics export needs: flat schemas of required columns, one row group per
+// file, one PLAIN-encoded, gzip-compressed data page per column.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const magic = "PAR1"
+
+// Physical types
+const (
+	typeInt64     = 2
+	typeByteArray = 6
+)
+
+// Converted (logical) types
+const (
+	convertedNone            = -1
+	convertedUTF8            = 0
+	convertedDecimal         = 5
+	convertedTimestampMicros = 10
+	convertedJSON            = 19
+)
+
+const (
+	repetitionRequired = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecGzip          = 2
+	pageData           = 0
+)
+
+// Decimals are written as DECIMAL(20,8), the NUMERIC type of the ledger tables
+const (
+	decimalPrecision = 20
+	decimalScale     = 8
+)
+
+// Column is one column of a file, already PLAIN-encoded
+type Column struct {
+	name      string
+	physical  int32
+	converted int32
+	count     int
+	values    []byte
+}
+
+func StringColumn(name string, values []string) Column {
+	return byteArrayColumn(name, convertedUTF8, values)
+}
+
+// JSONColumn holds JSON documents, which query engines can parse back into structures
+func JSONColumn(name string, values []string) Column {
+	return byteArrayColumn(name, convertedJSON, values)
+}
+
+func byteArrayColumn(name string, converted int32, values []string) Column {
+	var buf []byte
+	for _, value := range values {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
+		buf = append(buf, value...)
+	}
+	return Column{name: name, physical: typeByteArray, converted: converted, count: len(values), values: buf}
+}
+
+func Int64Column(name string, values []int64) Column {
+	var buf []byte
+	for _, value := range values {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(value))
+	}
+	return Column{name: name, physical: typeInt64, converted: convertedNone, count: len(values), values: buf}
+}
+
+// TimestampColumn stores UTC microseconds since the epoch
+func TimestampColumn(name string, values []time.Time) Column {
+	var buf []byte
+	for _, value := range values {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(value.UnixMicro()))
+	}
+	return Column{name: name, physical: typeInt64, converted: convertedTimestampMicros, count: len(values), values: buf}
+}
+
+// DecimalColumn stores the unscaled value as big-endian two's complement bytes
+func DecimalColumn(name string, values []decimal.Decimal) Column {
+	var buf []byte
+	for _, value := range values {
+		unscaled := twosComplement(value.Shift(decimalScale).BigInt())
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(unscaled)))
+		buf = append(buf, unscaled...)
+	}
+	return Column{name: name, physical: typeByteArray, converted: convertedDecimal, count: len(values), values: buf}
+}
+
+func twosComplement(n *big.Int) []byte {
+	if n.Sign() >= 0 {
+		b := n.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+
+	// -n-1 with every bit flipped is n in two's complement
+	b := new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)).Bytes()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		b = append([]byte{0xff}, b...)
+	}
+	return b
+}
+
+// chunk records where a column chunk landed in the file
+type chunk struct {
+	offset       int64
+	compressed   int64
+	uncompressed int64
+}
+
+// Write writes a complete Parquet file with the columns in order. Every column must
+// hold the same number of values.
+func Write(w io.Writer, columns ...Column) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("parquet: no columns")
+	}
+	rows := columns[0].count
+	for _, column := range columns {
+		if column.count != rows {
+			return fmt.Errorf("parquet: column %s has %d values, expected %d", column.name, column.count, rows)
+		}
+	}
+
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	chunks := make([]chunk, len(columns))
+	for i, column := range columns {
+		var page bytes.Buffer
+		zw := gzip.NewWriter(&page)
+		if _, err := zw.Write(column.values); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		// Required columns of a flat schema have no repetition or definition levels
+		header := newThriftWriter()
+		header.i32(1, pageData)
+		header.i32(2, int32(len(column.values)))
+		header.i32(3, int32(page.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(rows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.endStruct()
+		header.buf.WriteByte(0)
+
+		chunks[i] = chunk{
+			offset:       int64(file.Len()),
+			compressed:   int64(header.buf.Len() + page.Len()),
+			uncompressed: int64(header.buf.Len() + len(column.values)),
+		}
+		file.Write(header.buf.Bytes())
+		file.Write(page.Bytes())
+	}
+
+	footer := fileMetadata(columns, chunks, rows)
+	file.Write(footer)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	file.WriteString(magic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+func fileMetadata(columns []Column, chunks []chunk, rows int) []byte {
+	t := newThriftWriter()
+	t.i32(1, 1) // format version
+
+	// The schema is a root group followed by its leaf columns
+	t.list(2, thriftStruct, len(columns)+1)
+	t.beginStruct(0)
+	t.string(4, "schema")
+	t.i32(5, int32(len(columns)))
+	t.endStruct()
+	for _, column := range columns {
+		t.beginStruct(0)
+		t.i32(1, column.physical)
+		t.i32(3, repetitionRequired)
+		t.string(4, column.name)
+		if column.converted != convertedNone {
+			t.i32(6, column.converted)
+		}
+		if column.converted == convertedDecimal {
+			t.i32(7, decimalScale)
+			t.i32(8, decimalPrecision)
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, int64(rows))
+
+	var totalSize int64
+	for _, c := range chunks {
+		totalSize += c.uncompressed
+	}
+	t.list(4, thriftStruct, 1)
+	t.beginStruct(0)
+	t.list(1, thriftStruct, len(columns))
+	for i, column := range columns {
+		t.beginStruct(0)
+		t.i64(2, chunks[i].offset)
+		t.beginStruct(3)
+		t.i32(1, column.physical)
+		t.list(2, thriftI32, 2)
+		t.i32Element(encodingPlain)
+		t.i32Element(encodingRLE)
+		t.list(3, thriftBinary, 1)
+		t.stringElement(column.name)
+		t.i32(4, codecGzip)
+		t.i64(5, int64(rows))
+		t.i64(6, chunks[i].uncompressed)
+		t.i64(7, chunks[i].compressed)
+		t.i64(9, chunks[i].offset)
+		t.endStruct()
+		t.endStruct()
+	}
+	t.i64(2, totalSize)
+	t.i64(3, int64(rows))
+	t.endStruct()
+
+	t.string(6, "distributed-payments-ledger-system")
+	t.buf.WriteByte(0)
+	return t.buf.Bytes()
+}
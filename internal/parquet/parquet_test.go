@@ -0,0 +1,338 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Field IDs from parquet.thrift in apache/parquet-format
+const (
+	fileMetaSchema    = 2
+	fileMetaNumRows   = 3
+	fileMetaRowGroups = 4
+
+	schemaType          = 1
+	schemaName          = 4
+	schemaNumChildren   = 5
+	schemaConvertedType = 6
+	schemaScale         = 7
+	schemaPrecision     = 8
+
+	rowGroupColumns = 1
+	chunkMetaData   = 3
+
+	metaType            = 1
+	metaPath            = 3
+	metaCodec           = 4
+	metaNumValues       = 5
+	metaUncompressed    = 6
+	metaCompressed      = 7
+	metaDataPageOffset  = 9
+	pageHeaderType      = 1
+	pageUncompressed    = 2
+	pageCompressed      = 3
+	pageDataPageHeader  = 5
+	dataPageNumValues   = 1
+	dataPageEncoding    = 2
+	dataPageDefEncoding = 3
+	dataPageRepEncoding = 4
+)
+
+// thriftStructValue is a decoded compact-protocol struct keyed by field ID
+type thriftStructValue map[int16]any
+
+// thriftReader decodes the Thrift compact protocol independently of thriftWriter, so
+// the tests check the writer against the specification rather than against itself
+type thriftReader struct {
+	t    *testing.T
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	if r.pos >= len(r.data) {
+		r.t.Fatalf("thrift: read past the end at %d", r.pos)
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.t.Fatalf("thrift: bad varint at %d", r.pos)
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 5, 6:
+		return r.zigzag()
+	case 8:
+		n := int(r.uvarint())
+		v := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return v
+	case 9:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case 12:
+		return r.structValue()
+	}
+	r.t.Fatalf("thrift: unexpected type %d at %d", typ, r.pos)
+	return nil
+}
+
+func (r *thriftReader) structValue() thriftStructValue {
+	fields := thriftStructValue{}
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+func (s thriftStructValue) int(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+func (s thriftStructValue) list(id int16) []any {
+	v, _ := s[id].([]any)
+	return v
+}
+
+func TestFileMetadataMatchesHandEncodedFooter(t *testing.T) {
+	footer := fileMetadata(
+		[]Column{Int64Column("n", []int64{7})},
+		[]chunk{{offset: 4, compressed: 40, uncompressed: 30}},
+		1,
+	)
+
+	var want []byte
+	want = append(want, 0x15, 0x02)                              // 1: version = 1
+	want = append(want, 0x19, 0x2c)                              // 2: schema, list of 2 structs
+	want = append(want, 0x48, 0x06)                              //   root 4: name
+	want = append(want, "schema"...)                             //
+	want = append(want, 0x15, 0x02, 0x00)                        //   root 5: num_children = 1
+	want = append(want, 0x15, 0x04, 0x25, 0x00)                  //   n 1: type = INT64, 3: repetition = REQUIRED
+	want = append(want, 0x18, 0x01, 'n', 0x00)                   //   n 4: name
+	want = append(want, 0x16, 0x02)                              // 3: num_rows = 1
+	want = append(want, 0x19, 0x1c)                              // 4: row_groups, list of 1 struct
+	want = append(want, 0x19, 0x1c)                              //   1: columns, list of 1 struct
+	want = append(want, 0x26, 0x08, 0x1c)                        //     2: file_offset = 4, 3: meta_data
+	want = append(want, 0x15, 0x04)                              //       1: type = INT64
+	want = append(want, 0x19, 0x25, 0x00, 0x06)                  //       2: encodings = [PLAIN, RLE]
+	want = append(want, 0x19, 0x18, 0x01, 'n')                   //       3: path_in_schema = [n]
+	want = append(want, 0x15, 0x04, 0x16, 0x02)                  //       4: codec = GZIP, 5: num_values = 1
+	want = append(want, 0x16, 0x3c, 0x16, 0x50)                  //       6: uncompressed = 30, 7: compressed = 40
+	want = append(want, 0x26, 0x08, 0x00, 0x00)                  //       9: data_page_offset = 4
+	want = append(want, 0x16, 0x3c, 0x16, 0x02, 0x00)            //   2: total_byte_size = 30, 3: num_rows = 1
+	want = append(want, 0x28, 0x22)                              // 6: created_by
+	want = append(want, "distributed-payments-ledger-system"...) //
+	want = append(want, 0x00)
+
+	if !bytes.Equal(footer, want) {
+		t.Fatalf("footer =\n%x\nwant\n%x", footer, want)
+	}
+}
+
+func TestWriteDecodesPerSpecification(t *testing.T) {
+	ids := []string{"e-1", "e-2", "e-3"}
+	seqs := []int64{1, -2, 1 << 40}
+	amounts := []decimal.Decimal{
+		decimal.RequireFromString("100.5"),
+		decimal.RequireFromString("-0.00000001"),
+		decimal.RequireFromString("-128"),
+	}
+	createdAt := []time.Time{
+		time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 12, 0, 0, 1000, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+	metadata := []string{`{}`, `{"order":"42"}`, `{"a":[1,2]}`}
+
+	var buf bytes.Buffer
+	if err := Write(&buf,
+		StringColumn("id", ids),
+		Int64Column("seq", seqs),
+		DecimalColumn("amount", amounts),
+		TimestampColumn("created_at", createdAt),
+		JSONColumn("metadata", metadata),
+	); err != nil {
+		t.Fatal(err)
+	}
+	file := buf.Bytes()
+
+	if string(file[:4]) != "PAR1" || string(file[len(file)-4:]) != "PAR1" {
+		t.Fatal("file is not framed by PAR1")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thriftReader{t: t, data: file[len(file)-8-footerLen : len(file)-8]}
+	meta := footer.structValue()
+	if footer.pos != footerLen {
+		t.Fatalf("footer decoded %d of %d bytes", footer.pos, footerLen)
+	}
+	if meta.int(fileMetaNumRows) != 3 {
+		t.Fatalf("num_rows = %d, want 3", meta.int(fileMetaNumRows))
+	}
+
+	schema := meta.list(fileMetaSchema)
+	if len(schema) != 6 || schema[0].(thriftStructValue).int(schemaNumChildren) != 5 {
+		t.Fatalf("schema = %v, want a root with 5 columns", schema)
+	}
+	wantSchema := []struct {
+		name      string
+		physical  int64
+		converted int64
+	}{
+		// Enum values as numbered in parquet.thrift; -1 means no converted type
+		{"id", 6, 0},          // BYTE_ARRAY, UTF8
+		{"seq", 2, -1},        // INT64
+		{"amount", 6, 5},      // BYTE_ARRAY, DECIMAL
+		{"created_at", 2, 10}, // INT64, TIMESTAMP_MICROS
+		{"metadata", 6, 19},   // BYTE_ARRAY, JSON
+	}
+	for i, want := range wantSchema {
+		element := schema[i+1].(thriftStructValue)
+		converted, ok := element[schemaConvertedType].(int64)
+		if !ok {
+			converted = -1
+		}
+		if element[schemaName] != want.name || element.int(schemaType) != want.physical || converted != want.converted {
+			t.Errorf("schema[%d] = %v, want %+v", i+1, element, want)
+		}
+	}
+	if amount := schema[3].(thriftStructValue); amount.int(schemaScale) != 8 || amount.int(schemaPrecision) != 20 {
+		t.Errorf("amount is DECIMAL(%d,%d)", amount.int(schemaPrecision), amount.int(schemaScale))
+	}
+
+	rowGroups := meta.list(fileMetaRowGroups)
+	if len(rowGroups) != 1 {
+		t.Fatalf("%d row groups, want 1", len(rowGroups))
+	}
+	columns := rowGroups[0].(thriftStructValue).list(rowGroupColumns)
+	if len(columns) != len(wantSchema) {
+		t.Fatalf("%d column chunks, want %d", len(columns), len(wantSchema))
+	}
+
+	// values reads the PLAIN values of one column from its data page
+	values := func(i int) []byte {
+		t.Helper()
+		chunkMeta := columns[i].(thriftStructValue)[chunkMetaData].(thriftStructValue)
+		if path := chunkMeta.list(metaPath); len(path) != 1 || path[0] != wantSchema[i].name {
+			t.Fatalf("column %d has path %v", i, path)
+		}
+		if chunkMeta.int(metaCodec) != 2 || chunkMeta.int(metaNumValues) != 3 || chunkMeta.int(metaType) != wantSchema[i].physical {
+			t.Fatalf("column %s meta_data = %v, want GZIP with 3 values", wantSchema[i].name, chunkMeta)
+		}
+
+		offset := chunkMeta.int(metaDataPageOffset)
+		r := &thriftReader{t: t, data: file, pos: int(offset)}
+		header := r.structValue()
+		page := header[pageDataPageHeader].(thriftStructValue)
+		// DATA_PAGE with PLAIN values and RLE levels
+		if header.int(pageHeaderType) != 0 || page.int(dataPageNumValues) != 3 || page.int(dataPageEncoding) != 0 ||
+			page.int(dataPageDefEncoding) != 3 || page.int(dataPageRepEncoding) != 3 {
+			t.Fatalf("column %s page header = %v", wantSchema[i].name, header)
+		}
+
+		compressed := header.int(pageCompressed)
+		headerLen := int64(r.pos) - offset
+		if chunkMeta.int(metaCompressed) != headerLen+compressed {
+			t.Errorf("column %s total_compressed_size = %d, want %d", wantSchema[i].name, chunkMeta.int(metaCompressed), headerLen+compressed)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(file[r.pos : int64(r.pos)+compressed]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(plain)) != header.int(pageUncompressed) || chunkMeta.int(metaUncompressed) != headerLen+int64(len(plain)) {
+			t.Errorf("column %s uncompressed sizes do not match %d bytes of values", wantSchema[i].name, len(plain))
+		}
+		return plain
+	}
+	byteArrays := func(plain []byte) [][]byte {
+		var out [][]byte
+		for len(plain) > 0 {
+			n := binary.LittleEndian.Uint32(plain)
+			out = append(out, plain[4:4+n])
+			plain = plain[4+n:]
+		}
+		return out
+	}
+	int64s := func(plain []byte) []int64 {
+		var out []int64
+		for ; len(plain) > 0; plain = plain[8:] {
+			out = append(out, int64(binary.LittleEndian.Uint64(plain)))
+		}
+		return out
+	}
+
+	for i, value := range byteArrays(values(0)) {
+		if string(value) != ids[i] {
+			t.Errorf("id[%d] = %q, want %q", i, value, ids[i])
+		}
+	}
+	for i, value := range int64s(values(1)) {
+		if value != seqs[i] {
+			t.Errorf("seq[%d] = %d, want %d", i, value, seqs[i])
+		}
+	}
+	for i, value := range byteArrays(values(2)) {
+		// Big-endian two's complement: a set high bit means subtracting 2^(8n)
+		unscaled := new(big.Int).SetBytes(value)
+		if len(value) > 0 && value[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(value))))
+		}
+		if got := decimal.NewFromBigInt(unscaled, -8); !got.Equal(amounts[i]) {
+			t.Errorf("amount[%d] = %s, want %s", i, got, amounts[i])
+		}
+	}
+	for i, value := range int64s(values(3)) {
+		if got := time.UnixMicro(value).UTC(); !got.Equal(createdAt[i]) {
+			t.Errorf("created_at[%d] = %s, want %s", i, got, createdAt[i])
+		}
+	}
+	for i, value := range byteArrays(values(4)) {
+		if string(value) != metadata[i] {
+			t.Errorf("metadata[%d] = %q, want %q", i, value, metadata[i])
+		}
+	}
+}
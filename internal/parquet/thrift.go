@@ -0,0 +1,97 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type IDs
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift compact protocol, which Parquet uses for page
+// headers and the file footer. Only the types those structures need are covered.
+type thriftWriter struct {
+	buf bytes.Buffer
+
+	// Field IDs are written as deltas from the previous field of the same struct
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendVarint(nil, v)) // zigzag, as the compact protocol expects
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) string(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+// list writes a list header; the caller then writes size elements of elemType
+func (t *thriftWriter) list(id int16, elemType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.buf.WriteByte(0xf0 | elemType)
+	t.uvarint(uint64(size))
+}
+
+// beginStruct opens a struct field; id 0 opens a list element instead
+func (t *thriftWriter) beginStruct(id int16) {
+	if id != 0 {
+		t.field(id, thriftStruct)
+	}
+	t.lastField = append(t.lastField, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0) // stop field
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// Elements of lists of i32 and strings carry no field header
+func (t *thriftWriter) i32Element(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) stringElement(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
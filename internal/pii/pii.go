@@ -0,0 +1,147 @@
+// Package pii encrypts personal data field by field with AES-256-GCM, so names and emails
+// are never written to the database in plaintext.
+//
+// A Keyring holds every key that may still be needed to read old values; new values are
+// always sealed with the current one. Rotating means adding a new current key in front,
+// letting the store re-encrypt what was sealed with the older keys, then dropping them.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrNoKey      = errors.New("no PII encryption key configured")
+	ErrUnknownKey = errors.New("value sealed with an unknown PII key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// prefix marks a sealed value: enc:v1:<key id>:<base64 nonce+ciphertext>
+const prefix = "enc:v1:"
+
+// Keyring seals and opens field values. The zero value and nil hold no key: they open
+// nothing and refuse to seal anything but the empty string.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeys reads "id:base64key,id:base64key"; the first key is the current one. Keys
+// are 32 bytes (AES-256), e.g. from `openssl rand -base64 32` or a KMS data key.
+func ParseKeys(spec string) (*Keyring, error) {
+	k := &Keyring{keys: map[string]cipher.AEAD{}}
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(item, ":")
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("key %q must be written as id:base64key", item)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("key id %q appears twice", id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("key %q is %d bytes, want 32", id, len(raw))
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+		if k.current == "" {
+			k.current = id
+		}
+	}
+	if k.current == "" {
+		return nil, ErrNoKey
+	}
+	return k, nil
+}
+
+// KeyringFromEnv reads PII_ENCRYPTION_KEYS; it returns nil without an error when it is unset
+func KeyringFromEnv() (*Keyring, error) {
+	spec := os.Getenv("PII_ENCRYPTION_KEYS")
+	if spec == "" {
+		return nil, nil
+	}
+	return ParseKeys(spec)
+}
+
+// Current is the ID of the key new values are sealed with
+func (k *Keyring) Current() string {
+	if k == nil {
+		return ""
+	}
+	return k.current
+}
+
+// CurrentPrefix is how every value sealed with the current key starts
+func (k *Keyring) CurrentPrefix() string {
+	return prefix + k.Current() + ":"
+}
+
+// Seal encrypts a value. The binding - typically the record ID and field name - is
+// authenticated but not stored, so a sealed value copied to another row fails to open.
+func (k *Keyring) Seal(plaintext, binding string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	if k == nil || k.current == "" {
+		return "", ErrNoKey
+	}
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(binding))
+	return prefix + k.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value produced by Seal with the same binding
+func (k *Keyring) Open(value, binding string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", ErrMalformed
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	if k == nil {
+		return "", ErrNoKey
+	}
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(binding))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,234 @@
+// Package proofs signs Merkle checkpoints over the entry hash chain and answers inclusion
+// proofs against them, so a third party holding only the public key can check that an
+// entry is part of the ledger without access to the database.
+package proofs
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/merkle"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrEntryNotFound      = errors.New("entry not found")
+	ErrNotCheckpointed    = errors.New("entry is not covered by a checkpoint yet")
+	ErrProofUnavailable   = errors.New("entries of the checkpoint are no longer all in the database")
+	ErrCheckpointMismatch = errors.New("entries no longer match their checkpoint")
+)
+
+var (
+	checkpointsMade = metrics.NewCounter("ledger_checkpoints_total", "Signed Merkle checkpoints made")
+	checkpointedSeq = metrics.NewGauge("ledger_checkpoint_last_seq", "Sequence of the newest entry covered by a checkpoint")
+)
+
+// KeyFromEnv reads CHECKPOINT_SIGNING_KEY, a base64 32-byte Ed25519 seed (e.g. from
+// `openssl rand -base64 32`); it returns nil without an error when it is unset
+func KeyFromEnv() (ed25519.PrivateKey, error) {
+	encoded := os.Getenv("CHECKPOINT_SIGNING_KEY")
+	if encoded == "" {
+		return nil, nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("CHECKPOINT_SIGNING_KEY: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("CHECKPOINT_SIGNING_KEY is %d bytes, want %d", len(seed), ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// Checkpointer makes the checkpoints and the proofs against them
+type Checkpointer struct {
+	store      interfaces.CheckpointStore
+	key        ed25519.PrivateKey
+	keyID      string
+	public     string // base64
+	maxEntries int    // per checkpoint, which bounds the entries read to answer one proof
+	clock      clock.Clock
+	appLogger  *slog.Logger
+
+	mu sync.Mutex
+	// horizon is the newest sequence seen on the previous run. Sequence numbers are taken
+	// before commit, so a newer entry may still be missing below the current maximum; by
+	// the next run every entry up to the old one has committed or rolled back.
+	horizon int64
+}
+
+func NewCheckpointer(store interfaces.CheckpointStore, key ed25519.PrivateKey, maxEntries int, clk clock.Clock, appLogger *slog.Logger) *Checkpointer {
+	public := key.Public().(ed25519.PublicKey)
+	id := sha256.Sum256(public)
+	return &Checkpointer{
+		store:      store,
+		key:        key,
+		keyID:      hex.EncodeToString(id[:8]),
+		public:     base64.StdEncoding.EncodeToString(public),
+		maxEntries: max(maxEntries, 1),
+		clock:      clk,
+		appLogger:  appLogger,
+	}
+}
+
+// PublicKey describes the key checkpoints are verified with
+func (c *Checkpointer) PublicKey() map[string]string {
+	return map[string]string{
+		"algorithm":  "ed25519",
+		"key_id":     c.keyID,
+		"public_key": c.public,
+	}
+}
+
+// Run checkpoints every entry up to the horizon, in checkpoints of at most maxEntries.
+// The first run after a start only sets the horizon.
+func (c *Checkpointer) Run(ctx context.Context) ([]models.Checkpoint, error) {
+	newest, err := c.store.MaxEntrySeq(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	horizon := c.horizon
+	c.horizon = newest
+	c.mu.Unlock()
+
+	made := []models.Checkpoint{}
+	for horizon > 0 {
+		last, err := c.store.LastCheckpoint(ctx)
+		if err != nil {
+			return made, err
+		}
+		checkpoint := models.Checkpoint{FromSeq: 1, KeyID: c.keyID}
+		if last != nil {
+			checkpoint.FromSeq, checkpoint.PrevRoot = last.ToSeq+1, last.Root
+		}
+		if checkpoint.FromSeq > horizon {
+			break
+		}
+
+		entries, err := c.store.EntriesBetween(ctx, checkpoint.FromSeq, horizon, c.maxEntries)
+		if err != nil {
+			return made, err
+		}
+		if len(entries) == 0 {
+			break // only sequence gaps left
+		}
+		checkpoint.ToSeq = horizon
+		if len(entries) == c.maxEntries {
+			checkpoint.ToSeq = entries[len(entries)-1].Sequence
+		}
+		checkpoint.Size = len(entries)
+		checkpoint.Root = hex.EncodeToString(merkle.Root(leaves(entries)))
+		// Postgres keeps microseconds; the signed payload must survive the round trip
+		checkpoint.CreatedAt = c.clock.Now().UTC().Truncate(time.Microsecond)
+		checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(c.key, []byte(checkpoint.SignedPayload())))
+
+		saved, err := c.store.SaveCheckpoint(ctx, &checkpoint)
+		if err != nil {
+			return made, err
+		}
+		if !saved {
+			break // another instance checkpointed this range first
+		}
+		checkpointsMade.Inc()
+		checkpointedSeq.Set(float64(checkpoint.ToSeq))
+		made = append(made, checkpoint)
+		c.appLogger.Info("ledger checkpoint signed",
+			"checkpoint_id", checkpoint.ID,
+			"from_seq", checkpoint.FromSeq,
+			"to_seq", checkpoint.ToSeq,
+			"entries", checkpoint.Size,
+			"root", checkpoint.Root,
+		)
+	}
+	return made, nil
+}
+
+// List returns the newest checkpoints first
+func (c *Checkpointer) List(ctx context.Context, limit int) ([]models.Checkpoint, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	return c.store.ListCheckpoints(ctx, limit)
+}
+
+// Proof builds the inclusion proof of an entry. A tenant only gets proofs of its own entries.
+func (c *Checkpointer) Proof(ctx context.Context, entryId string) (models.InclusionProof, error) {
+	entry, err := c.store.GetEntry(ctx, entryId)
+	if err != nil {
+		return models.InclusionProof{}, err
+	}
+	if tenantId := tenant.FromContext(ctx); entry == nil || (tenantId != "" && entry.TenantID != tenantId) {
+		return models.InclusionProof{}, fmt.Errorf("%w: %s", ErrEntryNotFound, entryId)
+	}
+	checkpoint, err := c.store.CheckpointCovering(ctx, entry.Sequence)
+	if err != nil {
+		return models.InclusionProof{}, err
+	}
+	if checkpoint == nil {
+		return models.InclusionProof{}, fmt.Errorf("%w: %s", ErrNotCheckpointed, entryId)
+	}
+
+	entries, err := c.store.EntriesBetween(ctx, checkpoint.FromSeq, checkpoint.ToSeq, checkpoint.Size+1)
+	if err != nil {
+		return models.InclusionProof{}, err
+	}
+	if len(entries) != checkpoint.Size {
+		return models.InclusionProof{}, fmt.Errorf("%w: checkpoint %d", ErrProofUnavailable, checkpoint.ID)
+	}
+	hashes := leaves(entries)
+	if hex.EncodeToString(merkle.Root(hashes)) != checkpoint.Root {
+		c.appLogger.Error("ALERT: ledger entries no longer match their signed checkpoint",
+			"checkpoint_id", checkpoint.ID,
+			"from_seq", checkpoint.FromSeq,
+			"to_seq", checkpoint.ToSeq,
+		)
+		return models.InclusionProof{}, fmt.Errorf("%w: checkpoint %d", ErrCheckpointMismatch, checkpoint.ID)
+	}
+
+	index := -1
+	for i, e := range entries {
+		if e.ID == entry.ID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return models.InclusionProof{}, fmt.Errorf("%w: %s", ErrEntryNotFound, entryId)
+	}
+	path := []string{}
+	for _, sibling := range merkle.Proof(hashes, index) {
+		path = append(path, hex.EncodeToString(sibling))
+	}
+	return models.InclusionProof{
+		Entry:         *entry,
+		LeafIndex:     index,
+		TreeSize:      len(entries),
+		LeafHash:      hex.EncodeToString(hashes[index]),
+		AuditPath:     path,
+		Checkpoint:    *checkpoint,
+		SignedPayload: checkpoint.SignedPayload(),
+		PublicKey:     c.public,
+	}, nil
+}
+
+func leaves(entries []models.LedgerEntry) [][]byte {
+	hashes := make([][]byte, len(entries))
+	for i, entry := range entries {
+		hashes[i] = merkle.LeafHash([]byte(entry.Hash))
+	}
+	return hashes
+}
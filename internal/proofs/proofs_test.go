@@ -0,0 +1,167 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/merkle"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+// checkpointStore keeps entries and checkpoints in memory
+type checkpointStore struct {
+	entries     []models.LedgerEntry
+	checkpoints []models.Checkpoint
+}
+
+func (s *checkpointStore) LastCheckpoint(ctx context.Context) (*models.Checkpoint, error) {
+	if len(s.checkpoints) == 0 {
+		return nil, nil
+	}
+	last := s.checkpoints[len(s.checkpoints)-1]
+	return &last, nil
+}
+
+func (s *checkpointStore) SaveCheckpoint(ctx context.Context, checkpoint *models.Checkpoint) (bool, error) {
+	checkpoint.ID = int64(len(s.checkpoints) + 1)
+	s.checkpoints = append(s.checkpoints, *checkpoint)
+	return true, nil
+}
+
+func (s *checkpointStore) CheckpointCovering(ctx context.Context, seq int64) (*models.Checkpoint, error) {
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.FromSeq <= seq && seq <= checkpoint.ToSeq {
+			return &checkpoint, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *checkpointStore) ListCheckpoints(ctx context.Context, limit int) ([]models.Checkpoint, error) {
+	return s.checkpoints, nil
+}
+
+func (s *checkpointStore) MaxEntrySeq(ctx context.Context) (int64, error) {
+	if len(s.entries) == 0 {
+		return 0, nil
+	}
+	return s.entries[len(s.entries)-1].Sequence, nil
+}
+
+func (s *checkpointStore) EntriesBetween(ctx context.Context, from, to int64, limit int) ([]models.LedgerEntry, error) {
+	var out []models.LedgerEntry
+	for _, entry := range s.entries {
+		if from <= entry.Sequence && entry.Sequence <= to && len(out) < limit {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+func (s *checkpointStore) GetEntry(ctx context.Context, id string) (*models.LedgerEntry, error) {
+	for _, entry := range s.entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func sum(parts ...[]byte) []byte {
+	h := sha256.Sum256(bytes.Join(parts, nil))
+	return h[:]
+}
+
+func TestProofVerifiesAgainstSignedCheckpoint(t *testing.T) {
+	store := &checkpointStore{}
+	for i, hash := range []string{"h1", "h2", "h3", "h4", "h5"} {
+		store.entries = append(store.entries, models.LedgerEntry{
+			ID:       "e-" + hash[1:],
+			Sequence: int64(i + 1),
+			TenantID: "acme",
+			Hash:     hash,
+		})
+	}
+	store.entries[4].TenantID = "globex"
+
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	key := ed25519.NewKeyFromSeed(seed)
+	checkpointer := NewCheckpointer(store, key, 3, clock.NewFixed(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx := context.Background()
+	// The first run only sets the horizon
+	if made, err := checkpointer.Run(ctx); err != nil || len(made) != 0 {
+		t.Fatalf("first run made %d checkpoints, err %v", len(made), err)
+	}
+	made, err := checkpointer.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(made) != 2 || made[0].ToSeq != 3 || made[1].FromSeq != 4 || made[1].Size != 2 {
+		t.Fatalf("checkpoints = %+v, want 1-3 and 4-5", made)
+	}
+
+	// The first checkpoint covers h1, h2 and h3: node(node(leaf(h1), leaf(h2)), leaf(h3))
+	leaf := func(hash string) []byte { return sum([]byte{0}, []byte(hash)) }
+	node := func(left, right []byte) []byte { return sum([]byte{1}, left, right) }
+	root := node(node(leaf("h1"), leaf("h2")), leaf("h3"))
+	if made[0].Root != hex.EncodeToString(root) {
+		t.Fatalf("checkpoint root = %s, want %x", made[0].Root, root)
+	}
+	if made[1].PrevRoot != made[0].Root {
+		t.Errorf("second checkpoint chains to %s, want %s", made[1].PrevRoot, made[0].Root)
+	}
+
+	proof, err := checkpointer.Proof(tenant.WithTenant(ctx, "acme"), "e-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.LeafIndex != 1 || proof.TreeSize != 3 || proof.LeafHash != hex.EncodeToString(leaf("h2")) {
+		t.Fatalf("proof = %+v", proof)
+	}
+
+	// Verify as a third party would, from the proof alone
+	path := make([][]byte, len(proof.AuditPath))
+	for i, sibling := range proof.AuditPath {
+		if path[i], err = hex.DecodeString(sibling); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := [][]byte{leaf("h1"), leaf("h3")}; len(path) != 2 || !bytes.Equal(path[0], want[0]) || !bytes.Equal(path[1], want[1]) {
+		t.Errorf("audit path = %x, want %x", path, want)
+	}
+	if !merkle.Verify(leaf(proof.Entry.Hash), proof.LeafIndex, proof.TreeSize, path, root) {
+		t.Error("audit path does not lead to the checkpoint root")
+	}
+	public, err := base64.StdEncoding.DecodeString(proof.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(proof.Checkpoint.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(public, key.Public().(ed25519.PublicKey)) || !ed25519.Verify(public, []byte(proof.SignedPayload), signature) {
+		t.Error("checkpoint signature does not verify")
+	}
+
+	// Another tenant's entry is not found, and a changed entry no longer matches its checkpoint
+	if _, err := checkpointer.Proof(tenant.WithTenant(ctx, "acme"), "e-5"); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("proof of another tenant's entry = %v, want %v", err, ErrEntryNotFound)
+	}
+	store.entries[0].Hash = "forged"
+	if _, err := checkpointer.Proof(ctx, "e-2"); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Errorf("proof after a change = %v, want %v", err, ErrCheckpointMismatch)
+	}
+}
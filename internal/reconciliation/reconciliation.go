@@ -0,0 +1,193 @@
+package reconciliation
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Rules control how statement lines are matched to ledger entries
+type Rules struct {
+	DateWindow       time.Duration   // max distance between statement date and entry date
+	AmountTolerance  decimal.Decimal // max absolute difference between amounts
+	RequireReference bool            // only match when the reference identifies the entry
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		DateWindow:      72 * time.Hour,
+		AmountTolerance: decimal.Zero,
+	}
+}
+
+// Match pairs a statement line with the ledger entry it was reconciled against
+type Match struct {
+	Statement StatementLine      `json:"statement"`
+	Entry     models.LedgerEntry `json:"entry"`
+	MatchedBy string             `json:"matched_by"` // "reference" or "amount_date"
+}
+
+// Report is the outcome of reconciling one statement against one account
+type Report struct {
+	AccountID         string               `json:"account_id"`
+	From              time.Time            `json:"from"`
+	To                time.Time            `json:"to"`
+	Matched           []Match              `json:"matched"`
+	UnmatchedInternal []models.LedgerEntry `json:"unmatched_internal"` // in the ledger, not on the statement
+	UnmatchedExternal []StatementLine      `json:"unmatched_external"` // on the statement, not in the ledger
+	ReconciledAt      time.Time            `json:"reconciled_at"`
+}
+
+// Service reconciles external statements against ledger entries
+type Service struct {
+	store     interfaces.LedgerStore
+	clock     clock.Clock
+	appLogger *slog.Logger
+}
+
+func NewService(store interfaces.LedgerStore, clk clock.Clock, appLogger *slog.Logger) *Service {
+	return &Service{
+		store:     store,
+		clock:     clk,
+		appLogger: appLogger,
+	}
+}
+
+// Reconcile matches the statement lines against the account's entries in the statement period.
+// Reference matches are made first, then the remaining lines are paired by amount and closest date.
+func (s *Service) Reconcile(ctx context.Context, accountId string, lines []StatementLine, rules Rules) (Report, error) {
+	report := Report{
+		AccountID:         accountId,
+		Matched:           []Match{},
+		UnmatchedInternal: []models.LedgerEntry{},
+		UnmatchedExternal: []StatementLine{},
+		ReconciledAt:      s.clock.Now(),
+	}
+	if len(lines) == 0 {
+		return report, nil
+	}
+
+	report.From, report.To = lines[0].Date, lines[0].Date
+	for _, line := range lines {
+		if line.Date.Before(report.From) {
+			report.From = line.Date
+		}
+		if line.Date.After(report.To) {
+			report.To = line.Date
+		}
+	}
+
+	allEntries, err := s.store.GetEntriesByAccount(accountId)
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Statement dates are whole days, so the window is measured from the end of the last day
+	from := report.From.Add(-rules.DateWindow)
+	to := report.To.Add(24*time.Hour + rules.DateWindow)
+	var entries []models.LedgerEntry
+	for _, entry := range allEntries {
+		if !entry.CreatedAt.Before(from) && entry.CreatedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+
+	used := make([]bool, len(entries))
+	unmatched := make([]StatementLine, 0, len(lines))
+
+	// Pass 1: reference identifies the entry or its transaction
+	for _, line := range lines {
+		i := s.findByReference(line, entries, used, rules)
+		if i < 0 {
+			unmatched = append(unmatched, line)
+			continue
+		}
+		used[i] = true
+		report.Matched = append(report.Matched, Match{Statement: line, Entry: entries[i], MatchedBy: "reference"})
+	}
+
+	// Pass 2: amount within tolerance and closest date within the window
+	for _, line := range unmatched {
+		i := -1
+		if !rules.RequireReference {
+			i = s.findByAmountAndDate(line, entries, used, rules)
+		}
+		if i < 0 {
+			report.UnmatchedExternal = append(report.UnmatchedExternal, line)
+			continue
+		}
+		used[i] = true
+		report.Matched = append(report.Matched, Match{Statement: line, Entry: entries[i], MatchedBy: "amount_date"})
+	}
+
+	for i, entry := range entries {
+		if !used[i] {
+			report.UnmatchedInternal = append(report.UnmatchedInternal, entry)
+		}
+	}
+
+	s.appLogger.Info("reconciliation completed",
+		"account_id", accountId,
+		"matched", len(report.Matched),
+		"unmatched_internal", len(report.UnmatchedInternal),
+		"unmatched_external", len(report.UnmatchedExternal),
+	)
+	return report, nil
+}
+
+func (s *Service) findByReference(line StatementLine, entries []models.LedgerEntry, used []bool, rules Rules) int {
+	if line.Reference == "" {
+		return -1
+	}
+	for i, entry := range entries {
+		if used[i] || !amountMatches(line.Amount, entry.Amount, rules) {
+			continue
+		}
+		if strings.EqualFold(line.Reference, entry.TransactionID) || strings.EqualFold(line.Reference, entry.ID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Service) findByAmountAndDate(line StatementLine, entries []models.LedgerEntry, used []bool, rules Rules) int {
+	best := -1
+	var bestDistance time.Duration
+	for i, entry := range entries {
+		if used[i] || !amountMatches(line.Amount, entry.Amount, rules) {
+			continue
+		}
+		distance := dateDistance(line.Date, entry.CreatedAt)
+		if distance > rules.DateWindow {
+			continue
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
+
+func amountMatches(statement, entry decimal.Decimal, rules Rules) bool {
+	return statement.Sub(entry).Abs().Cmp(rules.AmountTolerance) <= 0
+}
+
+// dateDistance is zero when the entry falls on the statement day
+func dateDistance(statementDay, at time.Time) time.Duration {
+	start := statementDay
+	end := statementDay.Add(24 * time.Hour)
+	switch {
+	case at.Before(start):
+		return start.Sub(at)
+	case at.Before(end):
+		return 0
+	default:
+		return at.Sub(end)
+	}
+}
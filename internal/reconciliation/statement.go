@@ -0,0 +1,214 @@
+package reconciliation
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatementLine is one movement reported by the external bank statement.
+// Amount is signed from the account holder's point of view: credits positive, debits negative.
+type StatementLine struct {
+	Line        int             `json:"line"` // position in the source file, for operators
+	Date        time.Time       `json:"date"`
+	Amount      decimal.Decimal `json:"amount"`
+	Reference   string          `json:"reference"`
+	Description string          `json:"description,omitempty"`
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported statement format")
+
+// ParseStatement reads a statement file in the given format ("csv" or "mt940")
+func ParseStatement(format string, r io.Reader) ([]StatementLine, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return parseCSV(r)
+	case "mt940":
+		return parseMT940(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// parseCSV expects a header row with at least "date" and "amount" columns;
+// "reference" and "description" are optional.
+func parseCSV(r io.Reader) ([]StatementLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"date", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header is missing the %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var lines []StatementLine
+	for lineNo := 2; ; lineNo++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		date, err := parseDate(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		amount, err := decimal.NewFromString(field(record, "amount"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount: %w", lineNo, err)
+		}
+
+		lines = append(lines, StatementLine{
+			Line:        lineNo,
+			Date:        date,
+			Amount:      amount,
+			Reference:   field(record, "reference"),
+			Description: field(record, "description"),
+		})
+	}
+	return lines, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "02/01/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseMT940 reads the :61: statement lines (and their :86: narratives) of an MT940 file.
+// Balances and header tags are ignored; only movements take part in matching.
+func parseMT940(r io.Reader) ([]StatementLine, error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []StatementLine
+	inNarrative := false
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case strings.HasPrefix(text, ":61:"):
+			line, err := parseMT940Line(text[4:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			line.Line = lineNo
+			lines = append(lines, line)
+			inNarrative = false
+		case strings.HasPrefix(text, ":86:") && len(lines) > 0:
+			lines[len(lines)-1].Description = strings.TrimSpace(text[4:])
+			inNarrative = true
+		case strings.HasPrefix(text, ":"), strings.HasPrefix(text, "-}"):
+			inNarrative = false
+		case inNarrative:
+			// :86: narratives may continue over several lines
+			lines[len(lines)-1].Description += " " + strings.TrimSpace(text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseMT940Line parses the body of a :61: tag, e.g. "2310011001C100,00NTRFREF123//BANK456"
+func parseMT940Line(body string) (StatementLine, error) {
+	if len(body) < 6 {
+		return StatementLine{}, errors.New("statement line too short")
+	}
+	date, err := time.Parse("060102", body[:6])
+	if err != nil {
+		return StatementLine{}, fmt.Errorf("invalid value date: %w", err)
+	}
+	rest := body[6:]
+
+	// Optional 4-digit entry date
+	if len(rest) >= 4 && isDigits(rest[:4]) {
+		rest = rest[4:]
+	}
+
+	negative := false
+	switch {
+	case strings.HasPrefix(rest, "RC"):
+		negative, rest = true, rest[2:] // reversal of credit
+	case strings.HasPrefix(rest, "RD"):
+		rest = rest[2:] // reversal of debit
+	case strings.HasPrefix(rest, "D"):
+		negative, rest = true, rest[1:]
+	case strings.HasPrefix(rest, "C"):
+		rest = rest[1:]
+	default:
+		return StatementLine{}, errors.New("missing debit/credit mark")
+	}
+
+	// Optional funds code (third character of the currency)
+	if len(rest) > 0 && rest[0] >= 'A' && rest[0] <= 'Z' {
+		rest = rest[1:]
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != ',' })
+	if end <= 0 {
+		return StatementLine{}, errors.New("missing amount")
+	}
+	amount, err := decimal.NewFromString(strings.Replace(rest[:end], ",", ".", 1))
+	if err != nil {
+		return StatementLine{}, fmt.Errorf("invalid amount: %w", err)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	rest = rest[end:]
+
+	// Transaction type identification code: one letter plus three characters
+	if len(rest) >= 4 {
+		rest = rest[4:]
+	}
+	reference := rest
+	if i := strings.Index(rest, "//"); i >= 0 {
+		reference = rest[:i]
+	}
+	if reference == "NONREF" {
+		reference = ""
+	}
+
+	return StatementLine{
+		Date:      date,
+		Amount:    amount,
+		Reference: strings.TrimSpace(reference),
+	}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package reconciliation
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/events/kafka"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// TransactionsTopic carries one event per posted transaction
+const TransactionsTopic = "transactions.completed"
+
+const (
+	maxReportedIDs = 1000 // per kind of mismatch
+	lookupBatch    = 1000 // IDs per MissingTransactions call
+)
+
+var (
+	streamMismatches = metrics.NewGaugeVec("stream_reconciliation_mismatches",
+		"Mismatches between transaction events and rows found by the last stream check", "kind")
+	streamChecks = metrics.NewCounterVec("stream_reconciliation_runs_total",
+		"Stream checks by outcome", "outcome")
+)
+
+// StreamChecker replays the transaction events and compares them with the transactions
+// table, closing the loop on at-least-once publishing: a row without an event is an event
+// lost between the commit and the broker, an event without a row is one published for a
+// posting that never committed.
+type StreamChecker struct {
+	store     interfaces.StreamReconciliationStore
+	brokers   []string
+	appLogger *slog.Logger
+}
+
+func NewStreamChecker(store interfaces.StreamReconciliationStore, brokers []string, appLogger *slog.Logger) *StreamChecker {
+	return &StreamChecker{
+		store:     store,
+		brokers:   brokers,
+		appLogger: appLogger,
+	}
+}
+
+// Check compares the events published and the rows created in [from, to); a zero from
+// starts at the beginning of the topic, a zero to is now. Events are read past to, so the
+// event of a row created just before it still counts, and an event whose row was created
+// before from is looked up rather than reported.
+func (c *StreamChecker) Check(ctx context.Context, from, to time.Time) (models.StreamReconciliation, error) {
+	now := time.Now().UTC()
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	report := models.StreamReconciliation{
+		Topic:                TransactionsTopic,
+		From:                 from,
+		To:                   to,
+		EventsWithoutRowsIDs: []string{},
+		RowsWithoutEventsIDs: []string{},
+	}
+
+	seen := map[string]bool{}
+	var published []string
+	err := kafka.Replay(ctx, c.brokers, TransactionsTopic, from, func(message kafkago.Message) error {
+		inRange := message.Time.Before(to)
+		id, err := kafka.TransactionID(message)
+		switch {
+		case err != nil || id == "":
+			if inRange {
+				report.Undecodable++
+			}
+		case seen[id]:
+			if inRange {
+				report.Duplicates++
+			}
+		default:
+			seen[id] = true
+			if inRange {
+				report.Events++
+				published = append(published, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		streamChecks.With("error").Inc()
+		return models.StreamReconciliation{}, err
+	}
+
+	rows, err := c.store.TransactionIDsBetween(ctx, from, to)
+	if err != nil {
+		streamChecks.With("error").Inc()
+		return models.StreamReconciliation{}, err
+	}
+	report.Transactions = len(rows)
+	created := make(map[string]bool, len(rows))
+	for _, id := range rows {
+		created[id] = true
+		if !seen[id] {
+			report.RowsWithoutEvents++
+			if len(report.RowsWithoutEventsIDs) < maxReportedIDs {
+				report.RowsWithoutEventsIDs = append(report.RowsWithoutEventsIDs, id)
+			}
+		}
+	}
+
+	// Events of rows outside the range are looked up one batch at a time
+	var unknown []string
+	for _, id := range published {
+		if !created[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	for len(unknown) > 0 {
+		batch := unknown[:min(lookupBatch, len(unknown))]
+		unknown = unknown[len(batch):]
+		missing, err := c.store.MissingTransactions(ctx, batch)
+		if err != nil {
+			streamChecks.With("error").Inc()
+			return models.StreamReconciliation{}, err
+		}
+		report.EventsWithoutRows += len(missing)
+		for _, id := range missing {
+			if len(report.EventsWithoutRowsIDs) < maxReportedIDs {
+				report.EventsWithoutRowsIDs = append(report.EventsWithoutRowsIDs, id)
+			}
+		}
+	}
+	report.CheckedAt = time.Now().UTC()
+
+	streamMismatches.With("rows_without_events").Set(float64(report.RowsWithoutEvents))
+	streamMismatches.With("events_without_rows").Set(float64(report.EventsWithoutRows))
+	if report.Consistent() {
+		streamChecks.With("consistent").Inc()
+		c.appLogger.Info("transaction events match the database",
+			"from", from,
+			"to", to,
+			"events", report.Events,
+			"transactions", report.Transactions,
+			"duplicates", report.Duplicates,
+		)
+		return report, nil
+	}
+	streamChecks.With("mismatches").Inc()
+	c.appLogger.Error("ALERT: transaction events and rows disagree",
+		"from", from,
+		"to", to,
+		"rows_without_events", report.RowsWithoutEvents,
+		"events_without_rows", report.EventsWithoutRows,
+		"sample", sample(slices.Concat(report.RowsWithoutEventsIDs, report.EventsWithoutRowsIDs), 10),
+	)
+	return report, nil
+}
+
+func sample(ids []string, n int) []string {
+	return ids[:min(n, len(ids))]
+}
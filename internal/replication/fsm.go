@@ -0,0 +1,46 @@
+package replication
+
+import (
+	"io"
+
+	"github.com/hashicorp/raft"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+)
+
+// fsm applies the committed log to the node's MemoryLedgerStore. Apply's error is handed
+// back to the proposer as the log's response, so a rejected command fails the write on
+// the leader, while every node rejects it alike and stays in step.
+type fsm struct {
+	store *memory.MemoryLedgerStore
+}
+
+func (f *fsm) Apply(log *raft.Log) any {
+	if log.Type != raft.LogCommand {
+		return nil
+	}
+	return f.store.Apply(log.Data)
+}
+
+// Snapshot only copies the store; raft persists the copy off the apply path
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	return &fsmSnapshot{snapshot: f.store.Snapshot()}, nil
+}
+
+func (f *fsm) Restore(snapshot io.ReadCloser) error {
+	defer snapshot.Close()
+	return f.store.Restore(snapshot)
+}
+
+type fsmSnapshot struct {
+	snapshot *memory.Snapshot
+}
+
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := s.snapshot.Persist(sink); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *fsmSnapshot) Release() {}
@@ -0,0 +1,201 @@
+// Package replication binds memory.ReplicatedStore to a Raft group (hashicorp/raft). Each
+// node keeps the log and its snapshots under its own data directory and applies every
+// committed command to its MemoryLedgerStore.
+//
+//	store := memory.NewMemoryLedgerStore()
+//	node, err := replication.Open(replication.Config{ID: "node1", BindAddr: "10.0.0.1:7000", DataDir: "/var/lib/ledger", Peers: peers, Bootstrap: true}, store, appLogger)
+//	ledgerService := ledger.NewLedger(memory.NewReplicatedStore(store, node), appLogger, publisher)
+//
+// The group is fixed by Peers when it is first bootstrapped; nodes are not added or removed
+// at runtime.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/storage/memory"
+)
+
+var ErrInvalidConfig = errors.New("invalid replication config")
+
+// Peer is one voting member of the group
+type Peer struct {
+	ID      string
+	Address string // host:port of its Raft transport
+}
+
+type Config struct {
+	ID       string
+	BindAddr string // host:port the transport listens on; peers must reach it there
+	DataDir  string
+	// Peers lists every member of the group, this node included. Only used to bootstrap
+	// a group that has no state yet.
+	Peers     []Peer
+	Bootstrap bool
+	// ApplyTimeout bounds a write whose context has no deadline; 5s when zero
+	ApplyTimeout time.Duration
+	// A snapshot is taken, and the log before it dropped, once SnapshotThreshold commands
+	// have been applied since the last one; checked every SnapshotInterval. Raft's defaults
+	// (8192 and 2m) when zero.
+	SnapshotThreshold uint64
+	SnapshotInterval  time.Duration
+}
+
+// ParsePeers reads a group from "node1=10.0.0.1:7000,node2=10.0.0.2:7000,..."
+func ParsePeers(spec string) ([]Peer, error) {
+	var peers []Peer
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, address, ok := strings.Cut(item, "=")
+		if !ok || id == "" || address == "" {
+			return nil, fmt.Errorf("%w: peer %q is not id=host:port", ErrInvalidConfig, item)
+		}
+		peers = append(peers, Peer{ID: id, Address: address})
+	}
+	return peers, nil
+}
+
+// Node is this process's member of the group. It implements memory.Replicator.
+type Node struct {
+	raft         *raft.Raft
+	logs         *raftboltdb.BoltStore
+	applyTimeout time.Duration
+}
+
+// Open starts the node and, when cfg.Bootstrap is set and the data directory is empty,
+// bootstraps the group from cfg.Peers. Bootstrapping every node with the same peers is
+// safe: a node with existing state skips it. The latest snapshot is restored into store
+// before Open returns; the log after it is replayed once the node learns the commit index.
+func Open(cfg Config, store *memory.MemoryLedgerStore, appLogger *slog.Logger) (*Node, error) {
+	if cfg.ID == "" || cfg.BindAddr == "" || cfg.DataDir == "" {
+		return nil, fmt.Errorf("%w: id, bind address and data directory are required", ErrInvalidConfig)
+	}
+	if cfg.ApplyTimeout <= 0 {
+		cfg.ApplyTimeout = 5 * time.Second
+	}
+	if err := os.MkdirAll(cfg.DataDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(cfg.ID)
+	if cfg.SnapshotThreshold > 0 {
+		conf.SnapshotThreshold = cfg.SnapshotThreshold
+	}
+	if cfg.SnapshotInterval > 0 {
+		conf.SnapshotInterval = cfg.SnapshotInterval
+	}
+	conf.Logger = hclog.New(&hclog.LoggerOptions{
+		Name:       "raft",
+		Level:      hclog.Info,
+		Output:     os.Stderr,
+		JSONFormat: true,
+	})
+
+	logs, err := raftboltdb.NewBoltStore(filepath.Join(cfg.DataDir, "raft.db"))
+	if err != nil {
+		return nil, fmt.Errorf("opening raft log: %w", err)
+	}
+	cachedLogs, err := raft.NewLogCache(512, logs)
+	if err != nil {
+		logs.Close()
+		return nil, err
+	}
+	snapshots, err := raft.NewFileSnapshotStore(cfg.DataDir, 2, os.Stderr)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("opening raft snapshots: %w", err)
+	}
+	advertise, err := net.ResolveTCPAddr("tcp", cfg.BindAddr)
+	if err != nil {
+		logs.Close()
+		return nil, fmt.Errorf("%w: bind address: %w", ErrInvalidConfig, err)
+	}
+	transport, err := raft.NewTCPTransport(cfg.BindAddr, advertise, 3, 10*time.Second, os.Stderr)
+	if err != nil {
+		logs.Close()
+		return nil, err
+	}
+
+	hasState, err := raft.HasExistingState(cachedLogs, logs, snapshots)
+	if err != nil {
+		transport.Close()
+		logs.Close()
+		return nil, err
+	}
+	r, err := raft.NewRaft(conf, &fsm{store: store}, cachedLogs, logs, snapshots, transport)
+	if err != nil {
+		transport.Close()
+		logs.Close()
+		return nil, err
+	}
+	node := &Node{raft: r, logs: logs, applyTimeout: cfg.ApplyTimeout}
+
+	if cfg.Bootstrap && !hasState {
+		servers := make([]raft.Server, 0, len(cfg.Peers))
+		for _, peer := range cfg.Peers {
+			servers = append(servers, raft.Server{Suffrage: raft.Voter, ID: raft.ServerID(peer.ID), Address: raft.ServerAddress(peer.Address)})
+		}
+		if len(servers) == 0 {
+			servers = append(servers, raft.Server{Suffrage: raft.Voter, ID: conf.LocalID, Address: transport.LocalAddr()})
+		}
+		if err := r.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+			node.Close()
+			return nil, fmt.Errorf("bootstrapping raft group: %w", err)
+		}
+		appLogger.Info("raft group bootstrapped", "id", cfg.ID, "peers", len(servers))
+	}
+	return node, nil
+}
+
+// Propose appends command to the log and waits until it is committed and applied here.
+// A leader that loses its leadership mid-write cannot tell whether the command committed;
+// the caller retries against the new leader and the idempotency key settles it.
+func (n *Node) Propose(ctx context.Context, command []byte) error {
+	timeout := n.applyTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return ctx.Err()
+		}
+	}
+	future := n.raft.Apply(command, timeout)
+	if err := future.Error(); err != nil {
+		if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) {
+			address, _ := n.Leader()
+			return fmt.Errorf("%w: %w, leader is %q", memory.ErrNotLeader, err, address)
+		}
+		return err
+	}
+	if err, ok := future.Response().(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Leader reports the Raft address of the current leader, empty while there is none
+func (n *Node) Leader() (string, bool) {
+	address, _ := n.raft.LeaderWithID()
+	return string(address), n.raft.State() == raft.Leader
+}
+
+// Close leaves the group running without this node; it rejoins from its data directory
+func (n *Node) Close() error {
+	err := n.raft.Shutdown().Error()
+	return errors.Join(err, n.logs.Close())
+}
+
+var _ memory.Replicator = (*Node)(nil)
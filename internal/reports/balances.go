@@ -0,0 +1,107 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var ErrBalanceNotFound = errors.New("account has no materialized balance")
+
+const (
+	defaultDiscrepancyLimit = 100
+	maxDiscrepancyLimit     = 1000
+)
+
+var (
+	balanceDiscrepancies = metrics.NewGauge("ledger_balance_discrepancies",
+		"Accounts whose materialized balance disagreed with their entries in the last check")
+	balanceChecked = metrics.NewGauge("ledger_balance_checked_accounts",
+		"Accounts compared in the last balance check")
+	balanceChecks = metrics.NewCounterVec("ledger_balance_checks_total",
+		"Balance consistency checks by outcome", "outcome")
+)
+
+// BalanceChecker verifies that materialized balances still equal the sum of their entries.
+// A mismatch means a posting updated one without the other, so it is alerted on, and kept
+// as a discrepancy until a later check finds the account consistent again.
+type BalanceChecker struct {
+	store     interfaces.DiscrepancyStore
+	clock     clock.Clock
+	appLogger *slog.Logger
+}
+
+func NewBalanceChecker(store interfaces.DiscrepancyStore, clk clock.Clock, appLogger *slog.Logger) *BalanceChecker {
+	return &BalanceChecker{
+		store:     store,
+		clock:     clk,
+		appLogger: appLogger,
+	}
+}
+
+// Check compares a random sample of size accounts, or every account when size is zero
+func (c *BalanceChecker) Check(ctx context.Context, size int) (models.BalanceCheck, error) {
+	check, err := c.store.CheckBalances(ctx, size, c.clock.Now())
+	if err != nil {
+		balanceChecks.With("error").Inc()
+		return models.BalanceCheck{}, err
+	}
+
+	balanceChecked.Set(float64(check.Checked))
+	balanceDiscrepancies.Set(float64(len(check.Discrepancies)))
+	if check.Resolved > 0 {
+		c.appLogger.Info("balance discrepancies resolved", "accounts", check.Resolved)
+	}
+	if len(check.Discrepancies) == 0 {
+		balanceChecks.With("consistent").Inc()
+		return check, nil
+	}
+
+	balanceChecks.With("discrepancies").Inc()
+	accounts := make([]string, len(check.Discrepancies))
+	for i, d := range check.Discrepancies {
+		accounts[i] = d.AccountID
+	}
+	c.appLogger.Error("ALERT: materialized balances disagree with entries",
+		"checked", check.Checked,
+		"discrepancies", len(check.Discrepancies),
+		"sample", sample(accounts, 10),
+	)
+	return check, nil
+}
+
+// List returns recorded discrepancies, most recently detected first
+func (c *BalanceChecker) List(ctx context.Context, filter models.DiscrepancyFilter) ([]models.BalanceDiscrepancy, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDiscrepancyLimit
+	}
+	filter.Limit = min(filter.Limit, maxDiscrepancyLimit)
+	return c.store.ListBalanceDiscrepancies(ctx, filter)
+}
+
+// Repair rebuilds an account's materialized balance from its entries, to recover from
+// drift the check found. A balance that already agrees is left alone.
+func (c *BalanceChecker) Repair(ctx context.Context, accountId string) (models.BalanceRepair, error) {
+	repair, err := c.store.RepairBalance(ctx, accountId, c.clock.Now().UTC())
+	if err != nil {
+		return models.BalanceRepair{}, err
+	}
+	if repair == nil {
+		return models.BalanceRepair{}, fmt.Errorf("%w: %s", ErrBalanceNotFound, accountId)
+	}
+	if repair.Repaired {
+		c.appLogger.Warn("materialized balance repaired",
+			"account_id", accountId,
+			"materialized", repair.Materialized.String(),
+			"recomputed", repair.Recomputed.String(),
+			"delta", repair.Delta.String(),
+		)
+	}
+	return *repair, nil
+}
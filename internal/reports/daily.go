@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// dailyBatchSize is how many transactions one projection step folds in
+const dailyBatchSize = 500
+
+var ErrInvalidDate = errors.New("date must be formatted as YYYY-MM-DD")
+
+// DailyProjection keeps the daily aggregate tables up to date. It is woken after every
+// committed posting and also polls, so postings made by other replicas are picked up too.
+type DailyProjection struct {
+	store     interfaces.DailyReportStore
+	clock     clock.Clock
+	wake      chan struct{}
+	appLogger *slog.Logger
+}
+
+func NewDailyProjection(store interfaces.DailyReportStore, clk clock.Clock, appLogger *slog.Logger) *DailyProjection {
+	return &DailyProjection{
+		store:     store,
+		clock:     clk,
+		wake:      make(chan struct{}, 1),
+		appLogger: appLogger,
+	}
+}
+
+// EntriesCommitted only signals the projection loop; listeners must not block the posting path
+func (p *DailyProjection) EntriesCommitted(updates []models.BalanceUpdate) {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run folds new postings into the aggregates until ctx is cancelled
+func (p *DailyProjection) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+		if _, err := p.CatchUp(ctx); err != nil {
+			p.appLogger.Error("daily aggregates projection failed", "error", err)
+		}
+	}
+}
+
+// CatchUp projects every transaction committed so far and returns how many it folded in
+func (p *DailyProjection) CatchUp(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := p.store.ProjectDailyAggregates(ctx, dailyBatchSize)
+		total += n
+		if err != nil || n < dailyBatchSize {
+			return total, err
+		}
+	}
+}
+
+// Report returns the aggregates of a UTC date, catching up first so it includes every
+// posting committed before the request
+func (p *DailyProjection) Report(ctx context.Context, date string, top int) (models.DailyReport, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return models.DailyReport{}, ErrInvalidDate
+	}
+	if _, err := p.CatchUp(ctx); err != nil {
+		p.appLogger.Warn("serving daily report from a lagging projection", "error", err)
+	}
+
+	report, err := p.store.GetDailyReport(ctx, date, top)
+	if err != nil {
+		return models.DailyReport{}, err
+	}
+	report.GeneratedAt = p.clock.Now()
+	return report, nil
+}
@@ -0,0 +1,137 @@
+package reports
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	invariantHealthy = metrics.NewGauge("ledger_invariant_healthy",
+		"1 when the last double-entry verification passed, 0 otherwise")
+	invariantUnbalanced = metrics.NewGauge("ledger_invariant_unbalanced_transactions",
+		"Number of transactions whose legs did not balance in the last verification")
+	invariantRuns = metrics.NewCounterVec("ledger_invariant_checks_total",
+		"Double-entry verification runs by outcome", "outcome")
+)
+
+// Service builds accounting reports and verifies ledger invariants
+type Service struct {
+	store     interfaces.ReportStore
+	clock     clock.Clock
+	appLogger *slog.Logger
+}
+
+// NewService stamps reports with clk, normally the ledger's
+func NewService(store interfaces.ReportStore, clk clock.Clock, appLogger *slog.Logger) *Service {
+	return &Service{
+		store:     store,
+		clock:     clk,
+		appLogger: appLogger,
+	}
+}
+
+// TrialBalance lists debits and credits per account and whether they net to zero
+func (s *Service) TrialBalance(ctx context.Context) (models.TrialBalance, error) {
+	lines, err := s.store.GetTrialBalance(ctx)
+	if err != nil {
+		return models.TrialBalance{}, err
+	}
+
+	report := models.TrialBalance{
+		Lines:        lines,
+		TotalDebits:  decimal.Zero,
+		TotalCredits: decimal.Zero,
+		GeneratedAt:  s.clock.Now(),
+	}
+	for _, line := range lines {
+		report.TotalDebits = report.TotalDebits.Add(line.Debits)
+		report.TotalCredits = report.TotalCredits.Add(line.Credits)
+	}
+	report.Net = report.TotalCredits.Sub(report.TotalDebits)
+	report.Balanced = report.Net.IsZero()
+	return report, nil
+}
+
+// classOrder lists classes in balance sheet order followed by the income statement
+var classOrder = []string{models.ClassAsset, models.ClassLiability, models.ClassEquity, models.ClassRevenue, models.ClassExpense, ""}
+
+// ChartOfAccounts groups the trial balance by account class. Unclassified accounts come last.
+func (s *Service) ChartOfAccounts(ctx context.Context) ([]models.ClassSummary, error) {
+	lines, err := s.store.GetTrialBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byClass := make(map[string]*models.ClassSummary, len(classOrder))
+	for _, class := range classOrder {
+		byClass[class] = &models.ClassSummary{Class: class, NormalBalance: models.NormalBalance(class), Accounts: []models.TrialBalanceLine{}}
+	}
+	for _, line := range lines {
+		summary, ok := byClass[line.Class]
+		if !ok {
+			summary = byClass[""]
+		}
+		summary.Debits = summary.Debits.Add(line.Debits)
+		summary.Credits = summary.Credits.Add(line.Credits)
+		summary.Balance = summary.Balance.Add(line.Balance)
+		summary.Accounts = append(summary.Accounts, line)
+	}
+
+	summaries := make([]models.ClassSummary, 0, len(classOrder))
+	for _, class := range classOrder {
+		summaries = append(summaries, *byClass[class])
+	}
+	return summaries, nil
+}
+
+// VerifyInvariants asserts that all entries sum to zero and every transaction has
+// matching debit and credit legs. Violations are logged as alerts and exported as metrics.
+func (s *Service) VerifyInvariants(ctx context.Context) (models.InvariantReport, error) {
+	trialBalance, err := s.TrialBalance(ctx)
+	if err != nil {
+		invariantRuns.With("error").Inc()
+		return models.InvariantReport{}, err
+	}
+
+	unbalanced, err := s.store.FindUnbalancedTransactions(ctx)
+	if err != nil {
+		invariantRuns.With("error").Inc()
+		return models.InvariantReport{}, err
+	}
+
+	report := models.InvariantReport{
+		LedgerNet:              trialBalance.Net,
+		UnbalancedTransactions: unbalanced,
+		Healthy:                trialBalance.Balanced && len(unbalanced) == 0,
+		CheckedAt:              s.clock.Now(),
+	}
+
+	invariantUnbalanced.Set(float64(len(unbalanced)))
+	if report.Healthy {
+		invariantHealthy.Set(1)
+		invariantRuns.With("healthy").Inc()
+		return report, nil
+	}
+
+	invariantHealthy.Set(0)
+	invariantRuns.With("violated").Inc()
+	s.appLogger.Error("ALERT: double-entry invariant violated",
+		"ledger_net", report.LedgerNet.String(),
+		"unbalanced_transactions", len(unbalanced),
+		"sample", sample(unbalanced, 10),
+	)
+	return report, nil
+}
+
+func sample(ids []string, n int) []string {
+	if len(ids) > n {
+		return ids[:n]
+	}
+	return ids
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/cron"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+const leaderLease = "scheduler-leader"
+
+var (
+	jobRuns = metrics.NewCounterVec("scheduler_job_runs_total",
+		"Scheduled job runs by job and outcome", "job", "outcome")
+	jobDuration = metrics.NewGaugeVec("scheduler_job_last_duration_seconds",
+		"Duration of the last run of each job", "job")
+	jobLastSuccess = metrics.NewGaugeVec("scheduler_job_last_success_timestamp_seconds",
+		"Unix time of the last successful run of each job", "job")
+	isLeader = metrics.NewGauge("scheduler_leader",
+		"1 when this replica is the scheduler leader and runs jobs")
+)
+
+// Job is a periodic task. Run returns an error to have the run counted as failed.
+type Job struct {
+	Name     string
+	Schedule *cron.Expression
+	Jitter   time.Duration // random delay added to each run so replicas and jobs don't fire in lockstep
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on their schedules. With a LeaseStore, replicas elect a leader through
+// a lease and only the leader runs jobs; a replica that stops renewing loses it after the TTL.
+type Scheduler struct {
+	leases    interfaces.LeaseStore // nil runs every job locally
+	appLogger *slog.Logger
+	holder    string
+	ttl       time.Duration
+	leader    atomic.Bool
+
+	mu   sync.Mutex
+	jobs []Job
+}
+
+func New(leases interfaces.LeaseStore, ttl time.Duration, appLogger *slog.Logger) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		leases:    leases,
+		appLogger: appLogger,
+		holder:    hostname + "-" + uuid.New().String(),
+		ttl:       ttl,
+	}
+}
+
+// Register adds a job with a cron expression or "@every <duration>" schedule
+func (s *Scheduler) Register(name, spec string, jitter time.Duration, run func(ctx context.Context) error) error {
+	expr, err := cron.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, Job{Name: name, Schedule: expr, Jitter: jitter, Run: run})
+	return nil
+}
+
+// IsLeader reports whether this replica currently runs jobs
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// Start launches the election loop and one goroutine per job until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	if s.leases == nil {
+		s.setLeader(true)
+	} else {
+		s.elect(ctx)
+		go s.electionLoop(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	if s.leader.Swap(leader) != leader {
+		s.appLogger.Info("scheduler leadership changed", "holder", s.holder, "leader", leader)
+	}
+	if leader {
+		isLeader.Set(1)
+	} else {
+		isLeader.Set(0)
+	}
+}
+
+// electionLoop renews well within the TTL so a healthy leader never lapses
+func (s *Scheduler) electionLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if s.IsLeader() {
+				// Hand over straight away instead of making the others wait out the TTL
+				if err := s.leases.ReleaseLease(context.Background(), leaderLease, s.holder); err != nil {
+					s.appLogger.Error("failed to release scheduler lease", "error", err)
+				}
+			}
+			s.setLeader(false)
+			return
+		case <-ticker.C:
+			s.elect(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) elect(ctx context.Context) {
+	acquired, err := s.leases.TryAcquireLease(ctx, leaderLease, s.holder, s.ttl)
+	if err != nil {
+		// Without a confirmed lease another replica may take over, so stop running jobs
+		s.appLogger.Error("scheduler lease renewal failed", "error", err)
+		acquired = false
+	}
+	s.setLeader(acquired)
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	next := job.Schedule.Next(time.Now())
+	for !next.IsZero() {
+		delay := time.Until(next)
+		if job.Jitter > 0 {
+			delay += rand.N(job.Jitter)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if s.IsLeader() {
+			s.runJob(ctx, job)
+		} else {
+			jobRuns.With(job.Name, "skipped").Inc()
+		}
+		next = job.Schedule.Next(time.Now())
+	}
+	s.appLogger.Warn("job schedule never fires again", "job", job.Name, "schedule", job.Schedule.String())
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	started := time.Now()
+	err := job.Run(ctx)
+	jobDuration.With(job.Name).Set(time.Since(started).Seconds())
+
+	if err != nil {
+		jobRuns.With(job.Name, "failed").Inc()
+		s.appLogger.Error("scheduled job failed", "job", job.Name, "error", err)
+		return
+	}
+	jobRuns.With(job.Name, "succeeded").Inc()
+	jobLastSuccess.With(job.Name).Set(float64(time.Now().Unix()))
+}
@@ -0,0 +1,129 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrPendingNotFound   = errors.New("pending transaction not found")
+	ErrPendingNotPending = errors.New("pending transaction can no longer be cancelled")
+	ErrIdempotencyKey    = errors.New("future-dated transactions need an idempotency key")
+)
+
+// SchedulePayment holds a transaction until executeAt instead of posting it now
+func (s *Service) SchedulePayment(ctx context.Context, tx models.Transaction, executeAt time.Time) (models.PendingTransaction, error) {
+	if tx.IdempotencyKey == "" {
+		return models.PendingTransaction{}, ErrIdempotencyKey
+	}
+	if !tx.Amount.IsPositive() {
+		return models.PendingTransaction{}, fmt.Errorf("%w: amount must be positive", ErrInvalidSchedule)
+	}
+
+	tx.TenantID = tenant.FromContext(ctx)
+	now := s.ledger.Now().UTC()
+	return s.pending.SavePendingTransaction(ctx, models.PendingTransaction{
+		ID:          tx.ID,
+		Transaction: tx,
+		Force:       tx.Force,
+		ExecuteAt:   executeAt.UTC(),
+		Status:      models.PendingScheduled,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+func (s *Service) GetPayment(ctx context.Context, id string) (models.PendingTransaction, error) {
+	pending, err := s.pending.GetPendingTransaction(ctx, id)
+	if err != nil {
+		return models.PendingTransaction{}, err
+	}
+	if pending == nil || !visible(ctx, pending.Transaction.TenantID) {
+		return models.PendingTransaction{}, ErrPendingNotFound
+	}
+	return *pending, nil
+}
+
+func (s *Service) ListPayments(ctx context.Context, status string) ([]models.PendingTransaction, error) {
+	return s.pending.ListPendingTransactions(ctx, status)
+}
+
+// CancelPayment cancels a future-dated transaction that has not started executing
+func (s *Service) CancelPayment(ctx context.Context, id string) (models.PendingTransaction, error) {
+	if _, err := s.GetPayment(ctx, id); err != nil {
+		return models.PendingTransaction{}, err
+	}
+	cancelled, err := s.pending.TransitionPendingTransaction(ctx, id, models.PendingScheduled, models.PendingCancelled, "", s.ledger.Now().UTC())
+	if err != nil {
+		return models.PendingTransaction{}, err
+	}
+	if !cancelled {
+		return models.PendingTransaction{}, ErrPendingNotPending
+	}
+	return s.GetPayment(ctx, id)
+}
+
+// RunDuePayments posts every future-dated transaction whose time has come
+func (s *Service) RunDuePayments(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.pending.ListDuePendingTransactions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for _, pending := range due {
+		// Claiming first means a concurrent cancel either wins outright or finds it executing
+		claimed, err := s.pending.TransitionPendingTransaction(ctx, pending.ID, models.PendingScheduled, models.PendingExecuting, "", s.ledger.Now().UTC())
+		if err != nil {
+			return executed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		tx := pending.Transaction
+		tx.Force = pending.Force
+		tx.CreatedAt = s.ledger.Now()
+		if _, err := s.ledger.PostTransaction(tenant.WithTenant(ctx, tx.TenantID), tx); err != nil {
+			executions.With("failed").Inc()
+			s.notifyPaymentFailure(pending, err)
+			if _, err := s.pending.TransitionPendingTransaction(ctx, pending.ID, models.PendingExecuting, models.PendingFailed, err.Error(), s.ledger.Now().UTC()); err != nil {
+				return executed, err
+			}
+			continue
+		}
+
+		executions.With("posted").Inc()
+		if _, err := s.pending.TransitionPendingTransaction(ctx, pending.ID, models.PendingExecuting, models.PendingExecuted, "", s.ledger.Now().UTC()); err != nil {
+			return executed, err
+		}
+		executed++
+	}
+	return executed, nil
+}
+
+func (s *Service) notifyPaymentFailure(pending models.PendingTransaction, cause error) {
+	s.appLogger.Error("future-dated transaction failed",
+		"transaction_id", pending.ID,
+		"execute_at", pending.ExecuteAt,
+		"error", cause,
+	)
+	event := events.PendingTransactionFailed{
+		TransactionID: pending.ID,
+		FromAccount:   pending.Transaction.FromAccount,
+		ToAccount:     pending.Transaction.ToAccount,
+		Amount:        pending.Transaction.Amount,
+		ExecuteAt:     pending.ExecuteAt,
+		Error:         cause.Error(),
+		OccurredAt:    s.ledger.Now(),
+	}
+	if err := s.publisher.Publish("transactions.pending_failed", event); err != nil {
+		s.appLogger.Error("failed to publish kafka event", "transaction_id", pending.ID, "error", err)
+	}
+}
@@ -0,0 +1,223 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/cron"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models/events"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrNotActive        = errors.New("schedule is not active")
+)
+
+// maxCatchUp bounds how many missed occurrences of one schedule are executed in a single run,
+// so a long outage drains gradually instead of flooding the ledger
+const maxCatchUp = 50
+
+var executions = metrics.NewCounterVec("schedule_executions_total",
+	"Standing order executions by outcome", "outcome")
+
+// Service manages standing orders and future-dated transactions and executes them when due
+type Service struct {
+	ledger    *ledger.Ledger
+	store     interfaces.ScheduleStore
+	pending   interfaces.PendingTransactionStore
+	publisher interfaces.EventPublisher
+	appLogger *slog.Logger
+}
+
+func NewService(ledgerService *ledger.Ledger, store interfaces.ScheduleStore, pending interfaces.PendingTransactionStore,
+	publisher interfaces.EventPublisher, appLogger *slog.Logger) *Service {
+	return &Service{
+		ledger:    ledgerService,
+		store:     store,
+		pending:   pending,
+		publisher: publisher,
+		appLogger: appLogger,
+	}
+}
+
+// Create validates a standing order and computes its first occurrence at or after StartAt
+func (s *Service) Create(ctx context.Context, schedule models.Schedule) (models.Schedule, error) {
+	if schedule.FromAccount == "" || schedule.ToAccount == "" || schedule.FromAccount == schedule.ToAccount {
+		return models.Schedule{}, fmt.Errorf("%w: from_account and to_account must be two different accounts", ErrInvalidSchedule)
+	}
+	if !schedule.Amount.IsPositive() {
+		return models.Schedule{}, fmt.Errorf("%w: amount must be positive", ErrInvalidSchedule)
+	}
+	expr, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return models.Schedule{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	now := s.ledger.Now().UTC()
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	schedule.StartAt = schedule.StartAt.UTC()
+	schedule.NextRunAt = expr.Next(schedule.StartAt.Add(-time.Minute))
+	if schedule.NextRunAt.IsZero() || (schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt)) {
+		return models.Schedule{}, fmt.Errorf("%w: cron expression never fires within the schedule", ErrInvalidSchedule)
+	}
+
+	schedule.ID = uuid.New().String()
+	schedule.TenantID = tenant.FromContext(ctx)
+	schedule.Status = models.ScheduleActive
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	if err := s.store.SaveSchedule(ctx, schedule); err != nil {
+		return models.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// visible reports whether a record owned by tenantId may be seen by the tenant of ctx
+func visible(ctx context.Context, tenantId string) bool {
+	current := tenant.FromContext(ctx)
+	return current == "" || current == tenantId
+}
+
+func (s *Service) Get(ctx context.Context, id string) (models.Schedule, error) {
+	schedule, err := s.store.GetSchedule(ctx, id)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	// Another tenant's schedule is reported as missing rather than forbidden
+	if schedule == nil || !visible(ctx, schedule.TenantID) {
+		return models.Schedule{}, ErrScheduleNotFound
+	}
+	return *schedule, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]models.Schedule, error) {
+	return s.store.ListSchedules(ctx)
+}
+
+// Cancel stops future occurrences; executions already posted are unaffected
+func (s *Service) Cancel(ctx context.Context, id string) (models.Schedule, error) {
+	schedule, err := s.Get(ctx, id)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	if schedule.Status != models.ScheduleActive {
+		return schedule, ErrNotActive
+	}
+	schedule.Status = models.ScheduleCancelled
+	schedule.UpdatedAt = s.ledger.Now().UTC()
+	if err := s.store.SaveSchedule(ctx, schedule); err != nil {
+		return models.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// RunDue executes every occurrence that fell due up to now, including those missed while
+// the service was down. Each occurrence has its own idempotency key, so a run that is
+// interrupted and retried never pays the same occurrence twice.
+func (s *Service) RunDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.store.ListDueSchedules(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for _, schedule := range due {
+		n, err := s.runSchedule(ctx, schedule, now)
+		executed += n
+		if err != nil {
+			return executed, err
+		}
+	}
+	return executed, nil
+}
+
+func (s *Service) runSchedule(ctx context.Context, schedule models.Schedule, now time.Time) (int, error) {
+	expr, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for i := 0; i < maxCatchUp && !schedule.NextRunAt.After(now); i++ {
+		if schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt) {
+			break
+		}
+		dueAt := schedule.NextRunAt
+		if err := s.execute(ctx, schedule, dueAt); err != nil {
+			schedule.Failures++
+			schedule.LastError = err.Error()
+			s.notifyFailure(schedule, dueAt, err)
+		} else {
+			schedule.Failures = 0
+			schedule.LastError = ""
+			executed++
+		}
+		// A failed occurrence is skipped rather than retried forever; the failure event lets someone act on it
+		schedule.LastRunAt = &dueAt
+		schedule.NextRunAt = expr.Next(dueAt)
+	}
+
+	if schedule.NextRunAt.IsZero() || (schedule.EndAt != nil && schedule.NextRunAt.After(*schedule.EndAt)) {
+		schedule.Status = models.ScheduleCompleted
+	}
+	schedule.UpdatedAt = s.ledger.Now().UTC()
+	return executed, s.store.SaveSchedule(ctx, schedule)
+}
+
+func (s *Service) execute(ctx context.Context, schedule models.Schedule, dueAt time.Time) error {
+	// Executions run on behalf of the tenant that created the schedule
+	_, err := s.ledger.PostTransaction(tenant.WithTenant(ctx, schedule.TenantID), models.Transaction{
+		ID:             s.ledger.NewID(),
+		IdempotencyKey: "schedule-" + schedule.ID + "-" + dueAt.Format(time.RFC3339),
+		FromAccount:    schedule.FromAccount,
+		ToAccount:      schedule.ToAccount,
+		Amount:         schedule.Amount,
+		CreatedAt:      s.ledger.Now(),
+		Force:          true, // repeating the same payment is the point of a standing order
+		Reference:      schedule.Reference,
+		Description:    schedule.Description,
+		Metadata: map[string]string{
+			"schedule_id": schedule.ID,
+			"due_at":      dueAt.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		executions.With("failed").Inc()
+		return err
+	}
+	executions.With("posted").Inc()
+	return nil
+}
+
+func (s *Service) notifyFailure(schedule models.Schedule, dueAt time.Time, cause error) {
+	s.appLogger.Error("standing order execution failed",
+		"schedule_id", schedule.ID,
+		"due_at", dueAt,
+		"error", cause,
+	)
+	event := events.ScheduleExecutionFailed{
+		ScheduleID:  schedule.ID,
+		FromAccount: schedule.FromAccount,
+		ToAccount:   schedule.ToAccount,
+		Amount:      schedule.Amount,
+		DueAt:       dueAt,
+		Failures:    schedule.Failures,
+		Error:       cause.Error(),
+		OccurredAt:  s.ledger.Now(),
+	}
+	if err := s.publisher.Publish("schedules.execution_failed", event); err != nil {
+		s.appLogger.Error("failed to publish kafka event", "schedule_id", schedule.ID, "error", err)
+	}
+}
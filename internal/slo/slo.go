@@ -0,0 +1,316 @@
+// Package slo keeps service level indicators of transaction posting in process - success
+// rate and latency over rolling windows, with their error budget burn rates - so a
+// deployment without a Prometheus server still sees when it is eating its budget.
+//
+// Burn rate is how fast the error budget goes: 1 spends exactly the budget over the SLO
+// period, 14.4 spends 2% of a 30-day budget in an hour. Alerts follow the multi-window
+// rule: a long window shows the burn is significant, a short one that it is still going on.
+package slo
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/httputil"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+// Indicators
+const (
+	SuccessSLI = "success" // postings not failed by a server error
+	LatencySLI = "latency" // postings finished within the latency threshold
+)
+
+// Windows the indicators are kept over; the longest bounds the memory held
+var Windows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// alertRules pair a long and a short window with the burn rate both must exceed
+var alertRules = []struct {
+	severity    string
+	long, short time.Duration
+	burnRate    float64
+}{
+	{"page", time.Hour, 5 * time.Minute, 14.4},
+	{"ticket", 6 * time.Hour, 30 * time.Minute, 6},
+}
+
+// bounds of the latency buckets in seconds; the last bucket holds everything slower
+var bounds = metrics.DefaultBuckets
+
+var (
+	postings = metrics.NewCounterVec("slo_transactions_total",
+		"Postings counted towards the SLOs, by outcome", "outcome")
+	postingSeconds = metrics.NewHistogram("slo_transaction_seconds",
+		"Time to answer a posting request", nil)
+	burnRates = metrics.NewGaugeVec("slo_burn_rate",
+		"Error budget burn rate over a rolling window", "sli", "window")
+)
+
+// Objectives are the targets, as fractions of postings
+type Objectives struct {
+	Success          float64       // e.g. 0.999
+	Latency          float64       // e.g. 0.99
+	LatencyThreshold time.Duration // a posting slower than this misses the latency objective
+}
+
+// minute holds the postings answered within one minute
+type minute struct {
+	start   int64 // unix minute
+	total   uint64
+	failed  uint64
+	slow    uint64
+	latency []uint64 // per bucket of bounds, with one more for the overflow
+}
+
+// Tracker records postings into per-minute slots, a day of them in a ring
+type Tracker struct {
+	objectives Objectives
+	appLogger  *slog.Logger
+	started    time.Time
+
+	mu      sync.Mutex
+	minutes []minute
+}
+
+func NewTracker(objectives Objectives, appLogger *slog.Logger) *Tracker {
+	return &Tracker{
+		objectives: objectives,
+		appLogger:  appLogger,
+		started:    time.Now().UTC(),
+		minutes:    make([]minute, int(Windows[len(Windows)-1]/time.Minute)),
+	}
+}
+
+// Record counts one posting answered at the given time
+func (t *Tracker) Record(at time.Time, latency time.Duration, failed bool) {
+	slow := latency > t.objectives.LatencyThreshold
+	outcome := "ok"
+	switch {
+	case failed:
+		outcome = "failed"
+	case slow:
+		outcome = "slow"
+	}
+	postings.With(outcome).Inc()
+	postingSeconds.Observe(latency.Seconds())
+
+	unixMinute := at.Unix() / 60
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot := &t.minutes[unixMinute%int64(len(t.minutes))]
+	if slot.start != unixMinute {
+		*slot = minute{start: unixMinute, latency: make([]uint64, len(bounds)+1)}
+	}
+	slot.total++
+	if failed {
+		slot.failed++
+	}
+	if slow {
+		slot.slow++
+	}
+	bucket := len(bounds)
+	for i, bound := range bounds {
+		if latency.Seconds() <= bound {
+			bucket = i
+			break
+		}
+	}
+	slot.latency[bucket]++
+}
+
+// Window summarises the postings of one rolling window
+type Window struct {
+	Window          string  `json:"window"`
+	Partial         bool    `json:"partial,omitempty"` // the process has not been up for the whole window
+	Requests        uint64  `json:"requests"`
+	Failed          uint64  `json:"failed"`
+	Slow            uint64  `json:"slow"`
+	SuccessRate     float64 `json:"success_rate"`
+	WithinThreshold float64 `json:"within_latency_threshold"`
+	P99Milliseconds float64 `json:"p99_ms"`
+	SuccessBurnRate float64 `json:"success_burn_rate"`
+	LatencyBurnRate float64 `json:"latency_burn_rate"`
+}
+
+// Alert is a burn rate over both windows of an alert rule
+type Alert struct {
+	SLI      string   `json:"sli"`
+	Severity string   `json:"severity"`
+	BurnRate float64  `json:"burn_rate"` // the lower of the two windows
+	Windows  []string `json:"windows"`
+}
+
+type Summary struct {
+	Success          float64   `json:"success_objective"`
+	Latency          float64   `json:"latency_objective"`
+	LatencyThreshold string    `json:"latency_threshold"`
+	TrackedSince     time.Time `json:"tracked_since"`
+	Windows          []Window  `json:"windows"`
+	Alerts           []Alert   `json:"alerts"`
+}
+
+// Summary computes every window as of now
+func (t *Tracker) Summary(now time.Time) Summary {
+	summary := Summary{
+		Success:          t.objectives.Success,
+		Latency:          t.objectives.Latency,
+		LatencyThreshold: t.objectives.LatencyThreshold.String(),
+		TrackedSince:     t.started,
+		Windows:          []Window{},
+		Alerts:           []Alert{},
+	}
+	byDuration := map[time.Duration]Window{}
+	for _, d := range Windows {
+		window := t.window(now, d)
+		byDuration[d] = window
+		summary.Windows = append(summary.Windows, window)
+	}
+
+	for _, rule := range alertRules {
+		long, short := byDuration[rule.long], byDuration[rule.short]
+		for _, sli := range []struct {
+			name string
+			rate func(Window) float64
+		}{
+			{SuccessSLI, func(w Window) float64 { return w.SuccessBurnRate }},
+			{LatencySLI, func(w Window) float64 { return w.LatencyBurnRate }},
+		} {
+			if burn := min(sli.rate(long), sli.rate(short)); burn > rule.burnRate {
+				summary.Alerts = append(summary.Alerts, Alert{
+					SLI:      sli.name,
+					Severity: rule.severity,
+					BurnRate: burn,
+					Windows:  []string{long.Window, short.Window},
+				})
+			}
+		}
+	}
+	return summary
+}
+
+func (t *Tracker) window(now time.Time, d time.Duration) Window {
+	window := Window{
+		Window:          windowName(d),
+		Partial:         now.Sub(t.started) < d,
+		SuccessRate:     1,
+		WithinThreshold: 1,
+	}
+	latency := make([]uint64, len(bounds)+1)
+	newest := now.Unix() / 60
+	oldest := newest - int64(d/time.Minute) + 1
+
+	t.mu.Lock()
+	for _, slot := range t.minutes {
+		if slot.start < oldest || slot.start > newest {
+			continue
+		}
+		window.Requests += slot.total
+		window.Failed += slot.failed
+		window.Slow += slot.slow
+		for i, n := range slot.latency {
+			latency[i] += n
+		}
+	}
+	t.mu.Unlock()
+
+	if window.Requests == 0 {
+		return window
+	}
+	requests := float64(window.Requests)
+	window.SuccessRate = round(1 - float64(window.Failed)/requests)
+	window.WithinThreshold = round(1 - float64(window.Slow)/requests)
+	window.SuccessBurnRate = burnRate(window.SuccessRate, t.objectives.Success)
+	window.LatencyBurnRate = burnRate(window.WithinThreshold, t.objectives.Latency)
+	window.P99Milliseconds = quantile(0.99, latency, window.Requests) * 1000
+	return window
+}
+
+// burnRate is the share of bad postings over the share the objective allows
+func burnRate(good, objective float64) float64 {
+	budget := 1 - objective
+	if budget <= 0 {
+		return 0
+	}
+	return round((1 - good) / budget)
+}
+
+// quantile interpolates within the bucket holding the rank, like Prometheus'
+// histogram_quantile; a rank in the overflow bucket reports the highest bound
+func quantile(q float64, counts []uint64, total uint64) float64 {
+	rank := q * float64(total)
+	var cumulative uint64
+	lower := 0.0
+	for i, n := range counts {
+		if i == len(bounds) {
+			return bounds[len(bounds)-1]
+		}
+		if float64(cumulative+n) >= rank && n > 0 {
+			return round(lower + (bounds[i]-lower)*(rank-float64(cumulative))/float64(n))
+		}
+		cumulative += n
+		lower = bounds[i]
+	}
+	return lower
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
+
+func windowName(d time.Duration) string {
+	if d >= time.Hour {
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(d/time.Minute)) + "m"
+}
+
+// Run exports the burn rates as gauges and logs an alert while a page-level burn lasts
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		summary := t.Summary(time.Now())
+		for _, window := range summary.Windows {
+			burnRates.With(SuccessSLI, window.Window).Set(window.SuccessBurnRate)
+			burnRates.With(LatencySLI, window.Window).Set(window.LatencyBurnRate)
+		}
+		for _, alert := range summary.Alerts {
+			logAlert := t.appLogger.Warn
+			if alert.Severity == "page" {
+				logAlert = t.appLogger.Error
+			}
+			logAlert("ALERT: transaction SLO error budget burning",
+				"sli", alert.SLI,
+				"severity", alert.Severity,
+				"burn_rate", alert.BurnRate,
+				"windows", alert.Windows,
+			)
+		}
+	}
+}
+
+// Middleware records every posting request: POST /transactions other than dry runs. A
+// server error, including a 504 from the handler deadline, fails the success objective.
+// It must wrap everything else so the time counted is the time the client waited.
+func Middleware(t *Tracker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/transactions" || r.URL.Query().Get("dry_run") == "true" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		recorder := httputil.NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+		t.Record(time.Now(), time.Since(start), recorder.Status >= http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,112 @@
+package statements
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camt053Document struct {
+	XMLName   xml.Name `xml:"Document"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	Statement struct {
+		GroupHeader struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Statement camt053Statement `xml:"Stmt"`
+	} `xml:"BkToCstmrStmt"`
+}
+
+type camt053Statement struct {
+	ID        string         `xml:"Id"`
+	CreatedAt string         `xml:"CreDtTm"`
+	FromDate  string         `xml:"FrToDt>FrDtTm"`
+	ToDate    string         `xml:"FrToDt>ToDtTm"`
+	AccountID string         `xml:"Acct>Id>Othr>Id"`
+	Currency  string         `xml:"Acct>Ccy"`
+	Balances  []camt053Bal   `xml:"Bal"`
+	Entries   []camt053Entry `xml:"Ntry"`
+}
+
+type camt053Bal struct {
+	Type        string        `xml:"Tp>CdOrPrtry>Cd"` // OPBD opening booked, CLBD closing booked
+	Amount      camt053Amount `xml:"Amt"`
+	CreditDebit string        `xml:"CdtDbtInd"`
+	Date        string        `xml:"Dt>Dt"`
+}
+
+type camt053Entry struct {
+	Reference           string        `xml:"NtryRef"`
+	Amount              camt053Amount `xml:"Amt"`
+	CreditDebit         string        `xml:"CdtDbtInd"`
+	Status              string        `xml:"Sts"`
+	BookingDate         string        `xml:"BookgDt>DtTm"`
+	ValueDate           string        `xml:"ValDt>Dt"`
+	ServicerReference   string        `xml:"AcctSvcrRef"`
+	BankTransactionCode string        `xml:"BkTxCd>Prtry>Cd"`
+	AdditionalInfo      string        `xml:"AddtlNtryInf,omitempty"`
+}
+
+type camt053Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// WriteCAMT053 renders the statement as an ISO 20022 camt.053 bank-to-customer statement.
+// Each ledger entry becomes a booked entry referenced by its entry ID, with the
+// transaction ID as the servicer reference.
+func WriteCAMT053(w io.Writer, statement Statement) error {
+	var doc camt053Document
+	doc.Xmlns = camt053Namespace
+	doc.Statement.GroupHeader.MessageID = statementReference(statement) + "-" + statement.AccountID
+	doc.Statement.GroupHeader.CreatedAt = statement.GeneratedAt.Format(time.RFC3339)
+
+	stmt := camt053Statement{
+		ID:        statementReference(statement),
+		CreatedAt: statement.GeneratedAt.Format(time.RFC3339),
+		FromDate:  statement.From.Format(time.RFC3339),
+		ToDate:    statement.To.Format(time.RFC3339),
+		AccountID: statement.AccountID,
+		Currency:  statement.Currency,
+		Balances: []camt053Bal{
+			camt053Balance("OPBD", statement.OpeningBalance, statement.From, statement.Currency),
+			camt053Balance("CLBD", statement.ClosingBalance, lastDay(statement), statement.Currency),
+		},
+		Entries: make([]camt053Entry, 0, len(statement.Lines)),
+	}
+	for _, line := range statement.Lines {
+		stmt.Entries = append(stmt.Entries, camt053Entry{
+			Reference:           line.EntryID,
+			Amount:              camt053Amount{Currency: statement.Currency, Value: line.Amount.Abs().String()},
+			CreditDebit:         debitCredit(line.Amount, "DBIT", "CRDT"),
+			Status:              "BOOK",
+			BookingDate:         line.Date.Format(time.RFC3339),
+			ValueDate:           line.Date.Format("2006-01-02"),
+			ServicerReference:   line.TransactionID,
+			BankTransactionCode: "LEDGER",
+			AdditionalInfo:      truncate(line.Narrative, 500),
+		})
+	}
+	doc.Statement.Statement = stmt
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func camt053Balance(code string, balance decimal.Decimal, date time.Time, currency string) camt053Bal {
+	return camt053Bal{
+		Type:        code,
+		Amount:      camt053Amount{Currency: currency, Value: balance.Abs().String()},
+		CreditDebit: debitCredit(balance, "DBIT", "CRDT"),
+		Date:        date.Format("2006-01-02"),
+	}
+}
@@ -0,0 +1,52 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// WriteCSV streams the statement as CSV: a header block with the opening balance,
+// one row per entry, and a closing balance row.
+func WriteCSV(w io.Writer, statement Statement) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"account_id", statement.AccountID},
+		{"from", statement.From.Format(time.RFC3339)},
+		{"to", statement.To.Format(time.RFC3339)},
+		{"opening_balance", statement.OpeningBalance.String()},
+		{},
+		{"date", "entry_id", "transaction_id", "amount", "running_balance", "direction", "counterparty", "narrative"},
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range statement.Lines {
+		err := writer.Write([]string{
+			line.Date.Format(time.RFC3339),
+			line.EntryID,
+			line.TransactionID,
+			line.Amount.String(),
+			line.RunningBalance.String(),
+			line.Direction,
+			line.Counterparty,
+			line.Narrative,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Write([]string{}); err != nil {
+		return err
+	}
+	if err := writer.Write([]string{"closing_balance", statement.ClosingBalance.String()}); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,68 @@
+package statements
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WriteMT940 renders the statement as a SWIFT MT940 customer statement (text block only,
+// without the SWIFT envelope), the format most ERP and treasury systems import.
+// The whole range is one statement: opening balance at from, closing balance at to.
+func WriteMT940(w io.Writer, statement Statement) error {
+	writer := bufio.NewWriter(w)
+
+	fmt.Fprintf(writer, ":20:%s\r\n", statementReference(statement))
+	fmt.Fprintf(writer, ":25:%s\r\n", statement.AccountID[:min(len(statement.AccountID), 35)])
+	fmt.Fprintf(writer, ":28C:00001/001\r\n")
+	fmt.Fprintf(writer, ":60F:%s\r\n", mt940Balance(statement.OpeningBalance, statement.From, statement.Currency))
+
+	for _, line := range statement.Lines {
+		// Ledger IDs do not fit the 16-character reference fields, so they travel in the narrative
+		fmt.Fprintf(writer, ":61:%s%s%s%sNTRFNONREF\r\n",
+			line.Date.Format("060102"), line.Date.Format("0102"), debitCredit(line.Amount, "D", "C"), mt940Amount(line.Amount))
+		fmt.Fprintf(writer, ":86:/TRID/%s\r\n/EREF/%s\r\n", line.TransactionID, line.EntryID)
+		if line.Counterparty != "" {
+			// The ordering party of a credit, the beneficiary of a debit
+			fmt.Fprintf(writer, "/%s/%s\r\n", debitCredit(line.Amount, "BENM", "ORDP"), truncate(line.Counterparty, 59))
+		}
+		if line.Narrative != "" {
+			fmt.Fprintf(writer, "/REMI/%s\r\n", truncate(line.Narrative, 59))
+		}
+	}
+
+	fmt.Fprintf(writer, ":62F:%s\r\n", mt940Balance(statement.ClosingBalance, lastDay(statement), statement.Currency))
+	fmt.Fprintf(writer, "-\r\n")
+	return writer.Flush()
+}
+
+// statementReference identifies the statement in the 16 characters MT940 allows
+func statementReference(statement Statement) string {
+	return "STMT" + statement.From.Format("20060102")
+}
+
+// lastDay is the last date the half-open statement range covers
+func lastDay(statement Statement) time.Time {
+	return statement.To.Add(-time.Microsecond)
+}
+
+func mt940Balance(balance decimal.Decimal, date time.Time, currency string) string {
+	return debitCredit(balance, "D", "C") + date.Format("060102") + currency + mt940Amount(balance)
+}
+
+// mt940Amount formats an unsigned amount with a decimal comma, always keeping two decimals
+func mt940Amount(amount decimal.Decimal) string {
+	places := max(int32(2), -amount.Exponent())
+	return strings.Replace(amount.Abs().StringFixed(places), ".", ",", 1)
+}
+
+func debitCredit(amount decimal.Decimal, debit, credit string) string {
+	if amount.IsNegative() {
+		return debit
+	}
+	return credit
+}
@@ -0,0 +1,149 @@
+package statements
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	pdfPageWidth    = 595 // A4 in points
+	pdfPageHeight   = 842
+	pdfMargin       = 50
+	pdfLineHeight   = 14
+	pdfLinesPerPage = 48
+)
+
+// WritePDF renders the statement as a plain, multi-page PDF using the built-in
+// Courier font (monospaced, so columns line up) - no font files or third-party libraries needed.
+func WritePDF(w io.Writer, statement Statement) error {
+	pages := paginate(statementText(statement))
+
+	pdf := &pdfWriter{w: bufio.NewWriter(w)}
+	pdf.printf("%%PDF-1.4\n")
+
+	// Object layout: 1 catalog, 2 page tree, 3 font, then a page + content pair per page
+	pageIds := make([]int, len(pages))
+	for i := range pages {
+		pageIds[i] = 4 + i*2
+	}
+
+	pdf.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pageIds))
+	for i, id := range pageIds {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	pdf.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	pdf.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		pageId := pageIds[i]
+		contentId := pageId + 1
+		pdf.object(pageId, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, contentId))
+
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT\n/F1 8 Tf\n%d TL\n%d %d Td\n", pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDF(line))
+		}
+		fmt.Fprintf(&content, "(Page %d of %d) '\nET\n", i+1, len(pages))
+		pdf.object(contentId, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	pdf.trailer(3 + len(pages)*2)
+	return pdf.flush()
+}
+
+func statementText(statement Statement) []string {
+	lines := []string{
+		"Account Statement",
+		"",
+		"Account:          " + statement.AccountID,
+		"Period:           " + statement.From.Format(time.RFC3339) + " to " + statement.To.Format(time.RFC3339),
+		"Generated:        " + statement.GeneratedAt.Format(time.RFC3339),
+		"Opening balance:  " + statement.OpeningBalance.String(),
+		"",
+		fmt.Sprintf("%-22s %-40s %18s %18s", "Date", "Details", "Amount", "Balance"),
+	}
+	for _, line := range statement.Lines {
+		details := line.Narrative
+		if details == "" {
+			details = line.TransactionID
+		}
+		lines = append(lines, fmt.Sprintf("%-22s %-40s %18s %18s",
+			line.Date.Format("2006-01-02 15:04:05"),
+			truncate(details, 40),
+			line.Amount.String(),
+			line.RunningBalance.String(),
+		))
+	}
+	lines = append(lines, "", "Closing balance:  "+statement.ClosingBalance.String())
+	return lines
+}
+
+func paginate(lines []string) [][]string {
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	return append(pages, lines)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n-3] + "..."
+	}
+	return s
+}
+
+// escapePDF escapes a string for use inside a PDF literal string
+func escapePDF(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// pdfWriter keeps track of object byte offsets for the cross-reference table
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int
+	offsets map[int]int
+	err     error
+}
+
+func (p *pdfWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(p.w, format, args...)
+	p.offset += n
+	p.err = err
+}
+
+func (p *pdfWriter) object(id int, body string) {
+	if p.offsets == nil {
+		p.offsets = make(map[int]int)
+	}
+	p.offsets[id] = p.offset
+	p.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (p *pdfWriter) trailer(objects int) {
+	xref := p.offset
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", objects+1)
+	for id := 1; id <= objects; id++ {
+		p.printf("%010d 00000 n \n", p.offsets[id])
+	}
+	p.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", objects+1, xref)
+}
+
+func (p *pdfWriter) flush() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
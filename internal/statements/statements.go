@@ -0,0 +1,122 @@
+package statements
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidRange = errors.New("statement range must have from before to")
+
+// Line is one entry of a statement with the balance after it
+type Line struct {
+	Date           time.Time       `json:"date"`
+	EntryID        string          `json:"entry_id"`
+	TransactionID  string          `json:"transaction_id"`
+	Amount         decimal.Decimal `json:"amount"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+
+	// Derived from the transaction, so readers need not look it up
+	Direction    string `json:"direction"`
+	Counterparty string `json:"counterparty,omitempty"`
+	Narrative    string `json:"narrative,omitempty"`
+}
+
+// Statement covers the half-open range [From, To)
+type Statement struct {
+	AccountID      string          `json:"account_id"`
+	Currency       string          `json:"currency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	Lines          []Line          `json:"lines"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// Service builds account statements from the ledger
+type Service struct {
+	ledger *ledger.Ledger
+	store  interfaces.StatementStore
+	cold   interfaces.ColdStorageStore // nil when the store has no cold storage
+}
+
+func NewService(ledgerService *ledger.Ledger, store interfaces.StatementStore) *Service {
+	service := &Service{
+		ledger: ledgerService,
+		store:  store,
+	}
+	if cold, ok := store.(interfaces.ColdStorageStore); ok {
+		service.cold = cold
+	}
+	return service
+}
+
+// Generate computes the opening balance at from, every entry in the range with its
+// running balance, and the closing balance at to. Entries in cold storage are only
+// listed with includeArchived; the balances always include them.
+func (s *Service) Generate(ctx context.Context, accountId string, from, to time.Time, includeArchived bool) (Statement, error) {
+	if !from.Before(to) {
+		return Statement{}, ErrInvalidRange
+	}
+
+	// Entries are stored at microsecond precision, so this excludes exactly the entries at from
+	opening, err := s.ledger.GetBalanceAsOf(accountId, from.Add(-time.Microsecond))
+	if err != nil {
+		return Statement{}, err
+	}
+
+	entries, err := s.store.GetEntriesByAccountBetween(ctx, accountId, from, to)
+	if err != nil {
+		return Statement{}, err
+	}
+	if includeArchived && s.cold != nil {
+		cold, err := s.cold.GetColdEntries(ctx, accountId, from, to)
+		if err != nil {
+			return Statement{}, err
+		}
+		// Cold entries are always older than the live and archived ones
+		entries = append(cold, entries...)
+	}
+
+	described, err := s.ledger.DescribeEntries(ctx, entries)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	currency, err := s.ledger.AccountCurrency(ctx, accountId)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	statement := Statement{
+		AccountID:      accountId,
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		Lines:          make([]Line, 0, len(described)),
+		GeneratedAt:    s.ledger.Now().UTC(),
+	}
+
+	running := opening
+	for _, entry := range described {
+		running = running.Add(entry.Amount)
+		statement.Lines = append(statement.Lines, Line{
+			Date:           entry.CreatedAt,
+			EntryID:        entry.ID,
+			TransactionID:  entry.TransactionID,
+			Amount:         entry.Amount,
+			RunningBalance: running,
+			Direction:      entry.Direction,
+			Counterparty:   entry.Counterparty,
+			Narrative:      entry.Narrative,
+		})
+	}
+	statement.ClosingBalance = running
+	return statement, nil
+}
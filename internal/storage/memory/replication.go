@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ErrNotLeader rejects a write on a follower; clients retry against the leader
+var ErrNotLeader = errors.New("not the replication leader")
+
+// Replicator is the consensus log a ReplicatedStore writes through, e.g. a Raft group of
+// three or more nodes. Propose returns once the command is committed by a quorum and
+// applied to this node's store with Apply; every node applies the same commands in the
+// same order, so their stores stay identical.
+type Replicator interface {
+	Propose(ctx context.Context, command []byte) error
+	// Leader reports the address of the current leader and whether this node is it
+	Leader() (address string, isLeader bool)
+}
+
+// command is one replicated write
+type command struct {
+	Op          string               `json:"op"`
+	Transaction *models.Transaction  `json:"transaction,omitempty"`
+	Entries     []models.LedgerEntry `json:"entries,omitempty"`
+}
+
+const (
+	opSaveEntry       = "save_entry"
+	opSaveTransaction = "save_transaction"
+	opSaveLegs        = "save_legs"
+)
+
+// Apply runs a replicated command against the store. It is the state machine of the
+// replication log and must stay deterministic: no clocks, no randomness. A transaction
+// whose idempotency key is already stored is rejected with ErrKeyApplied on every node,
+// so a write retried after a leader change is applied once.
+func (m *MemoryLedgerStore) Apply(data []byte) error {
+	var cmd command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("decoding replicated command: %w", err)
+	}
+	switch cmd.Op {
+	case opSaveEntry:
+		if len(cmd.Entries) != 1 {
+			return fmt.Errorf("%s carries %d entries", cmd.Op, len(cmd.Entries))
+		}
+		return m.SaveEntry(context.Background(), cmd.Entries[0])
+	case opSaveTransaction:
+		if cmd.Transaction == nil {
+			return fmt.Errorf("%s carries no transaction", cmd.Op)
+		}
+		return m.SaveTransaction(*cmd.Transaction, nil)
+	case opSaveLegs:
+		if cmd.Transaction == nil {
+			return fmt.Errorf("%s carries no transaction", cmd.Op)
+		}
+		return m.SaveTransactionWithLegs(context.Background(), *cmd.Transaction, cmd.Entries)
+	default:
+		return fmt.Errorf("unknown replicated command %q", cmd.Op)
+	}
+}
+
+// ReplicatedStore keeps a MemoryLedgerStore in step across nodes. Writes are accepted on
+// the leader only and reach the local store through the log; reads are served by whichever
+// node is asked, so a follower may briefly lag the leader.
+type ReplicatedStore struct {
+	local      *MemoryLedgerStore
+	replicator Replicator
+}
+
+func NewReplicatedStore(local *MemoryLedgerStore, replicator Replicator) *ReplicatedStore {
+	return &ReplicatedStore{
+		local:      local,
+		replicator: replicator,
+	}
+}
+
+func (r *ReplicatedStore) propose(ctx context.Context, cmd command) error {
+	if address, isLeader := r.replicator.Leader(); !isLeader {
+		return fmt.Errorf("%w: leader is %q", ErrNotLeader, address)
+	}
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	return r.replicator.Propose(ctx, data)
+}
+
+func (r *ReplicatedStore) SaveEntry(ctx context.Context, entry models.LedgerEntry) error {
+	return r.propose(ctx, command{Op: opSaveEntry, Entries: []models.LedgerEntry{entry}})
+}
+
+// SaveTransaction ignores dbTx like the store it replicates
+func (r *ReplicatedStore) SaveTransaction(transaction models.Transaction, dbTx *sql.Tx) error {
+	return r.propose(context.Background(), command{Op: opSaveTransaction, Transaction: &transaction})
+}
+
+func (r *ReplicatedStore) SaveTransactionWithEntries(ctx context.Context, tx models.Transaction, debit models.LedgerEntry, credit models.LedgerEntry) error {
+	return r.SaveTransactionWithLegs(ctx, tx, []models.LedgerEntry{debit, credit})
+}
+
+// SaveTransactionWithLegs replicates the transaction and its legs as one command, so no
+// node ever applies only part of it
+func (r *ReplicatedStore) SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	return r.propose(ctx, command{Op: opSaveLegs, Transaction: &tx, Entries: entries})
+}
+
+func (r *ReplicatedStore) GetLedgerEntries() ([]models.LedgerEntry, error) {
+	return r.local.GetLedgerEntries()
+}
+
+func (r *ReplicatedStore) GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error) {
+	return r.local.GetEntriesByAccount(accountId)
+}
+
+// TransactionExists reads the local copy; on the leader it includes every committed write
+func (r *ReplicatedStore) TransactionExists(tenantId, idempotencyKey string) (bool, error) {
+	return r.local.TransactionExists(tenantId, idempotencyKey)
+}
+
+var _ interfaces.LedgerStore = (*ReplicatedStore)(nil)
+var _ interfaces.MultiLegStore = (*ReplicatedStore)(nil)
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyRejectsAppliedKey(t *testing.T) {
+	tx := models.Transaction{ID: "tx-1", IdempotencyKey: "key-1", FromAccount: "a", ToAccount: "b", Amount: decimal.NewFromInt(5)}
+	data, err := json.Marshal(command{Op: opSaveLegs, Transaction: &tx, Entries: []models.LedgerEntry{
+		{ID: "tx-1-debit", TransactionID: "tx-1", AccountID: "a", Amount: decimal.NewFromInt(-5)},
+		{ID: "tx-1-credit", TransactionID: "tx-1", AccountID: "b", Amount: decimal.NewFromInt(5)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewMemoryLedgerStore()
+	if err := store.Apply(data); err != nil {
+		t.Fatal(err)
+	}
+	// The same write proposed again, e.g. retried against a new leader
+	if err := store.Apply(data); !errors.Is(err, ErrKeyApplied) {
+		t.Fatalf("second apply = %v, want %v", err, ErrKeyApplied)
+	}
+
+	entries, err := store.GetEntriesByAccount("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("b has %d entries, want 1", len(entries))
+	}
+}
@@ -1,26 +1,44 @@
 package memory
 
 import (
-	"context" // standard Go package for request-scoped context (timeouts, cancellation)
-	"sync"    // standard Go package for concurrency primitives like Mutex
-
-	// interface LedgerStore
-	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models" // domain models: LedgerEntry
+	"context"      // standard Go package for request-scoped context (timeouts, cancellation)
+	"database/sql" // only for the LedgerStore signature; there is no database here
+	"errors"
+	"fmt"
+	"sync" // standard Go package for concurrency primitives like Mutex
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces" // interface LedgerStore
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"   // test tenants, left out of platform lookups
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"     // domain models: LedgerEntry
 )
 
+// ErrKeyApplied rejects a second transaction with an idempotency key already stored, as the
+// unique constraint does in Postgres
+var ErrKeyApplied = errors.New("idempotency key already applied")
+
 // MemoryLedgerStore is an in-memory implementation of storage.LedgerStore.
 // It stores ledger entries in memory (slice) and is thread-safe for concurrent writes.
 type MemoryLedgerStore struct {
-	mu           sync.Mutex                    // mutex to protect entries slice from concurrent access
-	entries      []models.LedgerEntry          // slice that holds all ledger entries
-	transactions map[string]models.Transaction // slice that holds all transaction entries
+	mu           sync.Mutex                              // mutex to protect entries slice from concurrent access
+	entries      []models.LedgerEntry                    // slice that holds all ledger entries
+	transactions map[idempotencyScope]models.Transaction // transactions by tenant and idempotency key
+}
+
+// idempotencyScope is what a key is unique within, like UNIQUE (tenant_id, idempotency_key)
+type idempotencyScope struct {
+	tenantId string
+	key      string
+}
+
+func scopeOf(tx models.Transaction) idempotencyScope {
+	return idempotencyScope{tenantId: tx.TenantID, key: tx.IdempotencyKey}
 }
 
 // NewMemoryLedgerStore creates and returns a new MemoryLedgerStore instance
 func NewMemoryLedgerStore() *MemoryLedgerStore {
 	return &MemoryLedgerStore{
 		entries:      make([]models.LedgerEntry, 0),
-		transactions: make(map[string]models.Transaction), // initialize an empty slice of Transactions
+		transactions: make(map[idempotencyScope]models.Transaction), // initialize an empty map of Transactions
 	}
 }
 
@@ -31,8 +49,9 @@ func (m *MemoryLedgerStore) SaveEntry(ctx context.Context, entry models.LedgerEn
 	m.mu.Lock()         // lock the mutex to prevent concurrent writes
 	defer m.mu.Unlock() // unlock automatically when function exits (even if error occurs)
 
-	m.entries = append(m.entries, entry) // append the new entry to the slice
-	return nil                           // always succeeds in memory, so returns nil
+	entry.Sequence = int64(len(m.entries) + 1) // the store assigns positions, like the seq column in Postgres
+	m.entries = append(m.entries, entry)       // append the new entry to the slice
+	return nil                                 // always succeeds in memory, so returns nil
 }
 
 // GetEntries returns a copy of all ledger entries stored in memory.
@@ -63,22 +82,57 @@ func (m *MemoryLedgerStore) GetEntriesByAccount(accountId string) ([]models.Ledg
 	return result, nil
 }
 
-func (m *MemoryLedgerStore) TransactionExists(idempotencyKey string) (bool, error) {
+func (m *MemoryLedgerStore) TransactionExists(tenantId, idempotencyKey string) (bool, error) {
 
 	m.mu.Lock()         // lock the mutex to prevent concurrent writes
 	defer m.mu.Unlock() // unlock automatically when function exits (even if error occurs)
-	_, exists := m.transactions[idempotencyKey]
-	return exists, nil
+	if tenantId != "" {
+		_, exists := m.transactions[idempotencyScope{tenantId: tenantId, key: idempotencyKey}]
+		return exists, nil
+	}
+	for scope := range m.transactions {
+		if scope.key == idempotencyKey && !livemode.IsTestTenant(scope.tenantId) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-func (m *MemoryLedgerStore) SaveTransaction(transaction models.Transaction) error {
+// SaveTransaction ignores dbTx; every write is already atomic under the mutex
+func (m *MemoryLedgerStore) SaveTransaction(transaction models.Transaction, dbTx *sql.Tx) error {
 
 	m.mu.Lock()         // lock the mutex to prevent concurrent writes
 	defer m.mu.Unlock() // unlock automatically when function exits (even if error occurs)
 
-	m.transactions[transaction.IdempotencyKey] = transaction
+	if _, exists := m.transactions[scopeOf(transaction)]; exists {
+		return fmt.Errorf("%w: %s", ErrKeyApplied, transaction.IdempotencyKey)
+	}
+	m.transactions[scopeOf(transaction)] = transaction
+	return nil
+}
+
+func (m *MemoryLedgerStore) SaveTransactionWithEntries(ctx context.Context, tx models.Transaction, debit models.LedgerEntry, credit models.LedgerEntry) error {
+	return m.SaveTransactionWithLegs(ctx, tx, []models.LedgerEntry{debit, credit})
+}
+
+// SaveTransactionWithLegs stores the transaction and its legs under one lock,
+// so readers never see a transaction with only some of its entries
+func (m *MemoryLedgerStore) SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+
+	m.mu.Lock()         // lock the mutex to prevent concurrent writes
+	defer m.mu.Unlock() // unlock automatically when function exits (even if error occurs)
+
+	if _, exists := m.transactions[scopeOf(tx)]; exists {
+		return fmt.Errorf("%w: %s", ErrKeyApplied, tx.IdempotencyKey)
+	}
+	m.transactions[scopeOf(tx)] = tx
+	for _, entry := range entries {
+		entry.Sequence = int64(len(m.entries) + 1)
+		m.entries = append(m.entries, entry)
+	}
 	return nil
 }
 
 // Compile-time check: ensure MemoryLedgerStore implements LedgerStore interface
-// var _ interfaces.LedgerStore = (*MemoryLedgerStore)(nil)
+var _ interfaces.LedgerStore = (*MemoryLedgerStore)(nil)
+var _ interfaces.MultiLegStore = (*MemoryLedgerStore)(nil)
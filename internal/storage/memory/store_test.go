@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func TestTransactionExistsByTenant(t *testing.T) {
+	store := NewMemoryLedgerStore()
+	for _, tenantId := range []string{"acme", "test:acme"} {
+		tx := models.Transaction{ID: tenantId + "-tx", IdempotencyKey: "key-" + tenantId, TenantID: tenantId}
+		if err := store.SaveTransactionWithLegs(context.Background(), tx, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		tenantId, key string
+		want          bool
+	}{
+		{"acme", "key-acme", true},
+		{"globex", "key-acme", false},
+		{"", "key-acme", true},
+		// A test-mode key never settles a live posting, not even a platform-level one
+		{"acme", "key-test:acme", false},
+		{"", "key-test:acme", false},
+		{"test:acme", "key-test:acme", true},
+	}
+	for _, tt := range tests {
+		got, err := store.TransactionExists(tt.tenantId, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("TransactionExists(%q, %q) = %v, want %v", tt.tenantId, tt.key, got, tt.want)
+		}
+	}
+}
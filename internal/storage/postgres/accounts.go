@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+// accountColumns matches the scan order used by scanAccount
+const accountColumns = `id, tenant_id, status, status_reason, type, class, currency, overdraft_limit, limit_profile_id, COALESCE(parent_id, ''),
+	holder_name, holder_email, created_at, updated_at, closed_at, erased_at`
+
+// scanAccount reads one account row and opens its holder details
+func (p *PostgresLedgerStore) scanAccount(scan func(dest ...any) error) (models.Account, error) {
+	var account models.Account
+	err := scan(
+		&account.ID, &account.TenantID, &account.Status, &account.StatusReason, &account.Type, &account.Class, &account.Currency, &account.OverdraftLimit, &account.LimitProfileID, &account.ParentID,
+		&account.HolderName, &account.HolderEmail, &account.CreatedAt, &account.UpdatedAt, &account.ClosedAt, &account.ErasedAt,
+	)
+	if err != nil {
+		return account, err
+	}
+	return account, p.openHolder(&account)
+}
+
+func (p *PostgresLedgerStore) GetAccount(ctx context.Context, id string) (*models.Account, error) {
+	account, err := p.scanAccount(p.db.QueryRowContext(ctx, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (p *PostgresLedgerStore) SaveAccount(ctx context.Context, account models.Account) error {
+	const query = `INSERT INTO accounts (id, tenant_id, status, status_reason, type, class, currency, overdraft_limit, limit_profile_id, parent_id, holder_name, holder_email, created_at, updated_at, closed_at, erased_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NULLIF($10, ''),$11,$12,$13,$14,$15,$16)
+	ON CONFLICT (id) DO UPDATE SET tenant_id = EXCLUDED.tenant_id, status = EXCLUDED.status, status_reason = EXCLUDED.status_reason, type = EXCLUDED.type, class = EXCLUDED.class, currency = EXCLUDED.currency,
+		overdraft_limit = EXCLUDED.overdraft_limit, limit_profile_id = EXCLUDED.limit_profile_id, parent_id = EXCLUDED.parent_id, holder_name = EXCLUDED.holder_name, holder_email = EXCLUDED.holder_email,
+		updated_at = EXCLUDED.updated_at, closed_at = EXCLUDED.closed_at, erased_at = EXCLUDED.erased_at`
+
+	if err := p.sealHolder(&account); err != nil {
+		return err
+	}
+	_, err := p.db.ExecContext(ctx, query,
+		account.ID, account.TenantID, account.Status, account.StatusReason, account.Type, account.Class, account.Currency, account.OverdraftLimit, account.LimitProfileID, account.ParentID,
+		account.HolderName, account.HolderEmail, account.CreatedAt, account.UpdatedAt, account.ClosedAt, account.ErasedAt,
+	)
+	return err
+}
+
+// likeEscaper keeps a search prefix from being read as a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (p *PostgresLedgerStore) SearchAccounts(ctx context.Context, filter models.AccountFilter) ([]models.Account, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	// The platform sees every live tenant's accounts
+	tenantId := tenant.FromContext(ctx)
+	if tenantId != "" {
+		add("tenant_id = $%d", tenantId)
+	} else {
+		add("tenant_id NOT LIKE $%d", livemode.TenantPattern)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.Currency != "" {
+		add("currency = $%d", filter.Currency)
+	}
+	if filter.Query != "" {
+		// The prefix is served by idx_accounts_id_pattern, the alias by the aliases primary key
+		args = append(args, likeEscaper.Replace(filter.Query)+"%", filter.Query, tenantId)
+		conditions = append(conditions, fmt.Sprintf(
+			"(id LIKE $%d OR id IN (SELECT account_id FROM account_aliases WHERE alias = $%d AND tenant_id = $%d))",
+			len(args)-2, len(args)-1, len(args)))
+	}
+
+	column, descending := strings.CutPrefix(filter.Sort, "-")
+	if column == "" {
+		column = "id"
+	}
+	direction, compare := "ASC", ">"
+	if descending {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		// Keyset pagination: the ID breaks ties between accounts with the same timestamp
+		if column == "id" {
+			add("id "+compare+" $%d", filter.After.ID)
+		} else {
+			args = append(args, filter.After.At, filter.After.ID)
+			conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, compare, len(args)-1, len(args)))
+		}
+	}
+
+	query := `SELECT ` + accountColumns + ` FROM accounts`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	order := "id " + direction
+	if column != "id" {
+		order = column + " " + direction + ", " + order
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", order, len(args))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		account, err := p.scanAccount(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+var (
+	_ interfaces.AccountStore       = (*PostgresLedgerStore)(nil)
+	_ interfaces.AccountSearchStore = (*PostgresLedgerStore)(nil)
+)
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+const aliasColumns = `alias, type, account_id, tenant_id, created_at`
+
+func scanAlias(scan func(dest ...any) error) (models.AccountAlias, error) {
+	var alias models.AccountAlias
+	err := scan(&alias.Alias, &alias.Type, &alias.AccountID, &alias.TenantID, &alias.CreatedAt)
+	return alias, err
+}
+
+func (p *PostgresLedgerStore) GetAlias(ctx context.Context, alias string) (*models.AccountAlias, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+aliasColumns+` FROM account_aliases WHERE tenant_id = $1 AND alias = $2`,
+		tenant.FromContext(ctx), alias)
+	found, err := scanAlias(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (p *PostgresLedgerStore) ListAliases(ctx context.Context, accountId string) ([]models.AccountAlias, error) {
+	// Served by idx_account_aliases_account
+	rows, err := p.db.QueryContext(ctx, `SELECT `+aliasColumns+` FROM account_aliases
+	WHERE account_id = $1 AND tenant_id = $2 ORDER BY type, alias`, accountId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []models.AccountAlias{}
+	for rows.Next() {
+		alias, err := scanAlias(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+func (p *PostgresLedgerStore) CreateAlias(ctx context.Context, alias models.AccountAlias) (bool, error) {
+	const query = `INSERT INTO account_aliases (` + aliasColumns + `) VALUES ($1,$2,$3,$4,$5)
+	ON CONFLICT (tenant_id, alias) DO NOTHING`
+
+	result, err := p.db.ExecContext(ctx, query, alias.Alias, alias.Type, alias.AccountID, alias.TenantID, alias.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted == 1, err
+}
+
+func (p *PostgresLedgerStore) DeleteAlias(ctx context.Context, alias string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM account_aliases WHERE tenant_id = $1 AND alias = $2`,
+		tenant.FromContext(ctx), alias)
+	return err
+}
+
+var _ interfaces.AliasStore = (*PostgresLedgerStore)(nil)
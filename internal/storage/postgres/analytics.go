@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) GetLastAnalyticsExport(ctx context.Context) (*models.AnalyticsExport, error) {
+	const query = `SELECT date, entries, transactions, entries_key, transactions_key, exported_at
+	FROM analytics_exports ORDER BY date DESC LIMIT 1`
+
+	var export models.AnalyticsExport
+	err := p.db.QueryRowContext(ctx, query).Scan(&export.Date, &export.Entries, &export.Transactions,
+		&export.EntriesKey, &export.TransactionsKey, &export.ExportedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (p *PostgresLedgerStore) SaveAnalyticsExport(ctx context.Context, export models.AnalyticsExport) error {
+	// A re-export overwrites the same objects, so it replaces the record as well
+	const query = `INSERT INTO analytics_exports (date, entries, transactions, entries_key, transactions_key, exported_at)
+	VALUES ($1,$2,$3,$4,$5,$6)
+	ON CONFLICT (date) DO UPDATE SET entries = EXCLUDED.entries, transactions = EXCLUDED.transactions,
+		entries_key = EXCLUDED.entries_key, transactions_key = EXCLUDED.transactions_key, exported_at = EXCLUDED.exported_at`
+
+	_, err := p.db.ExecContext(ctx, query, export.Date, export.Entries, export.Transactions,
+		export.EntriesKey, export.TransactionsKey, export.ExportedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) GetFirstTransactionTime(ctx context.Context) (*time.Time, error) {
+	var first sql.NullTime
+	if err := p.db.QueryRowContext(ctx, `SELECT MIN(created_at) FROM transactions`).Scan(&first); err != nil {
+		return nil, err
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+	return &first.Time, nil
+}
+
+func (p *PostgresLedgerStore) GetTransactionsBetween(ctx context.Context, from, to time.Time) ([]models.Transaction, error) {
+	const query = `SELECT ` + transactionColumns + ` FROM transactions
+	WHERE created_at >= $1 AND created_at < $2 ORDER BY created_at, id`
+
+	rows, err := p.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanTransactions(rows)
+}
+
+func (p *PostgresLedgerStore) GetEntriesBetween(ctx context.Context, from, to time.Time) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM (
+		SELECT ` + entryColumns + ` FROM ledger_entries WHERE created_at >= $1 AND created_at < $2
+		UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive WHERE created_at >= $1 AND created_at < $2
+	) e ORDER BY seq`
+
+	rows, err := p.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+var _ interfaces.AnalyticsStore = (*PostgresLedgerStore)(nil)
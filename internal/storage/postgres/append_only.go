@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// appendOnlyTables are guarded by the reject_ledger_mutation triggers in schema.sql
+var appendOnlyTables = []string{"transactions", "ledger_entries", "ledger_entries_archive", "cold_entry_batches", "ledger_checkpoints", "ledger_chain_links"}
+
+// compactionMode lets the deletes of compaction through the append-only triggers, for the
+// rest of the database transaction
+const compactionMode = `SET LOCAL ledger.compaction = 'on'`
+
+// UnprotectedTables checks that both triggers of every table exist and are enabled.
+// tgenabled 'D' is a trigger disabled with ALTER TABLE ... DISABLE TRIGGER.
+func (p *PostgresLedgerStore) UnprotectedTables(ctx context.Context) ([]string, error) {
+	const query = `SELECT t.name FROM unnest($1::text[]) AS t(name)
+	WHERE (SELECT COUNT(*) FROM pg_trigger g
+		WHERE g.tgrelid = to_regclass(t.name)
+		AND g.tgname IN (t.name || '_append_only', t.name || '_no_truncate')
+		AND g.tgenabled <> 'D') < 2
+	ORDER BY t.name`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(appendOnlyTables))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+var _ interfaces.AppendOnlyStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) AppendAudit(ctx context.Context, record models.AuditRecord) error {
+	const query = `INSERT INTO audit_log (request_id, actor, action, resource, before, after, status, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`
+
+	_, err := p.db.ExecContext(ctx, query,
+		record.RequestID, record.Actor, record.Action, record.Resource,
+		nullableJSON(record.Before), nullableJSON(record.After), record.Status, record.CreatedAt,
+	)
+	return err
+}
+
+func (p *PostgresLedgerStore) QueryAudit(ctx context.Context, filter models.AuditFilter) ([]models.AuditRecord, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Resource != "" {
+		add("resource LIKE $%d", filter.Resource+"%")
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+
+	query := `SELECT id, request_id, actor, action, resource, COALESCE(before, 'null'), COALESCE(after, 'null'), status, created_at FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.AuditRecord{}
+	for rows.Next() {
+		var record models.AuditRecord
+		var before, after []byte
+		if err := rows.Scan(&record.ID, &record.RequestID, &record.Actor, &record.Action, &record.Resource,
+			&before, &after, &record.Status, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		if string(before) != "null" {
+			record.Before = before
+		}
+		if string(after) != "null" {
+			record.After = after
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// nullableJSON stores an empty document as SQL NULL
+func nullableJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+var _ interfaces.AuditStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+var ErrLedgerNotEmpty = errors.New("restore target already holds transactions or entries")
+
+func (p *PostgresLedgerStore) DumpLedger(ctx context.Context, transaction func(models.Transaction) error, entry func(models.LedgerEntry) error) (time.Time, error) {
+	// Every query below sees the same snapshot, so postings made during the dump are left out entirely
+	dbTx, err := p.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer dbTx.Rollback()
+
+	var snapshotAt time.Time
+	if err := dbTx.QueryRowContext(ctx, `SELECT NOW()`).Scan(&snapshotAt); err != nil {
+		return time.Time{}, err
+	}
+
+	rows, err := dbTx.QueryContext(ctx, `SELECT `+transactionColumns+` FROM transactions ORDER BY created_at, id`)
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = eachRow(rows, func() error {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		return transaction(tx)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Cold entries are the oldest, so they come first
+	rows, err = dbTx.QueryContext(ctx, `SELECT data FROM cold_entry_batches ORDER BY first_seq`)
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = eachRow(rows, func() error {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		batch, err := decompressEntries(data)
+		if err != nil {
+			return err
+		}
+		for _, e := range batch {
+			if err := entry(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	const entries = `SELECT ` + entryColumns + ` FROM (
+		SELECT ` + entryColumns + ` FROM ledger_entries
+		UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive
+	) e ORDER BY seq`
+
+	rows, err = dbTx.QueryContext(ctx, entries)
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = eachRow(rows, func() error {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return err
+		}
+		return entry(e)
+	})
+	return snapshotAt, err
+}
+
+// eachRow calls visit for every row and closes rows
+func eachRow(rows *sql.Rows, visit func() error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := visit(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (p *PostgresLedgerStore) BeginRestore(ctx context.Context) (interfaces.LedgerRestore, error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var used bool
+	const query = `SELECT EXISTS (SELECT 1 FROM transactions) OR EXISTS (SELECT 1 FROM ledger_entries)
+		OR EXISTS (SELECT 1 FROM ledger_entries_archive) OR EXISTS (SELECT 1 FROM cold_entry_batches)`
+	if err := dbTx.QueryRowContext(ctx, query).Scan(&used); err != nil {
+		dbTx.Rollback()
+		return nil, err
+	}
+	if used {
+		dbTx.Rollback()
+		return nil, ErrLedgerNotEmpty
+	}
+	return &ledgerRestore{store: p, ctx: ctx, dbTx: dbTx}, nil
+}
+
+// ledgerRestore writes every entry back into ledger_entries with its original sequence and
+// hashes; balances and snapshots are rebuilt from the entries as they are read.
+type ledgerRestore struct {
+	store *PostgresLedgerStore
+	ctx   context.Context
+	dbTx  *sql.Tx
+}
+
+func (r *ledgerRestore) RestoreTransaction(tx models.Transaction) error {
+	return r.store.SaveTransaction(tx, r.dbTx)
+}
+
+func (r *ledgerRestore) RestoreEntry(entry models.LedgerEntry) error {
+	const query = `INSERT INTO ledger_entries (id, seq, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`
+
+	_, err := r.dbTx.ExecContext(r.ctx, query, entry.ID, entry.Sequence, entry.TransactionID, entry.AccountID,
+		entry.Amount, entry.CreatedAt, entry.PrevHash, entry.Hash, entry.TenantID)
+	if err != nil {
+		return err
+	}
+	return saveChainLink(r.ctx, r.dbTx, entry)
+}
+
+func (r *ledgerRestore) Commit() error {
+	// New postings must continue after the highest restored sequence
+	const query = `SELECT setval(pg_get_serial_sequence('ledger_entries', 'seq'),
+		COALESCE(MAX(seq), 1), MAX(seq) IS NOT NULL) FROM ledger_entries`
+
+	if _, err := r.dbTx.ExecContext(r.ctx, query); err != nil {
+		r.dbTx.Rollback()
+		return err
+	}
+	return r.dbTx.Commit()
+}
+
+func (r *ledgerRestore) Rollback() error {
+	return r.dbTx.Rollback()
+}
+
+var _ interfaces.BackupStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const balanceAlertColumns = `id, account_id, type, threshold, webhook_url, enabled, updated_at`
+
+func scanBalanceAlert(scan func(dest ...any) error) (models.BalanceAlert, error) {
+	var alert models.BalanceAlert
+	err := scan(&alert.ID, &alert.AccountID, &alert.Type, &alert.Threshold, &alert.WebhookURL,
+		&alert.Enabled, &alert.UpdatedAt)
+	return alert, err
+}
+
+func (p *PostgresLedgerStore) ListBalanceAlerts(ctx context.Context, accountIds ...string) ([]models.BalanceAlert, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+balanceAlertColumns+` FROM balance_alerts
+	WHERE account_id = ANY($1) ORDER BY account_id, id`, pq.Array(accountIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []models.BalanceAlert{}
+	for rows.Next() {
+		alert, err := scanBalanceAlert(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (p *PostgresLedgerStore) GetBalanceAlert(ctx context.Context, id string) (*models.BalanceAlert, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+balanceAlertColumns+` FROM balance_alerts WHERE id = $1`, id)
+	alert, err := scanBalanceAlert(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (p *PostgresLedgerStore) SaveBalanceAlert(ctx context.Context, alert models.BalanceAlert) error {
+	const query = `INSERT INTO balance_alerts (` + balanceAlertColumns + `) VALUES ($1,$2,$3,$4,$5,$6,$7)
+	ON CONFLICT (id) DO UPDATE SET account_id = EXCLUDED.account_id, type = EXCLUDED.type,
+	threshold = EXCLUDED.threshold, webhook_url = EXCLUDED.webhook_url, enabled = EXCLUDED.enabled,
+	updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, alert.ID, alert.AccountID, alert.Type, alert.Threshold,
+		alert.WebhookURL, alert.Enabled, alert.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeleteBalanceAlert(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM balance_alerts WHERE id = $1`, id)
+	return err
+}
+
+var _ interfaces.BalanceAlertStore = (*PostgresLedgerStore)(nil)
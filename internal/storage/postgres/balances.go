@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// How concurrent postings to the same account are kept from losing updates
+const (
+	Pessimistic = "pessimistic" // lock the balance rows with SELECT ... FOR UPDATE
+	Optimistic  = "optimistic"  // read without locks and compare-and-swap the row versions
+)
+
+var (
+	ErrUnknownConcurrencyMode = errors.New("unknown balance concurrency mode")
+	// ErrVersionConflict means another posting changed a balance row after it was read;
+	// the posting is retried from the start
+	ErrVersionConflict = errors.New("balance changed concurrently")
+)
+
+var versionConflicts = metrics.NewCounter("balance_version_conflicts_total",
+	"Postings whose optimistic balance update lost to a concurrent posting")
+
+// SetConcurrencyMode switches between row locks and optimistic version checks. Optimistic
+// mode avoids holding locks while the transaction is written, at the cost of retries
+// when the same accounts are posted to at once.
+func (p *PostgresLedgerStore) SetConcurrencyMode(mode string) error {
+	if mode != Pessimistic && mode != Optimistic {
+		return fmt.Errorf("%w: %q", ErrUnknownConcurrencyMode, mode)
+	}
+	p.concurrency = mode
+	return nil
+}
+
+func (p *PostgresLedgerStore) GetMaterializedBalance(ctx context.Context, accountId string) (decimal.Decimal, bool, error) {
+	var balance decimal.Decimal
+	err := p.db.QueryRowContext(ctx, `SELECT balance FROM account_balances WHERE account_id = $1`, accountId).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+	return balance, true, nil
+}
+
+func (p *PostgresLedgerStore) SaveTransactionChecked(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+	check func(balances map[string]decimal.Decimal) error) error {
+	return p.withRetry(ctx, "save_transaction", func() error {
+		return p.saveTransactionWithLegs(ctx, tx, entries, check)
+	})
+}
+
+// balanceAccounts returns the distinct accounts of the legs in account order
+func balanceAccounts(entries []models.LedgerEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.AccountID)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// readBalances returns the balance rows of the accounts with their versions. In pessimistic
+// mode the rows are locked with SELECT ... FOR UPDATE, in account order, so two postings
+// touching the same accounts queue up instead of deadlocking. Missing rows are first
+// created from the sum of the account's entries.
+func (p *PostgresLedgerStore) readBalances(ctx context.Context, dbTx *sql.Tx, ids []string) (map[string]decimal.Decimal, map[string]int64, error) {
+	const create = `INSERT INTO account_balances (account_id, balance, updated_at)
+	SELECT a.id, COALESCE((SELECT SUM(e.amount) FROM ` + allEntries + ` e WHERE e.account_id = a.id), 0), NOW()
+	FROM unnest($1::text[]) AS a(id)
+	WHERE NOT EXISTS (SELECT 1 FROM account_balances b WHERE b.account_id = a.id)
+	ON CONFLICT (account_id) DO NOTHING`
+	if _, err := dbTx.ExecContext(ctx, create, pq.Array(ids)); err != nil {
+		return nil, nil, err
+	}
+
+	query := `SELECT account_id, balance, version FROM account_balances WHERE account_id = ANY($1) ORDER BY account_id`
+	if p.concurrency != Optimistic {
+		query += ` FOR UPDATE`
+	}
+	rows, err := dbTx.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	balances := make(map[string]decimal.Decimal, len(ids))
+	versions := make(map[string]int64, len(ids))
+	for rows.Next() {
+		var id string
+		var balance decimal.Decimal
+		var version int64
+		if err := rows.Scan(&id, &balance, &version); err != nil {
+			return nil, nil, err
+		}
+		balances[id] = balance
+		versions[id] = version
+	}
+	return balances, versions, rows.Err()
+}
+
+// applyBalances adds the legs to the balance rows, in account order. Each update only
+// matches the version that was read; in pessimistic mode that always holds, in optimistic
+// mode a miss means another posting got there first.
+func (p *PostgresLedgerStore) applyBalances(ctx context.Context, dbTx *sql.Tx, ids []string, entries []models.LedgerEntry, versions map[string]int64) error {
+	deltas := make(map[string]decimal.Decimal)
+	for _, entry := range entries {
+		deltas[entry.AccountID] = deltas[entry.AccountID].Add(entry.Amount)
+	}
+
+	const query = `UPDATE account_balances SET balance = balance + $2, version = version + 1, updated_at = NOW()
+	WHERE account_id = $1 AND version = $3`
+	for _, id := range ids {
+		result, err := dbTx.ExecContext(ctx, query, id, deltas[id], versions[id])
+		if err != nil {
+			return err
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			versionConflicts.Inc()
+			return fmt.Errorf("%w: %s", ErrVersionConflict, id)
+		}
+	}
+	return nil
+}
+
+var _ interfaces.BalanceStore = (*PostgresLedgerStore)(nil)
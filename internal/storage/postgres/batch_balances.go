@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// GetBalances prefers the materialized balance and sums the entries of accounts that have none
+func (p *PostgresLedgerStore) GetBalances(ctx context.Context, accountIds []string) ([]models.BatchBalance, error) {
+	const query = `SELECT ids.id, COALESCE(a.tenant_id, ''), COALESCE(a.currency, ''), COALESCE(b.balance, e.balance, 0)
+	FROM unnest($1::TEXT[]) WITH ORDINALITY AS ids(id, n)
+	LEFT JOIN accounts a ON a.id = ids.id
+	LEFT JOIN account_balances b ON b.account_id = ids.id
+	LEFT JOIN LATERAL (
+		SELECT SUM(x.amount) AS balance FROM ` + allEntries + ` x WHERE x.account_id = ids.id AND b.account_id IS NULL
+	) e ON TRUE
+	ORDER BY ids.n`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(accountIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make([]models.BatchBalance, 0, len(accountIds))
+	for rows.Next() {
+		var balance models.BatchBalance
+		if err := rows.Scan(&balance.AccountID, &balance.TenantID, &balance.Currency, &balance.Balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+var _ interfaces.BatchBalanceStore = (*PostgresLedgerStore)(nil)
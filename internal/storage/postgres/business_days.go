@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const businessDayColumns = `date, starts_at, ends_at, transaction_count, volume, accounts, closed_at`
+
+func (p *PostgresLedgerStore) queryBusinessDay(ctx context.Context, query string, args ...any) (*models.BusinessDay, error) {
+	var day models.BusinessDay
+	err := p.db.QueryRowContext(ctx, query, args...).Scan(&day.Date, &day.StartsAt, &day.EndsAt,
+		&day.TransactionCount, &day.Volume, &day.Accounts, &day.ClosedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &day, nil
+}
+
+func (p *PostgresLedgerStore) GetLatestBusinessDay(ctx context.Context) (*models.BusinessDay, error) {
+	return p.queryBusinessDay(ctx, `SELECT `+businessDayColumns+` FROM business_days ORDER BY ends_at DESC LIMIT 1`)
+}
+
+func (p *PostgresLedgerStore) GetBusinessDay(ctx context.Context, date string) (*models.BusinessDay, error) {
+	return p.queryBusinessDay(ctx, `SELECT `+businessDayColumns+` FROM business_days WHERE date = $1`, date)
+}
+
+func (p *PostgresLedgerStore) ListDailyAccountSummaries(ctx context.Context, date string) ([]models.DailyAccountSummary, error) {
+	const query = `SELECT business_date, account_id, debits, credits, net, entries
+	FROM daily_account_summaries WHERE business_date = $1 ORDER BY account_id`
+
+	rows, err := p.db.QueryContext(ctx, query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []models.DailyAccountSummary{}
+	for rows.Next() {
+		var s models.DailyAccountSummary
+		if err := rows.Scan(&s.Date, &s.AccountID, &s.Debits, &s.Credits, &s.Net, &s.Entries); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+func (p *PostgresLedgerStore) CloseBusinessDay(ctx context.Context, day models.BusinessDay) (models.BusinessDay, error) {
+	var closed models.BusinessDay
+	err := p.withRetry(ctx, "close_business_day", func() (err error) {
+		closed, err = p.closeBusinessDay(ctx, day)
+		return err
+	})
+	return closed, err
+}
+
+func (p *PostgresLedgerStore) closeBusinessDay(ctx context.Context, day models.BusinessDay) (closed models.BusinessDay, err error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback()
+		}
+	}()
+
+	const insertDay = `INSERT INTO business_days (` + businessDayColumns + `)
+	SELECT $1, $2, $3, COUNT(*), COALESCE(SUM(amount), 0), 0, $4
+	FROM transactions WHERE created_at >= $2 AND created_at < $3
+	ON CONFLICT (date) DO NOTHING`
+
+	result, err := dbTx.ExecContext(ctx, insertDay, day.Date, day.StartsAt, day.EndsAt, day.ClosedAt)
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	if inserted == 0 {
+		// Another replica closed the day first
+		dbTx.Rollback()
+		existing, err := p.GetBusinessDay(ctx, day.Date)
+		if err != nil || existing == nil {
+			return models.BusinessDay{}, err
+		}
+		return *existing, nil
+	}
+
+	const insertSummaries = `INSERT INTO daily_account_summaries (business_date, account_id, debits, credits, net, entries)
+	SELECT $1, account_id,
+		COALESCE(SUM(-amount) FILTER (WHERE amount < 0), 0),
+		COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0),
+		SUM(amount), COUNT(*)
+	FROM ` + allEntries + ` e
+	WHERE created_at >= $2 AND created_at < $3
+	GROUP BY account_id`
+
+	if _, err = dbTx.ExecContext(ctx, insertSummaries, day.Date, day.StartsAt, day.EndsAt); err != nil {
+		return models.BusinessDay{}, err
+	}
+
+	const countAccounts = `UPDATE business_days
+	SET accounts = (SELECT COUNT(*) FROM daily_account_summaries WHERE business_date = $1)
+	WHERE date = $1
+	RETURNING ` + businessDayColumns
+
+	err = dbTx.QueryRowContext(ctx, countAccounts, day.Date).Scan(&closed.Date, &closed.StartsAt, &closed.EndsAt,
+		&closed.TransactionCount, &closed.Volume, &closed.Accounts, &closed.ClosedAt)
+	if err != nil {
+		return models.BusinessDay{}, err
+	}
+	return closed, dbTx.Commit()
+}
+
+var _ interfaces.BusinessDayStore = (*PostgresLedgerStore)(nil)
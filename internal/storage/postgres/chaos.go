@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/chaos"
+)
+
+// SetCommitFailureRate makes that share of postings fail at commit time with what looks
+// like a serialization failure, exercising the retry policy. For resilience testing only.
+func (p *PostgresLedgerStore) SetCommitFailureRate(rate float64) {
+	p.commitFailureRate = rate
+}
+
+// commitPosting commits a posting unless a fault is injected; the caller rolls back on error
+func (p *PostgresLedgerStore) commitPosting(dbTx *sql.Tx) error {
+	if chaos.Hit(p.commitFailureRate) {
+		chaos.Injected("commit")
+		return fmt.Errorf("%w: %w", chaos.ErrInjectedFault, &pq.Error{Code: "40001", Message: "injected commit failure"})
+	}
+	return dbTx.Commit()
+}
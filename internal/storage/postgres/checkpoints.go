@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// checkpointColumns matches the scan order used by scanCheckpoint
+const checkpointColumns = `id, from_seq, to_seq, size, root, prev_root, key_id, signature, created_at`
+
+func scanCheckpoint(scan func(dest ...any) error) (models.Checkpoint, error) {
+	var c models.Checkpoint
+	err := scan(&c.ID, &c.FromSeq, &c.ToSeq, &c.Size, &c.Root, &c.PrevRoot, &c.KeyID, &c.Signature, &c.CreatedAt)
+	return c, err
+}
+
+func (p *PostgresLedgerStore) LastCheckpoint(ctx context.Context) (*models.Checkpoint, error) {
+	checkpoint, err := scanCheckpoint(p.db.QueryRowContext(ctx,
+		`SELECT `+checkpointColumns+` FROM ledger_checkpoints ORDER BY to_seq DESC LIMIT 1`).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (p *PostgresLedgerStore) SaveCheckpoint(ctx context.Context, checkpoint *models.Checkpoint) (bool, error) {
+	const query = `INSERT INTO ledger_checkpoints (from_seq, to_seq, size, root, prev_root, key_id, signature, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT (from_seq) DO NOTHING RETURNING id`
+
+	err := p.db.QueryRowContext(ctx, query, checkpoint.FromSeq, checkpoint.ToSeq, checkpoint.Size, checkpoint.Root,
+		checkpoint.PrevRoot, checkpoint.KeyID, checkpoint.Signature, checkpoint.CreatedAt).Scan(&checkpoint.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (p *PostgresLedgerStore) CheckpointCovering(ctx context.Context, seq int64) (*models.Checkpoint, error) {
+	checkpoint, err := scanCheckpoint(p.db.QueryRowContext(ctx,
+		`SELECT `+checkpointColumns+` FROM ledger_checkpoints WHERE from_seq <= $1 AND to_seq >= $1`, seq).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (p *PostgresLedgerStore) ListCheckpoints(ctx context.Context, limit int) ([]models.Checkpoint, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT `+checkpointColumns+` FROM ledger_checkpoints ORDER BY to_seq DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := []models.Checkpoint{}
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func (p *PostgresLedgerStore) MaxEntrySeq(ctx context.Context) (int64, error) {
+	// Compaction moves entries, keeping their seq, so the newest may be in either table
+	const query = `SELECT GREATEST(
+		(SELECT COALESCE(MAX(seq), 0) FROM ledger_entries),
+		(SELECT COALESCE(MAX(seq), 0) FROM ledger_entries_archive))`
+
+	var seq int64
+	err := p.db.QueryRowContext(ctx, query).Scan(&seq)
+	return seq, err
+}
+
+func (p *PostgresLedgerStore) EntriesBetween(ctx context.Context, from, to int64, limit int) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM (
+		SELECT ` + entryColumns + ` FROM ledger_entries WHERE seq BETWEEN $1 AND $2
+		UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive WHERE seq BETWEEN $1 AND $2
+	) e ORDER BY seq LIMIT $3`
+
+	rows, err := p.db.QueryContext(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+func (p *PostgresLedgerStore) GetEntry(ctx context.Context, id string) (*models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM ledger_entries WHERE id = $1
+	UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive WHERE id = $1`
+
+	rows, err := p.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanEntries(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+var _ interfaces.CheckpointStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// coldEntry is the line format of a cold batch; models.LedgerEntry carries no JSON tags
+type coldEntry struct {
+	ID            string          `json:"id"`
+	Sequence      int64           `json:"seq"`
+	TransactionID string          `json:"transaction_id"`
+	AccountID     string          `json:"account_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PrevHash      string          `json:"prev_hash"`
+	Hash          string          `json:"hash"`
+	TenantID      string          `json:"tenant_id"`
+}
+
+// coldBatch groups the entries of one account, tenant and month
+type coldBatch struct {
+	accountId string
+	tenantId  string
+	period    string
+	entries   []models.LedgerEntry
+}
+
+func (p *PostgresLedgerStore) FreezeEntriesBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer dbTx.Rollback()
+
+	if _, err := dbTx.ExecContext(ctx, compactionMode); err != nil {
+		return 0, err
+	}
+	// The archive only holds entries a snapshot already covers, so nothing here feeds a balance
+	const take = `DELETE FROM ledger_entries_archive WHERE created_at < $1
+	RETURNING ` + entryColumns
+
+	rows, err := dbTx.QueryContext(ctx, take, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	const insert = `INSERT INTO cold_entry_batches (account_id, tenant_id, period, entries, first_seq, last_seq,
+		first_created_at, last_created_at, debits, credits, last_hash, data, archived_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`
+
+	archivedAt := p.clock.Now()
+	for _, batch := range coldBatches(entries) {
+		data, err := compressEntries(batch.entries)
+		if err != nil {
+			return 0, err
+		}
+
+		debits, credits := decimal.Zero, decimal.Zero
+		for _, entry := range batch.entries {
+			if entry.Amount.IsNegative() {
+				debits = debits.Sub(entry.Amount)
+			} else {
+				credits = credits.Add(entry.Amount)
+			}
+		}
+
+		first, last := batch.entries[0], batch.entries[len(batch.entries)-1]
+		_, err = dbTx.ExecContext(ctx, insert, batch.accountId, batch.tenantId, batch.period, len(batch.entries),
+			first.Sequence, last.Sequence, first.CreatedAt, last.CreatedAt, debits, credits, last.Hash, data, archivedAt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(entries)), dbTx.Commit()
+}
+
+// coldBatches splits entries per account, tenant and month, each batch ordered by sequence
+func coldBatches(entries []models.LedgerEntry) []*coldBatch {
+	slices.SortFunc(entries, func(a, b models.LedgerEntry) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+
+	var batches []*coldBatch
+	index := make(map[[3]string]*coldBatch)
+	for _, entry := range entries {
+		key := [3]string{entry.AccountID, entry.TenantID, entry.CreatedAt.UTC().Format("2006-01")}
+		batch, ok := index[key]
+		if !ok {
+			batch = &coldBatch{accountId: key[0], tenantId: key[1], period: key[2]}
+			index[key] = batch
+			batches = append(batches, batch)
+		}
+		batch.entries = append(batch.entries, entry)
+	}
+	return batches
+}
+
+// compressEntries writes one JSON line per entry through gzip
+func compressEntries(entries []models.LedgerEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, entry := range entries {
+		err := encoder.Encode(coldEntry{
+			ID:            entry.ID,
+			Sequence:      entry.Sequence,
+			TransactionID: entry.TransactionID,
+			AccountID:     entry.AccountID,
+			Amount:        entry.Amount,
+			CreatedAt:     entry.CreatedAt,
+			PrevHash:      entry.PrevHash,
+			Hash:          entry.Hash,
+			TenantID:      entry.TenantID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressEntries(data []byte) ([]models.LedgerEntry, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var entries []models.LedgerEntry
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var line coldEntry
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, err
+		}
+		entries = append(entries, models.LedgerEntry{
+			ID:            line.ID,
+			TransactionID: line.TransactionID,
+			AccountID:     line.AccountID,
+			Amount:        line.Amount,
+			CreatedAt:     line.CreatedAt,
+			Sequence:      line.Sequence,
+			PrevHash:      line.PrevHash,
+			Hash:          line.Hash,
+			TenantID:      line.TenantID,
+		})
+	}
+	return entries, scanner.Err()
+}
+
+func (p *PostgresLedgerStore) GetColdEntries(ctx context.Context, accountId string, from, to time.Time) ([]models.LedgerEntry, error) {
+	// Only batches overlapping the range are decompressed
+	const query = `SELECT data FROM cold_entry_batches
+	WHERE account_id = $1 AND last_created_at >= $2 AND ($3::TIMESTAMP IS NULL OR first_created_at < $3)
+	ORDER BY first_seq`
+
+	var until sql.NullTime
+	if !to.IsZero() {
+		until = sql.NullTime{Time: to, Valid: true}
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, accountId, from, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.LedgerEntry
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		batch, err := decompressEntries(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range batch {
+			if entry.CreatedAt.Before(from) || (!to.IsZero() && !entry.CreatedAt.Before(to)) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Batches of different months or tenants can interleave
+	slices.SortFunc(entries, func(a, b models.LedgerEntry) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return entries, nil
+}
+
+func (p *PostgresLedgerStore) GetColdChainHead(ctx context.Context, accountId string) (string, error) {
+	const query = `SELECT last_hash FROM cold_entry_batches WHERE account_id = $1 ORDER BY last_seq DESC LIMIT 1`
+
+	var hash string
+	err := p.db.QueryRowContext(ctx, query, accountId).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+var _ interfaces.ColdStorageStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+)
+
+// ConnStringFromEnv builds the connection string from DB_USER, DB_PASSWORD, DB_HOST,
+// DB_PORT and DB_NAME, shared by the server and the operational commands
+func ConnStringFromEnv() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+}
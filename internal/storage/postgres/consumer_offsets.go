@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+func (p *PostgresLedgerStore) ConsumerOffsets(ctx context.Context, consumer, topic string) (map[int]int64, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT partition, next_offset FROM consumer_offsets
+	WHERE consumer = $1 AND topic = $2`, consumer, topic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offsets := map[int]int64{}
+	for rows.Next() {
+		var partition int
+		var offset int64
+		if err := rows.Scan(&partition, &offset); err != nil {
+			return nil, err
+		}
+		offsets[partition] = offset
+	}
+	return offsets, rows.Err()
+}
+
+func (p *PostgresLedgerStore) ConsumeOnce(ctx context.Context, consumer, topic string, partition int, offset int64, apply func(tx *sql.Tx) error) (bool, error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer dbTx.Rollback()
+
+	// Create the row on first use, then lock it so two consumers with the same name
+	// cannot both apply the message
+	if _, err := dbTx.ExecContext(ctx, `INSERT INTO consumer_offsets (consumer, topic, partition, next_offset, updated_at)
+	VALUES ($1, $2, $3, 0, NOW()) ON CONFLICT DO NOTHING`, consumer, topic, partition); err != nil {
+		return false, err
+	}
+	var next int64
+	if err := dbTx.QueryRowContext(ctx, `SELECT next_offset FROM consumer_offsets
+	WHERE consumer = $1 AND topic = $2 AND partition = $3 FOR UPDATE`, consumer, topic, partition).Scan(&next); err != nil {
+		return false, err
+	}
+	if offset < next {
+		return false, nil
+	}
+
+	if err := apply(dbTx); err != nil {
+		return false, err
+	}
+	if _, err := dbTx.ExecContext(ctx, `UPDATE consumer_offsets SET next_offset = $4, updated_at = NOW()
+	WHERE consumer = $1 AND topic = $2 AND partition = $3`, consumer, topic, partition, offset+1); err != nil {
+		return false, err
+	}
+	return true, dbTx.Commit()
+}
+
+var _ interfaces.ConsumerOffsetStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const (
+	dailyProjection = "daily_aggregates"
+
+	// dailyLargestKept is how many of each day's largest transactions the projection retains
+	dailyLargestKept = 25
+
+	// dailyProjectionOverlap re-reads this many sequence numbers behind the checkpoint. Entries of
+	// concurrent postings can commit out of sequence order; the overlap catches the stragglers and
+	// daily_projected_transactions keeps them from being counted twice.
+	dailyProjectionOverlap = 1000
+)
+
+func (p *PostgresLedgerStore) ProjectDailyAggregates(ctx context.Context, limit int) (int, error) {
+	var projected int
+	err := p.withRetry(ctx, "project_daily_aggregates", func() (err error) {
+		projected, err = p.projectDailyAggregates(ctx, limit)
+		return err
+	})
+	return projected, err
+}
+
+func (p *PostgresLedgerStore) projectDailyAggregates(ctx context.Context, limit int) (projected int, err error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback()
+		}
+	}()
+
+	// Locking the checkpoint row serialises projection runs across replicas
+	if _, err = dbTx.ExecContext(ctx, `INSERT INTO projection_checkpoints (name, last_seq) VALUES ($1, 0)
+	ON CONFLICT (name) DO NOTHING`, dailyProjection); err != nil {
+		return 0, err
+	}
+	var lastSeq int64
+	err = dbTx.QueryRowContext(ctx, `SELECT last_seq FROM projection_checkpoints WHERE name = $1 FOR UPDATE`,
+		dailyProjection).Scan(&lastSeq)
+	if err != nil {
+		return 0, err
+	}
+
+	// Whole transactions are projected together, so a batch never splits a transaction's legs.
+	// Legs share the transaction's created_at; matching on it lets Postgres skip other partitions.
+	const selectBatch = `CREATE TEMP TABLE daily_batch ON COMMIT DROP AS
+	SELECT e.id, e.transaction_id, e.account_id, e.amount, to_char(e.created_at, 'YYYY-MM-DD') AS date, e.seq
+	FROM ledger_entries e
+	WHERE (e.transaction_id, e.created_at) IN (
+		SELECT x.transaction_id, MIN(x.created_at) FROM ledger_entries x
+		WHERE x.seq > $1 AND NOT EXISTS (
+			SELECT 1 FROM daily_projected_transactions d WHERE d.transaction_id = x.transaction_id
+		)
+		GROUP BY x.transaction_id ORDER BY MIN(x.seq) LIMIT $2
+	)`
+	if _, err = dbTx.ExecContext(ctx, selectBatch, lastSeq-dailyProjectionOverlap, limit); err != nil {
+		return 0, err
+	}
+
+	result, err := dbTx.ExecContext(ctx, `INSERT INTO daily_projected_transactions (transaction_id, projected_at)
+	SELECT DISTINCT transaction_id, $1::TIMESTAMP FROM daily_batch`, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if inserted == 0 {
+		return 0, dbTx.Commit()
+	}
+
+	statements := []string{
+		`INSERT INTO daily_account_movements (date, account_id, debits, credits, net, entries)
+		SELECT date, account_id,
+			COALESCE(SUM(-amount) FILTER (WHERE amount < 0), 0),
+			COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0),
+			SUM(amount), COUNT(*)
+		FROM daily_batch GROUP BY date, account_id
+		ON CONFLICT (date, account_id) DO UPDATE SET
+			debits = daily_account_movements.debits + EXCLUDED.debits,
+			credits = daily_account_movements.credits + EXCLUDED.credits,
+			net = daily_account_movements.net + EXCLUDED.net,
+			entries = daily_account_movements.entries + EXCLUDED.entries`,
+
+		// The main debit leg carries the transaction amount; fee and FX legs are not volume
+		`INSERT INTO daily_stats (date, transaction_count, volume)
+		SELECT date, COUNT(*), SUM(-amount) FROM daily_batch
+		WHERE id = transaction_id || '-debit'
+		GROUP BY date
+		ON CONFLICT (date) DO UPDATE SET
+			transaction_count = daily_stats.transaction_count + EXCLUDED.transaction_count,
+			volume = daily_stats.volume + EXCLUDED.volume`,
+
+		`INSERT INTO daily_largest_transactions (date, transaction_id, from_account, to_account, amount)
+		SELECT d.date, d.transaction_id, d.account_id, c.account_id, -d.amount
+		FROM daily_batch d JOIN daily_batch c ON c.id = d.transaction_id || '-credit'
+		WHERE d.id = d.transaction_id || '-debit'
+		ON CONFLICT (date, transaction_id) DO NOTHING`,
+
+		`DELETE FROM daily_largest_transactions l
+		USING (
+			SELECT date, transaction_id,
+				ROW_NUMBER() OVER (PARTITION BY date ORDER BY amount DESC, transaction_id) AS rank
+			FROM daily_largest_transactions
+			WHERE date IN (SELECT DISTINCT date FROM daily_batch)
+		) r
+		WHERE l.date = r.date AND l.transaction_id = r.transaction_id AND r.rank > $1`,
+
+		`UPDATE projection_checkpoints
+		SET last_seq = GREATEST(last_seq, (SELECT MAX(seq) FROM daily_batch))
+		WHERE name = $2`,
+	}
+	args := [][]any{nil, nil, nil, {dailyLargestKept}, {dailyProjection}}
+	for i, statement := range statements {
+		if _, err = dbTx.ExecContext(ctx, statement, args[i]...); err != nil {
+			return 0, err
+		}
+	}
+	return int(inserted), dbTx.Commit()
+}
+
+func (p *PostgresLedgerStore) GetDailyReport(ctx context.Context, date string, top int) (models.DailyReport, error) {
+	report := models.DailyReport{
+		Date:                date,
+		LargestTransactions: []models.DailyTransaction{},
+		Accounts:            []models.DailyAccountSummary{},
+	}
+
+	err := p.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(transaction_count), 0), COALESCE(SUM(volume), 0)
+	FROM daily_stats WHERE date = $1`, date).Scan(&report.TransactionCount, &report.Volume)
+	if err != nil {
+		return models.DailyReport{}, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, `SELECT transaction_id, from_account, to_account, amount
+	FROM daily_largest_transactions WHERE date = $1
+	ORDER BY amount DESC, transaction_id LIMIT $2`, date, min(top, dailyLargestKept))
+	if err != nil {
+		return models.DailyReport{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tx models.DailyTransaction
+		if err := rows.Scan(&tx.TransactionID, &tx.FromAccount, &tx.ToAccount, &tx.Amount); err != nil {
+			return models.DailyReport{}, err
+		}
+		report.LargestTransactions = append(report.LargestTransactions, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return models.DailyReport{}, err
+	}
+
+	rows, err = p.db.QueryContext(ctx, `SELECT date, account_id, debits, credits, net, entries
+	FROM daily_account_movements WHERE date = $1 ORDER BY account_id`, date)
+	if err != nil {
+		return models.DailyReport{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.DailyAccountSummary
+		if err := rows.Scan(&s.Date, &s.AccountID, &s.Debits, &s.Credits, &s.Net, &s.Entries); err != nil {
+			return models.DailyReport{}, err
+		}
+		report.Accounts = append(report.Accounts, s)
+	}
+	return report, rows.Err()
+}
+
+var _ interfaces.DailyReportStore = (*PostgresLedgerStore)(nil)
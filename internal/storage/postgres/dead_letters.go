@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const deadLetterColumns = `id, topic, payload, created_at, attempts, last_error, last_attempt_at`
+
+func scanDeadLetter(scan func(dest ...any) error) (models.DeadLetter, error) {
+	var letter models.DeadLetter
+	var payload []byte
+	err := scan(&letter.ID, &letter.Topic, &payload, &letter.CreatedAt, &letter.Attempts, &letter.LastError, &letter.LastAttemptAt)
+	letter.Payload = payload
+	return letter, err
+}
+
+func (p *PostgresLedgerStore) SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	const query = `INSERT INTO event_dead_letters (topic, payload, created_at) VALUES ($1,$2,$3)`
+
+	_, err := p.db.ExecContext(ctx, query, letter.Topic, []byte(letter.Payload), letter.CreatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) ListDeadLetters(ctx context.Context, filter models.DeadLetterFilter) ([]models.DeadLetter, error) {
+	const query = `SELECT ` + deadLetterColumns + ` FROM event_dead_letters
+	WHERE $1 = '' OR ($1 = 'pending' AND attempts = 0) OR ($1 = 'failed' AND attempts > 0)
+	ORDER BY id LIMIT $2`
+
+	rows, err := p.db.QueryContext(ctx, query, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []models.DeadLetter
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
+
+func (p *PostgresLedgerStore) GetDeadLetter(ctx context.Context, id int64) (*models.DeadLetter, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+deadLetterColumns+` FROM event_dead_letters WHERE id = $1`, id)
+	letter, err := scanDeadLetter(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &letter, nil
+}
+
+func (p *PostgresLedgerStore) DeleteDeadLetter(ctx context.Context, id int64) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `DELETE FROM event_dead_letters WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted == 1, err
+}
+
+func (p *PostgresLedgerStore) RecordDeadLetterFailure(ctx context.Context, id int64, cause string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE event_dead_letters
+	SET attempts = attempts + 1, last_error = $2, last_attempt_at = $3 WHERE id = $1`, id, cause, at)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeadLetterStats(ctx context.Context) (models.DeadLetterStats, error) {
+	var stats models.DeadLetterStats
+	err := p.db.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(*) FILTER (WHERE attempts > 0), MIN(created_at)
+	FROM event_dead_letters`).Scan(&stats.Backlog, &stats.Failed, &stats.OldestAt)
+	return stats, err
+}
+
+var _ interfaces.DeadLetterStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const discrepancyColumns = `id, account_id, materialized, recomputed, difference, occurrences, first_detected_at, last_detected_at, resolved_at`
+
+// CheckBalances compares each balance with the sum of its entries in one statement, so a
+// posting committing meanwhile is seen on both sides or neither. Mismatches are recorded,
+// and open discrepancies on checked accounts that now agree are marked resolved.
+func (p *PostgresLedgerStore) CheckBalances(ctx context.Context, sample int, at time.Time) (models.BalanceCheck, error) {
+	// nil checks every account
+	var ids []string
+	if sample > 0 {
+		rows, err := p.db.QueryContext(ctx, `SELECT account_id FROM account_balances ORDER BY random() LIMIT $1`, sample)
+		if err != nil {
+			return models.BalanceCheck{}, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return models.BalanceCheck{}, err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return models.BalanceCheck{}, err
+		}
+		if len(ids) == 0 {
+			return models.BalanceCheck{Discrepancies: []models.BalanceDiscrepancy{}, CheckedAt: at}, nil
+		}
+	}
+
+	rows, err := p.db.QueryContext(ctx, `SELECT b.account_id, b.balance, COALESCE(e.balance, 0)
+	FROM account_balances b
+	LEFT JOIN LATERAL (SELECT SUM(x.amount) AS balance FROM `+allEntries+` x WHERE x.account_id = b.account_id) e ON TRUE
+	WHERE ($1::TEXT[] IS NULL OR b.account_id = ANY($1)) AND b.balance <> COALESCE(e.balance, 0)`, pq.Array(ids))
+	if err != nil {
+		return models.BalanceCheck{}, err
+	}
+	check := models.BalanceCheck{Checked: len(ids), Discrepancies: []models.BalanceDiscrepancy{}, CheckedAt: at}
+	for rows.Next() {
+		var found models.BalanceDiscrepancy
+		if err := rows.Scan(&found.AccountID, &found.Materialized, &found.Recomputed); err != nil {
+			rows.Close()
+			return models.BalanceCheck{}, err
+		}
+		found.Difference = found.Materialized.Sub(found.Recomputed)
+		check.Discrepancies = append(check.Discrepancies, found)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.BalanceCheck{}, err
+	}
+	if ids == nil {
+		if err := p.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM account_balances`).Scan(&check.Checked); err != nil {
+			return models.BalanceCheck{}, err
+		}
+	}
+
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.BalanceCheck{}, err
+	}
+	defer dbTx.Rollback()
+
+	const upsert = `INSERT INTO balance_discrepancies (account_id, materialized, recomputed, difference, first_detected_at, last_detected_at)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	ON CONFLICT (account_id) WHERE resolved_at IS NULL DO UPDATE SET materialized = EXCLUDED.materialized, recomputed = EXCLUDED.recomputed,
+		difference = EXCLUDED.difference, last_detected_at = EXCLUDED.last_detected_at, occurrences = balance_discrepancies.occurrences + 1
+	RETURNING ` + discrepancyColumns
+
+	mismatched := make([]string, len(check.Discrepancies))
+	for i, found := range check.Discrepancies {
+		row := dbTx.QueryRowContext(ctx, upsert, found.AccountID, found.Materialized, found.Recomputed, found.Difference, at)
+		if err := row.Scan(&found.ID, &found.AccountID, &found.Materialized, &found.Recomputed, &found.Difference, &found.Occurrences,
+			&found.FirstDetectedAt, &found.LastDetectedAt, &found.ResolvedAt); err != nil {
+			return models.BalanceCheck{}, err
+		}
+		check.Discrepancies[i] = found
+		mismatched[i] = found.AccountID
+	}
+
+	// Only accounts this run looked at can be declared fixed
+	result, err := dbTx.ExecContext(ctx, `UPDATE balance_discrepancies SET resolved_at = $1
+	WHERE resolved_at IS NULL AND NOT (account_id = ANY($2)) AND ($3::TEXT[] IS NULL OR account_id = ANY($3))`,
+		at, pq.Array(mismatched), pq.Array(ids))
+	if err != nil {
+		return models.BalanceCheck{}, err
+	}
+	resolved, err := result.RowsAffected()
+	if err != nil {
+		return models.BalanceCheck{}, err
+	}
+	check.Resolved = int(resolved)
+	return check, dbTx.Commit()
+}
+
+func (p *PostgresLedgerStore) ListBalanceDiscrepancies(ctx context.Context, filter models.DiscrepancyFilter) ([]models.BalanceDiscrepancy, error) {
+	var conditions []string
+	var args []any
+	if filter.AccountID != "" {
+		args = append(args, filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("account_id = $%d", len(args)))
+	}
+	if filter.Open != nil {
+		if *filter.Open {
+			conditions = append(conditions, "resolved_at IS NULL")
+		} else {
+			conditions = append(conditions, "resolved_at IS NOT NULL")
+		}
+	}
+
+	query := `SELECT ` + discrepancyColumns + ` FROM balance_discrepancies`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY last_detected_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discrepancies := []models.BalanceDiscrepancy{}
+	for rows.Next() {
+		var d models.BalanceDiscrepancy
+		if err := rows.Scan(&d.ID, &d.AccountID, &d.Materialized, &d.Recomputed, &d.Difference, &d.Occurrences,
+			&d.FirstDetectedAt, &d.LastDetectedAt, &d.ResolvedAt); err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	return discrepancies, rows.Err()
+}
+
+func (p *PostgresLedgerStore) RepairBalance(ctx context.Context, accountId string, at time.Time) (*models.BalanceRepair, error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer dbTx.Rollback()
+
+	// Postings lock the row before writing entries, so the sum below cannot miss one in flight.
+	// In optimistic mode the version bump makes a posting that read the old balance retry.
+	repair := models.BalanceRepair{AccountID: accountId, RepairedAt: at}
+	err = dbTx.QueryRowContext(ctx, `SELECT balance FROM account_balances WHERE account_id = $1 FOR UPDATE`, accountId).
+		Scan(&repair.Materialized)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := dbTx.QueryRowContext(ctx, `SELECT COALESCE(SUM(x.amount), 0) FROM `+allEntries+` x WHERE x.account_id = $1`,
+		accountId).Scan(&repair.Recomputed); err != nil {
+		return nil, err
+	}
+	repair.Delta = repair.Recomputed.Sub(repair.Materialized)
+	repair.Repaired = !repair.Delta.IsZero()
+
+	if repair.Repaired {
+		if _, err := dbTx.ExecContext(ctx, `UPDATE account_balances SET balance = $2, version = version + 1, updated_at = $3
+		WHERE account_id = $1`, accountId, repair.Recomputed, at); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dbTx.ExecContext(ctx, `UPDATE balance_discrepancies SET resolved_at = $2
+	WHERE account_id = $1 AND resolved_at IS NULL`, accountId, at); err != nil {
+		return nil, err
+	}
+	return &repair, dbTx.Commit()
+}
+
+var _ interfaces.DiscrepancyStore = (*PostgresLedgerStore)(nil)
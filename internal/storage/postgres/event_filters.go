@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) GetEventFilter(ctx context.Context) (*models.EventFilter, error) {
+	var filter models.EventFilter
+	err := p.db.QueryRowContext(ctx, `SELECT topics, tenants, accounts, system_accounts, updated_at FROM event_filter WHERE id`).
+		Scan(pq.Array(&filter.Topics), pq.Array(&filter.Tenants), pq.Array(&filter.Accounts), &filter.SystemAccounts, &filter.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+func (p *PostgresLedgerStore) SaveEventFilter(ctx context.Context, filter models.EventFilter) error {
+	const query = `INSERT INTO event_filter (id, topics, tenants, accounts, system_accounts, updated_at)
+	VALUES (TRUE, $1, $2, $3, $4, $5)
+	ON CONFLICT (id) DO UPDATE SET topics = EXCLUDED.topics, tenants = EXCLUDED.tenants,
+	accounts = EXCLUDED.accounts, system_accounts = EXCLUDED.system_accounts, updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, pq.Array(filter.Topics), pq.Array(filter.Tenants),
+		pq.Array(filter.Accounts), filter.SystemAccounts, filter.UpdatedAt)
+	return err
+}
+
+var _ interfaces.EventFilterStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const feeScheduleColumns = `id, name, account_type, min_amount, max_amount, flat_fee, percentage, updated_at`
+
+func scanFeeSchedule(scan func(dest ...any) error) (models.FeeSchedule, error) {
+	var schedule models.FeeSchedule
+	err := scan(&schedule.ID, &schedule.Name, &schedule.AccountType, &schedule.MinAmount, &schedule.MaxAmount,
+		&schedule.FlatFee, &schedule.Percentage, &schedule.UpdatedAt)
+	return schedule, err
+}
+
+func (p *PostgresLedgerStore) ListFeeSchedules(ctx context.Context) ([]models.FeeSchedule, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+feeScheduleColumns+` FROM fee_schedules ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.FeeSchedule{}
+	for rows.Next() {
+		schedule, err := scanFeeSchedule(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (p *PostgresLedgerStore) GetFeeSchedule(ctx context.Context, id string) (*models.FeeSchedule, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+feeScheduleColumns+` FROM fee_schedules WHERE id = $1`, id)
+	schedule, err := scanFeeSchedule(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (p *PostgresLedgerStore) SaveFeeSchedule(ctx context.Context, schedule models.FeeSchedule) error {
+	const query = `INSERT INTO fee_schedules (` + feeScheduleColumns + `) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, account_type = EXCLUDED.account_type,
+	min_amount = EXCLUDED.min_amount, max_amount = EXCLUDED.max_amount, flat_fee = EXCLUDED.flat_fee,
+	percentage = EXCLUDED.percentage, updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, schedule.ID, schedule.Name, schedule.AccountType, schedule.MinAmount,
+		schedule.MaxAmount, schedule.FlatFee, schedule.Percentage, schedule.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeleteFeeSchedule(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM fee_schedules WHERE id = $1`, id)
+	return err
+}
+
+var (
+	_ interfaces.FeeStore      = (*PostgresLedgerStore)(nil)
+	_ interfaces.MultiLegStore = (*PostgresLedgerStore)(nil)
+)
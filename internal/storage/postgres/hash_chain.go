@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// queryRower is a *sql.DB or a *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (p *PostgresLedgerStore) GetLastEntryHash(accountId string) (string, error) {
+	return lastEntryHash(context.Background(), p.db, accountId)
+}
+
+func lastEntryHash(ctx context.Context, q queryRower, accountId string) (string, error) {
+	// The head may already have been compacted into the archive or frozen into cold storage
+	const query = `SELECT hash FROM (
+		SELECT seq, hash FROM ledger_entries WHERE account_id = $1
+		UNION ALL SELECT seq, hash FROM ledger_entries_archive WHERE account_id = $1
+		UNION ALL SELECT last_seq, last_hash FROM cold_entry_batches WHERE account_id = $1
+	) e ORDER BY seq DESC LIMIT 1`
+
+	var hash string
+	err := q.QueryRowContext(ctx, query, accountId).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+// checkChainHeads compares the first entry of each account with the head of its chain, read
+// in dbTx once the balance rows are locked. Entries linked against a head that another
+// instance has moved since are refused rather than forking the chain.
+func checkChainHeads(ctx context.Context, dbTx *sql.Tx, entries []models.LedgerEntry) error {
+	checked := make(map[string]bool)
+	for _, entry := range entries {
+		// Unlinked entries come from a ledger without the hash chain
+		if entry.Hash == "" || checked[entry.AccountID] {
+			continue
+		}
+		checked[entry.AccountID] = true
+		head, err := lastEntryHash(ctx, dbTx, entry.AccountID)
+		if err != nil {
+			return err
+		}
+		if entry.PrevHash != head {
+			return fmt.Errorf("%w: %s", interfaces.ErrChainHeadMoved, entry.AccountID)
+		}
+	}
+	return nil
+}
+
+// saveChainLink claims the entry's place on its account's chain. ledger_entries is
+// partitioned, so it cannot hold UNIQUE (account_id, prev_hash) itself.
+func saveChainLink(ctx context.Context, dbTx *sql.Tx, entry models.LedgerEntry) error {
+	if entry.Hash == "" {
+		return nil
+	}
+	const query = `INSERT INTO ledger_chain_links (account_id, prev_hash, entry_id) VALUES ($1,$2,$3)`
+
+	_, err := dbTx.ExecContext(ctx, query, entry.AccountID, entry.PrevHash, entry.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation: the link is taken
+		return fmt.Errorf("%w: %s", interfaces.ErrChainHeadMoved, entry.AccountID)
+	}
+	return err
+}
+
+func (p *PostgresLedgerStore) GetAccountChain(ctx context.Context, accountId string) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM (
+		SELECT ` + entryColumns + ` FROM ledger_entries WHERE account_id = $1
+		UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive WHERE account_id = $1
+	) e ORDER BY seq`
+
+	rows, err := p.db.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+func (p *PostgresLedgerStore) ListAccountIDs(ctx context.Context) ([]string, error) {
+	const query = `SELECT account_id FROM ledger_entries
+	UNION SELECT account_id FROM ledger_entries_archive
+	UNION SELECT account_id FROM cold_entry_batches ORDER BY account_id`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accountIds []string
+	for rows.Next() {
+		var accountId string
+		if err := rows.Scan(&accountId); err != nil {
+			return nil, err
+		}
+		accountIds = append(accountIds, accountId)
+	}
+	return accountIds, rows.Err()
+}
+
+var _ interfaces.HashChainStore = (*PostgresLedgerStore)(nil)
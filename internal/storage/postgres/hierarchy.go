@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// GetSubtreeBalances collects the subtree with a recursive CTE and sums every account in one pass.
+// The ledger rejects cycles; the depth cap only guards the query against a corrupted table.
+func (p *PostgresLedgerStore) GetSubtreeBalances(ctx context.Context, accountId string) ([]models.AccountBalance, error) {
+	const query = `WITH RECURSIVE tree (id, parent_id, depth) AS (
+		SELECT $1::TEXT, '', 0
+		UNION ALL
+		SELECT a.id, a.parent_id, t.depth + 1
+		FROM accounts a JOIN tree t ON a.parent_id = t.id
+		WHERE t.depth < 64
+	)
+	SELECT t.id, t.parent_id, t.depth, COALESCE(SUM(e.amount), 0)
+	FROM tree t LEFT JOIN ` + allEntries + ` e ON e.account_id = t.id
+	GROUP BY t.id, t.parent_id, t.depth
+	ORDER BY t.depth, t.id`
+
+	rows, err := p.db.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []models.AccountBalance
+	for rows.Next() {
+		var balance models.AccountBalance
+		if err := rows.Scan(&balance.AccountID, &balance.ParentID, &balance.Depth, &balance.Balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+var _ interfaces.AccountHierarchyStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) ListHolidays(ctx context.Context) ([]models.Holiday, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT currency, to_char(date, 'YYYY-MM-DD'), name FROM holidays ORDER BY currency, date`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []models.Holiday{}
+	for rows.Next() {
+		var holiday models.Holiday
+		if err := rows.Scan(&holiday.Currency, &holiday.Date, &holiday.Name); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SaveHoliday(ctx context.Context, holiday models.Holiday) error {
+	const query = `INSERT INTO holidays (currency, date, name) VALUES ($1, $2, $3)
+	ON CONFLICT (currency, date) DO UPDATE SET name = EXCLUDED.name`
+
+	_, err := p.db.ExecContext(ctx, query, holiday.Currency, holiday.Date, holiday.Name)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeleteHoliday(ctx context.Context, currency, date string) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `DELETE FROM holidays WHERE currency = $1 AND date = $2`, currency, date)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+var _ interfaces.HolidayStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) ListInterestRates(ctx context.Context) ([]models.InterestRate, error) {
+	const query = `SELECT scope, id, annual_rate, updated_at FROM interest_rates ORDER BY scope, id`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.InterestRate{}
+	for rows.Next() {
+		var rate models.InterestRate
+		if err := rows.Scan(&rate.Scope, &rate.ID, &rate.AnnualRate, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SaveInterestRate(ctx context.Context, rate models.InterestRate) error {
+	const query = `INSERT INTO interest_rates (scope, id, annual_rate, updated_at) VALUES ($1,$2,$3,$4)
+	ON CONFLICT (scope, id) DO UPDATE SET annual_rate = EXCLUDED.annual_rate, updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, rate.Scope, rate.ID, rate.AnnualRate, rate.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeleteInterestRate(ctx context.Context, scope, id string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM interest_rates WHERE scope = $1 AND id = $2`, scope, id)
+	return err
+}
+
+func (p *PostgresLedgerStore) ListInterestAccounts(ctx context.Context) ([]models.InterestAccount, error) {
+	// Accounts without a row are active and have no type, so only an account rate can apply to them
+	const query = `SELECT a.id, COALESCE(ar.annual_rate, pr.annual_rate)
+	FROM (
+		SELECT id, type FROM accounts WHERE status <> 'closed'
+		UNION
+		SELECT r.id, '' FROM interest_rates r
+		WHERE r.scope = 'account' AND NOT EXISTS (SELECT 1 FROM accounts x WHERE x.id = r.id)
+	) a
+	LEFT JOIN interest_rates ar ON ar.scope = 'account' AND ar.id = a.id
+	LEFT JOIN interest_rates pr ON pr.scope = 'product' AND pr.id = a.type AND a.type <> ''
+	WHERE COALESCE(ar.annual_rate, pr.annual_rate) > 0
+	ORDER BY a.id`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []models.InterestAccount
+	for rows.Next() {
+		var account models.InterestAccount
+		if err := rows.Scan(&account.AccountID, &account.AnnualRate); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (p *PostgresLedgerStore) GetAccruedInterest(ctx context.Context, accountId string) (models.AccruedInterest, error) {
+	const query = `SELECT COALESCE(SUM(amount), 0), MAX((metadata->>'accrual_date')::DATE)
+	FROM transactions WHERE to_account = $1 AND metadata @> '{"type":"interest_accrual"}'`
+
+	accrued := models.AccruedInterest{AccountID: accountId}
+	var last sql.NullTime
+	if err := p.db.QueryRowContext(ctx, query, accountId).Scan(&accrued.AccruedToDate, &last); err != nil {
+		return models.AccruedInterest{}, err
+	}
+	if last.Valid {
+		accrued.LastAccrualDate = &last.Time
+	}
+	return accrued, nil
+}
+
+var _ interfaces.InterestStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+func (p *PostgresLedgerStore) TryAcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	// Expiry uses the database clock so replicas with skewed clocks still agree
+	const query = `INSERT INTO scheduler_leases (name, holder, expires_at)
+	VALUES ($1, $2, NOW() + $3 * INTERVAL '1 millisecond')
+	ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+	WHERE scheduler_leases.holder = EXCLUDED.holder OR scheduler_leases.expires_at < NOW()`
+
+	result, err := p.db.ExecContext(ctx, query, name, holder, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	acquired, err := result.RowsAffected()
+	return acquired == 1, err
+}
+
+func (p *PostgresLedgerStore) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM scheduler_leases WHERE name = $1 AND holder = $2`, name, holder)
+	return err
+}
+
+var _ interfaces.LeaseStore = (*PostgresLedgerStore)(nil)
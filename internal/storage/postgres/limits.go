@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func (p *PostgresLedgerStore) GetLimitProfile(ctx context.Context, id string) (*models.LimitProfile, error) {
+	const query = `SELECT id, name, rules, updated_at FROM limit_profiles WHERE id = $1`
+
+	var profile models.LimitProfile
+	var rules []byte
+	err := p.db.QueryRowContext(ctx, query, id).Scan(&profile.ID, &profile.Name, &rules, &profile.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rules, &profile.Rules); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (p *PostgresLedgerStore) ListLimitProfiles(ctx context.Context) ([]models.LimitProfile, error) {
+	const query = `SELECT id, name, rules, updated_at FROM limit_profiles ORDER BY id`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []models.LimitProfile{}
+	for rows.Next() {
+		var profile models.LimitProfile
+		var rules []byte
+		if err := rows.Scan(&profile.ID, &profile.Name, &rules, &profile.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(rules, &profile.Rules); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SaveLimitProfile(ctx context.Context, profile models.LimitProfile) error {
+	const query = `INSERT INTO limit_profiles (id, name, rules, updated_at) VALUES ($1,$2,$3,$4)
+	ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, rules = EXCLUDED.rules, updated_at = EXCLUDED.updated_at`
+
+	rules, err := json.Marshal(profile.Rules)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx, query, profile.ID, profile.Name, string(rules), profile.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) GetDebitTotals(ctx context.Context, accountId string, since time.Time) (decimal.Decimal, int, error) {
+	// Served by idx_ledger_entries_account_created_at
+	const query = `SELECT COALESCE(SUM(-amount), 0), COUNT(*) FROM ledger_entries
+	WHERE account_id = $1 AND created_at >= $2 AND amount < 0`
+
+	var total decimal.Decimal
+	var count int
+	err := p.db.QueryRowContext(ctx, query, accountId, since).Scan(&total, &count)
+	return total, count, err
+}
+
+var _ interfaces.LimitStore = (*PostgresLedgerStore)(nil)
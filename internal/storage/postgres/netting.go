@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const nettingColumns = `id, from_institution, to_institution, amount, reference, window_start, window_end,
+	status, settlement_transaction_id, created_at, settled_at`
+
+func (p *PostgresLedgerStore) SaveNettingObligation(ctx context.Context, o models.NettingObligation) error {
+	const query = `INSERT INTO netting_obligations (` + nettingColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`
+
+	_, err := p.db.ExecContext(ctx, query, o.ID, o.FromInstitution, o.ToInstitution, o.Amount, o.Reference,
+		o.WindowStart, o.WindowEnd, o.Status, o.SettlementTransactionID, o.CreatedAt, o.SettledAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) ListNettingObligations(ctx context.Context, windowStart time.Time) ([]models.NettingObligation, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+nettingColumns+` FROM netting_obligations
+	WHERE window_start = $1 ORDER BY created_at`, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	obligations := []models.NettingObligation{}
+	for rows.Next() {
+		var o models.NettingObligation
+		err := rows.Scan(&o.ID, &o.FromInstitution, &o.ToInstitution, &o.Amount, &o.Reference, &o.WindowStart, &o.WindowEnd,
+			&o.Status, &o.SettlementTransactionID, &o.CreatedAt, &o.SettledAt)
+		if err != nil {
+			return nil, err
+		}
+		obligations = append(obligations, o)
+	}
+	return obligations, rows.Err()
+}
+
+func (p *PostgresLedgerStore) ListDueNettingWindows(ctx context.Context, now time.Time) ([]time.Time, error) {
+	const query = `SELECT DISTINCT window_start FROM netting_obligations
+	WHERE status = 'pending' AND window_end <= $1 ORDER BY window_start`
+
+	rows, err := p.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []time.Time
+	for rows.Next() {
+		var start time.Time
+		if err := rows.Scan(&start); err != nil {
+			return nil, err
+		}
+		windows = append(windows, start)
+	}
+	return windows, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SettleNettingObligations(ctx context.Context, ids []string, transactionId string, settledAt time.Time) error {
+	const query = `UPDATE netting_obligations SET status = 'settled', settlement_transaction_id = $2, settled_at = $3
+	WHERE id = ANY($1) AND status = 'pending'`
+
+	_, err := p.db.ExecContext(ctx, query, pq.Array(ids), transactionId, settledAt)
+	return err
+}
+
+var _ interfaces.NettingStore = (*PostgresLedgerStore)(nil)
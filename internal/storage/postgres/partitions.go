@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+// EnsureEntryPartitions creates a ledger_entries partition per month, e.g. ledger_entries_2024_06
+// covering [2024-06-01, 2024-07-01). Existing partitions are left alone, so the job can run
+// on every replica and as often as needed.
+func (p *PostgresLedgerStore) EnsureEntryPartitions(ctx context.Context, from time.Time, monthsAhead int) ([]string, error) {
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var created []string
+	for i := 0; i <= monthsAhead; i++ {
+		start := month.AddDate(0, i, 0)
+		end := start.AddDate(0, 1, 0)
+		name := fmt.Sprintf("ledger_entries_%04d_%02d", start.Year(), start.Month())
+
+		var exists bool
+		if err := p.db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		// Identifiers and bounds cannot be bind parameters; both are generated above
+		query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF ledger_entries FOR VALUES FROM ('%s') TO ('%s')`,
+			name, start.Format("2006-01-02"), end.Format("2006-01-02"))
+		if _, err := p.db.ExecContext(ctx, query); err != nil {
+			return created, fmt.Errorf("creating partition %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+var _ interfaces.PartitionStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ErrPaymentRequestClosed means the request was paid, cancelled or expired between the
+// ledger's check and the commit, e.g. by a payment on another replica
+var ErrPaymentRequestClosed = errors.New("payment request is no longer open")
+
+const paymentRequestColumns = `id, tenant_id, payee_account, amount, reference, description, status,
+	expires_at, created_at, transaction_id, paid_at, cancelled_at`
+
+func scanPaymentRequest(scan func(dest ...any) error) (models.PaymentRequest, error) {
+	var request models.PaymentRequest
+	err := scan(&request.ID, &request.TenantID, &request.PayeeAccount, &request.Amount, &request.Reference,
+		&request.Description, &request.Status, &request.ExpiresAt, &request.CreatedAt, &request.TransactionID,
+		&request.PaidAt, &request.CancelledAt)
+	return request, err
+}
+
+func (p *PostgresLedgerStore) SavePaymentRequest(ctx context.Context, request models.PaymentRequest) error {
+	const query = `INSERT INTO payment_requests (` + paymentRequestColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
+
+	_, err := p.db.ExecContext(ctx, query, request.ID, request.TenantID, request.PayeeAccount, request.Amount,
+		request.Reference, request.Description, request.Status, request.ExpiresAt, request.CreatedAt,
+		request.TransactionID, request.PaidAt, request.CancelledAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) GetPaymentRequest(ctx context.Context, id string) (*models.PaymentRequest, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+paymentRequestColumns+` FROM payment_requests WHERE id = $1`, id)
+	request, err := scanPaymentRequest(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (p *PostgresLedgerStore) ListPaymentRequests(ctx context.Context, payeeAccount string) ([]models.PaymentRequest, error) {
+	// Served by idx_payment_requests_payee
+	rows, err := p.db.QueryContext(ctx, `SELECT `+paymentRequestColumns+` FROM payment_requests
+	WHERE payee_account = $1 ORDER BY created_at DESC`, payeeAccount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []models.PaymentRequest{}
+	for rows.Next() {
+		request, err := scanPaymentRequest(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+func (p *PostgresLedgerStore) CancelPaymentRequest(ctx context.Context, id string, at time.Time) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `UPDATE payment_requests SET status = 'cancelled', cancelled_at = $2
+	WHERE id = $1 AND status = 'open'`, id, at)
+	if err != nil {
+		return false, err
+	}
+	cancelled, err := result.RowsAffected()
+	return cancelled == 1, err
+}
+
+// fulfillPaymentRequest marks the request paid by tx inside the posting's database transaction,
+// so the payment and the status change commit together or not at all
+func fulfillPaymentRequest(ctx context.Context, dbTx *sql.Tx, tx models.Transaction, now time.Time) error {
+	const query = `UPDATE payment_requests SET status = 'paid', transaction_id = $2, paid_at = $3
+	WHERE id = $1 AND status = 'open' AND expires_at > $3`
+
+	// Expiry goes by the current time, not by a backdated transaction's date
+	result, err := dbTx.ExecContext(ctx, query, tx.PaymentRequestID, tx.ID, now)
+	if err != nil {
+		return err
+	}
+	paid, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if paid == 0 {
+		return ErrPaymentRequestClosed
+	}
+	return nil
+}
+
+var _ interfaces.PaymentRequestStore = (*PostgresLedgerStore)(nil)
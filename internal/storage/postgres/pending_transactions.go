@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+const pendingColumns = `id, payload, force, execute_at, status, last_error, executed_at, created_at, updated_at`
+
+func scanPending(scan func(dest ...any) error) (models.PendingTransaction, error) {
+	var pending models.PendingTransaction
+	var payload []byte
+	err := scan(&pending.ID, &payload, &pending.Force, &pending.ExecuteAt, &pending.Status, &pending.LastError,
+		&pending.ExecutedAt, &pending.CreatedAt, &pending.UpdatedAt)
+	if err != nil {
+		return pending, err
+	}
+	return pending, json.Unmarshal(payload, &pending.Transaction)
+}
+
+func (p *PostgresLedgerStore) queryPending(ctx context.Context, query string, args ...any) ([]models.PendingTransaction, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.PendingTransaction{}
+	for rows.Next() {
+		pending, err := scanPending(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, pending)
+	}
+	return list, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SavePendingTransaction(ctx context.Context, pending models.PendingTransaction) (models.PendingTransaction, error) {
+	const query = `INSERT INTO pending_transactions (` + pendingColumns + `, idempotency_key, tenant_id)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	ON CONFLICT (tenant_id, idempotency_key) DO NOTHING`
+
+	payload, err := json.Marshal(pending.Transaction)
+	if err != nil {
+		return models.PendingTransaction{}, err
+	}
+	result, err := p.db.ExecContext(ctx, query, pending.ID, string(payload), pending.Force, pending.ExecuteAt, pending.Status,
+		pending.LastError, pending.ExecutedAt, pending.CreatedAt, pending.UpdatedAt, pending.Transaction.IdempotencyKey,
+		pending.Transaction.TenantID)
+	if err != nil {
+		return models.PendingTransaction{}, err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 1 {
+		return pending, err
+	}
+
+	row := p.db.QueryRowContext(ctx, `SELECT `+pendingColumns+` FROM pending_transactions
+	WHERE tenant_id = $1 AND idempotency_key = $2`, pending.Transaction.TenantID, pending.Transaction.IdempotencyKey)
+	return scanPending(row.Scan)
+}
+
+func (p *PostgresLedgerStore) GetPendingTransaction(ctx context.Context, id string) (*models.PendingTransaction, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+pendingColumns+` FROM pending_transactions WHERE id = $1`, id)
+	pending, err := scanPending(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+func (p *PostgresLedgerStore) ListPendingTransactions(ctx context.Context, status string) ([]models.PendingTransaction, error) {
+	return p.queryPending(ctx, `SELECT `+pendingColumns+` FROM pending_transactions
+	WHERE ($1 = '' OR status = $1) AND ($2 = '' OR payload->>'tenant_id' = $2)
+	ORDER BY execute_at`, status, tenant.FromContext(ctx))
+}
+
+func (p *PostgresLedgerStore) ListDuePendingTransactions(ctx context.Context, now time.Time) ([]models.PendingTransaction, error) {
+	return p.queryPending(ctx, `SELECT `+pendingColumns+` FROM pending_transactions
+	WHERE status = 'pending' AND execute_at <= $1 ORDER BY execute_at`, now)
+}
+
+func (p *PostgresLedgerStore) TransitionPendingTransaction(ctx context.Context, id, from, to, lastError string, now time.Time) (bool, error) {
+	const query = `UPDATE pending_transactions SET status = $3, last_error = $4, updated_at = $5,
+	executed_at = CASE WHEN $3 = 'executed' THEN $5 ELSE executed_at END
+	WHERE id = $1 AND status = $2`
+
+	result, err := p.db.ExecContext(ctx, query, id, from, to, lastError, now)
+	if err != nil {
+		return false, err
+	}
+	updated, err := result.RowsAffected()
+	return updated == 1, err
+}
+
+var _ interfaces.PendingTransactionStore = (*PostgresLedgerStore)(nil)
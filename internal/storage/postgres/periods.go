@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ErrPeriodClosed is returned for a posting dated in a period that closed while it was being saved
+var ErrPeriodClosed = errors.New("accounting period closed while the posting was saved")
+
+const (
+	periodLayout = "2006-01"
+	// periodLock is the advisory lock key of the period passed as $1
+	periodLock = `hashtext('accounting_period:' || $1)`
+)
+
+func (p *PostgresLedgerStore) GetPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error) {
+	const query = `SELECT id, start_date, end_date, status, closed_at FROM accounting_periods WHERE id = $1`
+
+	var period models.AccountingPeriod
+	err := p.db.QueryRowContext(ctx, query, id).Scan(&period.ID, &period.Start, &period.End, &period.Status, &period.ClosedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+func (p *PostgresLedgerStore) ListPeriods(ctx context.Context) ([]models.AccountingPeriod, error) {
+	const query = `SELECT id, start_date, end_date, status, closed_at FROM accounting_periods ORDER BY start_date`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []models.AccountingPeriod
+	for rows.Next() {
+		var period models.AccountingPeriod
+		if err := rows.Scan(&period.ID, &period.Start, &period.End, &period.Status, &period.ClosedAt); err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	return periods, rows.Err()
+}
+
+// SavePeriod holds the period's lock exclusively, so a close waits for postings saving into
+// the period on any instance, and postings after it find the period closed
+func (p *PostgresLedgerStore) SavePeriod(ctx context.Context, period models.AccountingPeriod) error {
+	const query = `INSERT INTO accounting_periods (id, start_date, end_date, status, closed_at)
+	VALUES ($1,$2,$3,$4,$5)
+	ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, closed_at = EXCLUDED.closed_at`
+
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Rollback()
+
+	if _, err := dbTx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(`+periodLock+`)`, period.ID); err != nil {
+		return err
+	}
+	if _, err := dbTx.ExecContext(ctx, query, period.ID, period.Start, period.End, period.Status, period.ClosedAt); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+// checkPeriodOpen holds the lock of the period the posting is dated in, shared, for the rest of
+// dbTx, and refuses the posting if the period closed after the ledger checked it
+func checkPeriodOpen(ctx context.Context, dbTx *sql.Tx, createdAt time.Time) error {
+	id := createdAt.UTC().Format(periodLayout)
+	if _, err := dbTx.ExecContext(ctx, `SELECT pg_advisory_xact_lock_shared(`+periodLock+`)`, id); err != nil {
+		return err
+	}
+
+	var closed bool
+	err := dbTx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounting_periods WHERE id = $1 AND status = $2)`,
+		id, models.PeriodClosed).Scan(&closed)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return fmt.Errorf("%w: %s", ErrPeriodClosed, id)
+	}
+	return nil
+}
+
+var _ interfaces.PeriodStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/pii"
+)
+
+// reencryptBatch bounds how many accounts one re-encryption statement rewrites
+const reencryptBatch = 500
+
+// SetPIIKeyring sets the keys account holder details are sealed with. Without one,
+// accounts without holder details still save, but any holder detail is refused.
+func (p *PostgresLedgerStore) SetPIIKeyring(keys *pii.Keyring) {
+	p.pii = keys
+}
+
+// holderBinding ties a sealed value to its account and column
+func holderBinding(accountId, column string) string {
+	return "accounts/" + accountId + "/" + column
+}
+
+func (p *PostgresLedgerStore) sealHolder(account *models.Account) error {
+	var err error
+	if account.HolderName, err = p.pii.Seal(account.HolderName, holderBinding(account.ID, "holder_name")); err != nil {
+		return fmt.Errorf("sealing holder name of %s: %w", account.ID, err)
+	}
+	if account.HolderEmail, err = p.pii.Seal(account.HolderEmail, holderBinding(account.ID, "holder_email")); err != nil {
+		return fmt.Errorf("sealing holder email of %s: %w", account.ID, err)
+	}
+	return nil
+}
+
+func (p *PostgresLedgerStore) openHolder(account *models.Account) error {
+	var err error
+	if account.HolderName, err = p.pii.Open(account.HolderName, holderBinding(account.ID, "holder_name")); err != nil {
+		return fmt.Errorf("opening holder name of %s: %w", account.ID, err)
+	}
+	if account.HolderEmail, err = p.pii.Open(account.HolderEmail, holderBinding(account.ID, "holder_email")); err != nil {
+		return fmt.Errorf("opening holder email of %s: %w", account.ID, err)
+	}
+	return nil
+}
+
+// ReencryptAccounts seals again, with the current key, every holder detail still sealed
+// with an older one. Once it reports nothing left, the older keys can be retired.
+// Each row is rewritten only if unchanged since it was read, so a concurrent save wins.
+func (p *PostgresLedgerStore) ReencryptAccounts(ctx context.Context) (int, error) {
+	if p.pii == nil {
+		return 0, nil
+	}
+	const query = `SELECT id, holder_name, holder_email FROM accounts
+	WHERE (holder_name <> '' AND NOT starts_with(holder_name, $1)) OR (holder_email <> '' AND NOT starts_with(holder_email, $1))
+	ORDER BY id LIMIT $2`
+	const update = `UPDATE accounts SET holder_name = $2, holder_email = $3 WHERE id = $1 AND holder_name = $4 AND holder_email = $5`
+
+	total := 0
+	for {
+		rows, err := p.db.QueryContext(ctx, query, p.pii.CurrentPrefix(), reencryptBatch)
+		if err != nil {
+			return total, err
+		}
+		type sealed struct{ id, name, email string }
+		var stale []sealed
+		for rows.Next() {
+			var s sealed
+			if err := rows.Scan(&s.id, &s.name, &s.email); err != nil {
+				rows.Close()
+				return total, err
+			}
+			stale = append(stale, s)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+		if len(stale) == 0 {
+			return total, nil
+		}
+
+		rewritten := 0
+		for _, s := range stale {
+			account := models.Account{ID: s.id, HolderName: s.name, HolderEmail: s.email}
+			if err := p.openHolder(&account); err != nil {
+				return total, err
+			}
+			if err := p.sealHolder(&account); err != nil {
+				return total, err
+			}
+			result, err := p.db.ExecContext(ctx, update, s.id, account.HolderName, account.HolderEmail, s.name, s.email)
+			if err != nil {
+				return total, err
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				rewritten++
+			}
+		}
+		total += rewritten
+		// Every row lost to a concurrent save was sealed with the current key by that save
+		if len(stale) < reencryptBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+// PoolConfig sizes the connection pool. The database/sql defaults (unlimited open
+// connections, two idle ones) exhaust Postgres under load and then reconnect in bursts.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration // recycles connections, e.g. after a failover
+	ConnMaxIdleTime time.Duration
+}
+
+// ConfigurePool applies the pool settings to db
+func ConfigurePool(db *sql.DB, config PoolConfig) {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+}
+
+// RegisterPoolMetrics exposes the pool statistics of db, read on every scrape
+func RegisterPoolMetrics(db *sql.DB) {
+	stat := func(read func(sql.DBStats) float64) func() float64 {
+		return func() float64 { return read(db.Stats()) }
+	}
+
+	metrics.NewGaugeFunc("db_pool_max_open_connections", "Maximum number of open connections to the database",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	metrics.NewGaugeFunc("db_pool_open_connections", "Established connections, in use or idle",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	metrics.NewGaugeFunc("db_pool_in_use_connections", "Connections currently in use",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	metrics.NewGaugeFunc("db_pool_idle_connections", "Idle connections",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	metrics.NewCounterFunc("db_pool_wait_count_total", "Times a caller had to wait for a connection",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	metrics.NewCounterFunc("db_pool_wait_duration_seconds_total", "Total time spent waiting for a connection",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	metrics.NewCounterFunc("db_pool_max_idle_closed_total", "Connections closed because the idle pool was full",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	metrics.NewCounterFunc("db_pool_max_idle_time_closed_total", "Connections closed after ConnMaxIdleTime",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }))
+	metrics.NewCounterFunc("db_pool_max_lifetime_closed_total", "Connections closed after ConnMaxLifetime",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
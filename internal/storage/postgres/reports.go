@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+// allEntries includes compacted entries so reports cover the full history. Cold batches only
+// contribute their debit and credit totals, under an empty transaction ID.
+const allEntries = `(SELECT transaction_id, account_id, amount, tenant_id, created_at FROM ledger_entries
+	UNION ALL SELECT transaction_id, account_id, amount, tenant_id, created_at FROM ledger_entries_archive
+	UNION ALL SELECT '', account_id, -debits, tenant_id, last_created_at FROM cold_entry_batches
+	UNION ALL SELECT '', account_id, credits, tenant_id, last_created_at FROM cold_entry_batches)`
+
+func (p *PostgresLedgerStore) GetTrialBalance(ctx context.Context) ([]models.TrialBalanceLine, error) {
+	const query = `SELECT e.account_id, COALESCE(a.class, ''),
+		COALESCE(SUM(-e.amount) FILTER (WHERE e.amount < 0), 0),
+		COALESCE(SUM(e.amount) FILTER (WHERE e.amount > 0), 0),
+		SUM(e.amount)
+	FROM ` + allEntries + ` e LEFT JOIN accounts a ON a.id = e.account_id
+	WHERE $1 = '' OR e.tenant_id = $1
+	GROUP BY e.account_id, a.class ORDER BY e.account_id`
+
+	// A tenant sees only its own accounts; the platform sees the whole ledger
+	rows, err := p.db.QueryContext(ctx, query, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []models.TrialBalanceLine
+	for rows.Next() {
+		var line models.TrialBalanceLine
+		if err := rows.Scan(&line.AccountID, &line.Class, &line.Debits, &line.Credits, &line.Balance); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func (p *PostgresLedgerStore) FindUnbalancedTransactions(ctx context.Context) ([]string, error) {
+	// Legs in cold storage can no longer be matched one by one, so transactions up to the
+	// newest cold entry are skipped; they were checked while still in the archive.
+	const query = `SELECT t.id FROM transactions t
+	LEFT JOIN ` + allEntries + ` e ON e.transaction_id = t.id
+	WHERE t.created_at > (SELECT COALESCE(MAX(last_created_at), '-infinity') FROM cold_entry_batches)
+	GROUP BY t.id, t.from_account, t.to_account, t.amount, t.fx
+	HAVING COUNT(e.transaction_id) < 2
+		OR SUM(e.amount) <> 0
+		OR COUNT(*) FILTER (WHERE e.account_id = t.from_account AND e.amount = -t.amount) = 0
+		OR COUNT(*) FILTER (WHERE e.account_id = t.to_account
+			AND e.amount = COALESCE((t.fx->>'converted_amount')::NUMERIC, t.amount)) = 0
+	UNION
+	SELECT DISTINCT e.transaction_id FROM ` + allEntries + ` e
+	WHERE e.transaction_id <> '' AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.id = e.transaction_id)`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+var _ interfaces.ReportStore = (*PostgresLedgerStore)(nil)
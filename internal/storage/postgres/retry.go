@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+)
+
+var ErrRetriesExhausted = errors.New("database operation kept failing")
+
+var retries = metrics.NewCounterVec("postgres_retries_total",
+	"Units of work retried after a transient database error", "operation")
+
+// RetryError is returned once a unit of work has failed with a transient error on every attempt.
+// It matches both ErrRetriesExhausted and the last error.
+type RetryError struct {
+	Operation string
+	Attempts  int
+	Err       error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s: %s failed %d times: %v", ErrRetriesExhausted, e.Operation, e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() []error {
+	return []error{ErrRetriesExhausted, e.Err}
+}
+
+// RetryPolicy bounds how often and how fast a unit of work is retried
+type RetryPolicy struct {
+	Attempts  int           // total tries, including the first
+	BaseDelay time.Duration // doubled after every failure
+	MaxDelay  time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{Attempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second}
+
+// SetRetryPolicy changes how transactional writes are retried; Attempts of 1 disables retries
+func (p *PostgresLedgerStore) SetRetryPolicy(policy RetryPolicy) {
+	policy.Attempts = max(policy.Attempts, 1)
+	p.retry = policy
+}
+
+// withRetry runs a unit of work - one database transaction - again when it failed for a
+// reason that may go away on its own. The work must start its own transaction so a retry
+// begins from scratch. Single statements need no wrapping: database/sql already retries
+// them on a bad connection.
+func (p *PostgresLedgerStore) withRetry(ctx context.Context, operation string, work func() error) error {
+	delay := p.retry.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := work()
+		if err == nil || !isTransient(err) {
+			return err
+		}
+		if attempt >= p.retry.Attempts {
+			return &RetryError{Operation: operation, Attempts: attempt, Err: err}
+		}
+		retries.With(operation).Inc()
+
+		// Full jitter keeps replicas that collided on a deadlock from colliding again
+		select {
+		case <-time.After(rand.N(delay + 1)):
+		case <-ctx.Done():
+			return &RetryError{Operation: operation, Attempts: attempt, Err: err}
+		}
+		delay = min(delay*2, p.retry.MaxDelay)
+	}
+}
+
+// isTransient reports whether retrying the failed transaction can succeed: serialization
+// failures, deadlocks, lost optimistic version checks, and connections that broke or
+// could not be made
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrVersionConflict) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"53300", // too_many_connections
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		return pqErr.Code.Class() == "08" // connection_exception
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const ruleColumns = `id, name, type, action, enabled, threshold, window_seconds, max_count, updated_at`
+
+func scanRule(scan func(dest ...any) error) (models.FraudRule, error) {
+	var rule models.FraudRule
+	var windowSeconds int64
+	err := scan(&rule.ID, &rule.Name, &rule.Type, &rule.Action, &rule.Enabled,
+		&rule.Threshold, &windowSeconds, &rule.MaxCount, &rule.UpdatedAt)
+	rule.Window = models.Duration(time.Duration(windowSeconds) * time.Second)
+	return rule, err
+}
+
+func (p *PostgresLedgerStore) ListRules(ctx context.Context) ([]models.FraudRule, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+ruleColumns+` FROM fraud_rules ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.FraudRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (p *PostgresLedgerStore) GetRule(ctx context.Context, id string) (*models.FraudRule, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+ruleColumns+` FROM fraud_rules WHERE id = $1`, id)
+	rule, err := scanRule(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (p *PostgresLedgerStore) SaveRule(ctx context.Context, rule models.FraudRule) error {
+	const query = `INSERT INTO fraud_rules (` + ruleColumns + `) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, type = EXCLUDED.type, action = EXCLUDED.action,
+	enabled = EXCLUDED.enabled, threshold = EXCLUDED.threshold, window_seconds = EXCLUDED.window_seconds,
+	max_count = EXCLUDED.max_count, updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, rule.ID, rule.Name, rule.Type, rule.Action, rule.Enabled,
+		rule.Threshold, int64(time.Duration(rule.Window)/time.Second), rule.MaxCount, rule.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) DeleteRule(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM fraud_rules WHERE id = $1`, id)
+	return err
+}
+
+func (p *PostgresLedgerStore) HasPaidCounterparty(ctx context.Context, from, to string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM transactions WHERE from_account = $1 AND to_account = $2)`
+
+	var exists bool
+	err := p.db.QueryRowContext(ctx, query, from, to).Scan(&exists)
+	return exists, err
+}
+
+func (p *PostgresLedgerStore) CountDebitsSince(ctx context.Context, accountId string, since time.Time) (int, error) {
+	const query = `SELECT COUNT(*) FROM ledger_entries WHERE account_id = $1 AND created_at >= $2 AND amount < 0`
+
+	var count int
+	err := p.db.QueryRowContext(ctx, query, accountId, since).Scan(&count)
+	return count, err
+}
+
+var _ interfaces.RuleStore = (*PostgresLedgerStore)(nil)
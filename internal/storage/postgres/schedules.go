@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+const scheduleColumns = `id, tenant_id, from_account, to_account, amount, cron, reference, description, status,
+	start_at, end_at, next_run_at, last_run_at, failures, last_error, created_at, updated_at`
+
+func scanSchedule(scan func(dest ...any) error) (models.Schedule, error) {
+	var s models.Schedule
+	err := scan(&s.ID, &s.TenantID, &s.FromAccount, &s.ToAccount, &s.Amount, &s.Cron, &s.Reference, &s.Description, &s.Status,
+		&s.StartAt, &s.EndAt, &s.NextRunAt, &s.LastRunAt, &s.Failures, &s.LastError, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (p *PostgresLedgerStore) querySchedules(ctx context.Context, query string, args ...any) ([]models.Schedule, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SaveSchedule(ctx context.Context, s models.Schedule) error {
+	const query = `INSERT INTO schedules (` + scheduleColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17)
+	ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, end_at = EXCLUDED.end_at,
+	next_run_at = EXCLUDED.next_run_at, last_run_at = EXCLUDED.last_run_at, failures = EXCLUDED.failures,
+	last_error = EXCLUDED.last_error, updated_at = EXCLUDED.updated_at`
+
+	_, err := p.db.ExecContext(ctx, query, s.ID, s.TenantID, s.FromAccount, s.ToAccount, s.Amount, s.Cron, s.Reference, s.Description,
+		s.Status, s.StartAt, s.EndAt, s.NextRunAt, s.LastRunAt, s.Failures, s.LastError, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) GetSchedule(ctx context.Context, id string) (*models.Schedule, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+scheduleColumns+` FROM schedules WHERE id = $1`, id)
+	schedule, err := scanSchedule(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (p *PostgresLedgerStore) ListSchedules(ctx context.Context) ([]models.Schedule, error) {
+	return p.querySchedules(ctx, `SELECT `+scheduleColumns+` FROM schedules
+	WHERE $1 = '' OR tenant_id = $1 ORDER BY created_at`, tenant.FromContext(ctx))
+}
+
+func (p *PostgresLedgerStore) ListDueSchedules(ctx context.Context, now time.Time) ([]models.Schedule, error) {
+	return p.querySchedules(ctx, `SELECT `+scheduleColumns+` FROM schedules
+	WHERE status = 'active' AND next_run_at <= $1 ORDER BY next_run_at`, now)
+}
+
+var _ interfaces.ScheduleStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) GetLatestSnapshot(accountId string) (*models.BalanceSnapshot, error) {
+	const query = `SELECT account_id, balance, as_of_seq, as_of_time, created_at FROM balance_snapshots
+	WHERE account_id = $1 ORDER BY as_of_seq DESC LIMIT 1`
+
+	return p.scanSnapshot(p.db.QueryRow(query, accountId))
+}
+
+func (p *PostgresLedgerStore) GetLatestSnapshotBefore(accountId string, asOf time.Time) (*models.BalanceSnapshot, error) {
+	const query = `SELECT account_id, balance, as_of_seq, as_of_time, created_at FROM balance_snapshots
+	WHERE account_id = $1 AND as_of_time <= $2 ORDER BY as_of_seq DESC LIMIT 1`
+
+	return p.scanSnapshot(p.db.QueryRow(query, accountId, asOf))
+}
+
+// scanSnapshot returns nil without an error when the account has no snapshot yet
+func (p *PostgresLedgerStore) scanSnapshot(row *sql.Row) (*models.BalanceSnapshot, error) {
+	var snapshot models.BalanceSnapshot
+	err := row.Scan(&snapshot.AccountID, &snapshot.Balance, &snapshot.AsOfSequence, &snapshot.AsOfTime, &snapshot.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (p *PostgresLedgerStore) GetEntriesByAccountAfter(accountId string, afterSequence int64) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM ledger_entries
+	WHERE account_id = $1 AND seq > $2 ORDER BY seq`
+
+	rows, err := p.db.Query(query, accountId, afterSequence)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+func (p *PostgresLedgerStore) GetAccountsWithEntriesAfterSnapshot(ctx context.Context) ([]string, error) {
+	const query = `SELECT e.account_id FROM ledger_entries e
+	LEFT JOIN (SELECT account_id, MAX(as_of_seq) AS as_of_seq FROM balance_snapshots GROUP BY account_id) s
+	ON s.account_id = e.account_id
+	GROUP BY e.account_id, s.as_of_seq
+	HAVING MAX(e.seq) > COALESCE(s.as_of_seq, 0)`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accountIds []string
+	for rows.Next() {
+		var accountId string
+		if err := rows.Scan(&accountId); err != nil {
+			return nil, err
+		}
+		accountIds = append(accountIds, accountId)
+	}
+	return accountIds, rows.Err()
+}
+
+func (p *PostgresLedgerStore) SaveBalanceSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error {
+	const query = `INSERT INTO balance_snapshots (account_id, balance, as_of_seq, as_of_time, created_at)
+	VALUES ($1,$2,$3,$4,$5) ON CONFLICT (account_id, as_of_seq) DO NOTHING`
+
+	_, err := p.db.ExecContext(ctx, query, snapshot.AccountID, snapshot.Balance, snapshot.AsOfSequence, snapshot.AsOfTime, snapshot.CreatedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) ArchiveEntriesBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	// Entries are only moved when the latest snapshot of their account already includes them,
+	// so balances stay correct without ever reading the archive.
+	const query = `WITH moved AS (
+		DELETE FROM ledger_entries e
+		USING (SELECT account_id, MAX(as_of_seq) AS as_of_seq FROM balance_snapshots GROUP BY account_id) s
+		WHERE e.account_id = s.account_id AND e.seq <= s.as_of_seq AND e.created_at < $1
+		RETURNING e.id, e.seq, e.transaction_id, e.account_id, e.amount, e.created_at, e.prev_hash, e.hash, e.tenant_id
+	)
+	INSERT INTO ledger_entries_archive (id, seq, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id, archived_at)
+	SELECT id, seq, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id, $2 FROM moved`
+
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer dbTx.Rollback()
+
+	if _, err := dbTx.ExecContext(ctx, compactionMode); err != nil {
+		return 0, err
+	}
+	res, err := dbTx.ExecContext(ctx, query, cutoff, p.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return moved, dbTx.Commit()
+}
+
+var _ interfaces.SnapshotStore = (*PostgresLedgerStore)(nil)
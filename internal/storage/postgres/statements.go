@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+func (p *PostgresLedgerStore) GetEntriesByAccountBetween(ctx context.Context, accountId string, from, to time.Time) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` FROM (
+		SELECT ` + entryColumns + ` FROM ledger_entries WHERE account_id = $1 AND created_at >= $2 AND created_at < $3
+		UNION ALL SELECT ` + entryColumns + ` FROM ledger_entries_archive WHERE account_id = $1 AND created_at >= $2 AND created_at < $3
+	) e ORDER BY seq`
+
+	rows, err := p.db.QueryContext(ctx, query, accountId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+var _ interfaces.StatementStore = (*PostgresLedgerStore)(nil)
@@ -3,26 +3,48 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
 	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces" // interface LedgerStore
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
 	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/pii"
+	"github.com/shopspring/decimal"
 )
 
 type PostgresLedgerStore struct {
-	db *sql.DB
+	db          *sql.DB
+	retry       RetryPolicy
+	concurrency string // Pessimistic or Optimistic
+
+	commitFailureRate float64 // injected faults, zero outside resilience tests
+
+	pii *pii.Keyring // seals account holder details; nil refuses to store any
+
+	clock clock.Clock // decides payment request expiry and stamps archives and compactions
 }
 
 func NewPostgresLedgerStore(db *sql.DB) *PostgresLedgerStore {
 	return &PostgresLedgerStore{
-		db: db,
+		db:          db,
+		retry:       defaultRetryPolicy,
+		concurrency: Pessimistic,
+		clock:       clock.System{},
 	}
 }
 
-func (p *PostgresLedgerStore) TransactionExists(idempotencyKey string) (bool, error) {
-	const query = `select 1 from transactions where idempotency_key = $1 Limit 1`
+// SetClock replaces the wall clock, e.g. with the ledger's simulated one
+func (p *PostgresLedgerStore) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+func (p *PostgresLedgerStore) TransactionExists(tenantId, idempotencyKey string) (bool, error) {
+	const query = `select 1 from transactions where idempotency_key = $1
+	AND (tenant_id = $2 OR ($2 = '' AND tenant_id NOT LIKE $3)) Limit 1`
 
 	var exists int
-	err := p.db.QueryRow(query, idempotencyKey).Scan(&exists)
+	err := p.db.QueryRow(query, idempotencyKey, tenantId, livemode.TenantPattern).Scan(&exists)
 
 	if err == sql.ErrNoRows {
 		return false, nil
@@ -35,24 +57,75 @@ func (p *PostgresLedgerStore) TransactionExists(idempotencyKey string) (bool, er
 }
 
 func (p *PostgresLedgerStore) SaveTransaction(tx models.Transaction, dbTx *sql.Tx) error {
-	const query = `INSERT INTO transactions(id, idempotency_key,from_account,to_account,amount,created_at)
-	VALUES ($1,$2,$3,$4,$5,$6)`
+	const query = `INSERT INTO transactions(id, idempotency_key,from_account,to_account,amount,created_at,adjustment,original_created_at,
+	reference,description,metadata,fees,fx,tenant_id,tags,payment_request_id,value_date)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,NULLIF($17, '')::date)`
 
-	_, err := dbTx.Exec(query, tx.ID, tx.IdempotencyKey, tx.FromAccount, tx.ToAccount, tx.Amount, tx.CreatedAt)
+	metadata, err := json.Marshal(tx.Metadata)
+	if err != nil {
+		return err
+	}
+	if tx.Metadata == nil {
+		metadata = []byte("{}")
+	}
+	fees, err := json.Marshal(tx.Fees)
+	if err != nil {
+		return err
+	}
+	if tx.Fees == nil {
+		fees = []byte("[]")
+	}
+	tags, err := json.Marshal(tx.Tags)
+	if err != nil {
+		return err
+	}
+	if tx.Tags == nil {
+		tags = []byte("[]")
+	}
+	var fx any // NULL for single-currency transfers
+	if tx.FX != nil {
+		encoded, err := json.Marshal(tx.FX)
+		if err != nil {
+			return err
+		}
+		fx = string(encoded)
+	}
+
+	_, err = dbTx.Exec(query, tx.ID, tx.IdempotencyKey, tx.FromAccount, tx.ToAccount, tx.Amount, tx.CreatedAt, tx.Adjustment, tx.OriginalCreatedAt,
+		tx.Reference, tx.Description, string(metadata), string(fees), fx, tx.TenantID, string(tags), tx.PaymentRequestID,
+		tx.ValueDate)
 
 	return err
 }
 
 func (p *PostgresLedgerStore) SaveEntry(ctx context.Context, ledgerEntry models.LedgerEntry, dbTx *sql.Tx) error {
-	const query = `INSERT INTO ledger_entries (id,account_id, amount,created_at)
-	VALUES ($1,$2,$3,$4)`
+	const query = `INSERT INTO ledger_entries (id,transaction_id,account_id, amount,created_at,prev_hash,hash,tenant_id)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`
 
-	_, err := dbTx.ExecContext(ctx, query, ledgerEntry.ID, ledgerEntry.AccountID, ledgerEntry.Amount, ledgerEntry.CreatedAt)
-	return err
+	_, err := dbTx.ExecContext(ctx, query, ledgerEntry.ID, ledgerEntry.TransactionID, ledgerEntry.AccountID, ledgerEntry.Amount, ledgerEntry.CreatedAt, ledgerEntry.PrevHash, ledgerEntry.Hash, ledgerEntry.TenantID)
+	if err != nil {
+		return err
+	}
+	return saveChainLink(ctx, dbTx, ledgerEntry)
 }
 
 func (p *PostgresLedgerStore) SaveTransactionWithEntries(ctx context.Context, tx models.Transaction, debit models.LedgerEntry, credit models.LedgerEntry) error {
+	return p.SaveTransactionWithLegs(ctx, tx, []models.LedgerEntry{debit, credit})
+}
 
+// SaveTransactionWithLegs stores the transaction and any number of legs atomically, in order.
+// A retry after a commit whose outcome was lost fails on the transaction's unique keys
+// instead of posting twice.
+func (p *PostgresLedgerStore) SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	return p.withRetry(ctx, "save_transaction", func() error {
+		return p.saveTransactionWithLegs(ctx, tx, entries, nil)
+	})
+}
+
+// saveTransactionWithLegs also moves the materialized balances; check, when given, sees
+// the balances as read (and, in pessimistic mode, locked) in this database transaction
+func (p *PostgresLedgerStore) saveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+	check func(balances map[string]decimal.Decimal) error) (err error) {
 	dbTx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -64,45 +137,54 @@ func (p *PostgresLedgerStore) SaveTransactionWithEntries(ctx context.Context, tx
 		}
 	}()
 
-	err = p.SaveTransaction(tx, dbTx)
+	ids := balanceAccounts(entries)
+	balances, versions, err := p.readBalances(ctx, dbTx, ids)
 	if err != nil {
 		return err
 	}
+	if err = checkChainHeads(ctx, dbTx, entries); err != nil {
+		return err
+	}
+	if err = checkPeriodOpen(ctx, dbTx, tx.CreatedAt); err != nil {
+		return err
+	}
+	if check != nil {
+		if err = check(balances); err != nil {
+			return err
+		}
+	}
 
-	err = p.SaveEntry(ctx, debit, dbTx)
+	err = p.SaveTransaction(tx, dbTx)
 	if err != nil {
 		return err
 	}
+	if tx.PaymentRequestID != "" {
+		if err = fulfillPaymentRequest(ctx, dbTx, tx, p.clock.Now().UTC()); err != nil {
+			return err
+		}
+	}
 
-	err = p.SaveEntry(ctx, credit, dbTx)
-	if err != nil {
+	for _, entry := range entries {
+		err = p.SaveEntry(ctx, entry, dbTx)
+		if err != nil {
+			return err
+		}
+	}
+	if err = p.applyBalances(ctx, dbTx, ids, entries, versions); err != nil {
 		return err
 	}
-	return dbTx.Commit()
+	return p.commitPosting(dbTx)
 }
 
-func (p *PostgresLedgerStore) GetLedgerEntries() ([]models.LedgerEntry, error) {
-
-	const query = `SELECT id, account_id, amount, created_at from ledger_entries`
-
-	rows, err := p.db.Query(query)
-
-	if err != nil {
-		return nil, err
-	}
+// entryColumns matches the scan order used by scanEntries
+const entryColumns = `id, transaction_id, account_id, amount, created_at, seq, prev_hash, hash, tenant_id`
 
+func scanEntries(rows *sql.Rows) ([]models.LedgerEntry, error) {
 	defer rows.Close()
 
 	var entries []models.LedgerEntry
-
 	for rows.Next() {
-		var entry models.LedgerEntry
-		err := rows.Scan(
-			&entry.ID,
-			&entry.AccountID,
-			&entry.Amount,
-			&entry.CreatedAt,
-		)
+		entry, err := scanEntry(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -115,28 +197,44 @@ func (p *PostgresLedgerStore) GetLedgerEntries() ([]models.LedgerEntry, error) {
 	return entries, nil
 }
 
-func (p *PostgresLedgerStore) GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error) {
-	const query = `SELECT id, account_id, amount, created_at from ledger_entries 
-	WHERE account_id = $1`
+func scanEntry(rows *sql.Rows) (models.LedgerEntry, error) {
+	var entry models.LedgerEntry
+	err := rows.Scan(
+		&entry.ID,
+		&entry.TransactionID,
+		&entry.AccountID,
+		&entry.Amount,
+		&entry.CreatedAt,
+		&entry.Sequence,
+		&entry.PrevHash,
+		&entry.Hash,
+		&entry.TenantID,
+	)
+	return entry, err
+}
 
-	rows, err := p.db.Query(query, accountId)
+func (p *PostgresLedgerStore) GetLedgerEntries() ([]models.LedgerEntry, error) {
+
+	const query = `SELECT ` + entryColumns + ` from ledger_entries ORDER BY seq`
+
+	rows, err := p.db.Query(query)
 
 	if err != nil {
 		return nil, err
 	}
+	return scanEntries(rows)
+}
 
-	defer rows.Close()
+func (p *PostgresLedgerStore) GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error) {
+	const query = `SELECT ` + entryColumns + ` from ledger_entries 
+	WHERE account_id = $1 ORDER BY seq`
 
-	var entries []models.LedgerEntry
-	for rows.Next() {
-		var entry models.LedgerEntry
-		if err := rows.Scan(&entry.ID, &entry.AccountID, &entry.Amount, &entry.CreatedAt); err != nil {
-			return nil, err
-		}
+	rows, err := p.db.Query(query, accountId)
 
-		entries = append(entries, entry)
+	if err != nil {
+		return nil, err
 	}
-	return entries, nil
+	return scanEntries(rows)
 }
 
 var _ interfaces.LedgerStore = (*PostgresLedgerStore)(nil)
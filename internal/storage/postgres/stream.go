@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+func (p *PostgresLedgerStore) StreamLedgerEntries(ctx context.Context, accountId string, fn func(models.LedgerEntry) error) error {
+	query := `SELECT ` + entryColumns + ` FROM ledger_entries ORDER BY seq`
+	var args []any
+	switch tenantId := tenant.FromContext(ctx); {
+	case accountId != "":
+		query = `SELECT ` + entryColumns + ` FROM ledger_entries WHERE account_id = $1 ORDER BY seq`
+		args = append(args, accountId)
+	case tenantId != "":
+		query = `SELECT ` + entryColumns + ` FROM ledger_entries WHERE tenant_id = $1 ORDER BY seq`
+		args = append(args, tenantId)
+	default:
+		// A live export leaves test entries out
+		query = `SELECT ` + entryColumns + ` FROM ledger_entries WHERE tenant_id NOT LIKE $1 ORDER BY seq`
+		args = append(args, livemode.TenantPattern)
+	}
+
+	// lib/pq reads rows from the connection as they are scanned, so memory stays flat
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.LedgerEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TransactionID,
+			&entry.AccountID,
+			&entry.Amount,
+			&entry.CreatedAt,
+			&entry.Sequence,
+			&entry.PrevHash,
+			&entry.Hash,
+			&entry.TenantID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+var _ interfaces.EntryStreamer = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+)
+
+func (p *PostgresLedgerStore) TransactionIDsBetween(ctx context.Context, from, to time.Time) ([]string, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT id FROM transactions WHERE created_at >= $1 AND created_at < $2`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (p *PostgresLedgerStore) MissingTransactions(ctx context.Context, ids []string) ([]string, error) {
+	const query = `SELECT wanted.id FROM unnest($1::text[]) AS wanted(id)
+	WHERE NOT EXISTS (SELECT 1 FROM transactions t WHERE t.id = wanted.id)`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missing := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		missing = append(missing, id)
+	}
+	return missing, rows.Err()
+}
+
+var _ interfaces.StreamReconciliationStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+const suspenseItemColumns = `id, tenant_id, source, transaction_id, from_account, intended_account, amount, reason,
+	status, created_at, resolved_account, resolution_transaction_id, resolved_at`
+
+func scanSuspenseItem(scan func(dest ...any) error) (models.SuspenseItem, error) {
+	var item models.SuspenseItem
+	err := scan(&item.ID, &item.TenantID, &item.Source, &item.TransactionID, &item.FromAccount, &item.IntendedAccount,
+		&item.Amount, &item.Reason, &item.Status, &item.CreatedAt, &item.ResolvedAccount, &item.ResolutionTransactionID,
+		&item.ResolvedAt)
+	return item, err
+}
+
+// SaveSuspenseItem ignores an item whose posting is already recorded, so a flow retried
+// after a crash does not park the same money twice
+func (p *PostgresLedgerStore) SaveSuspenseItem(ctx context.Context, item models.SuspenseItem) error {
+	const query = `INSERT INTO suspense_items (` + suspenseItemColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+	ON CONFLICT (transaction_id) DO NOTHING`
+
+	_, err := p.db.ExecContext(ctx, query, item.ID, item.TenantID, item.Source, item.TransactionID, item.FromAccount,
+		item.IntendedAccount, item.Amount, item.Reason, item.Status, item.CreatedAt, item.ResolvedAccount,
+		item.ResolutionTransactionID, item.ResolvedAt)
+	return err
+}
+
+func (p *PostgresLedgerStore) GetSuspenseItem(ctx context.Context, id string) (*models.SuspenseItem, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+suspenseItemColumns+` FROM suspense_items WHERE id = $1`, id)
+	item, err := scanSuspenseItem(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (p *PostgresLedgerStore) ListSuspenseItems(ctx context.Context, status string) ([]models.SuspenseItem, error) {
+	// Served by idx_suspense_items_status when a status is given
+	rows, err := p.db.QueryContext(ctx, `SELECT `+suspenseItemColumns+` FROM suspense_items
+	WHERE $1 = '' OR status = $1 ORDER BY created_at DESC`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.SuspenseItem{}
+	for rows.Next() {
+		item, err := scanSuspenseItem(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (p *PostgresLedgerStore) ResolveSuspenseItem(ctx context.Context, id, account, transactionId string, at time.Time) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `UPDATE suspense_items
+	SET status = 'resolved', resolved_account = $2, resolution_transaction_id = $3, resolved_at = $4
+	WHERE id = $1 AND status = 'open'`, id, account, transactionId, at)
+	if err != nil {
+		return false, err
+	}
+	resolved, err := result.RowsAffected()
+	return resolved == 1, err
+}
+
+var _ interfaces.SuspenseStore = (*PostgresLedgerStore)(nil)
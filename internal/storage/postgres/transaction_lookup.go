@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+func (p *PostgresLedgerStore) GetTransactions(ctx context.Context, ids []string) ([]models.Transaction, error) {
+	if len(ids) == 0 {
+		return []models.Transaction{}, nil
+	}
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = ANY($1)`
+	args := []any{pq.Array(ids)}
+	if tenantId := tenant.FromContext(ctx); tenantId != "" {
+		query += ` AND tenant_id = $2`
+		args = append(args, tenantId)
+	}
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanTransactions(rows)
+}
+
+var _ interfaces.TransactionLookupStore = (*PostgresLedgerStore)(nil)
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+	"github.com/shopspring/decimal"
+)
+
+// transactionColumns matches the scan order used by scanTransactions
+const transactionColumns = `id, tenant_id, idempotency_key, from_account, to_account, amount, created_at,
+	adjustment, original_created_at, reference, description, metadata, fees, fx, tags, payment_request_id,
+	COALESCE(to_char(value_date, 'YYYY-MM-DD'), '')`
+
+func scanTransactions(rows *sql.Rows) ([]models.Transaction, error) {
+	defer rows.Close()
+
+	transactions := []models.Transaction{}
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
+
+func scanTransaction(rows *sql.Rows) (models.Transaction, error) {
+	var tx models.Transaction
+	var metadata, fees, fx, tags []byte
+	err := rows.Scan(&tx.ID, &tx.TenantID, &tx.IdempotencyKey, &tx.FromAccount, &tx.ToAccount, &tx.Amount, &tx.CreatedAt,
+		&tx.Adjustment, &tx.OriginalCreatedAt, &tx.Reference, &tx.Description, &metadata, &fees, &fx, &tags, &tx.PaymentRequestID,
+		&tx.ValueDate)
+	if err != nil {
+		return tx, err
+	}
+	if err := json.Unmarshal(metadata, &tx.Metadata); err != nil {
+		return tx, err
+	}
+	if err := json.Unmarshal(fees, &tx.Fees); err != nil {
+		return tx, err
+	}
+	if err := json.Unmarshal(tags, &tx.Tags); err != nil {
+		return tx, err
+	}
+	if fx != nil {
+		if err := json.Unmarshal(fx, &tx.FX); err != nil {
+			return tx, err
+		}
+	}
+	return tx, nil
+}
+
+func (p *PostgresLedgerStore) SearchTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if tenantId := tenant.FromContext(ctx); tenantId != "" {
+		add("tenant_id = $%d", tenantId)
+	} else {
+		add("tenant_id NOT LIKE $%d", livemode.TenantPattern)
+	}
+	if filter.ID != "" {
+		add("id = $%d", filter.ID)
+	}
+	if filter.Reference != "" {
+		add("reference = $%d", filter.Reference)
+	}
+	if len(filter.Metadata) > 0 {
+		// Containment is served by the GIN index on metadata
+		metadata, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		add("metadata @> $%d::jsonb", string(metadata))
+	}
+	if len(filter.Tags) > 0 {
+		// Served by the GIN index on tags
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		add("tags @> $%d::jsonb", string(tags))
+	}
+	if filter.Account != "" {
+		// Served by idx_transactions_counterparty and idx_transactions_to_account
+		add("(from_account = $%[1]d OR to_account = $%[1]d)", filter.Account)
+	}
+	if !filter.MinAmount.IsZero() {
+		add("amount >= $%d", filter.MinAmount)
+	}
+	if !filter.MaxAmount.IsZero() {
+		add("amount <= $%d", filter.MaxAmount)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", filter.To)
+	}
+	if filter.Status == models.TransactionAdjustment {
+		add("adjustment = $%d", true)
+	}
+	if filter.IdempotencyKey != "" {
+		add("idempotency_key = $%d", filter.IdempotencyKey)
+	}
+	if filter.After != nil {
+		// Keyset pagination: the ID breaks ties between transactions created in the same instant
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanTransactions(rows)
+}
+
+func (p *PostgresLedgerStore) FindRecentTransaction(ctx context.Context, from, to string, amount decimal.Decimal, since time.Time) (*models.Transaction, error) {
+	// Served by idx_transactions_counterparty
+	query := `SELECT ` + transactionColumns + ` FROM transactions
+	WHERE from_account = $1 AND to_account = $2 AND amount = $3 AND created_at >= $4
+	ORDER BY created_at DESC LIMIT 1`
+
+	rows, err := p.db.QueryContext(ctx, query, from, to, amount, since)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := scanTransactions(rows)
+	if err != nil || len(transactions) == 0 {
+		return nil, err
+	}
+	return &transactions[0], nil
+}
+
+// UpdateTransactionTags locks the transaction row, lets update compute the new tags and stores
+// them, so concurrent changes to the same transaction do not overwrite each other. It returns
+// nil when the transaction does not exist or belongs to another tenant.
+func (p *PostgresLedgerStore) UpdateTransactionTags(ctx context.Context, id string, update func(tags []string) ([]string, error)) (_ *models.Transaction, err error) {
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback()
+		}
+	}()
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+	args := []any{id}
+	if tenantId := tenant.FromContext(ctx); tenantId != "" {
+		query += ` AND tenant_id = $2`
+		args = append(args, tenantId)
+	}
+	rows, err := dbTx.QueryContext(ctx, query+` FOR UPDATE`, args...)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := scanTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(transactions) == 0 {
+		dbTx.Rollback()
+		return nil, nil
+	}
+
+	tx := transactions[0]
+	if tx.Tags, err = update(tx.Tags); err != nil {
+		return nil, err
+	}
+	tags, err := json.Marshal(tx.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Tags == nil {
+		tags = []byte("[]")
+	}
+	if _, err = dbTx.ExecContext(ctx, `UPDATE transactions SET tags = $2 WHERE id = $1`, id, string(tags)); err != nil {
+		return nil, err
+	}
+	return &tx, dbTx.Commit()
+}
+
+var (
+	_ interfaces.TransactionSearchStore = (*PostgresLedgerStore)(nil)
+	_ interfaces.TransactionTagStore    = (*PostgresLedgerStore)(nil)
+	_ interfaces.DuplicateStore         = (*PostgresLedgerStore)(nil)
+)
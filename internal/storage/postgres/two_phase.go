@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+const twoPhaseLegColumns = `xid, role, account_id, amount, coordinator, status, transaction_id, prepared_at, decided_at`
+
+const crossInstanceTransferColumns = `id, tenant_id, idempotency_key, from_account, to_instance, to_account, amount, status,
+	reason, created_at, decided_at, completed_at`
+
+func scanCrossInstanceTransfer(scan func(dest ...any) error) (models.CrossInstanceTransfer, error) {
+	var t models.CrossInstanceTransfer
+	err := scan(&t.ID, &t.TenantID, &t.IdempotencyKey, &t.FromAccount, &t.ToInstance, &t.ToAccount, &t.Amount, &t.Status, &t.Reason,
+		&t.CreatedAt, &t.DecidedAt, &t.CompletedAt)
+	return t, err
+}
+
+func (p *PostgresLedgerStore) SaveTwoPhaseLeg(ctx context.Context, leg models.TwoPhaseLeg) (bool, error) {
+	const query = `INSERT INTO two_phase_legs (` + twoPhaseLegColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	ON CONFLICT (xid) DO NOTHING`
+
+	result, err := p.db.ExecContext(ctx, query, leg.XID, leg.Role, leg.AccountID, leg.Amount, leg.Coordinator,
+		leg.Status, leg.TransactionID, leg.PreparedAt, leg.DecidedAt)
+	if err != nil {
+		return false, err
+	}
+	saved, err := result.RowsAffected()
+	return saved == 1, err
+}
+
+func (p *PostgresLedgerStore) GetTwoPhaseLeg(ctx context.Context, xid string) (*models.TwoPhaseLeg, error) {
+	var leg models.TwoPhaseLeg
+	err := p.db.QueryRowContext(ctx, `SELECT `+twoPhaseLegColumns+` FROM two_phase_legs WHERE xid = $1`, xid).Scan(
+		&leg.XID, &leg.Role, &leg.AccountID, &leg.Amount, &leg.Coordinator, &leg.Status, &leg.TransactionID,
+		&leg.PreparedAt, &leg.DecidedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leg, nil
+}
+
+func (p *PostgresLedgerStore) DecideTwoPhaseLeg(ctx context.Context, xid, status, transactionId string, at time.Time) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `UPDATE two_phase_legs
+	SET status = $2, transaction_id = CASE WHEN $3 = '' THEN transaction_id ELSE $3 END, decided_at = $4
+	WHERE xid = $1 AND status = 'prepared'`, xid, status, transactionId, at)
+	if err != nil {
+		return false, err
+	}
+	decided, err := result.RowsAffected()
+	return decided == 1, err
+}
+
+func (p *PostgresLedgerStore) SaveCrossInstanceTransfer(ctx context.Context, transfer models.CrossInstanceTransfer) (bool, error) {
+	const query = `INSERT INTO cross_instance_transfers (` + crossInstanceTransferColumns + `)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	ON CONFLICT (tenant_id, idempotency_key) DO NOTHING`
+
+	result, err := p.db.ExecContext(ctx, query, transfer.ID, transfer.TenantID, transfer.IdempotencyKey, transfer.FromAccount,
+		transfer.ToInstance, transfer.ToAccount, transfer.Amount, transfer.Status, transfer.Reason, transfer.CreatedAt,
+		transfer.DecidedAt, transfer.CompletedAt)
+	if err != nil {
+		return false, err
+	}
+	saved, err := result.RowsAffected()
+	return saved == 1, err
+}
+
+func (p *PostgresLedgerStore) GetCrossInstanceTransfer(ctx context.Context, id string) (*models.CrossInstanceTransfer, error) {
+	return p.getCrossInstanceTransfer(ctx, `id = $1`, id)
+}
+
+func (p *PostgresLedgerStore) GetCrossInstanceTransferByKey(ctx context.Context, idempotencyKey string) (*models.CrossInstanceTransfer, error) {
+	return p.getCrossInstanceTransfer(ctx, `tenant_id = $1 AND idempotency_key = $2`, tenant.FromContext(ctx), idempotencyKey)
+}
+
+func (p *PostgresLedgerStore) getCrossInstanceTransfer(ctx context.Context, condition string, args ...any) (*models.CrossInstanceTransfer, error) {
+	row := p.db.QueryRowContext(ctx, `SELECT `+crossInstanceTransferColumns+` FROM cross_instance_transfers WHERE `+condition, args...)
+	transfer, err := scanCrossInstanceTransfer(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (p *PostgresLedgerStore) DecideCrossInstanceTransfer(ctx context.Context, id, status, reason string, at time.Time) (bool, error) {
+	result, err := p.db.ExecContext(ctx, `UPDATE cross_instance_transfers SET status = $2, reason = $3, decided_at = $4
+	WHERE id = $1 AND status = 'preparing'`, id, status, reason, at)
+	if err != nil {
+		return false, err
+	}
+	decided, err := result.RowsAffected()
+	return decided == 1, err
+}
+
+func (p *PostgresLedgerStore) CompleteCrossInstanceTransfer(ctx context.Context, id string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE cross_instance_transfers SET completed_at = $2
+	WHERE id = $1 AND completed_at IS NULL`, id, at)
+	return err
+}
+
+func (p *PostgresLedgerStore) ListUnfinishedCrossInstanceTransfers(ctx context.Context, createdBefore time.Time) ([]models.CrossInstanceTransfer, error) {
+	// Served by idx_cross_instance_transfers_unfinished
+	rows, err := p.db.QueryContext(ctx, `SELECT `+crossInstanceTransferColumns+` FROM cross_instance_transfers
+	WHERE completed_at IS NULL AND created_at < $1 ORDER BY created_at`, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []models.CrossInstanceTransfer{}
+	for rows.Next() {
+		transfer, err := scanCrossInstanceTransfer(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rows.Err()
+}
+
+var (
+	_ interfaces.TwoPhaseParticipantStore = (*PostgresLedgerStore)(nil)
+	_ interfaces.TwoPhaseCoordinatorStore = (*PostgresLedgerStore)(nil)
+)
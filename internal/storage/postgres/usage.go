@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+func (p *PostgresLedgerStore) AddUsage(ctx context.Context, records []models.UsageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	dbTx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Rollback()
+
+	const query = `
+		INSERT INTO api_usage (tenant_id, key_id, day, api_calls, transactions)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, key_id, day) DO UPDATE SET
+			api_calls = api_usage.api_calls + EXCLUDED.api_calls,
+			transactions = api_usage.transactions + EXCLUDED.transactions`
+	for _, record := range records {
+		if _, err := dbTx.ExecContext(ctx, query, record.TenantID, record.KeyID, record.Date, record.APICalls, record.Transactions); err != nil {
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
+// ListUsage is scoped to the tenant of ctx, whatever tenant the filter names
+func (p *PostgresLedgerStore) ListUsage(ctx context.Context, filter models.UsageFilter) ([]models.UsageRecord, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if tenantId := tenant.FromContext(ctx); tenantId != "" {
+		add("tenant_id = $%d", tenantId)
+	} else if filter.TenantID != "" {
+		add("tenant_id = $%d", filter.TenantID)
+	}
+	if filter.KeyID != "" {
+		add("key_id = $%d", filter.KeyID)
+	}
+	if !filter.From.IsZero() {
+		add("day >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("day < $%d", filter.To)
+	}
+
+	query := `SELECT tenant_id, key_id, to_char(day, 'YYYY-MM-DD'), api_calls, transactions FROM api_usage`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	rows, err := p.db.QueryContext(ctx, query+` ORDER BY day, tenant_id, key_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.UsageRecord{}
+	for rows.Next() {
+		var record models.UsageRecord
+		if err := rows.Scan(&record.TenantID, &record.KeyID, &record.Date, &record.APICalls, &record.Transactions); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+var _ interfaces.UsageStore = (*PostgresLedgerStore)(nil)
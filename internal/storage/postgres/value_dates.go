@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+// ValueDateAdjustment reads only the hot entries: value and booking dates lie days apart,
+// far less than the retention that moves entries to the archive
+func (p *PostgresLedgerStore) ValueDateAdjustment(ctx context.Context, accountId, date string, bookedBy time.Time) (decimal.Decimal, error) {
+	const query = `SELECT COALESCE(SUM(CASE WHEN e.created_at <= $3 THEN -e.amount ELSE e.amount END), 0)
+	FROM ledger_entries e JOIN transactions t ON t.id = e.transaction_id
+	WHERE e.account_id = $1 AND t.value_date IS NOT NULL
+	AND ((e.created_at <= $3 AND t.value_date > $2) OR (e.created_at > $3 AND t.value_date <= $2))`
+
+	var adjustment decimal.Decimal
+	err := p.db.QueryRowContext(ctx, query, accountId, date, bookedBy).Scan(&adjustment)
+	return adjustment, err
+}
+
+var _ interfaces.ValueDateStore = (*PostgresLedgerStore)(nil)
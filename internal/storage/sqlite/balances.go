@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+func (s *SQLiteLedgerStore) GetMaterializedBalance(ctx context.Context, accountId string) (decimal.Decimal, bool, error) {
+	var balance decimal.Decimal
+	err := s.db.QueryRowContext(ctx, `SELECT balance FROM account_balances WHERE account_id = ?`, accountId).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+	return balance, true, nil
+}
+
+// SaveTransactionChecked needs no row locks: the write transaction holds the database
+// lock from its first statement, so no other posting can change a balance it has read
+func (s *SQLiteLedgerStore) SaveTransactionChecked(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+	check func(balances map[string]decimal.Decimal) error) error {
+	return s.saveTransactionWithLegs(ctx, tx, entries, check)
+}
+
+// readBalances returns the balances of the legs' accounts. An account without a balance
+// row is summed from its entries, so a database written before the row existed stays right.
+func readBalances(ctx context.Context, dbTx *sql.Tx, entries []models.LedgerEntry) (map[string]decimal.Decimal, error) {
+	balances := make(map[string]decimal.Decimal, len(entries))
+	for _, entry := range entries {
+		if _, ok := balances[entry.AccountID]; ok {
+			continue
+		}
+		var balance decimal.Decimal
+		err := dbTx.QueryRowContext(ctx, `SELECT balance FROM account_balances WHERE account_id = ?`, entry.AccountID).Scan(&balance)
+		if err == sql.ErrNoRows {
+			balance, err = sumEntries(ctx, dbTx, entry.AccountID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		balances[entry.AccountID] = balance
+	}
+	return balances, nil
+}
+
+// sumEntries adds the amounts up in Go: SQLite would sum the decimal strings as floats
+func sumEntries(ctx context.Context, dbTx *sql.Tx, accountId string) (decimal.Decimal, error) {
+	rows, err := dbTx.QueryContext(ctx, `SELECT amount FROM ledger_entries WHERE account_id = ?`, accountId)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer rows.Close()
+
+	total := decimal.Zero
+	for rows.Next() {
+		var amount decimal.Decimal
+		if err := rows.Scan(&amount); err != nil {
+			return decimal.Zero, err
+		}
+		total = total.Add(amount)
+	}
+	return total, rows.Err()
+}
+
+func writeBalances(ctx context.Context, dbTx *sql.Tx, balances map[string]decimal.Decimal) error {
+	for accountId, balance := range balances {
+		_, err := dbTx.ExecContext(ctx, `INSERT INTO account_balances (account_id, balance) VALUES (?, ?)
+		ON CONFLICT (account_id) DO UPDATE SET balance = excluded.balance`, accountId, balance.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ interfaces.BalanceStore = (*SQLiteLedgerStore)(nil)
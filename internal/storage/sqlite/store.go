@@ -0,0 +1,194 @@
+// Package sqlite is a single-file LedgerStore for benchmarks and small single-node
+// deployments. It keeps entries, transactions and materialized balances, and nothing
+// else: every optional capability beyond balances is left to the Postgres store.
+//
+// The driver is cgo; a binary built with CGO_ENABLED=0 fails to open the database.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// schema is created on open. Amounts are stored as decimal strings, since SQLite has no
+// exact numeric type, and a transaction is kept whole as JSON beside its keys.
+const schema = `
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    transaction_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    amount TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    tenant_id TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_seq ON ledger_entries(account_id, seq);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id TEXT PRIMARY KEY,
+    idempotency_key TEXT NOT NULL,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    data TEXT NOT NULL,
+    UNIQUE (tenant_id, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions(idempotency_key);
+
+CREATE TABLE IF NOT EXISTS account_balances (
+    account_id TEXT PRIMARY KEY,
+    balance TEXT NOT NULL
+);`
+
+type SQLiteLedgerStore struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database file at path. Write transactions take the database
+// lock when they begin, so concurrent postings queue on the busy timeout instead of
+// failing when one of them tries to upgrade a read lock.
+func Open(path string) (*SQLiteLedgerStore, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_journal_mode=WAL&_busy_timeout=10000&_txlock=immediate&_foreign_keys=on")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating sqlite schema: %w", err)
+	}
+	return &SQLiteLedgerStore{db: db}, nil
+}
+
+func (s *SQLiteLedgerStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteLedgerStore) TransactionExists(tenantId, idempotencyKey string) (bool, error) {
+	var exists int
+	err := s.db.QueryRow(`SELECT 1 FROM transactions WHERE idempotency_key = ?
+	AND (tenant_id = ? OR (? = '' AND tenant_id NOT LIKE ?)) LIMIT 1`,
+		idempotencyKey, tenantId, tenantId, livemode.TenantPattern).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SaveTransaction stores the transaction in dbTx when given, or on its own
+func (s *SQLiteLedgerStore) SaveTransaction(tx models.Transaction, dbTx *sql.Tx) error {
+	if dbTx != nil {
+		return saveTransaction(context.Background(), dbTx, tx)
+	}
+	return s.inTx(context.Background(), func(dbTx *sql.Tx) error {
+		return saveTransaction(context.Background(), dbTx, tx)
+	})
+}
+
+func saveTransaction(ctx context.Context, dbTx *sql.Tx, tx models.Transaction) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	_, err = dbTx.ExecContext(ctx, `INSERT INTO transactions (id, idempotency_key, tenant_id, created_at, data) VALUES (?, ?, ?, ?, ?)`,
+		tx.ID, tx.IdempotencyKey, tx.TenantID, tx.CreatedAt.UTC(), string(data))
+	return err
+}
+
+func saveEntry(ctx context.Context, dbTx *sql.Tx, entry models.LedgerEntry) error {
+	_, err := dbTx.ExecContext(ctx, `INSERT INTO ledger_entries (id, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.TransactionID, entry.AccountID, entry.Amount.String(), entry.CreatedAt.UTC(), entry.PrevHash, entry.Hash, entry.TenantID)
+	return err
+}
+
+func (s *SQLiteLedgerStore) SaveTransactionWithEntries(ctx context.Context, tx models.Transaction, debit models.LedgerEntry, credit models.LedgerEntry) error {
+	return s.SaveTransactionWithLegs(ctx, tx, []models.LedgerEntry{debit, credit})
+}
+
+// SaveTransactionWithLegs stores the transaction and its legs atomically, in order
+func (s *SQLiteLedgerStore) SaveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry) error {
+	return s.saveTransactionWithLegs(ctx, tx, entries, nil)
+}
+
+// saveTransactionWithLegs runs check, when given, against the balances of the legs'
+// accounts before anything is written, and keeps the balance rows in step with the legs
+func (s *SQLiteLedgerStore) saveTransactionWithLegs(ctx context.Context, tx models.Transaction, entries []models.LedgerEntry,
+	check func(balances map[string]decimal.Decimal) error) error {
+	return s.inTx(ctx, func(dbTx *sql.Tx) error {
+		balances, err := readBalances(ctx, dbTx, entries)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(balances); err != nil {
+				return err
+			}
+		}
+		if err := saveTransaction(ctx, dbTx, tx); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := saveEntry(ctx, dbTx, entry); err != nil {
+				return err
+			}
+			balances[entry.AccountID] = balances[entry.AccountID].Add(entry.Amount)
+		}
+		return writeBalances(ctx, dbTx, balances)
+	})
+}
+
+func (s *SQLiteLedgerStore) inTx(ctx context.Context, fn func(dbTx *sql.Tx) error) error {
+	dbTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(dbTx); err != nil {
+		dbTx.Rollback()
+		return err
+	}
+	return dbTx.Commit()
+}
+
+func (s *SQLiteLedgerStore) GetLedgerEntries() ([]models.LedgerEntry, error) {
+	return s.queryEntries(`SELECT seq, id, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id
+	FROM ledger_entries ORDER BY seq`)
+}
+
+func (s *SQLiteLedgerStore) GetEntriesByAccount(accountId string) ([]models.LedgerEntry, error) {
+	return s.queryEntries(`SELECT seq, id, transaction_id, account_id, amount, created_at, prev_hash, hash, tenant_id
+	FROM ledger_entries WHERE account_id = ? ORDER BY seq`, accountId)
+}
+
+func (s *SQLiteLedgerStore) queryEntries(query string, args ...any) ([]models.LedgerEntry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.LedgerEntry
+	for rows.Next() {
+		var entry models.LedgerEntry
+		if err := rows.Scan(&entry.Sequence, &entry.ID, &entry.TransactionID, &entry.AccountID, &entry.Amount,
+			&entry.CreatedAt, &entry.PrevHash, &entry.Hash, &entry.TenantID); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+var _ interfaces.LedgerStore = (*SQLiteLedgerStore)(nil)
+var _ interfaces.MultiLegStore = (*SQLiteLedgerStore)(nil)
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"sync"
+
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// subscriberBuffer is how many updates a subscriber may lag behind before it is dropped
+const subscriberBuffer = 256
+
+// Subscription receives updates until it is closed, either by the subscriber or by
+// the hub when the subscriber falls too far behind.
+type Subscription struct {
+	Updates   <-chan models.BalanceUpdate
+	accountId string
+	ch        chan models.BalanceUpdate
+	hub       *Hub
+}
+
+// Close stops the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Hub fans committed ledger entries out to in-process subscribers (SSE, WebSocket, ...)
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers for updates of one account, or of every account when accountId is ""
+func (h *Hub) Subscribe(accountId string) *Subscription {
+	ch := make(chan models.BalanceUpdate, subscriberBuffer)
+	sub := &Subscription{Updates: ch, accountId: accountId, ch: ch, hub: h}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.subscribers[sub]; exists {
+		delete(h.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+// EntriesCommitted implements interfaces.EntryListener. It never blocks:
+// a subscriber whose buffer is full is disconnected so it can reconnect and resync.
+func (h *Hub) EntriesCommitted(updates []models.BalanceUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		for _, update := range updates {
+			if sub.accountId != "" && sub.accountId != update.AccountID {
+				continue
+			}
+			select {
+			case sub.ch <- update:
+			default:
+				delete(h.subscribers, sub)
+				close(sub.ch)
+			}
+			if _, alive := h.subscribers[sub]; !alive {
+				break
+			}
+		}
+	}
+}
+
+// Subscribers returns the number of live subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+var _ interfaces.EntryListener = (*Hub)(nil)
@@ -0,0 +1,205 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ws"
+)
+
+const (
+	pingInterval = 30 * time.Second
+	pongTimeout  = 2 * pingInterval // connection is dropped when nothing arrives for this long
+)
+
+// clientMessage is what dashboard clients send over the socket
+type clientMessage struct {
+	Action     string   `json:"action"` // "subscribe" or "unsubscribe"
+	AccountIDs []string `json:"account_ids"`
+}
+
+// serverMessage is what the server pushes to clients
+type serverMessage struct {
+	Type      string `json:"type"` // "balance_changed", "subscribed", "unsubscribed" or "error"
+	AccountID string `json:"account_id,omitempty"`
+	Balance   string `json:"balance,omitempty"`
+	EntryID   string `json:"entry_id,omitempty"`
+	Amount    string `json:"amount,omitempty"`
+	Sequence  int64  `json:"sequence,omitempty"`
+
+	AccountIDs []string `json:"account_ids,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+var (
+	errTooManySubscriptions = errors.New("subscription limit reached")
+	errFellBehind           = errors.New("dropped for falling behind; subscribe again and re-read the balance")
+)
+
+// BalanceSession pushes balance changes for the accounts a WebSocket client subscribed to
+type BalanceSession struct {
+	conn             *ws.Conn
+	hub              *Hub
+	maxSubscriptions int
+
+	mu   sync.Mutex
+	subs map[string]*Subscription
+	done chan struct{}
+}
+
+func NewBalanceSession(conn *ws.Conn, hub *Hub, maxSubscriptions int) *BalanceSession {
+	return &BalanceSession{
+		conn:             conn,
+		hub:              hub,
+		maxSubscriptions: maxSubscriptions,
+		subs:             make(map[string]*Subscription),
+		done:             make(chan struct{}),
+	}
+}
+
+// Run serves the connection until the client disconnects or stops answering pings
+func (s *BalanceSession) Run() {
+	defer s.close()
+	go s.pingLoop()
+
+	for {
+		s.conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		opcode, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case ws.OpClose:
+			return
+		case ws.OpPong:
+			// Any frame extends the read deadline; nothing else to do
+		case ws.OpText:
+			s.handle(payload)
+		}
+	}
+}
+
+func (s *BalanceSession) handle(payload []byte) {
+	var msg clientMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		s.send(serverMessage{Type: "error", Error: "invalid message"})
+		return
+	}
+
+	switch msg.Action {
+	case "subscribe":
+		subscribed := make([]string, 0, len(msg.AccountIDs))
+		for _, accountId := range msg.AccountIDs {
+			if err := s.subscribe(accountId); err != nil {
+				s.send(serverMessage{Type: "error", AccountID: accountId, Error: err.Error()})
+				continue
+			}
+			subscribed = append(subscribed, accountId)
+		}
+		s.send(serverMessage{Type: "subscribed", AccountIDs: subscribed})
+	case "unsubscribe":
+		for _, accountId := range msg.AccountIDs {
+			s.unsubscribe(accountId)
+		}
+		s.send(serverMessage{Type: "unsubscribed", AccountIDs: msg.AccountIDs})
+	default:
+		s.send(serverMessage{Type: "error", Error: "unknown action " + msg.Action})
+	}
+}
+
+func (s *BalanceSession) subscribe(accountId string) error {
+	if accountId == "" {
+		return errors.New("account id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.subs[accountId]; exists {
+		return nil
+	}
+	if len(s.subs) >= s.maxSubscriptions {
+		return errTooManySubscriptions
+	}
+
+	sub := s.hub.Subscribe(accountId)
+	s.subs[accountId] = sub
+	go s.forward(sub)
+	return nil
+}
+
+func (s *BalanceSession) unsubscribe(accountId string) {
+	s.mu.Lock()
+	sub, exists := s.subs[accountId]
+	delete(s.subs, accountId)
+	s.mu.Unlock()
+
+	if exists {
+		sub.Close()
+	}
+}
+
+// forward relays one account's updates to the socket until the subscription closes. A
+// subscription the hub dropped for falling behind is still in s.subs: it is removed, so it
+// no longer counts toward the limit, and the client is told, so it can subscribe again.
+func (s *BalanceSession) forward(sub *Subscription) {
+	for update := range sub.Updates {
+		s.send(serverMessage{
+			Type:      "balance_changed",
+			AccountID: update.AccountID,
+			Balance:   update.Balance.String(),
+			EntryID:   update.Entry.ID,
+			Amount:    update.Entry.Amount.String(),
+			Sequence:  update.Entry.Sequence,
+		})
+	}
+
+	s.mu.Lock()
+	dropped := s.subs[sub.accountId] == sub
+	if dropped {
+		delete(s.subs, sub.accountId)
+	}
+	s.mu.Unlock()
+	if dropped {
+		s.send(serverMessage{Type: "unsubscribed", AccountIDs: []string{sub.accountId}, Error: errFellBehind.Error()})
+	}
+}
+
+func (s *BalanceSession) send(msg serverMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.conn.WriteMessage(ws.OpText, data)
+}
+
+func (s *BalanceSession) pingLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteMessage(ws.OpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *BalanceSession) close() {
+	close(s.done)
+
+	s.mu.Lock()
+	subs := s.subs
+	s.subs = make(map[string]*Subscription)
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	s.conn.Close(1000, "")
+}
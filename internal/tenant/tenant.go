@@ -0,0 +1,47 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// Header carries the tenant of a request until authentication supplies it
+const Header = "X-Tenant-ID"
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type tenantKey struct{}
+
+// WithTenant scopes ctx to a tenant; an empty ID means the platform itself (no scoping)
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of the request, or "" for platform-level calls and background jobs
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// AnyRequest lets every request run without a tenant. It suits single-tenant deployments
+// only: such a request sees and moves every tenant's money.
+func AnyRequest(*http.Request) bool { return true }
+
+// Middleware resolves the tenant of each request. A request without a tenant would run
+// with platform-wide visibility, so it is rejected unless platform accepts it, e.g.
+// because it carries the admin token.
+func Middleware(next http.Handler, platform func(r *http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if id != "" && !validID.MatchString(id) {
+			http.Error(w, "invalid "+Header, http.StatusBadRequest)
+			return
+		}
+		if id == "" && (platform == nil || !platform(r)) {
+			http.Error(w, Header+" is required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), id)))
+	})
+}
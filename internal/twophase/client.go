@@ -0,0 +1,107 @@
+package twophase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+)
+
+// ErrRefused means the remote instance answered but would not apply the call, e.g. its
+// account cannot be credited. Anything else from a Client may have been applied remotely.
+var ErrRefused = errors.New("remote ledger refused the request")
+
+// Client is a Peer on another ledger instance, reached over its /internal/2pc routes
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) Prepare(ctx context.Context, leg models.TwoPhaseLeg) (models.TwoPhaseLeg, error) {
+	return c.post(ctx, "/internal/2pc/prepare", leg)
+}
+
+func (c *Client) Commit(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	return c.post(ctx, "/internal/2pc/"+url.PathEscape(xid)+"/commit", nil)
+}
+
+func (c *Client) Abort(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	return c.post(ctx, "/internal/2pc/"+url.PathEscape(xid)+"/abort", nil)
+}
+
+func (c *Client) post(ctx context.Context, path string, body any) (models.TwoPhaseLeg, error) {
+	var payload io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, payload)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		err := fmt.Errorf("%s: %d %s", c.baseURL+path, resp.StatusCode, strings.TrimSpace(string(message)))
+		// Server errors and timeouts leave the outcome unknown; client errors were refused
+		if resp.StatusCode < 500 {
+			err = fmt.Errorf("%w: %w", ErrRefused, err)
+		}
+		return models.TwoPhaseLeg{}, err
+	}
+	var leg models.TwoPhaseLeg
+	err = json.NewDecoder(resp.Body).Decode(&leg)
+	return leg, err
+}
+
+// ParsePeers reads "name=url,name=url", naming each ledger instance this one may transfer to
+func ParsePeers(spec, token string) (map[string]Peer, error) {
+	peers := map[string]Peer{}
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, baseURL, ok := strings.Cut(item, "=")
+		name, baseURL = strings.TrimSpace(name), strings.TrimSpace(baseURL)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("peer %q must be written as name=url", item)
+		}
+		if parsed, err := url.Parse(baseURL); err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("peer %q: invalid url %q", name, baseURL)
+		}
+		if _, exists := peers[name]; exists {
+			return nil, fmt.Errorf("peer %q appears twice", name)
+		}
+		peers[name] = NewClient(baseURL, token)
+	}
+	return peers, nil
+}
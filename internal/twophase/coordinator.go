@@ -0,0 +1,217 @@
+package twophase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/metrics"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrInvalidTransfer  = errors.New("invalid cross-instance transfer")
+	ErrUnknownPeer      = errors.New("unknown ledger instance")
+	ErrTransferNotFound = errors.New("cross-instance transfer not found")
+)
+
+var transfers = metrics.NewCounterVec("cross_instance_transfers_total",
+	"Cross-instance transfers by decision", "outcome")
+
+// Coordinator runs transfers from local accounts to accounts on peer instances. The local
+// debit leg prepares first, then the remote credit leg; the transfer commits only if both
+// prepared, and the decision is stored before either leg hears it.
+type Coordinator struct {
+	instance  string
+	local     Peer
+	peers     map[string]Peer
+	store     interfaces.TwoPhaseCoordinatorStore
+	clock     clock.Clock
+	appLogger *slog.Logger
+}
+
+// NewCoordinator stamps transfers and times out undecided ones by clk, normally the ledger's clock
+func NewCoordinator(instance string, local Peer, peers map[string]Peer, store interfaces.TwoPhaseCoordinatorStore, clk clock.Clock, appLogger *slog.Logger) *Coordinator {
+	return &Coordinator{
+		instance:  instance,
+		local:     local,
+		peers:     peers,
+		store:     store,
+		clock:     clk,
+		appLogger: appLogger,
+	}
+}
+
+// Transfer runs a transfer to its decision. A decided transfer is returned even when a leg
+// has not acknowledged it yet; Recover keeps sending the decision until both have.
+// A repeated idempotency key returns the transfer it started.
+func (c *Coordinator) Transfer(ctx context.Context, request models.CrossInstanceTransfer) (models.CrossInstanceTransfer, error) {
+	if request.IdempotencyKey == "" || request.FromAccount == "" || request.ToAccount == "" || !request.Amount.IsPositive() {
+		return models.CrossInstanceTransfer{}, fmt.Errorf("%w: idempotency_key, from_account, to_account and a positive amount are required", ErrInvalidTransfer)
+	}
+	peer, ok := c.peers[request.ToInstance]
+	if !ok || request.ToInstance == c.instance {
+		return models.CrossInstanceTransfer{}, fmt.Errorf("%w: %q", ErrUnknownPeer, request.ToInstance)
+	}
+	if existing, err := c.store.GetCrossInstanceTransferByKey(ctx, request.IdempotencyKey); err != nil || existing != nil {
+		return deref(existing), err
+	}
+
+	transfer := models.CrossInstanceTransfer{
+		ID:             uuid.New().String(),
+		TenantID:       tenant.FromContext(ctx),
+		IdempotencyKey: request.IdempotencyKey,
+		FromAccount:    request.FromAccount,
+		ToInstance:     request.ToInstance,
+		ToAccount:      request.ToAccount,
+		Amount:         request.Amount,
+		Status:         models.TwoPhasePreparing,
+		CreatedAt:      c.clock.Now().UTC(),
+	}
+	saved, err := c.store.SaveCrossInstanceTransfer(ctx, transfer)
+	if err != nil {
+		return models.CrossInstanceTransfer{}, err
+	}
+	if !saved {
+		existing, err := c.store.GetCrossInstanceTransferByKey(ctx, request.IdempotencyKey)
+		return deref(existing), err
+	}
+
+	status, reason := models.TwoPhaseCommitted, ""
+	if err := prepare(ctx, c.local, c.leg(transfer, models.LegDebit)); err != nil {
+		status, reason = models.TwoPhaseAborted, "debit leg: "+err.Error()
+	} else if err := prepare(ctx, peer, c.leg(transfer, models.LegCredit)); err != nil {
+		status, reason = models.TwoPhaseAborted, "credit leg: "+err.Error()
+	}
+	if transfer, err = c.decide(ctx, transfer, status, reason); err != nil {
+		return transfer, err
+	}
+	c.finish(ctx, &transfer)
+	return transfer, nil
+}
+
+// Get returns a transfer this instance coordinates
+func (c *Coordinator) Get(ctx context.Context, id string) (models.CrossInstanceTransfer, error) {
+	transfer, err := c.store.GetCrossInstanceTransfer(ctx, id)
+	if err != nil {
+		return models.CrossInstanceTransfer{}, err
+	}
+	if transfer == nil {
+		return models.CrossInstanceTransfer{}, fmt.Errorf("%w: %s", ErrTransferNotFound, id)
+	}
+	return *transfer, nil
+}
+
+// Recover finishes transfers older than timeout: one still preparing is aborted, since its
+// coordinator crashed or gave up before deciding, and every decision is sent again to the
+// legs that may have missed it. It returns the number of transfers completed.
+func (c *Coordinator) Recover(ctx context.Context, timeout time.Duration) (int, error) {
+	unfinished, err := c.store.ListUnfinishedCrossInstanceTransfers(ctx, c.clock.Now().UTC().Add(-timeout))
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, transfer := range unfinished {
+		if transfer.Status == models.TwoPhasePreparing {
+			if transfer, err = c.decide(ctx, transfer, models.TwoPhaseAborted, "not decided within "+timeout.String()); err != nil {
+				return completed, err
+			}
+		}
+		if c.finish(ctx, &transfer) {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+func (c *Coordinator) leg(transfer models.CrossInstanceTransfer, role string) models.TwoPhaseLeg {
+	leg := models.TwoPhaseLeg{XID: transfer.ID, Role: role, Amount: transfer.Amount, Coordinator: c.instance}
+	leg.AccountID = transfer.FromAccount
+	if role == models.LegCredit {
+		leg.AccountID = transfer.ToAccount
+	}
+	return leg
+}
+
+// prepare treats a leg that answers but is not prepared - aborted by an earlier recovery - as a refusal
+func prepare(ctx context.Context, peer Peer, leg models.TwoPhaseLeg) error {
+	prepared, err := peer.Prepare(ctx, leg)
+	if err != nil {
+		return err
+	}
+	if prepared.Status != models.TwoPhasePrepared {
+		return fmt.Errorf("%w: leg is %s", ErrLegConflict, prepared.Status)
+	}
+	return nil
+}
+
+// decide stores the decision; when another run decided first, its decision stands
+func (c *Coordinator) decide(ctx context.Context, transfer models.CrossInstanceTransfer, status, reason string) (models.CrossInstanceTransfer, error) {
+	now := c.clock.Now().UTC()
+	decided, err := c.store.DecideCrossInstanceTransfer(ctx, transfer.ID, status, reason, now)
+	if err != nil {
+		return transfer, err
+	}
+	if !decided {
+		return c.Get(ctx, transfer.ID)
+	}
+	transfers.With(status).Inc()
+	transfer.Status = status
+	transfer.Reason = reason
+	transfer.DecidedAt = &now
+	return transfer, nil
+}
+
+// finish sends the decision to both legs and reports whether both applied it
+func (c *Coordinator) finish(ctx context.Context, transfer *models.CrossInstanceTransfer) bool {
+	apply := func(peer Peer) error {
+		if transfer.Status == models.TwoPhaseCommitted {
+			_, err := peer.Commit(ctx, transfer.ID)
+			return err
+		}
+		_, err := peer.Abort(ctx, transfer.ID)
+		return err
+	}
+
+	peer, ok := c.peers[transfer.ToInstance]
+	if !ok {
+		c.appLogger.Error("ALERT: cross-instance transfer to an instance no longer configured",
+			"transfer_id", transfer.ID, "to_instance", transfer.ToInstance, "status", transfer.Status)
+		return false
+	}
+	localErr := apply(c.local)
+	remoteErr := apply(peer)
+	if err := errors.Join(localErr, remoteErr); err != nil {
+		if errors.Is(err, ErrLegConflict) || errors.Is(err, ErrRefused) {
+			// A leg went the other way or will not listen: only an operator can square the two ledgers
+			c.appLogger.Error("ALERT: cross-instance transfer legs disagree",
+				"transfer_id", transfer.ID, "status", transfer.Status, "error", err)
+			return false
+		}
+		c.appLogger.Warn("cross-instance transfer decision not applied yet",
+			"transfer_id", transfer.ID, "status", transfer.Status, "error", err)
+		return false
+	}
+
+	now := c.clock.Now().UTC()
+	if err := c.store.CompleteCrossInstanceTransfer(ctx, transfer.ID, now); err != nil {
+		c.appLogger.Warn("failed to mark cross-instance transfer complete", "transfer_id", transfer.ID, "error", err)
+		return false
+	}
+	transfer.CompletedAt = &now
+	return true
+}
+
+func deref(transfer *models.CrossInstanceTransfer) models.CrossInstanceTransfer {
+	if transfer == nil {
+		return models.CrossInstanceTransfer{}
+	}
+	return *transfer
+}
@@ -0,0 +1,240 @@
+// Package twophase moves money between accounts kept by separate ledger deployments, e.g.
+// one per region, with a two-phase commit: the transfer lands on both instances or on neither.
+//
+// Each instance is a Participant for its own leg; the instance holding the sending account
+// also runs the Coordinator. Both sides go through the settlement account, which stands for
+// what the instances owe each other.
+package twophase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/ledger"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrInvalidLeg  = errors.New("invalid two-phase leg")
+	ErrUnknownLeg  = errors.New("two-phase leg not found")
+	ErrLegConflict = errors.New("two-phase leg already decided the other way")
+)
+
+// Peer is one side of a transfer as the coordinator sees it: the local Participant, or a
+// Client for a remote instance. Every call must be safe to repeat.
+type Peer interface {
+	Prepare(ctx context.Context, leg models.TwoPhaseLeg) (models.TwoPhaseLeg, error)
+	Commit(ctx context.Context, xid string) (models.TwoPhaseLeg, error)
+	Abort(ctx context.Context, xid string) (models.TwoPhaseLeg, error)
+}
+
+// Participant applies this instance's legs. Postings are keyed by XID and phase, so a
+// call repeated after a crash or a lost response posts nothing twice.
+type Participant struct {
+	ledger *ledger.Ledger
+	store  interfaces.TwoPhaseParticipantStore
+}
+
+func NewParticipant(ledgerService *ledger.Ledger, store interfaces.TwoPhaseParticipantStore) *Participant {
+	return &Participant{
+		ledger: ledgerService,
+		store:  store,
+	}
+}
+
+// Prepare reserves a debit leg by moving the amount to the settlement account, or checks
+// that the account of a credit leg can take it. A repeated prepare returns the leg as it is;
+// one that loses the race to an abort releases whatever it reserved.
+func (p *Participant) Prepare(ctx context.Context, leg models.TwoPhaseLeg) (models.TwoPhaseLeg, error) {
+	if leg.XID == "" || leg.AccountID == "" || !leg.Amount.IsPositive() || (leg.Role != models.LegDebit && leg.Role != models.LegCredit) {
+		return models.TwoPhaseLeg{}, fmt.Errorf("%w: xid, account_id, a positive amount and a debit or credit role are required", ErrInvalidLeg)
+	}
+	existing, err := p.store.GetTwoPhaseLeg(ctx, leg.XID)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	if existing != nil {
+		return *existing, nil
+	}
+
+	leg.Status = models.TwoPhasePrepared
+	leg.PreparedAt = p.ledger.Now().UTC()
+	leg.DecidedAt = nil
+	leg.TransactionID = ""
+	switch leg.Role {
+	case models.LegDebit:
+		tx, err := p.post(ctx, leg, "prepare", leg.AccountID, p.settlementAccount(), false)
+		if err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		leg.TransactionID = tx
+	case models.LegCredit:
+		if err := p.ledger.CheckAccountAccess(ctx, leg.AccountID); err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		if err := p.ledger.CreditRejected(ctx, leg.AccountID); err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+	}
+
+	saved, err := p.store.SaveTwoPhaseLeg(ctx, leg)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	if !saved {
+		// A concurrent prepare or an early abort got there first
+		current, err := p.get(ctx, leg.XID)
+		if err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		if current.Status == models.TwoPhaseAborted && leg.TransactionID != "" {
+			// The abort found nothing to release, so the reservation just posted is
+			// released here; keyed like the abort's own, it posts once whoever runs it
+			if _, err := p.post(ctx, leg, "abort", p.settlementAccount(), leg.AccountID, true); err != nil {
+				return models.TwoPhaseLeg{}, err
+			}
+		}
+		return current, nil
+	}
+	return leg, nil
+}
+
+// Commit credits the account of a credit leg; a debit leg was already moved when prepared
+func (p *Participant) Commit(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	leg, err := p.get(ctx, xid)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	switch leg.Status {
+	case models.TwoPhaseCommitted:
+		return leg, nil
+	case models.TwoPhaseAborted:
+		return models.TwoPhaseLeg{}, fmt.Errorf("%w: %s was aborted", ErrLegConflict, xid)
+	}
+
+	transactionId := ""
+	if leg.Role == models.LegCredit {
+		// Agreed at prepare; the money is already on its way, so the usual checks do not apply
+		if transactionId, err = p.post(ctx, leg, "commit", p.settlementAccount(), leg.AccountID, true); err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+	}
+	return p.decide(ctx, leg, models.TwoPhaseCommitted, transactionId)
+}
+
+// Abort releases the reservation of a debit leg. Aborting an XID never prepared here
+// leaves a marker, so a prepare arriving late is refused instead of reserving money.
+func (p *Participant) Abort(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	existing, err := p.store.GetTwoPhaseLeg(ctx, xid)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	if existing == nil {
+		now := p.ledger.Now().UTC()
+		marker := models.TwoPhaseLeg{XID: xid, Status: models.TwoPhaseAborted, PreparedAt: now, DecidedAt: &now}
+		saved, err := p.store.SaveTwoPhaseLeg(ctx, marker)
+		if err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		if saved {
+			return marker, nil
+		}
+		// Prepared in the meantime
+		return p.Abort(ctx, xid)
+	}
+
+	leg := *existing
+	switch leg.Status {
+	case models.TwoPhaseAborted:
+		return leg, nil
+	case models.TwoPhaseCommitted:
+		return models.TwoPhaseLeg{}, fmt.Errorf("%w: %s was committed", ErrLegConflict, xid)
+	}
+
+	transactionId := ""
+	if leg.Role == models.LegDebit {
+		if transactionId, err = p.post(ctx, leg, "abort", p.settlementAccount(), leg.AccountID, true); err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+	}
+	return p.decide(ctx, leg, models.TwoPhaseAborted, transactionId)
+}
+
+// Get returns a leg as this instance last recorded it
+func (p *Participant) Get(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	return p.get(ctx, xid)
+}
+
+func (p *Participant) get(ctx context.Context, xid string) (models.TwoPhaseLeg, error) {
+	leg, err := p.store.GetTwoPhaseLeg(ctx, xid)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	if leg == nil {
+		return models.TwoPhaseLeg{}, fmt.Errorf("%w: %s", ErrUnknownLeg, xid)
+	}
+	return *leg, nil
+}
+
+func (p *Participant) decide(ctx context.Context, leg models.TwoPhaseLeg, status, transactionId string) (models.TwoPhaseLeg, error) {
+	now := p.ledger.Now().UTC()
+	decided, err := p.store.DecideTwoPhaseLeg(ctx, leg.XID, status, transactionId, now)
+	if err != nil {
+		return models.TwoPhaseLeg{}, err
+	}
+	if !decided {
+		// A concurrent call decided first; report what it decided
+		current, err := p.get(ctx, leg.XID)
+		if err != nil {
+			return models.TwoPhaseLeg{}, err
+		}
+		if current.Status != status {
+			return models.TwoPhaseLeg{}, fmt.Errorf("%w: %s was %s", ErrLegConflict, leg.XID, current.Status)
+		}
+		return current, nil
+	}
+	leg.Status = status
+	if transactionId != "" {
+		leg.TransactionID = transactionId
+	}
+	leg.DecidedAt = &now
+	return leg, nil
+}
+
+// post moves the leg's amount for one phase. The ID and key are derived from the XID and
+// phase, so a repeated phase is a no-op that still reports the original posting.
+func (p *Participant) post(ctx context.Context, leg models.TwoPhaseLeg, phase, from, to string, internal bool) (string, error) {
+	key := "2pc-" + leg.XID + "-" + phase
+	tx := models.Transaction{
+		ID:             uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String(),
+		IdempotencyKey: key,
+		FromAccount:    from,
+		ToAccount:      to,
+		Amount:         leg.Amount,
+		CreatedAt:      p.ledger.Now(),
+		Internal:       internal,
+		Reference:      "2pc-" + leg.XID,
+		Description:    fmt.Sprintf("Cross-instance transfer %s: %s %s", leg.XID, leg.Role, phase),
+		Metadata: map[string]string{
+			"type":        "cross_instance_" + phase,
+			"xid":         leg.XID,
+			"coordinator": leg.Coordinator,
+		},
+	}
+	if internal {
+		// Carries out a decision already taken, for whichever tenant asked for the transfer
+		ctx = tenant.WithTenant(ctx, "")
+	}
+	if _, err := p.ledger.PostTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+	return tx.ID, nil
+}
+
+func (p *Participant) settlementAccount() string {
+	return p.ledger.SystemAccount(models.SystemAccountSettlement)
+}
@@ -0,0 +1,364 @@
+// Package usage meters API calls and posted transactions per tenant and API key, for
+// teams that charge the ledger's cost back to the teams using it, and enforces optional
+// monthly quotas per tenant.
+//
+// Counts are kept in memory and added to the store on every flush, so metering costs a
+// request no database round trip. Quotas are checked against the month's stored totals,
+// as of the last flush of any replica, plus what this replica counted since.
+package usage
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/clock"
+	interfaces "github.com/sheikh-saqib/distributed-payments-ledger-system/internal/interfaces"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/livemode"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/tenant"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("usage quota exceeded")
+	ErrInvalidQuota  = errors.New("invalid usage quota")
+)
+
+const dateLayout = "2006-01-02"
+
+// unmetered paths are probes and scrapes, not API use
+var unmetered = map[string]bool{
+	"/health":  true,
+	"/metrics": true,
+}
+
+// Limits caps a tenant's usage per calendar month (UTC); zero means unlimited
+type Limits struct {
+	Calls        int64
+	Transactions int64
+}
+
+// Quotas are the limits by tenant ID; "*" applies to every tenant without its own.
+// Platform-level calls, made without a tenant, are never limited.
+type Quotas map[string]Limits
+
+// ParseQuotas reads "acme=calls:100000,transactions:5000;*=calls:1000000"
+func ParseQuotas(spec string) (Quotas, error) {
+	quotas := Quotas{}
+	for item := range strings.SplitSeq(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		tenantId, limits, ok := strings.Cut(item, "=")
+		tenantId = strings.TrimSpace(tenantId)
+		if !ok || tenantId == "" {
+			return nil, fmt.Errorf("%w: %q must be tenant=kind:limit,...", ErrInvalidQuota, item)
+		}
+		var quota Limits
+		for limit := range strings.SplitSeq(limits, ",") {
+			kind, value, _ := strings.Cut(strings.TrimSpace(limit), ":")
+			n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%w: %q in %q must be a positive count", ErrInvalidQuota, limit, item)
+			}
+			switch strings.TrimSpace(kind) {
+			case "calls":
+				quota.Calls = n
+			case "transactions":
+				quota.Transactions = n
+			default:
+				return nil, fmt.Errorf("%w: unknown kind %q in %q, want calls or transactions", ErrInvalidQuota, kind, item)
+			}
+		}
+		quotas[tenantId] = quota
+	}
+	return quotas, nil
+}
+
+// KeyID fingerprints an API key: its mode prefix and a hash, enough to tell keys apart in
+// a usage report without storing the key
+func KeyID(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	prefix := ""
+	for _, p := range []string{livemode.LiveKeyPrefix, livemode.TestKeyPrefix} {
+		if strings.HasPrefix(key, p) {
+			prefix = p
+		}
+	}
+	return prefix + hex.EncodeToString(sum[:6])
+}
+
+type counter struct {
+	tenantId string
+	keyId    string
+	date     string
+}
+
+// Meter counts usage and enforces the quotas
+type Meter struct {
+	store     interfaces.UsageStore
+	clock     clock.Clock
+	quotas    Quotas
+	appLogger *slog.Logger
+
+	mu      sync.Mutex
+	pending map[counter]*models.UsageRecord // counted since the last flush
+	month   string                          // YYYY-MM the totals below cover
+	used    map[string]*Limits              // month to date by tenant: stored plus counted since
+}
+
+func NewMeter(store interfaces.UsageStore, clk clock.Clock, quotas Quotas, appLogger *slog.Logger) *Meter {
+	return &Meter{
+		store:     store,
+		clock:     clk,
+		quotas:    quotas,
+		appLogger: appLogger,
+		pending:   map[counter]*models.UsageRecord{},
+		used:      map[string]*Limits{},
+	}
+}
+
+func (m *Meter) limits(tenantId string) (Limits, bool) {
+	if tenantId == "" {
+		return Limits{}, false
+	}
+	if quota, ok := m.quotas[tenantId]; ok {
+		return quota, true
+	}
+	quota, ok := m.quotas["*"]
+	return quota, ok
+}
+
+// count adds to the pending counts and the month's totals; m.mu must be held
+func (m *Meter) count(c counter, calls, transactions int64) {
+	record, ok := m.pending[c]
+	if !ok {
+		record = &models.UsageRecord{TenantID: c.tenantId, KeyID: c.keyId, Date: c.date}
+		m.pending[c] = record
+	}
+	record.APICalls += calls
+	record.Transactions += transactions
+
+	if month := c.date[:7]; month != m.month {
+		// A new month starts from zero until the next refresh says otherwise
+		m.month = month
+		m.used = map[string]*Limits{}
+	}
+	used, ok := m.used[c.tenantId]
+	if !ok {
+		used = &Limits{}
+		m.used[c.tenantId] = used
+	}
+	used.Calls += calls
+	used.Transactions += transactions
+}
+
+// exceeded reports the quota the tenant has used up, if any; m.mu must be held
+func (m *Meter) exceeded(tenantId, month string, transaction bool) (string, int64) {
+	quota, ok := m.limits(tenantId)
+	used := m.used[tenantId]
+	if !ok || used == nil || month != m.month {
+		return "", 0
+	}
+	switch {
+	case transaction && quota.Transactions > 0 && used.Transactions >= quota.Transactions:
+		return "transactions", quota.Transactions
+	case !transaction && quota.Calls > 0 && used.Calls >= quota.Calls:
+		return "calls", quota.Calls
+	}
+	return "", 0
+}
+
+type meterKey struct{}
+
+type metered struct {
+	meter  *Meter
+	tenant string
+	keyId  string
+}
+
+// Middleware counts every request as an API call, refusing it with 429 once its tenant
+// has used up its monthly calls. It must run inside livemode.Middleware, so test-mode
+// calls are metered under the test tenant.
+func (m *Meter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unmetered[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		request := metered{
+			meter:  m,
+			tenant: tenant.FromContext(r.Context()),
+			keyId:  KeyID(r.Header.Get(livemode.KeyHeader)),
+		}
+		now := m.clock.Now().UTC()
+
+		m.mu.Lock()
+		kind, limit := m.exceeded(request.tenant, now.Format("2006-01"), false)
+		if kind == "" {
+			m.count(counter{request.tenant, request.keyId, now.Format(dateLayout)}, 1, 0)
+		}
+		m.mu.Unlock()
+
+		if kind != "" {
+			writeExceeded(w, now, kind, limit)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), meterKey{}, request)))
+	})
+}
+
+// writeExceeded answers 429 with Retry-After set to the start of the next month
+func writeExceeded(w http.ResponseWriter, now time.Time, kind string, limit int64) {
+	next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	w.Header().Set("Retry-After", strconv.Itoa(int(next.Sub(now).Seconds())+1))
+	http.Error(w, fmt.Sprintf("%v: %d %s a month", ErrQuotaExceeded, limit, kind), http.StatusTooManyRequests)
+}
+
+// AllowTransaction returns ErrQuotaExceeded once the tenant of a metered request has
+// posted its monthly transactions. Work outside a request, such as a standing order, is
+// neither limited nor counted.
+func AllowTransaction(ctx context.Context) error {
+	request, ok := ctx.Value(meterKey{}).(metered)
+	if !ok {
+		return nil
+	}
+	m := request.meter
+	month := m.clock.Now().UTC().Format("2006-01")
+	m.mu.Lock()
+	kind, limit := m.exceeded(request.tenant, month, true)
+	m.mu.Unlock()
+	if kind != "" {
+		return fmt.Errorf("%w: %d %s a month", ErrQuotaExceeded, limit, kind)
+	}
+	return nil
+}
+
+// TransactionPosted counts a transaction posted by a metered request
+func TransactionPosted(ctx context.Context) {
+	request, ok := ctx.Value(meterKey{}).(metered)
+	if !ok {
+		return
+	}
+	m := request.meter
+	date := m.clock.Now().UTC().Format(dateLayout)
+	m.mu.Lock()
+	m.count(counter{request.tenant, request.keyId, date}, 0, 1)
+	m.mu.Unlock()
+}
+
+// Flush adds the pending counts to the store, then reloads the month's totals so the
+// quotas see what the other replicas counted. Counts that fail to store are kept for the
+// next flush.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	records := make([]models.UsageRecord, 0, len(m.pending))
+	for _, record := range m.pending {
+		records = append(records, *record)
+	}
+	m.pending = map[counter]*models.UsageRecord{}
+	m.mu.Unlock()
+
+	// A fixed order keeps replicas flushing at once from deadlocking on the same rows
+	slices.SortFunc(records, func(a, b models.UsageRecord) int {
+		return cmp.Or(cmp.Compare(a.TenantID, b.TenantID), cmp.Compare(a.KeyID, b.KeyID), cmp.Compare(a.Date, b.Date))
+	})
+	if err := m.store.AddUsage(ctx, records); err != nil {
+		m.mu.Lock()
+		for _, record := range records {
+			c := counter{record.TenantID, record.KeyID, record.Date}
+			pending, ok := m.pending[c]
+			if !ok {
+				m.pending[c] = &record
+				continue
+			}
+			pending.APICalls += record.APICalls
+			pending.Transactions += record.Transactions
+		}
+		m.mu.Unlock()
+		return err
+	}
+	if len(m.quotas) == 0 {
+		return nil
+	}
+	return m.refresh(ctx)
+}
+
+// refresh replaces the month's totals with the stored ones plus what is still pending
+func (m *Meter) refresh(ctx context.Context) error {
+	now := m.clock.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	stored, err := m.store.ListUsage(tenant.WithTenant(ctx, ""), models.UsageFilter{From: start})
+	if err != nil {
+		return err
+	}
+
+	used := map[string]*Limits{}
+	add := func(record models.UsageRecord) {
+		if !strings.HasPrefix(record.Date, start.Format("2006-01")) {
+			return
+		}
+		total, ok := used[record.TenantID]
+		if !ok {
+			total = &Limits{}
+			used[record.TenantID] = total
+		}
+		total.Calls += record.APICalls
+		total.Transactions += record.Transactions
+	}
+	for _, record := range stored {
+		add(record)
+	}
+	m.mu.Lock()
+	for _, record := range m.pending {
+		add(*record)
+	}
+	m.month = start.Format("2006-01")
+	m.used = used
+	m.mu.Unlock()
+	return nil
+}
+
+// Run flushes on every tick until ctx is done
+func (m *Meter) Run(ctx context.Context, interval time.Duration) {
+	if len(m.quotas) > 0 {
+		if err := m.refresh(ctx); err != nil {
+			m.appLogger.Error("failed to load usage for quotas", "error", err)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil {
+				m.appLogger.Error("failed to flush usage", "error", err)
+			}
+		}
+	}
+}
+
+// Usage flushes what this replica counted and lists the stored usage, scoped to the
+// tenant of ctx
+func (m *Meter) Usage(ctx context.Context, filter models.UsageFilter) ([]models.UsageRecord, error) {
+	if err := m.Flush(ctx); err != nil {
+		return nil, err
+	}
+	return m.store.ListUsage(ctx, filter)
+}
@@ -0,0 +1,144 @@
+// Package validation checks requests for values that can never be right, before any
+// lock is taken or store is read, and reports every offending field at once.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sheikh-saqib/distributed-payments-ledger-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalid = errors.New("invalid request")
+
+// DefaultAccountIDPattern allows IDs such as UUIDs, "merchant-42" or "fx-position-USD";
+// a slash is left out because account IDs are path segments in the API
+const DefaultAccountIDPattern = `^[A-Za-z0-9][A-Za-z0-9._:@-]{0,127}$`
+
+// FieldError names one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors holds every invalid field of a request; it matches ErrInvalid
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalid, strings.Join(messages, "; "))
+}
+
+func (e Errors) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Rules are the limits every request is held to
+type Rules struct {
+	MaxAmount        decimal.Decimal // zero means no maximum
+	AccountIDPattern *regexp.Regexp
+}
+
+// RulesFromEnv reads MAX_TRANSACTION_AMOUNT and ACCOUNT_ID_PATTERN. On a bad value it
+// returns the defaults for that setting along with the error.
+func RulesFromEnv() (Rules, error) {
+	rules := Rules{AccountIDPattern: regexp.MustCompile(DefaultAccountIDPattern)}
+	var errs []error
+
+	if value := os.Getenv("MAX_TRANSACTION_AMOUNT"); value != "" {
+		max, err := decimal.NewFromString(value)
+		if err != nil || max.IsNegative() {
+			errs = append(errs, fmt.Errorf("invalid MAX_TRANSACTION_AMOUNT %q", value))
+		} else {
+			rules.MaxAmount = max
+		}
+	}
+	if pattern := os.Getenv("ACCOUNT_ID_PATTERN"); pattern != "" {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid ACCOUNT_ID_PATTERN: %w", err))
+		} else {
+			rules.AccountIDPattern = compiled
+		}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// Account checks the ID and holder details of a new account
+func (r Rules) Account(account models.Account) error {
+	var errs Errors
+	r.accountID(&errs, "id", account.ID)
+	if len(account.HolderName) > 200 {
+		errs = append(errs, FieldError{"holder_name", "must be at most 200 characters"})
+	}
+	if account.HolderEmail != "" {
+		if address, err := mail.ParseAddress(account.HolderEmail); err != nil || address.Address != account.HolderEmail {
+			errs = append(errs, FieldError{"holder_email", "is not a valid email address"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// AccountIDs checks a list of account IDs, naming each bad one by its position in field
+func (r Rules) AccountIDs(field string, ids []string) error {
+	var errs Errors
+	if len(ids) == 0 {
+		errs = append(errs, FieldError{field, "is required"})
+	}
+	for i, id := range ids {
+		r.accountID(&errs, fmt.Sprintf("%s[%d]", field, i), id)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (r Rules) accountID(errs *Errors, field, id string) {
+	switch {
+	case id == "":
+		*errs = append(*errs, FieldError{field, "is required"})
+	case r.AccountIDPattern != nil && !r.AccountIDPattern.MatchString(id):
+		*errs = append(*errs, FieldError{field, "is not a valid account ID"})
+	}
+}
+
+// Transaction checks the parties and amount of a transfer. Postings made by the ledger
+// itself are not held to the maximum amount.
+func (r Rules) Transaction(tx models.Transaction) error {
+	var errs Errors
+	r.accountID(&errs, "from_account", tx.FromAccount)
+	r.accountID(&errs, "to_account", tx.ToAccount)
+	if tx.FromAccount != "" && tx.FromAccount == tx.ToAccount {
+		errs = append(errs, FieldError{"to_account", "must differ from from_account"})
+	}
+
+	switch {
+	case !tx.Amount.IsPositive():
+		errs = append(errs, FieldError{"amount", "must be positive"})
+	case !tx.Internal && r.MaxAmount.IsPositive() && tx.Amount.GreaterThan(r.MaxAmount):
+		errs = append(errs, FieldError{"amount", "must not exceed " + r.MaxAmount.String()})
+	}
+	if tx.ValueDate != "" {
+		if _, err := time.Parse("2006-01-02", tx.ValueDate); err != nil {
+			errs = append(errs, FieldError{"value_date", "must be a date written as YYYY-MM-DD"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package ws is a minimal RFC 6455 WebSocket server implementation covering what the
+// ledger's live endpoints need: the upgrade handshake, text messages, ping/pong and close.
+// Fragmented messages and extensions (compression) are not supported.
+package ws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OpText  = 0x1
+	OpClose = 0x8
+	OpPing  = 0x9
+	OpPong  = 0xA
+
+	// MaxMessageSize bounds client messages; subscription requests are tiny
+	MaxMessageSize = 64 << 10
+
+	handshakeGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+var (
+	ErrNotWebSocket       = errors.New("not a websocket upgrade request")
+	ErrMessageTooLarge    = errors.New("websocket message too large")
+	ErrFragmentedMessage  = errors.New("fragmented websocket messages are not supported")
+	ErrUnmaskedClientData = errors.New("client frames must be masked")
+)
+
+// Conn is a server-side WebSocket connection. Writes are safe for concurrent use;
+// reads must happen from a single goroutine.
+type Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// Upgrade performs the opening handshake and takes over the underlying connection
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, ErrNotWebSocket.Error(), http.StatusBadRequest)
+		return nil, ErrNotWebSocket
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, ErrNotWebSocket
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + handshakeGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// Hijacking clears any deadline the server set on the request
+	conn.SetDeadline(time.Time{})
+	return &Conn{conn: conn, reader: rw.Reader}, nil
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage returns the next data or control frame. Pings are answered automatically.
+func (c *Conn) ReadMessage() (opcode byte, payload []byte, err error) {
+	for {
+		opcode, payload, err = c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		if opcode == OpPing {
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+		return opcode, payload, nil
+	}
+}
+
+func (c *Conn) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	if !fin || opcode == 0x0 {
+		return 0, nil, ErrFragmentedMessage
+	}
+	if !masked {
+		return 0, nil, ErrUnmaskedClientData
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// WriteMessage sends a single unfragmented, unmasked frame
+func (c *Conn) WriteMessage(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|opcode)
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// SetReadDeadline bounds how long the next ReadMessage may wait
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// Close sends a close frame with the given status code and closes the connection
+func (c *Conn) Close(code uint16, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	payload = append(payload, reason...)
+	c.WriteMessage(OpClose, payload)
+	return c.conn.Close()
+}
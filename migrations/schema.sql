@@ -1,20 +1,631 @@
+-- Partitioned by month of created_at; the maintenance job creates partitions ahead of time
+-- and the default partition catches anything outside them (e.g. backdated entries).
+-- Unique keys of a partitioned table must include created_at.
 CREATE TABLE ledger_entries (
-    id TEXT PRIMARY KEY,           -- Unique ledger entry ID
+    id TEXT NOT NULL,              -- Unique ledger entry ID
+    seq BIGSERIAL NOT NULL,        -- Global insertion order, used by balance snapshots
+    transaction_id TEXT NOT NULL,  -- Transaction that produced this entry
     account_id TEXT NOT NULL,      -- Which account this entry belongs to
     amount NUMERIC(20,8) NOT NULL,-- Amount (decimal, positive or negative)
-    created_at TIMESTAMP NOT NULL  -- Timestamp of the entry
-);
+    created_at TIMESTAMP NOT NULL, -- Timestamp of the entry
+    prev_hash TEXT NOT NULL,       -- Hash of the previous entry of the same account ('' for the first)
+    hash TEXT NOT NULL,            -- SHA-256 over prev_hash and this entry's contents
+    tenant_id TEXT NOT NULL DEFAULT '', -- Owner of the account; '' on single-tenant deployments
+    PRIMARY KEY (id, created_at),
+    UNIQUE (seq, created_at)
+) PARTITION BY RANGE (created_at);
+
+CREATE TABLE ledger_entries_default PARTITION OF ledger_entries DEFAULT;
 
 -- Index to make balance queries fast
 CREATE INDEX idx_ledger_entries_account_id
 ON ledger_entries(account_id);
 
+CREATE INDEX idx_ledger_entries_tenant_seq ON ledger_entries(tenant_id, seq);
+
+-- Index to find the legs of a transaction
+CREATE INDEX idx_ledger_entries_transaction_id
+ON ledger_entries(transaction_id);
+
+-- Index for rolling-window velocity checks
+CREATE INDEX idx_ledger_entries_account_created_at
+ON ledger_entries(account_id, created_at);
+
+-- Index to scan only the entries after an account's latest snapshot
+CREATE INDEX idx_ledger_entries_account_seq
+ON ledger_entries(account_id, seq);
+
+-- One entry per link of an account's hash chain, so two instances can never fork it.
+-- Kept apart from ledger_entries, whose unique keys must include created_at.
+CREATE TABLE ledger_chain_links (
+    account_id TEXT NOT NULL,
+    prev_hash TEXT NOT NULL,       -- The chain head the entry was linked onto
+    entry_id TEXT NOT NULL,
+    UNIQUE (account_id, prev_hash)
+);
+
 
 CREATE TABLE transactions (
     id TEXT PRIMARY KEY,               -- Logical transaction ID
-    idempotency_key TEXT NOT NULL UNIQUE, -- Prevent duplicate processing
+    tenant_id TEXT NOT NULL DEFAULT '', -- Tenant that posted it
+    idempotency_key TEXT NOT NULL,     -- Prevent duplicate processing; unique per tenant
     from_account TEXT NOT NULL,        -- Sender
     to_account TEXT NOT NULL,          -- Receiver
     amount NUMERIC(20,8) NOT NULL,    -- Transaction amount
-    created_at TIMESTAMP NOT NULL      -- Timestamp of the transaction
+    created_at TIMESTAMP NOT NULL,     -- Timestamp of the transaction
+    adjustment BOOLEAN NOT NULL DEFAULT FALSE, -- Backdated into the current period because its own period was closed
+    original_created_at TIMESTAMP,     -- Requested date of an adjustment
+    reference TEXT NOT NULL DEFAULT '',   -- Caller's order / invoice reference
+    description TEXT NOT NULL DEFAULT '', -- Free text shown on statements
+    metadata JSONB NOT NULL DEFAULT '{}', -- Caller-defined key/value pairs
+    tags JSONB NOT NULL DEFAULT '[]',  -- Labels such as payroll-2024-06; the only column changed after posting
+    fees JSONB NOT NULL DEFAULT '[]',  -- Fee legs charged on top of the amount
+    fx JSONB,                          -- Currencies, rates and gain/loss of a cross-currency transfer
+    payment_request_id TEXT NOT NULL DEFAULT '', -- Payment request this transaction fulfilled
+    value_date DATE,                   -- Business day the transfer takes effect; NULL on older rows
+    -- A key one tenant used neither settles nor reveals another tenant's posting
+    UNIQUE (tenant_id, idempotency_key)
+);
+
+CREATE INDEX idx_transactions_reference ON transactions(reference);
+-- Platform-level postings look their key up across every tenant
+CREATE INDEX idx_transactions_idempotency_key ON transactions(idempotency_key);
+CREATE INDEX idx_transactions_tenant_created_at ON transactions(tenant_id, created_at);
+CREATE INDEX idx_transactions_counterparty ON transactions(from_account, to_account);
+-- With the counterparty index, serves searches by account on either side
+CREATE INDEX idx_transactions_to_account ON transactions(to_account, created_at);
+-- Keyset pagination of searches, newest first
+CREATE INDEX idx_transactions_created_at ON transactions(created_at, id);
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_transactions_tags ON transactions USING GIN (tags jsonb_path_ops);
+-- A payment request can never be paid twice, whatever happens above the database
+CREATE UNIQUE INDEX idx_transactions_payment_request ON transactions(payment_request_id) WHERE payment_request_id <> '';
+
+
+CREATE TABLE balance_snapshots (
+    account_id TEXT NOT NULL,          -- Account the checkpoint belongs to
+    balance NUMERIC(20,8) NOT NULL,    -- Balance including every entry up to as_of_seq
+    as_of_seq BIGINT NOT NULL,         -- Sequence of the last entry included
+    as_of_time TIMESTAMP NOT NULL,     -- created_at of the last entry included
+    created_at TIMESTAMP NOT NULL,     -- When the snapshot was taken
+    PRIMARY KEY (account_id, as_of_seq)
+);
+
+-- Compacted entries already covered by a snapshot; same shape as ledger_entries
+CREATE TABLE ledger_entries_archive (
+    id TEXT PRIMARY KEY,
+    seq BIGINT NOT NULL UNIQUE,
+    transaction_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    amount NUMERIC(20,8) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_ledger_entries_archive_account_seq
+ON ledger_entries_archive(account_id, seq);
+
+
+-- Archived entries older than COLD_STORAGE_AFTER, compressed per account and month.
+-- Totals stay uncompressed so reports can add them up without reading data.
+CREATE TABLE cold_entry_batches (
+    id BIGSERIAL PRIMARY KEY,
+    account_id TEXT NOT NULL,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    period TEXT NOT NULL,              -- Month the entries were created in, e.g. 2024-06
+    entries INT NOT NULL,              -- Number of entries in data
+    first_seq BIGINT NOT NULL,
+    last_seq BIGINT NOT NULL,
+    first_created_at TIMESTAMP NOT NULL,
+    last_created_at TIMESTAMP NOT NULL,
+    debits NUMERIC(20,8) NOT NULL,     -- Sum of the negative amounts, as a positive number
+    credits NUMERIC(20,8) NOT NULL,    -- Sum of the positive amounts
+    last_hash TEXT NOT NULL,           -- Chain hash of the entry at last_seq
+    data BYTEA NOT NULL,               -- gzip-compressed JSON lines, one entry per line
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_cold_entry_batches_account_seq
+ON cold_entry_batches(account_id, last_seq);
+
+
+CREATE TABLE accounting_periods (
+    id TEXT PRIMARY KEY,               -- Month of the period, e.g. 2024-06
+    start_date TIMESTAMP NOT NULL,     -- Inclusive
+    end_date TIMESTAMP NOT NULL,       -- Exclusive
+    status TEXT NOT NULL,              -- open | closed
+    closed_at TIMESTAMP                -- When the period was closed
+);
+
+
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    request_id TEXT NOT NULL,          -- X-Request-ID of the API call
+    actor TEXT NOT NULL,               -- Who made the change
+    action TEXT NOT NULL,              -- Route or domain operation
+    resource TEXT NOT NULL,            -- What was changed
+    before JSONB,                      -- State before the change, when known
+    after JSONB,                       -- State after the change / request payload
+    status INT NOT NULL DEFAULT 0,     -- HTTP status of the call
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor);
+CREATE INDEX idx_audit_log_request_id ON audit_log(request_id);
+
+-- The audit log is append-only
+CREATE FUNCTION reject_audit_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION reject_audit_mutation();
+
+
+CREATE TABLE accounts (
+    id TEXT PRIMARY KEY,               -- Same ID used on ledger entries; accounts without a row are active
+    tenant_id TEXT NOT NULL DEFAULT '', -- Owning tenant; set when a tenant first uses the account
+    parent_id TEXT,                    -- Parent in the account hierarchy; NULL for top-level accounts
+    status TEXT NOT NULL DEFAULT 'active', -- active | frozen | closed
+    status_reason TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL DEFAULT '',     -- Product type, used to select fee schedules
+    class TEXT NOT NULL DEFAULT '',    -- Chart of accounts: asset | liability | equity | revenue | expense
+    currency TEXT NOT NULL DEFAULT '', -- ISO 4217 code; '' is the base currency
+    overdraft_limit NUMERIC(20,8) NOT NULL DEFAULT 0, -- Balance may go down to -overdraft_limit
+    limit_profile_id TEXT NOT NULL DEFAULT '', -- Velocity limits applied to outgoing payments
+    holder_name TEXT NOT NULL DEFAULT '',  -- AES-GCM sealed (enc:v1:<key id>:...); never plaintext
+    holder_email TEXT NOT NULL DEFAULT '', -- Sealed like holder_name
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP,               -- Set once when the account is closed
+    erased_at TIMESTAMP                -- Set when the holder's personal data was erased
+);
+
+-- Walks the account hierarchy downwards for roll-up balances
+CREATE INDEX idx_accounts_parent ON accounts(parent_id) WHERE parent_id IS NOT NULL;
+
+-- Account listing: filters and the sort orders of GET /accounts
+CREATE INDEX idx_accounts_listing ON accounts(tenant_id, type, status, currency);
+CREATE INDEX idx_accounts_created ON accounts(tenant_id, created_at, id);
+CREATE INDEX idx_accounts_updated ON accounts(tenant_id, updated_at, id);
+-- ID prefix search; the primary key cannot serve LIKE outside the C collation
+CREATE INDEX idx_accounts_id_pattern ON accounts(id text_pattern_ops);
+
+CREATE TABLE limit_profiles (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    rules JSONB NOT NULL,              -- [{"window":"24h","max_amount":"1000","max_count":20}, ...]
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE fraud_rules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,                -- amount_threshold | new_counterparty | rapid_fire
+    action TEXT NOT NULL,              -- allow | flag | block
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    threshold NUMERIC(20,8) NOT NULL DEFAULT 0,
+    window_seconds BIGINT NOT NULL DEFAULT 0,
+    max_count INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE fee_schedules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    account_type TEXT NOT NULL DEFAULT '',      -- Sender account type; '' applies to every account
+    min_amount NUMERIC(20,8) NOT NULL DEFAULT 0, -- Transaction size band the schedule applies to
+    max_amount NUMERIC(20,8) NOT NULL DEFAULT 0, -- 0 means no upper bound
+    flat_fee NUMERIC(20,8) NOT NULL DEFAULT 0,
+    percentage NUMERIC(9,4) NOT NULL DEFAULT 0,  -- Percent of the amount, e.g. 1.5
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE interest_rates (
+    scope TEXT NOT NULL,               -- account | product
+    id TEXT NOT NULL,                  -- Account ID or account type
+    annual_rate NUMERIC(9,4) NOT NULL, -- Percent per year, e.g. 3.5
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, id)
+);
+
+CREATE INDEX idx_transactions_interest_accruals ON transactions(to_account)
+    WHERE metadata @> '{"type":"interest_accrual"}';
+
+
+CREATE TABLE schedules (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    from_account TEXT NOT NULL,
+    to_account TEXT NOT NULL,
+    amount NUMERIC(20,8) NOT NULL,
+    cron TEXT NOT NULL,                -- Five-field cron expression in UTC
+    reference TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,              -- active | cancelled | completed
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP,
+    next_run_at TIMESTAMP NOT NULL,    -- Next occurrence not yet executed
+    last_run_at TIMESTAMP,
+    failures INT NOT NULL DEFAULT 0,   -- Consecutive failed executions
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_schedules_due ON schedules(next_run_at) WHERE status = 'active';
+
+
+CREATE TABLE pending_transactions (
+    id TEXT PRIMARY KEY,               -- Becomes the transaction ID when posted
+    tenant_id TEXT NOT NULL DEFAULT '', -- Tenant that scheduled it
+    idempotency_key TEXT NOT NULL,
+    payload JSONB NOT NULL,            -- The transaction to post
+    force BOOLEAN NOT NULL DEFAULT FALSE,
+    execute_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,              -- pending | executing | executed | cancelled | failed
+    last_error TEXT NOT NULL DEFAULT '',
+    executed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (tenant_id, idempotency_key)
+);
+
+CREATE INDEX idx_pending_transactions_due ON pending_transactions(execute_at) WHERE status = 'pending';
+
+
+CREATE TABLE scheduler_leases (
+    name TEXT PRIMARY KEY,             -- Lease name, e.g. the scheduler leader lease
+    holder TEXT NOT NULL,              -- Replica currently holding it
+    expires_at TIMESTAMP NOT NULL      -- Other replicas may take over after this
+);
+
+
+CREATE TABLE netting_obligations (
+    id TEXT PRIMARY KEY,
+    from_institution TEXT NOT NULL,    -- Account of the paying institution
+    to_institution TEXT NOT NULL,      -- Account of the receiving institution
+    amount NUMERIC(20,8) NOT NULL CHECK (amount > 0),
+    reference TEXT NOT NULL DEFAULT '',
+    window_start TIMESTAMP NOT NULL,   -- Settlement window the obligation is netted in
+    window_end TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,              -- pending | settled
+    settlement_transaction_id TEXT NOT NULL DEFAULT '', -- Net transaction; '' when the pair netted to zero
+    created_at TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP
+);
+
+CREATE INDEX idx_netting_obligations_window ON netting_obligations(window_start);
+CREATE INDEX idx_netting_obligations_due ON netting_obligations(window_end) WHERE status = 'pending';
+
+
+CREATE TABLE business_days (
+    date TEXT PRIMARY KEY,             -- Business date, e.g. 2024-06-30
+    starts_at TIMESTAMP NOT NULL,      -- Previous cutoff, inclusive
+    ends_at TIMESTAMP NOT NULL,        -- Cutoff, exclusive
+    transaction_count BIGINT NOT NULL,
+    volume NUMERIC(20,8) NOT NULL,     -- Sum of transaction amounts
+    accounts INT NOT NULL,             -- Accounts with movement during the day
+    closed_at TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE daily_account_summaries (
+    business_date TEXT NOT NULL REFERENCES business_days(date),
+    account_id TEXT NOT NULL,
+    debits NUMERIC(20,8) NOT NULL,     -- Sum of debit entries, as a positive number
+    credits NUMERIC(20,8) NOT NULL,
+    net NUMERIC(20,8) NOT NULL,
+    entries BIGINT NOT NULL,
+    PRIMARY KEY (business_date, account_id)
+);
+
+
+-- Daily aggregates maintained by the reporting projection; reports never scan raw entries
+CREATE TABLE projection_checkpoints (
+    name TEXT PRIMARY KEY,
+    last_seq BIGINT NOT NULL           -- Highest ledger_entries.seq folded in
+);
+
+
+CREATE TABLE daily_projected_transactions (
+    transaction_id TEXT PRIMARY KEY,   -- Guards against counting a transaction twice
+    projected_at TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE daily_stats (
+    date TEXT PRIMARY KEY,             -- UTC date of the entries, e.g. 2024-06-30
+    transaction_count BIGINT NOT NULL,
+    volume NUMERIC(20,8) NOT NULL
+);
+
+
+CREATE TABLE daily_account_movements (
+    date TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    debits NUMERIC(20,8) NOT NULL,
+    credits NUMERIC(20,8) NOT NULL,
+    net NUMERIC(20,8) NOT NULL,
+    entries BIGINT NOT NULL,
+    PRIMARY KEY (date, account_id)
+);
+
+
+CREATE TABLE daily_largest_transactions (
+    date TEXT NOT NULL,
+    transaction_id TEXT NOT NULL,
+    from_account TEXT NOT NULL,
+    to_account TEXT NOT NULL,
+    amount NUMERIC(20,8) NOT NULL,
+    PRIMARY KEY (date, transaction_id)
+);
+
+
+-- Events that could not be published, waiting to be redriven to Kafka
+CREATE TABLE event_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    payload JSONB NOT NULL,            -- The event as JSON, whatever EVENT_FORMAT is
+    created_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,   -- Failed redrives; 0 while it has not been tried
+    last_error TEXT NOT NULL DEFAULT '',
+    last_attempt_at TIMESTAMP
+);
+
+
+-- Materialized balance per account, updated in the database transaction that stores the entries.
+-- Ledger entries stay the source of truth; a row is created from their sum on first use.
+CREATE TABLE account_balances (
+    account_id TEXT PRIMARY KEY,
+    balance NUMERIC(20,8) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 0, -- Bumped on every change, for optimistic concurrency
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+-- One row per UTC day exported to the analytics bucket as Parquet
+CREATE TABLE analytics_exports (
+    date DATE PRIMARY KEY,
+    entries INT NOT NULL,              -- Rows in the ledger_entries file
+    transactions INT NOT NULL,         -- Rows in the transactions file
+    entries_key TEXT NOT NULL,         -- Object keys, relative to ANALYTICS_EXPORT_URL
+    transactions_key TEXT NOT NULL,
+    exported_at TIMESTAMP NOT NULL
+);
+
+
+-- External identifiers (IBAN, card token, partner account ID) of accounts, resolved by the transactions API
+CREATE TABLE account_aliases (
+    tenant_id TEXT NOT NULL DEFAULT '', -- Aliases are unique per tenant
+    alias TEXT NOT NULL,               -- Normalized: IBANs are upper case without spaces
+    type TEXT NOT NULL,                -- iban, card_token or partner
+    account_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, alias)
+);
+
+CREATE INDEX idx_account_aliases_account ON account_aliases(account_id);
+
+
+-- Request-to-pay: an exact amount the payee asks for, fulfilled by the one transaction referencing it
+CREATE TABLE payment_requests (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    payee_account TEXT NOT NULL,       -- Credited by the fulfilling transaction
+    amount NUMERIC(20,8) NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,              -- open | paid | cancelled; expiry is derived from expires_at
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    transaction_id TEXT NOT NULL DEFAULT '', -- The fulfilling transaction
+    paid_at TIMESTAMP,
+    cancelled_at TIMESTAMP
+);
+
+CREATE INDEX idx_payment_requests_payee ON payment_requests(payee_account, created_at);
+
+
+CREATE TABLE balance_discrepancies (
+    id BIGSERIAL PRIMARY KEY,
+    account_id TEXT NOT NULL,
+    materialized NUMERIC(20,8) NOT NULL, -- account_balances.balance when last detected
+    recomputed NUMERIC(20,8) NOT NULL,   -- Sum of the account's entries when last detected
+    difference NUMERIC(20,8) NOT NULL,   -- materialized - recomputed
+    occurrences INT NOT NULL DEFAULT 1,  -- Checks that found it
+    first_detected_at TIMESTAMP NOT NULL,
+    last_detected_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP                -- Set by the first check that finds the account consistent again
+);
+
+-- At most one open discrepancy per account; later checks update it
+CREATE UNIQUE INDEX idx_balance_discrepancies_open ON balance_discrepancies(account_id) WHERE resolved_at IS NULL;
+CREATE INDEX idx_balance_discrepancies_detected ON balance_discrepancies(last_detected_at);
+
+
+CREATE TABLE suspense_items (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,                  -- The flow that parked the money, e.g. netting
+    transaction_id TEXT NOT NULL UNIQUE,   -- The posting into the suspense account
+    from_account TEXT NOT NULL,
+    intended_account TEXT NOT NULL,        -- The account that could not be credited
+    amount NUMERIC(20,8) NOT NULL,
+    reason TEXT NOT NULL,
+    status TEXT NOT NULL,                  -- open | resolved
+    created_at TIMESTAMP NOT NULL,
+    resolved_account TEXT NOT NULL DEFAULT '',
+    resolution_transaction_id TEXT NOT NULL DEFAULT '', -- The posting out of the suspense account
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX idx_suspense_items_status ON suspense_items(status, created_at);
+
+
+CREATE TABLE two_phase_legs (
+    xid TEXT PRIMARY KEY,                 -- Shared by both legs of a cross-instance transfer
+    role TEXT NOT NULL DEFAULT '',        -- debit | credit; empty for an abort that arrived before its prepare
+    account_id TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(20,8) NOT NULL DEFAULT 0,
+    coordinator TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,                 -- prepared | committed | aborted
+    transaction_id TEXT NOT NULL DEFAULT '',
+    prepared_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP
+);
+
+CREATE TABLE cross_instance_transfers (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT '',   -- Tenant that asked for it
+    idempotency_key TEXT NOT NULL,
+    from_account TEXT NOT NULL,
+    to_instance TEXT NOT NULL,
+    to_account TEXT NOT NULL,
+    amount NUMERIC(20,8) NOT NULL,
+    status TEXT NOT NULL,                 -- preparing | committed | aborted
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP,
+    completed_at TIMESTAMP,               -- Both legs applied the decision
+    UNIQUE (tenant_id, idempotency_key)
+);
+
+CREATE INDEX idx_cross_instance_transfers_unfinished ON cross_instance_transfers(created_at) WHERE completed_at IS NULL;
+
+
+CREATE TABLE consumer_offsets (
+    consumer TEXT NOT NULL,               -- Name of the read model fed from the topic
+    topic TEXT NOT NULL,
+    partition INT NOT NULL,
+    next_offset BIGINT NOT NULL,          -- Committed with the read model's own writes
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (consumer, topic, partition)
+);
+
+CREATE TABLE account_activity (
+    account_id TEXT PRIMARY KEY,          -- Read model kept by cmd/projector
+    inflow NUMERIC(20,8) NOT NULL DEFAULT 0,
+    outflow NUMERIC(20,8) NOT NULL DEFAULT 0,
+    transactions BIGINT NOT NULL DEFAULT 0,
+    last_transaction_at TIMESTAMP NOT NULL
+);
+
+
+-- Signed Merkle roots over consecutive ranges of entries, for third-party inclusion proofs
+CREATE TABLE ledger_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    from_seq BIGINT NOT NULL UNIQUE,   -- One checkpoint per range, whichever instance makes it
+    to_seq BIGINT NOT NULL,
+    size INT NOT NULL,                 -- Entries covered
+    root TEXT NOT NULL,                -- Hex RFC 6962 Merkle root over the entry hashes in seq order
+    prev_root TEXT NOT NULL,           -- Root of the previous checkpoint; '' for the first
+    key_id TEXT NOT NULL,              -- Which signing key made the signature
+    signature TEXT NOT NULL,           -- Base64 Ed25519 signature over the checkpoint fields
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_ledger_checkpoints_to_seq ON ledger_checkpoints(to_seq);
+
+
+-- The one event filter, shared by every replica
+CREATE TABLE event_filter (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    topics TEXT[] NOT NULL DEFAULT '{}',       -- Suppressed topics
+    tenants TEXT[] NOT NULL DEFAULT '{}',      -- Suppressed tenants
+    accounts TEXT[] NOT NULL DEFAULT '{}',     -- Movements only between these accounts are suppressed
+    system_accounts BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL
+);
+
+
+-- Ledger history is append-only. Updates, deletes and truncates of these tables are rejected,
+-- except a change of a transaction's tags and the deletes of compaction, which moves entries
+-- on within a transaction that has run SET LOCAL ledger.compaction = 'on'.
+CREATE FUNCTION reject_ledger_mutation() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND TG_NARGS > 0 AND TG_ARGV[0] = 'compactable'
+        AND current_setting('ledger.compaction', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND TG_TABLE_NAME = 'transactions' AND to_jsonb(NEW) - 'tags' = to_jsonb(OLD) - 'tags' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION '% on % rejected: ledger history is append-only', TG_OP, TG_TABLE_NAME
+        USING ERRCODE = 'insufficient_privilege';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_append_only BEFORE UPDATE OR DELETE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+CREATE TRIGGER transactions_no_truncate BEFORE TRUNCATE ON transactions
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER ledger_entries_append_only BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation('compactable');
+CREATE TRIGGER ledger_entries_no_truncate BEFORE TRUNCATE ON ledger_entries
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER ledger_entries_archive_append_only BEFORE UPDATE OR DELETE ON ledger_entries_archive
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation('compactable');
+CREATE TRIGGER ledger_entries_archive_no_truncate BEFORE TRUNCATE ON ledger_entries_archive
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER cold_entry_batches_append_only BEFORE UPDATE OR DELETE ON cold_entry_batches
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+CREATE TRIGGER cold_entry_batches_no_truncate BEFORE TRUNCATE ON cold_entry_batches
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER ledger_checkpoints_append_only BEFORE UPDATE OR DELETE ON ledger_checkpoints
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+CREATE TRIGGER ledger_checkpoints_no_truncate BEFORE TRUNCATE ON ledger_checkpoints
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER ledger_chain_links_append_only BEFORE UPDATE OR DELETE ON ledger_chain_links
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+CREATE TRIGGER ledger_chain_links_no_truncate BEFORE TRUNCATE ON ledger_chain_links
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
+
+
+-- Business-day calendar: days a currency does not settle on besides its weekend
+CREATE TABLE holidays (
+    currency TEXT NOT NULL,
+    date DATE NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (currency, date)
+);
+
+
+-- Balance alerts: thresholds per account checked after every posting
+CREATE TABLE balance_alerts (
+    id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL,
+    type TEXT NOT NULL,                -- balance_below | debit_above
+    threshold NUMERIC(20,8) NOT NULL,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_balance_alerts_account ON balance_alerts (account_id);
+
+
+-- Usage metering: API calls and posted transactions per tenant, API key and day
+CREATE TABLE api_usage (
+    tenant_id TEXT NOT NULL DEFAULT '',
+    key_id TEXT NOT NULL DEFAULT '',   -- fingerprint of the API key, never the key itself
+    day DATE NOT NULL,
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    transactions BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, key_id, day)
 );
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountRequest opens an account; ID is required
+type AccountRequest struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type,omitempty"`
+	Class          string          `json:"class,omitempty"`
+	Currency       string          `json:"currency,omitempty"`
+	ParentID       string          `json:"parent_id,omitempty"`
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+	HolderName     string          `json:"holder_name,omitempty"`
+	HolderEmail    string          `json:"holder_email,omitempty"`
+}
+
+type Account struct {
+	ID             string          `json:"id"`
+	TenantID       string          `json:"tenant_id,omitempty"`
+	Status         string          `json:"status"`
+	StatusReason   string          `json:"status_reason,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Class          string          `json:"class,omitempty"`
+	Currency       string          `json:"currency,omitempty"`
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+	ParentID       string          `json:"parent_id,omitempty"`
+	HolderName     string          `json:"holder_name,omitempty"`
+	HolderEmail    string          `json:"holder_email,omitempty"`
+	LimitProfileID string          `json:"limit_profile_id,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	ClosedAt       *time.Time      `json:"closed_at,omitempty"`
+}
+
+// CreateAccount is not retried: a second attempt after a lost response would fail as a conflict
+func (c *Client) CreateAccount(ctx context.Context, req AccountRequest) (Account, error) {
+	var account Account
+	_, err := c.do(ctx, call{method: http.MethodPost, path: "/accounts", body: req}, &account)
+	return account, err
+}
+
+func (c *Client) GetAccount(ctx context.Context, id string) (Account, error) {
+	var account Account
+	_, err := c.do(ctx, call{method: http.MethodGet, path: "/accounts/" + url.PathEscape(id), retryable: true}, &account)
+	return account, err
+}
+
+func (c *Client) GetBalance(ctx context.Context, accountId string) (decimal.Decimal, error) {
+	var balance struct {
+		Balance decimal.Decimal `json:"balance"`
+	}
+	_, err := c.do(ctx, call{method: http.MethodGet, path: "/accounts/" + url.PathEscape(accountId) + "/balance", retryable: true}, &balance)
+	return balance.Balance, err
+}
@@ -0,0 +1,207 @@
+// Package client is the Go SDK of the ledger HTTP API. It generates an idempotency key for
+// every posting that lacks one and retries network failures and transient server errors
+// with that same key, so a retried payment is never posted twice.
+//
+//	c := client.New("http://ledger:8080", client.WithAPIKey("sk_test_..."), client.WithTenant("acme"))
+//	posted, err := c.PostTransaction(ctx, client.TransferRequest{FromAccount: "a", ToAccount: "b", Amount: decimal.NewFromInt(10)})
+//	if errors.Is(err, client.ErrInsufficientFunds) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls one ledger instance; it is safe for concurrent use
+type Client struct {
+	baseUrl string
+	http    *http.Client
+	header  http.Header
+	retry   RetryPolicy
+}
+
+// RetryPolicy retries requests that are safe to repeat with exponential backoff
+type RetryPolicy struct {
+	Attempts  int // including the first; 1 disables retries
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default client, which times out after 30 seconds
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.http = h }
+}
+
+// WithAPIKey sends X-API-Key; an sk_test_ key keeps every call in test mode
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.header.Set("X-API-Key", key) }
+}
+
+// WithTenant sends X-Tenant-ID on every call
+func WithTenant(tenantId string) Option {
+	return func(c *Client) { c.header.Set("X-Tenant-ID", tenantId) }
+}
+
+// WithAdminToken sends the admin token as a bearer token. The admin routes need it, and so
+// does every call made without WithTenant.
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.header.Set("Authorization", "Bearer "+token) }
+}
+
+// WithActor names the caller in the audit log, with its roles
+func WithActor(actor string, roles ...string) Option {
+	return func(c *Client) {
+		c.header.Set("X-Actor", actor)
+		if len(roles) > 0 {
+			c.header.Set("X-Actor-Roles", strings.Join(roles, ","))
+		}
+	}
+}
+
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+func New(baseUrl string, opts ...Option) *Client {
+	c := &Client{
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+		header:  http.Header{},
+		retry:   RetryPolicy{Attempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// call is one API request. Retryable marks requests the server handles idempotently:
+// reads, and writes carrying an idempotency key.
+type call struct {
+	method    string
+	path      string
+	body      any
+	header    http.Header
+	retryable bool
+}
+
+// do sends the call, retrying it when allowed, and decodes a 2xx response into out
+func (c *Client) do(ctx context.Context, req call, out any) (*http.Response, error) {
+	var payload []byte
+	if req.body != nil {
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		payload = encoded
+	}
+
+	attempts := 1
+	if req.retryable {
+		attempts = max(c.retry.Attempts, 1)
+	}
+	delay := c.retry.BaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, payload, out)
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return resp, err
+		}
+
+		wait := delay
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, err
+		}
+		delay *= 2
+		if c.retry.MaxDelay > 0 {
+			delay = min(delay, c.retry.MaxDelay)
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req call, payload []byte, out any) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.baseUrl+req.path, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		httpReq.Header[name] = values
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, responseError(resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("decoding %s %s: %w", req.method, req.path, err)
+		}
+	}
+	return resp, nil
+}
+
+// responseError reads the error body, which is JSON for validation errors and plain text otherwise
+func responseError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+	var body struct {
+		Error  string       `json:"error"`
+		Fields []FieldError `json:"fields"`
+	}
+	if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+		apiErr.Message, apiErr.Fields = body.Error, body.Fields
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// retryable reports whether the failure may pass on its own: the request never got an
+// answer, or the server was unavailable or timed out. A 429 is a velocity limit, which
+// does not clear within a retry.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return true // a network error
+	}
+	switch apiErr.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors to match with errors.Is. The status code tells most apart; the rest are
+// recognised by the message the ledger answers with.
+var (
+	ErrInvalid           = errors.New("invalid request")
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrForbidden         = errors.New("forbidden")
+	ErrRejected          = errors.New("rejected")
+	ErrLimitExceeded     = errors.New("limit exceeded")
+	ErrUnavailable       = errors.New("service unavailable")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrPossibleDuplicate = errors.New("possible duplicate payment")
+)
+
+// FieldError is one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a non-2xx answer of the ledger
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     []FieldError  // set for validation errors
+	RetryAfter time.Duration // when the server asked for a pause before retrying
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("ledger: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	fields := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field.Field + ": " + field.Message
+	}
+	return fmt.Sprintf("ledger: %d %s: %s", e.StatusCode, e.Message, strings.Join(fields, "; "))
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalid:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRejected:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrLimitExceeded:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusServiceUnavailable
+	case ErrInsufficientFunds:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(e.Message, "insufficient funds")
+	case ErrAccountFrozen:
+		return e.StatusCode == http.StatusForbidden && strings.Contains(e.Message, "frozen")
+	case ErrPossibleDuplicate:
+		return e.StatusCode == http.StatusConflict && strings.Contains(e.Message, "duplicate")
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TransferRequest moves Amount from one account to another; an alias may stand in for either account
+type TransferRequest struct {
+	FromAccount string          `json:"from_account,omitempty"`
+	ToAccount   string          `json:"to_account,omitempty"`
+	FromAlias   string          `json:"from_alias,omitempty"`
+	ToAlias     string          `json:"to_alias,omitempty"`
+	Amount      decimal.Decimal `json:"amount"`
+	EffectiveAt *time.Time      `json:"effective_at,omitempty"` // backdates the posting
+	ValueDate   string          `json:"value_date,omitempty"`   // YYYY-MM-DD; rolled onto a business day
+
+	Reference   string            `json:"reference,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+
+	PaymentRequestID string           `json:"payment_request_id,omitempty"`
+	Force            bool             `json:"force,omitempty"`   // post even if it looks like a duplicate
+	FXRate           *decimal.Decimal `json:"fx_rate,omitempty"` // fixes the rate of a cross-currency transfer
+
+	// IdempotencyKey identifies the payment; one is generated when it is empty. Set it to
+	// the caller's own payment ID to stay safe across restarts of the caller too.
+	IdempotencyKey string `json:"-"`
+}
+
+// Posted is the outcome of PostTransaction
+type Posted struct {
+	TransactionID  string
+	IdempotencyKey string
+	Replayed       bool // the key had already been posted; nothing new was booked
+	Fees           []Fee
+	TotalFees      decimal.Decimal
+	FX             *FXConversion
+}
+
+type Transaction struct {
+	ID                string            `json:"id"`
+	TenantID          string            `json:"tenant_id,omitempty"`
+	IdempotencyKey    string            `json:"idempotency_key"`
+	FromAccount       string            `json:"from_account"`
+	ToAccount         string            `json:"to_account"`
+	Amount            decimal.Decimal   `json:"amount"`
+	CreatedAt         time.Time         `json:"created_at"`
+	Reference         string            `json:"reference,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`
+	Adjustment        bool              `json:"adjustment,omitempty"`
+	OriginalCreatedAt *time.Time        `json:"original_created_at,omitempty"`
+	PaymentRequestID  string            `json:"payment_request_id,omitempty"`
+	ValueDate         string            `json:"value_date,omitempty"`
+	Fees              []Fee             `json:"fees,omitempty"`
+	FX                *FXConversion     `json:"fx,omitempty"`
+}
+
+type Fee struct {
+	ScheduleID string          `json:"schedule_id"`
+	Account    string          `json:"account"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+type FXConversion struct {
+	FromCurrency    string          `json:"from_currency"`
+	ToCurrency      string          `json:"to_currency"`
+	Rate            decimal.Decimal `json:"rate"`
+	ReferenceRate   decimal.Decimal `json:"reference_rate"`
+	RateSource      string          `json:"rate_source"`
+	ConvertedAmount decimal.Decimal `json:"converted_amount"`
+	GainLoss        decimal.Decimal `json:"gain_loss"`
+}
+
+// PostTransaction posts a transfer. Every attempt carries the same idempotency key, so a
+// retry after a lost response replays the first posting instead of booking it again.
+func (c *Client) PostTransaction(ctx context.Context, req TransferRequest) (Posted, error) {
+	key := req.IdempotencyKey
+	if key == "" {
+		key = uuid.NewString()
+	}
+	var resp struct {
+		Status        string          `json:"status"`
+		TransactionID string          `json:"transaction_id"`
+		Fees          []Fee           `json:"fees"`
+		TotalFees     decimal.Decimal `json:"total_fees"`
+		FX            *FXConversion   `json:"fx"`
+	}
+	httpResp, err := c.do(ctx, call{
+		method:    http.MethodPost,
+		path:      "/transactions",
+		body:      req,
+		header:    http.Header{"Idempotency-Key": {key}},
+		retryable: true,
+	}, &resp)
+	if err != nil {
+		return Posted{}, err
+	}
+	if httpResp.StatusCode == http.StatusCreated {
+		return Posted{
+			TransactionID:  resp.TransactionID,
+			IdempotencyKey: key,
+			Fees:           resp.Fees,
+			TotalFees:      resp.TotalFees,
+			FX:             resp.FX,
+		}, nil
+	}
+
+	// A replay answers without the transaction; fetch the one posted first
+	posted := Posted{IdempotencyKey: key, Replayed: true}
+	found, _, err := c.ListTransactions(ctx, TransactionQuery{IdempotencyKey: key, Limit: 1})
+	if err != nil {
+		return posted, err
+	}
+	if len(found) > 0 {
+		tx := found[0]
+		posted.TransactionID, posted.Fees, posted.FX = tx.ID, tx.Fees, tx.FX
+		posted.TotalFees = decimal.Zero
+		for _, fee := range tx.Fees {
+			posted.TotalFees = posted.TotalFees.Add(fee.Amount)
+		}
+	}
+	return posted, nil
+}
+
+// TransactionQuery narrows ListTransactions; zero values are ignored
+type TransactionQuery struct {
+	Reference      string
+	Account        string // sender or receiver
+	Status         string
+	IdempotencyKey string
+	Metadata       map[string]string
+	Tags           []string
+	MinAmount      *decimal.Decimal
+	MaxAmount      *decimal.Decimal
+	From           time.Time
+	To             time.Time
+	Cursor         string // the next cursor of the previous page
+	Limit          int
+}
+
+func (q TransactionQuery) values() url.Values {
+	values := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	set("reference", q.Reference)
+	set("account", q.Account)
+	set("status", q.Status)
+	set("idempotency_key", q.IdempotencyKey)
+	set("cursor", q.Cursor)
+	for key, value := range q.Metadata {
+		values.Set("metadata."+key, value)
+	}
+	for _, tag := range q.Tags {
+		values.Add("tag", tag)
+	}
+	if q.MinAmount != nil {
+		values.Set("min_amount", q.MinAmount.String())
+	}
+	if q.MaxAmount != nil {
+		values.Set("max_amount", q.MaxAmount.String())
+	}
+	if !q.From.IsZero() {
+		values.Set("from", q.From.Format(time.RFC3339Nano))
+	}
+	if !q.To.IsZero() {
+		values.Set("to", q.To.Format(time.RFC3339Nano))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// ListTransactions searches transactions newest first. The cursor is empty on the last page.
+func (c *Client) ListTransactions(ctx context.Context, q TransactionQuery) ([]Transaction, string, error) {
+	path := "/transactions"
+	if values := q.values(); len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var transactions []Transaction
+	resp, err := c.do(ctx, call{method: http.MethodGet, path: path, retryable: true}, &transactions)
+	if err != nil {
+		return nil, "", err
+	}
+	return transactions, resp.Header.Get("X-Next-Cursor"), nil
+}
+
+// ReverseTransaction posts the mirror image of a transaction. The ledger keys the reversal
+// by the original, so it is retried like a posting.
+func (c *Client) ReverseTransaction(ctx context.Context, id, reason string) (Transaction, error) {
+	var reversal Transaction
+	_, err := c.do(ctx, call{
+		method:    http.MethodPost,
+		path:      "/transactions/" + url.PathEscape(id) + "/reverse",
+		body:      map[string]string{"reason": reason},
+		retryable: true,
+	}, &reversal)
+	return reversal, err
+}